@@ -0,0 +1,231 @@
+package api
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trezor/blockbook/common"
+)
+
+// Cost basis methods of the gains in the balance history
+const (
+	GainsFIFO = "fifo"
+	GainsLIFO = "lifo"
+	GainsAvg  = "avg"
+)
+
+// gainsLot is an acquired amount of coins with the rates (cost per coin) at the time of the acquisition
+type gainsLot struct {
+	amount float64
+	rates  map[string]float64
+}
+
+// costBasis keeps the lots of the acquired coins, for the avg method there is at most one lot with the average rates
+type costBasis struct {
+	method     string
+	currencies []string
+	lots       []gainsLot
+}
+
+func validateGains(method string, currencies []string) error {
+	switch method {
+	case GainsFIFO, GainsLIFO, GainsAvg:
+	default:
+		return NewAPIError("Invalid parameter 'gains', expected fifo, lifo or avg", true)
+	}
+	if len(currencies) == 0 {
+		return NewAPIError("Parameter 'gains' requires a fiat currency", true)
+	}
+	return nil
+}
+
+// acquire adds a lot, the currencies without a rate have zero cost basis
+func (cb *costBasis) acquire(amount float64, rates map[string]float32) {
+	lot := gainsLot{amount: amount, rates: make(map[string]float64, len(cb.currencies))}
+	for _, c := range cb.currencies {
+		lot.rates[c] = float64(rates[c])
+	}
+	if cb.method == GainsAvg && len(cb.lots) > 0 {
+		l := &cb.lots[0]
+		total := l.amount + amount
+		for _, c := range cb.currencies {
+			l.rates[c] = (l.amount*l.rates[c] + amount*lot.rates[c]) / total
+		}
+		l.amount = total
+		return
+	}
+	cb.lots = append(cb.lots, lot)
+}
+
+// dispose removes the amount from the lots (the oldest first for fifo and avg, the newest first for lifo)
+// and returns the realized gains in the currencies with a rate
+func (cb *costBasis) dispose(amount float64, rates map[string]float32) map[string]float64 {
+	realized := make(map[string]float64, len(cb.currencies))
+	for _, c := range cb.currencies {
+		if _, found := rates[c]; found {
+			realized[c] = 0
+		}
+	}
+	for amount > 0 && len(cb.lots) > 0 {
+		i := 0
+		if cb.method == GainsLIFO {
+			i = len(cb.lots) - 1
+		}
+		l := &cb.lots[i]
+		consumed := amount
+		if consumed > l.amount {
+			consumed = l.amount
+		}
+		for c := range realized {
+			realized[c] += consumed * (float64(rates[c]) - l.rates[c])
+		}
+		l.amount -= consumed
+		amount -= consumed
+		if l.amount <= 0 {
+			cb.lots = append(cb.lots[:i], cb.lots[i+1:]...)
+		}
+	}
+	// the amount exceeding the held coins has zero cost basis
+	if amount > 0 {
+		for c := range realized {
+			realized[c] += amount * float64(rates[c])
+		}
+	}
+	return realized
+}
+
+// balance returns the amount of the held coins and their cost basis
+func (cb *costBasis) balance() (float64, map[string]float64) {
+	var amount float64
+	cost := make(map[string]float64, len(cb.currencies))
+	for _, c := range cb.currencies {
+		cost[c] = 0
+	}
+	for i := range cb.lots {
+		l := &cb.lots[i]
+		amount += l.amount
+		for c := range cost {
+			cost[c] += l.amount * l.rates[c]
+		}
+	}
+	return amount, cost
+}
+
+// gainsTx is the net change of balance of a transaction, merged from the histories of all addresses of an xpub
+type gainsTx struct {
+	time uint32
+	net  big.Int
+}
+
+// mergeGainsTxs sorts the txHistories and merges the histories of the same transaction
+func mergeGainsTxs(txHistories BalanceHistories) []*gainsTx {
+	sort.Sort(txHistories)
+	txs := make([]*gainsTx, 0, len(txHistories))
+	for i := range txHistories {
+		bh := &txHistories[i]
+		var tx *gainsTx
+		if i > 0 && txHistories[i-1].Txid == bh.Txid && txHistories[i-1].Time == bh.Time {
+			tx = txs[len(txs)-1]
+		} else {
+			tx = &gainsTx{time: bh.Time}
+			txs = append(txs, tx)
+		}
+		tx.net.Add(&tx.net, (*big.Int)(bh.ReceivedSat))
+		tx.net.Sub(&tx.net, (*big.Int)(bh.SentSat))
+	}
+	return txs
+}
+
+// setGainsToBalanceHistories sets the realized gains to the aggregated histories and returns the cost basis
+// and unrealized gains of the balance at the end of the range, the cost basis is computed from txHistories,
+// which must contain the histories of all transactions since the beginning of the account up to the end of the range
+// the net change of balance of a transaction (including fees) is either an acquisition or a disposal
+func (w *Worker) setGainsToBalanceHistories(histories BalanceHistories, txHistories BalanceHistories, currencies []string, method string, groupBy uint32, fromUnix, toUnix uint32) (*BalanceHistoryGains, error) {
+	txs := mergeGainsTxs(txHistories)
+	timestamps := make([]int64, len(txs)+1)
+	for i, tx := range txs {
+		timestamps[i] = int64(tx.time)
+	}
+	endUnix := int64(toUnix)
+	if now := time.Now().Unix(); endUnix > now {
+		endUnix = now
+	}
+	timestamps[len(txs)] = endUnix
+	tickers, err := w.fiatRates.GetTickersForTimestamps(timestamps, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if tickers == nil {
+		return nil, NewAPIError("Fiat rates are not available", true)
+	}
+	return computeGains(histories, txs, *tickers, currencies, method, w.chainParser.AmountDecimals(), groupBy, fromUnix, uint32(endUnix))
+}
+
+// computeGains sets the realized gains of the disposals in the range to the histories aggregated by groupBy
+// and returns the gains of the balance at endUnix, tickers contain the ticker of each of txs followed by the ticker at endUnix
+func computeGains(histories BalanceHistories, txs []*gainsTx, tickers []*common.CurrencyRatesTicker, currencies []string, method string, decimals int, groupBy, fromUnix, endUnix uint32) (*BalanceHistoryGains, error) {
+	lower := make([]string, len(currencies))
+	for i := range currencies {
+		lower[i] = strings.ToLower(currencies[i])
+	}
+	bucketIndex := make(map[uint32]int, len(histories))
+	for i := range histories {
+		bucketIndex[histories[i].Time] = i
+	}
+	cb := costBasis{method: method, currencies: lower}
+	for i, tx := range txs {
+		var rates map[string]float32
+		if t := tickers[i]; t != nil {
+			rates = t.Rates
+		}
+		sign := tx.net.Sign()
+		if sign == 0 {
+			continue
+		}
+		amount, err := strconv.ParseFloat((*Amount)(new(big.Int).Abs(&tx.net)).DecimalString(decimals), 64)
+		if err != nil {
+			return nil, err
+		}
+		if sign > 0 {
+			cb.acquire(amount, rates)
+			continue
+		}
+		realized := cb.dispose(amount, rates)
+		if tx.time < fromUnix {
+			continue
+		}
+		if bi, found := bucketIndex[tx.time-tx.time%groupBy]; found {
+			bh := &histories[bi]
+			if bh.RealizedGains == nil {
+				bh.RealizedGains = make(map[string]float64, len(realized))
+			}
+			for c, g := range realized {
+				bh.RealizedGains[c] += g
+			}
+		}
+	}
+	amount, cost := cb.balance()
+	gains := &BalanceHistoryGains{
+		Time:            endUnix,
+		FiatRates:       make(map[string]float32, len(lower)),
+		CostBasis:       cost,
+		UnrealizedGains: make(map[string]float64, len(lower)),
+	}
+	endTicker := tickers[len(txs)]
+	for _, c := range lower {
+		rate, found := float32(0), false
+		if endTicker != nil {
+			rate, found = endTicker.Rates[c]
+		}
+		if found {
+			gains.FiatRates[c] = rate
+			gains.UnrealizedGains[c] = amount*float64(rate) - cost[c]
+		} else {
+			gains.FiatRates[c] = -1
+		}
+	}
+	return gains, nil
+}
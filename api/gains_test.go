@@ -0,0 +1,132 @@
+//go:build unittest
+
+package api
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/trezor/blockbook/common"
+)
+
+func Test_costBasis(t *testing.T) {
+	tests := []struct {
+		method       string
+		wantRealized float64
+		wantCost     float64
+	}{
+		// sell 1.5 at 40: first lot 1@10, then 0.5@20
+		{method: GainsFIFO, wantRealized: 1*(40-10) + 0.5*(40-20), wantCost: 1.5 * 20},
+		// sell 1.5 at 40: first lot 2@20, remains 0.5@20 and 1@10
+		{method: GainsLIFO, wantRealized: 1.5 * (40 - 20), wantCost: 0.5*20 + 1*10},
+		// average cost of 3 coins is 50/3
+		{method: GainsAvg, wantRealized: 1.5 * (40 - 50.0/3), wantCost: 1.5 * 50 / 3},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			cb := costBasis{method: tt.method, currencies: []string{"usd", "eur"}}
+			cb.acquire(1, map[string]float32{"usd": 10, "eur": 9})
+			cb.acquire(2, map[string]float32{"usd": 20, "eur": 18})
+			realized := cb.dispose(1.5, map[string]float32{"usd": 40})
+			if len(realized) != 1 || !near(realized["usd"], tt.wantRealized) {
+				t.Errorf("dispose() = %v, want usd %v", realized, tt.wantRealized)
+			}
+			amount, cost := cb.balance()
+			if !near(amount, 1.5) || !near(cost["usd"], tt.wantCost) {
+				t.Errorf("balance() = %v, %v, want 1.5, usd %v", amount, cost, tt.wantCost)
+			}
+			// disposal exceeding the balance has zero cost basis for the excess
+			realized = cb.dispose(2, map[string]float32{"usd": 30})
+			if want := 1.5*30 - tt.wantCost + 0.5*30; !near(realized["usd"], want) {
+				t.Errorf("dispose() = %v, want usd %v", realized, want)
+			}
+			if amount, _ = cb.balance(); amount != 0 {
+				t.Errorf("balance() = %v, want 0", amount)
+			}
+		})
+	}
+}
+
+func Test_validateGains(t *testing.T) {
+	if err := validateGains(GainsFIFO, []string{"usd"}); err != nil {
+		t.Errorf("validateGains() error = %v", err)
+	}
+	if err := validateGains("hifo", []string{"usd"}); err == nil {
+		t.Error("validateGains(hifo): expected error")
+	}
+	if err := validateGains(GainsAvg, nil); err == nil {
+		t.Error("validateGains() without currency: expected error")
+	}
+}
+
+func Test_computeGains(t *testing.T) {
+	bh := func(time uint32, txid string, received, sent int64) BalanceHistory {
+		return BalanceHistory{
+			Time:          time,
+			Txs:           1,
+			Txid:          txid,
+			ReceivedSat:   (*Amount)(big.NewInt(received)),
+			SentSat:       (*Amount)(big.NewInt(sent)),
+			SentToSelfSat: &Amount{},
+		}
+	}
+	const fromUnix, groupBy = 7200, 3600
+	txHistories := BalanceHistories{
+		bh(11000, "e", 100000000, 0),
+		// disposal before the range, affects only the cost basis
+		bh(3700, "b", 0, 50000000),
+		bh(100, "a", 200000000, 0),
+		// disposal at the start of a bucket
+		bh(7200, "c", 0, 50000000),
+		// one transaction of two addresses of an xpub, the net change is a disposal of 0.25
+		bh(9000, "d", 0, 100000000),
+		bh(9000, "d", 75000000, 0),
+	}
+	var inRange BalanceHistories
+	for i := range txHistories {
+		if txHistories[i].Time >= fromUnix {
+			inRange = append(inRange, txHistories[i])
+		}
+	}
+	histories := inRange.SortAndAggregate(groupBy)
+	txs := mergeGainsTxs(txHistories)
+	if len(txs) != 5 {
+		t.Fatalf("mergeGainsTxs() returned %d txs, want 5", len(txs))
+	}
+	usd := func(rate float32) *common.CurrencyRatesTicker {
+		return &common.CurrencyRatesTicker{Rates: map[string]float32{"usd": rate}}
+	}
+	// the tickers of the txs at 100, 3700, 7200, 9000, 11000 and at the end of the range
+	tickers := []*common.CurrencyRatesTicker{usd(10), usd(20), usd(30), usd(40), usd(50), usd(60)}
+	currencies := []string{"USD", "eur"}
+	gains, err := computeGains(histories, txs, tickers, currencies, GainsFIFO, 8, groupBy, fromUnix, 12000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if currencies[0] != "USD" {
+		t.Errorf("computeGains() modified currencies %v", currencies)
+	}
+	if len(histories) != 2 || histories[0].Time != 7200 || histories[1].Time != 10800 {
+		t.Fatalf("histories = %+v, want buckets 7200 and 10800", histories)
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	// 0.5*(30-10) + 0.25*(40-10)
+	if g := histories[0].RealizedGains; len(g) != 1 || !near(g["usd"], 17.5) {
+		t.Errorf("bucket 7200 RealizedGains = %v, want usd 17.5", g)
+	}
+	if g := histories[1].RealizedGains; g != nil {
+		t.Errorf("bucket 10800 RealizedGains = %v, want nil", g)
+	}
+	// held 0.75 bought at 10 and 1 bought at 50, valued at 60
+	if gains.Time != 12000 || !near(gains.CostBasis["usd"], 57.5) || !near(gains.UnrealizedGains["usd"], 1.75*60-57.5) {
+		t.Errorf("computeGains() = %+v, want cost basis 57.5 and unrealized gains 47.5", gains)
+	}
+	if gains.FiatRates["usd"] != 60 || gains.FiatRates["eur"] != -1 {
+		t.Errorf("computeGains() rates = %v, want usd 60, eur -1", gains.FiatRates)
+	}
+	if _, found := gains.UnrealizedGains["eur"]; found {
+		t.Errorf("computeGains() unrealized gains %v, want no eur", gains.UnrealizedGains)
+	}
+}
@@ -0,0 +1,380 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/db"
+)
+
+const defaultIpfsGateway = "https://ipfs.io/ipfs/"
+
+// the metadata larger than the limit are not downloaded
+const maxNftMetadataSize = 1 << 20
+
+// the failed download of the metadata is not retried for the duration
+const nftMetadataFailureTTL = 10 * time.Minute
+
+// maximum number of the failed downloads remembered, the expired ones are removed when the limit is reached
+const maxNftMetadataFailures = 10000
+
+// the cached metadata are downloaded again on the refresh request only if they are older than the interval
+const nftMetadataRefreshInterval = time.Hour
+
+type nftMetadataFailure struct {
+	time time.Time
+	uri  string
+}
+
+var nftMetadataFailures map[string]nftMetadataFailure
+var nftMetadataFailuresMux sync.Mutex
+
+var nftMetadataClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: checkNftMetadataDial,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 5 {
+			return errors.New("too many redirects")
+		}
+		if allowed, _ := req.Context().Value(nftMetadataHostsKey{}).(func(*url.URL) bool); allowed == nil || !allowed(req.URL) {
+			return errors.Errorf("redirect to %v not allowed", req.URL.Host)
+		}
+		return nil
+	},
+}
+
+// nftMetadataHostsKey is the context key of the check of the hosts allowed in the redirects
+type nftMetadataHostsKey struct{}
+
+// checkNftMetadataDial rejects the connections to the loopback, private and other non public addresses,
+// the check is done on the resolved address, therefore it cannot be bypassed by the DNS of the host
+func checkNftMetadataDial(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return errors.Errorf("address %v not allowed", host)
+	}
+	return nil
+}
+
+func (w *Worker) ipfsGateway() string {
+	if w.is != nil && w.is.IpfsGateway != "" {
+		return w.is.IpfsGateway
+	}
+	return defaultIpfsGateway
+}
+
+// nftMetadataHostAllowed checks that the metadata can be downloaded from the URL,
+// only https URLs of the host of the ipfs gateway or of the hosts configured by the nft_metadata_hosts option are allowed
+func (w *Worker) nftMetadataHostAllowed(u *url.URL) bool {
+	if u.Scheme != "https" || u.User != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if g, err := url.Parse(w.ipfsGateway()); err == nil && strings.ToLower(g.Hostname()) == host {
+		return true
+	}
+	if w.is != nil {
+		for _, h := range w.is.NftMetadataHosts {
+			if strings.ToLower(h) == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nftMetadataFailureKey(cd bchain.AddressDescriptor, id *big.Int) string {
+	return string(cd) + id.String()
+}
+
+// getNftMetadataFailure returns the failed download of the metadata of the token if it happened less than nftMetadataFailureTTL ago
+func getNftMetadataFailure(key string) (nftMetadataFailure, bool) {
+	nftMetadataFailuresMux.Lock()
+	defer nftMetadataFailuresMux.Unlock()
+	f, found := nftMetadataFailures[key]
+	if found && time.Since(f.time) >= nftMetadataFailureTTL {
+		delete(nftMetadataFailures, key)
+		return f, false
+	}
+	return f, found
+}
+
+func setNftMetadataFailure(key string, uri string) {
+	nftMetadataFailuresMux.Lock()
+	defer nftMetadataFailuresMux.Unlock()
+	if nftMetadataFailures == nil {
+		nftMetadataFailures = make(map[string]nftMetadataFailure)
+	}
+	if len(nftMetadataFailures) >= maxNftMetadataFailures {
+		for k, f := range nftMetadataFailures {
+			if time.Since(f.time) >= nftMetadataFailureTTL {
+				delete(nftMetadataFailures, k)
+			}
+		}
+		if len(nftMetadataFailures) >= maxNftMetadataFailures {
+			return
+		}
+	}
+	nftMetadataFailures[key] = nftMetadataFailure{time: time.Now(), uri: uri}
+}
+
+// resolveNftURI resolves the ipfs:// URI (and the URI of the default gateway returned by the chain) through the configured ipfs gateway,
+// only https:// URIs are returned, other URIs are replaced by an empty string
+func (w *Worker) resolveNftURI(uri string) string {
+	uri = strings.TrimSpace(uri)
+	if strings.HasPrefix(uri, "ipfs://") {
+		uri = strings.TrimPrefix(uri[len("ipfs://"):], "ipfs/")
+		return w.ipfsGateway() + uri
+	}
+	if strings.HasPrefix(uri, defaultIpfsGateway) {
+		return w.ipfsGateway() + uri[len(defaultIpfsGateway):]
+	}
+	if !strings.HasPrefix(uri, "https://") {
+		return ""
+	}
+	return uri
+}
+
+// fetchNftMetadata downloads the metadata from the token URI, the URI can point directly to the image of the token
+func (w *Worker) fetchNftMetadata(uri string) (*db.NftMetadata, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if !w.nftMetadataHostAllowed(u) {
+		return nil, errors.Errorf("host %v not allowed", u.Host)
+	}
+	ctx := context.WithValue(context.Background(), nftMetadataHostsKey{}, w.nftMetadataHostAllowed)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := nftMetadataClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("status code %v", resp.StatusCode)
+	}
+	m := &db.NftMetadata{
+		URI:  uri,
+		Time: time.Now().Unix(),
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "image/") {
+		m.ImageURI = uri
+		return m, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxNftMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxNftMetadataSize {
+		return nil, errors.New("metadata too large")
+	}
+	var metadata struct {
+		Image    interface{} `json:"image"`
+		ImageURL interface{} `json:"image_url"`
+	}
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, errors.Annotatef(err, "invalid metadata")
+	}
+	m.Metadata = string(body)
+	image, _ := metadata.Image.(string)
+	if image == "" {
+		image, _ = metadata.ImageURL.(string)
+	}
+	m.ImageURI = w.resolveNftURI(image)
+	return m, nil
+}
+
+// getNftMetadata returns the metadata of the token from the cache, if not cached or refresh of the metadata older than nftMetadataRefreshInterval is requested,
+// the metadata are downloaded and cached, the failed downloads are not retried for nftMetadataFailureTTL
+func (w *Worker) getNftMetadata(cd bchain.AddressDescriptor, id *big.Int, refresh bool) (*db.NftMetadata, error) {
+	cached, err := w.db.GetNftMetadata(cd, id)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && (!refresh || time.Since(time.Unix(cached.Time, 0)) < nftMetadataRefreshInterval) {
+		return cached, nil
+	}
+	failureKey := nftMetadataFailureKey(cd, id)
+	if f, found := getNftMetadataFailure(failureKey); found {
+		if cached != nil || f.uri == "" {
+			return cached, nil
+		}
+		return &db.NftMetadata{URI: f.uri}, nil
+	}
+	uri, err := w.chain.GetTokenURI(cd, id)
+	if err != nil {
+		glog.Warningf("GetTokenURI %v %v: %v", cd, id, err)
+		setNftMetadataFailure(failureKey, "")
+		return cached, nil
+	}
+	uri = w.resolveNftURI(uri)
+	if uri == "" {
+		return cached, nil
+	}
+	m, err := w.fetchNftMetadata(uri)
+	if err != nil {
+		glog.Warningf("fetchNftMetadata %v: %v", uri, err)
+		setNftMetadataFailure(failureKey, uri)
+		if cached != nil {
+			return cached, nil
+		}
+		return &db.NftMetadata{URI: uri}, nil
+	}
+	if err := w.db.StoreNftMetadata(cd, id, m); err != nil {
+		glog.Errorf("StoreNftMetadata %v %v: %v", cd, id, err)
+	}
+	return m, nil
+}
+
+func (w *Worker) getNftContract(contract string) (bchain.AddressDescriptor, *bchain.ContractInfo, error) {
+	if w.chainType != bchain.ChainEthereumType {
+		return nil, nil, NewAPIError("NFT collections are supported only for Ethereum type coins", true)
+	}
+	cd, err := w.chainParser.GetAddrDescFromAddress(contract)
+	if err != nil {
+		return nil, nil, NewAPIError(fmt.Sprintf("Invalid contract, %v", err), true)
+	}
+	ci, _, err := w.getContractDescriptorInfo(cd, bchain.UnknownTokenStandard)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cd, ci, nil
+}
+
+func (w *Worker) addressFromAddrDesc(addrDesc bchain.AddressDescriptor, addresses map[string]struct{}) string {
+	a, _, err := w.chainParser.GetAddressesFromAddrDesc(addrDesc)
+	if err != nil || len(a) == 0 {
+		glog.Warning("unparsable address descriptor ", addrDesc, ", error ", err)
+		return ""
+	}
+	if addresses != nil {
+		addresses[a[0]] = struct{}{}
+	}
+	return a[0]
+}
+
+// GetNftCollection returns a page of the tokens of the NFT collection with their current owners
+func (w *Worker) GetNftCollection(contract string, page int, pageSize int) (*NftCollection, error) {
+	start := time.Now()
+	page--
+	if page < 0 {
+		page = 0
+	}
+	cd, ci, err := w.getNftContract(contract)
+	if err != nil {
+		return nil, err
+	}
+	items, total, err := w.db.GetNftHolders(cd, page*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	r := &NftCollection{ContractInfo: ci}
+	var from int
+	r.Paging, from, _, _ = computePaging(total, page, pageSize)
+	if from != page*pageSize {
+		// requested page is out of range, return the last page
+		if items, _, err = w.db.GetNftHolders(cd, from, pageSize); err != nil {
+			return nil, err
+		}
+	}
+	addresses := w.newAddressesMapForAliases()
+	r.Tokens = make([]NftCollectionItem, len(items))
+	for i := range items {
+		item := &items[i]
+		r.Tokens[i] = NftCollectionItem{
+			Id:      (*Amount)(&item.Id),
+			Owner:   w.addressFromAddrDesc(item.Owner, addresses),
+			Balance: (*Amount)(&item.Balance),
+		}
+	}
+	r.AddressAliases = w.getAddressAliases(addresses)
+	glog.Info("GetNftCollection ", contract, ", page ", page, ", ", time.Since(start))
+	return r, nil
+}
+
+// GetNftToken returns the detail of the NFT token with its owners, metadata and the history of the transfers
+func (w *Worker) GetNftToken(contract string, id string, refresh bool) (*NftToken, error) {
+	start := time.Now()
+	cd, ci, err := w.getNftContract(contract)
+	if err != nil {
+		return nil, err
+	}
+	tokenId, ok := new(big.Int).SetString(id, 10)
+	if !ok {
+		return nil, NewAPIError("Invalid token id", true)
+	}
+	holders, err := w.db.GetNftTokenHolders(cd, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := w.db.GetNftTransfers(cd, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	m, err := w.getNftMetadata(cd, tokenId, refresh)
+	if err != nil {
+		return nil, err
+	}
+	addresses := w.newAddressesMapForAliases()
+	r := &NftToken{
+		ContractInfo: ci,
+		Id:           (*Amount)(tokenId),
+		Owners:       make([]NftOwner, len(holders)),
+		Transfers:    make([]NftTransfer, len(transfers)),
+	}
+	for i := range holders {
+		r.Owners[i] = NftOwner{
+			Address: w.addressFromAddrDesc(holders[i].Owner, addresses),
+			Balance: (*Amount)(&holders[i].Balance),
+		}
+	}
+	for i := range transfers {
+		t := &transfers[i]
+		r.Transfers[i] = NftTransfer{
+			Txid:        t.Txid,
+			BlockHeight: t.Height,
+			From:        w.addressFromAddrDesc(t.From, addresses),
+			To:          w.addressFromAddrDesc(t.To, addresses),
+			Value:       (*Amount)(&t.Value),
+		}
+	}
+	if m != nil {
+		r.URI = m.URI
+		r.ImageURI = m.ImageURI
+		r.MetadataTime = m.Time
+		if m.Metadata != "" {
+			r.Metadata = json.RawMessage(m.Metadata)
+		}
+	}
+	r.AddressAliases = w.getAddressAliases(addresses)
+	glog.Info("GetNftToken ", contract, " ", id, ", ", time.Since(start))
+	return r, nil
+}
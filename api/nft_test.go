@@ -0,0 +1,87 @@
+//go:build unittest
+
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/trezor/blockbook/common"
+)
+
+func Test_nftMetadataHostAllowed(t *testing.T) {
+	w := &Worker{is: &common.InternalState{
+		IpfsGateway:      "https://gateway.example.com/ipfs/",
+		NftMetadataHosts: []string{"Metadata.Example.org"},
+	}}
+	tests := []struct {
+		uri  string
+		want bool
+	}{
+		{"https://gateway.example.com/ipfs/Qm123", true},
+		{"https://GATEWAY.example.com:443/ipfs/Qm123", true},
+		{"https://metadata.example.org/token/1", true},
+		{"http://gateway.example.com/ipfs/Qm123", false},
+		{"https://user@gateway.example.com/ipfs/Qm123", false},
+		{"https://ipfs.io/ipfs/Qm123", false},
+		{"https://127.0.0.1/token/1", false},
+		{"https://metadata.example.org.attacker.com/token/1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			u, err := url.Parse(tt.uri)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := w.nftMetadataHostAllowed(u); got != tt.want {
+				t.Errorf("nftMetadataHostAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkNftMetadataDial(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", false},
+		{"127.0.0.1:443", true},
+		{"10.1.2.3:443", true},
+		{"172.16.0.1:443", true},
+		{"192.168.1.1:443", true},
+		{"169.254.169.254:80", true},
+		{"0.0.0.0:443", true},
+		{"[::1]:443", true},
+		{"[fd00::1]:443", true},
+		{"[fe80::1]:443", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if err := checkNftMetadataDial("tcp", tt.address, nil); (err != nil) != tt.wantErr {
+				t.Errorf("checkNftMetadataDial() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_nftMetadataFailure(t *testing.T) {
+	key := "failure-test"
+	if _, found := getNftMetadataFailure(key); found {
+		t.Fatal("unexpected failure")
+	}
+	setNftMetadataFailure(key, "https://gateway.example.com/ipfs/Qm123")
+	f, found := getNftMetadataFailure(key)
+	if !found || f.uri != "https://gateway.example.com/ipfs/Qm123" {
+		t.Errorf("getNftMetadataFailure() = %+v, %v", f, found)
+	}
+	// the expired failure is removed
+	nftMetadataFailuresMux.Lock()
+	f.time = f.time.Add(-nftMetadataFailureTTL)
+	nftMetadataFailures[key] = f
+	nftMetadataFailuresMux.Unlock()
+	if _, found := getNftMetadataFailure(key); found {
+		t.Error("expired failure found")
+	}
+}
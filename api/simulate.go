@@ -0,0 +1,212 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/eth"
+)
+
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// simulationBalances accumulates the balance changes per address in the order of the first appearance of the address
+type simulationBalances struct {
+	changes []SimulationBalanceChange
+	index   map[string]int
+}
+
+func (b *simulationBalances) get(address string) *SimulationBalanceChange {
+	i, found := b.index[address]
+	if !found {
+		i = len(b.changes)
+		b.index[address] = i
+		b.changes = append(b.changes, SimulationBalanceChange{Address: address, Delta: (*Amount)(new(big.Int))})
+	}
+	return &b.changes[i]
+}
+
+func (b *simulationBalances) addNative(from, to string, value *big.Int) {
+	if value.Sign() == 0 || from == to {
+		return
+	}
+	d := (*big.Int)(b.get(from).Delta)
+	d.Sub(d, value)
+	d = (*big.Int)(b.get(to).Delta)
+	d.Add(d, value)
+}
+
+func sameTokenId(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+func (b *simulationBalances) addToken(address string, t *TokenTransfer, id *big.Int, value *big.Int) {
+	if address == zeroAddress {
+		// mint or burn
+		return
+	}
+	c := b.get(address)
+	var tc *SimulationTokenChange
+	for i := range c.Tokens {
+		if c.Tokens[i].Contract == t.Contract && sameTokenId((*big.Int)(c.Tokens[i].Id), id) {
+			tc = &c.Tokens[i]
+			break
+		}
+	}
+	if tc == nil {
+		c.Tokens = append(c.Tokens, SimulationTokenChange{
+			Standard: t.Standard,
+			Contract: t.Contract,
+			Name:     t.Name,
+			Symbol:   t.Symbol,
+			Decimals: t.Decimals,
+			Id:       (*Amount)(id),
+			Delta:    (*Amount)(new(big.Int)),
+		})
+		tc = &c.Tokens[len(c.Tokens)-1]
+	}
+	(*big.Int)(tc.Delta).Add((*big.Int)(tc.Delta), value)
+}
+
+func (b *simulationBalances) transferToken(t *TokenTransfer, id *big.Int, value *big.Int) {
+	if value.Sign() == 0 || t.From == t.To {
+		return
+	}
+	b.addToken(t.From, t, id, new(big.Int).Neg(value))
+	b.addToken(t.To, t, id, value)
+}
+
+// nonZero returns the balance changes without the zero deltas
+func (b *simulationBalances) nonZero() []SimulationBalanceChange {
+	r := make([]SimulationBalanceChange, 0, len(b.changes))
+	for _, c := range b.changes {
+		tokens := c.Tokens[:0]
+		for _, t := range c.Tokens {
+			if (*big.Int)(t.Delta).Sign() != 0 {
+				tokens = append(tokens, t)
+			}
+		}
+		c.Tokens = tokens
+		if len(c.Tokens) == 0 {
+			c.Tokens = nil
+			if (*big.Int)(c.Delta).Sign() == 0 {
+				continue
+			}
+		}
+		r = append(r, c)
+	}
+	return r
+}
+
+// getSimulationFee returns the fee of the simulated transaction, nil if the gas price is not known
+func getSimulationFee(s *bchain.EthereumSimulation) *big.Int {
+	if s.GasPrice == nil {
+		return nil
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(s.GasUsed), s.GasPrice)
+}
+
+// getSimulationBalanceChanges computes the predicted balance changes from the fee, the value transfers and the token transfers of the simulation,
+// the token transfers must be created from the (sorted) token transfers of the simulation by getEthereumTokensTransfers,
+// only the fee is paid by the sender if the transaction fails
+func getSimulationBalanceChanges(s *bchain.EthereumSimulation, tokenTransfers []TokenTransfer) []SimulationBalanceChange {
+	b := simulationBalances{index: make(map[string]int)}
+	if fee := getSimulationFee(s); fee != nil {
+		d := (*big.Int)(b.get(s.From).Delta)
+		d.Sub(d, fee)
+	}
+	if s.Error != "" {
+		return b.nonZero()
+	}
+	b.addNative(s.From, s.To, &s.Value)
+	for i := range s.Transfers {
+		t := &s.Transfers[i]
+		b.addNative(t.From, t.To, &t.Value)
+	}
+	for i := range tokenTransfers {
+		t := &tokenTransfers[i]
+		// the transfer of an unknown contract is left empty by getEthereumTokensTransfers
+		if t.Contract == "" || i >= len(s.TokenTransfers) {
+			continue
+		}
+		st := s.TokenTransfers[i]
+		switch st.Standard {
+		case bchain.FungibleToken:
+			b.transferToken(t, nil, &st.Value)
+		case bchain.NonFungibleToken:
+			b.transferToken(t, &st.Value, big.NewInt(1))
+		case bchain.MultiToken:
+			for j := range st.MultiTokenValues {
+				b.transferToken(t, &st.MultiTokenValues[j].Id, &st.MultiTokenValues[j].Value)
+			}
+		}
+	}
+	return b.nonZero()
+}
+
+// SimulateTransaction simulates the Ethereum type transaction in the state of the latest block and returns its predicted effects,
+// the parameters are the fields of the eth_call transaction object with an optional stateOverrides set
+func (w *Worker) SimulateTransaction(params map[string]interface{}) (*TransactionSimulation, error) {
+	start := time.Now()
+	if w.chainType != bchain.ChainEthereumType {
+		return nil, NewAPIError("Transaction simulation is supported only for Ethereum type coins", true)
+	}
+	from, _ := eth.GetStringFromMap("from", params)
+	if from == "" {
+		return nil, NewAPIError("Missing parameter from", true)
+	}
+	if _, err := w.chainParser.GetAddrDescFromAddress(from); err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Invalid parameter from, %v", err), true)
+	}
+	s, err := w.chain.EthereumTypeSimulateTransaction(params)
+	if err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Transaction simulation failed, %v", err), true)
+	}
+	r := &TransactionSimulation{
+		Success:  s.Error == "",
+		GasUsed:  s.GasUsed,
+		GasPrice: (*Amount)(s.GasPrice),
+		Fee:      (*Amount)(getSimulationFee(s)),
+		Error:    s.Error,
+	}
+	if !r.Success {
+		if len(s.Output) > 2 {
+			r.RevertReason = w.decodeRevertReason(s.To, s.Output)
+		}
+		r.BalanceChanges = getSimulationBalanceChanges(s, nil)
+		glog.Info("SimulateTransaction ", from, ", ", time.Since(start))
+		return r, nil
+	}
+	addresses := w.newAddressesMapForAliases()
+	aggregateAddress(addresses, s.From)
+	aggregateAddress(addresses, s.To)
+	if s.Type == bchain.CREATE {
+		r.CreatedContract = s.To
+	}
+	if len(s.Transfers) > 0 {
+		r.InternalTransfers = make([]EthereumInternalTransfer, len(s.Transfers))
+		for i := range s.Transfers {
+			f := &s.Transfers[i]
+			r.InternalTransfers[i] = EthereumInternalTransfer{
+				Type:  f.Type,
+				From:  f.From,
+				To:    f.To,
+				Value: (*Amount)(&f.Value),
+			}
+			aggregateAddress(addresses, f.From)
+			aggregateAddress(addresses, f.To)
+		}
+	}
+	if len(s.TokenTransfers) > 0 {
+		r.TokenTransfers = w.getEthereumTokensTransfers(s.TokenTransfers, addresses)
+	}
+	r.BalanceChanges = getSimulationBalanceChanges(s, r.TokenTransfers)
+	r.AddressAliases = w.getAddressAliases(addresses)
+	glog.Info("SimulateTransaction ", from, ", ", time.Since(start))
+	return r, nil
+}
@@ -187,6 +187,7 @@ type Token struct {
 	BalanceSat       *Amount                  `json:"balance,omitempty" ts_doc:"Current token balance (in minimal base units)."`
 	BaseValue        float64                  `json:"baseValue,omitempty" ts_doc:"Value in the base currency (e.g. ETH for ERC20 tokens)."`
 	SecondaryValue   float64                  `json:"secondaryValue,omitempty" ts_doc:"Value in a secondary currency (e.g. fiat), if available."`
+	NoRate           bool                     `json:"noRate,omitempty" ts_doc:"True if the token has a balance but there is no fiat rate for it, its value is not included in the totals."`
 	Ids              []Amount                 `json:"ids,omitempty" ts_doc:"List of token IDs (for ERC721, each ID is a unique collectible)."`
 	MultiTokenValues []MultiTokenValue        `json:"multiTokenValues,omitempty" ts_doc:"Multiple ERC1155 token balances (id + value)."`
 	TotalReceivedSat *Amount                  `json:"totalReceived,omitempty" ts_doc:"Total amount of tokens received."`
@@ -244,12 +245,27 @@ type EthereumInternalTransfer struct {
 	Value *Amount                                `json:"value" ts_doc:"Value transferred internally (in Wei or base units)."`
 }
 
+// EthereumUserOperation represents an ERC-4337 UserOperation executed in the transaction by the EntryPoint contract
+type EthereumUserOperation struct {
+	EntryPoint     string                          `json:"entryPoint" ts_doc:"Address of the EntryPoint contract which executed the UserOperation."`
+	UserOpHash     string                          `json:"userOpHash" ts_doc:"Hash of the UserOperation."`
+	Sender         string                          `json:"sender" ts_doc:"Address of the smart account which sent the UserOperation."`
+	Paymaster      string                          `json:"paymaster,omitempty" ts_doc:"Address of the paymaster which paid for the UserOperation, empty if paid by the sender."`
+	Nonce          string                          `json:"nonce" ts_doc:"Nonce of the UserOperation (decimal string, the upper 192 bits are the nonce key)."`
+	Success        bool                            `json:"success" ts_doc:"True if the execution of the UserOperation succeeded."`
+	ActualGasCost  *Amount                         `json:"actualGasCost" ts_doc:"Actual cost of the UserOperation paid to the bundler (in Wei)."`
+	ActualGasUsed  *big.Int                        `json:"actualGasUsed" ts_doc:"Actual gas used by the UserOperation."`
+	CallData       string                          `json:"callData,omitempty" ts_doc:"Data of the call of the smart account, if decoded from the handleOps call."`
+	ParsedCallData *bchain.EthereumParsedInputData `json:"parsedCallData,omitempty" ts_doc:"Decoded call data of the smart account (function name, params, etc.)."`
+}
+
 // EthereumSpecific contains ethereum-specific transaction data
 type EthereumSpecific struct {
 	Type                 bchain.EthereumInternalTransactionType `json:"type,omitempty" ts_doc:"High-level type of the Ethereum tx (e.g., 'call', 'create')."`
 	CreatedContract      string                                 `json:"createdContract,omitempty" ts_doc:"Address of contract created by this transaction, if any."`
 	Status               eth.TxStatus                           `json:"status" ts_doc:"Execution status of the transaction (1: success, 0: fail, -1: pending)."`
 	Error                string                                 `json:"error,omitempty" ts_doc:"Error encountered during execution, if any."`
+	RevertReason         string                                 `json:"revertReason,omitempty" ts_doc:"Decoded reason of the revert of the failed transaction without the error from the traces, returned only in the transaction detail."`
 	Nonce                uint64                                 `json:"nonce" ts_doc:"Transaction nonce (sequential number from the sender)."`
 	GasLimit             *big.Int                               `json:"gasLimit" ts_doc:"Maximum gas allowed by the sender for this transaction."`
 	GasUsed              *big.Int                               `json:"gasUsed,omitempty" ts_doc:"Actual gas consumed by the transaction execution."`
@@ -261,9 +277,15 @@ type EthereumSpecific struct {
 	L1FeeScalar          string                                 `json:"l1FeeScalar,omitempty" ts_doc:"Scaling factor for L1 fees in certain Layer 2 solutions."`
 	L1GasPrice           *Amount                                `json:"l1GasPrice,omitempty" ts_doc:"Gas price for L1 component, if applicable."`
 	L1GasUsed            *big.Int                               `json:"l1GasUsed,omitempty" ts_doc:"Amount of gas used in L1 for this tx, if applicable."`
+	MaxFeePerBlobGas     *Amount                                `json:"maxFeePerBlobGas,omitempty" ts_doc:"Maximum fee per blob gas of the EIP-4844 blob transaction."`
+	BlobGasUsed          *big.Int                               `json:"blobGasUsed,omitempty" ts_doc:"Amount of blob gas used by the EIP-4844 blob transaction."`
+	BlobGasPrice         *Amount                                `json:"blobGasPrice,omitempty" ts_doc:"Price per blob gas paid by the EIP-4844 blob transaction."`
+	BlobVersionedHashes  []string                               `json:"blobVersionedHashes,omitempty" ts_doc:"Versioned hashes of the blobs of the EIP-4844 blob transaction."`
 	Data                 string                                 `json:"data,omitempty" ts_doc:"Hex-encoded input data for the transaction."`
 	ParsedData           *bchain.EthereumParsedInputData        `json:"parsedData,omitempty" ts_doc:"Decoded transaction data (function name, params, etc.)."`
+	ParsedLogs           []bchain.EthereumParsedLog             `json:"parsedLogs,omitempty" ts_doc:"Logs of the transaction, decoded by the known event signatures, omitted if no log can be decoded."`
 	InternalTransfers    []EthereumInternalTransfer             `json:"internalTransfers,omitempty" ts_doc:"List of internal (sub-call) transfers."`
+	UserOperations       []EthereumUserOperation                `json:"userOperations,omitempty" ts_doc:"ERC-4337 UserOperations executed in the transaction."`
 }
 
 // AddressAlias holds a specialized alias for an address
@@ -381,16 +403,21 @@ type Address struct {
 	Nonce                 string               `json:"nonce,omitempty" ts_doc:"Current transaction nonce for Ethereum-like addresses."`
 	UsedTokens            int                  `json:"usedTokens,omitempty" ts_doc:"Number of tokens with any historical usage at this address."`
 	Tokens                Tokens               `json:"tokens,omitempty" ts_doc:"List of tokens associated with this address."`
+	SecondaryCurrency     string               `json:"secondaryCurrency,omitempty" ts_doc:"Secondary currency (e.g. 'usd') of the secondary values, set if the rate of the currency is available."`
 	SecondaryValue        float64              `json:"secondaryValue,omitempty" ts_doc:"Total value of the address in secondary currency (e.g. fiat)."`
 	TokensBaseValue       float64              `json:"tokensBaseValue,omitempty" ts_doc:"Sum of token values in base currency."`
 	TokensSecondaryValue  float64              `json:"tokensSecondaryValue,omitempty" ts_doc:"Sum of token values in secondary currency (fiat)."`
 	TotalBaseValue        float64              `json:"totalBaseValue,omitempty" ts_doc:"Address's entire value in base currency, including tokens."`
 	TotalSecondaryValue   float64              `json:"totalSecondaryValue,omitempty" ts_doc:"Address's entire value in secondary currency, including tokens."`
 	ContractInfo          *bchain.ContractInfo `json:"contractInfo,omitempty" ts_doc:"Extra info if the address is a contract (ABI, type)."`
+	ContractABI           *bchain.ContractABI  `json:"contractAbi,omitempty" ts_doc:"ABI and verified source metadata of the contract, if uploaded by the operator."`
 	// Deprecated: replaced by ContractInfo
 	Erc20Contract  *bchain.ContractInfo `json:"erc20Contract,omitempty" ts_doc:"@deprecated: replaced by contractInfo"`
 	AddressAliases AddressAliasesMap    `json:"addressAliases,omitempty" ts_doc:"Aliases assigned to this address."`
 	StakingPools   []StakingPool        `json:"stakingPools,omitempty" ts_doc:"List of staking pool data if address interacts with staking."`
+	AtBlockHeight  uint32               `json:"atBlockHeight,omitempty" ts_doc:"Height of the block of the point-in-time snapshot of the account, set only if requested by atBlock."`
+	AtBlockHash    string               `json:"atBlockHash,omitempty" ts_doc:"Hash of the block of the point-in-time snapshot of the account, set only if requested by atBlock."`
+	AtBlockTime    int64                `json:"atBlockTime,omitempty" ts_doc:"Time of the block of the point-in-time snapshot of the account, set only if requested by atBlock."`
 	// helpers for explorer
 	Filter        string              `json:"-" ts_doc:"Filter used internally for data retrieval."`
 	XPubAddresses map[string]struct{} `json:"-" ts_doc:"Set of derived XPUB addresses (internal usage)."`
@@ -436,6 +463,21 @@ type BalanceHistory struct {
 	SentToSelfSat *Amount            `json:"sentToSelf" ts_doc:"Amount sent to the same address (self-transfer)."`
 	FiatRates     map[string]float32 `json:"rates,omitempty" ts_doc:"Exchange rates at this point in time, if available."`
 	Txid          string             `json:"txid,omitempty" ts_doc:"Transaction ID if the time corresponds to a specific tx."`
+	RealizedGains map[string]float64 `json:"realizedGains,omitempty" ts_doc:"Realized gains of the amounts sent in this interval by currency, if gains were requested."`
+}
+
+// BalanceHistoryGains contains the cost basis and the unrealized gains of the balance at the end of the balance history range
+type BalanceHistoryGains struct {
+	Time            uint32             `json:"time" ts_doc:"Unix timestamp of the valuation, the end of the range or the current time."`
+	FiatRates       map[string]float32 `json:"rates" ts_doc:"Exchange rates used for the valuation, -1 if not available."`
+	CostBasis       map[string]float64 `json:"costBasis" ts_doc:"Cost basis of the balance by currency."`
+	UnrealizedGains map[string]float64 `json:"unrealizedGains" ts_doc:"Unrealized gains of the balance by currency."`
+}
+
+// BalanceHistoryWithGains is the balance history with the gains of the balance at the end of the range, returned if gains were requested
+type BalanceHistoryWithGains struct {
+	Histories BalanceHistories     `json:"histories" ts_doc:"Balance history aggregated by the requested interval, with the realized gains."`
+	Gains     *BalanceHistoryGains `json:"gains" ts_doc:"Cost basis and unrealized gains of the balance at the end of the range."`
 }
 
 // BalanceHistories is array of BalanceHistory
@@ -494,6 +536,201 @@ func (a BalanceHistories) SortAndAggregate(groupByTime uint32) BalanceHistories
 	return bhs
 }
 
+// ChainStatsOutputs contains the number and the value of outputs of one script type
+type ChainStatsOutputs struct {
+	Count    uint64  `json:"count" ts_doc:"Number of outputs."`
+	ValueSat *Amount `json:"value" ts_doc:"Total value of the outputs (in satoshi or base units)."`
+}
+
+// ChainStatsCounters contains the counters of the chain statistics
+type ChainStatsCounters struct {
+	Txs         uint64                       `json:"txs" ts_doc:"Number of transactions."`
+	Outputs     map[string]ChainStatsOutputs `json:"outputs" ts_doc:"Created outputs by script type (p2pk, p2pkh, p2sh, p2wpkh, p2wsh, p2tr, opreturn, asset, nonstandard)."`
+	DustOutputs uint64                       `json:"dustOutputs" ts_doc:"Number of created outputs with value lower than the dust limit."`
+	Utxos       uint64                       `json:"utxos" ts_doc:"Size of the UTXO set at the end of the period."`
+	UtxosSat    *Amount                      `json:"utxosValue" ts_doc:"Total value of the UTXO set at the end of the period (in satoshi or base units)."`
+	DustUtxos   uint64                       `json:"dustUtxos" ts_doc:"Number of unspent outputs with value lower than the dust limit at the end of the period."`
+}
+
+// ChainStatsInterval contains the chain statistics of one interval of time
+type ChainStatsInterval struct {
+	Time       uint32 `json:"time" ts_doc:"Unix timestamp of the start of the interval."`
+	FromHeight uint32 `json:"fromHeight" ts_doc:"Height of the first block in the interval."`
+	ToHeight   uint32 `json:"toHeight" ts_doc:"Height of the last block in the interval."`
+	ChainStatsCounters
+}
+
+// ChainStats contains the cumulative chain statistics at the best block and optionally their history
+type ChainStats struct {
+	StartHeight uint32  `json:"startHeight" ts_doc:"Height from which the statistics are accumulated, 0 means from the genesis block."`
+	Height      uint32  `json:"height" ts_doc:"Height of the block of the statistics."`
+	Time        int64   `json:"time" ts_doc:"Unix timestamp of the block of the statistics."`
+	DustLimit   *Amount `json:"dustLimit" ts_doc:"Value under which an output is counted as dust (in satoshi or base units)."`
+	ChainStatsCounters
+	History []ChainStatsInterval `json:"history,omitempty" ts_doc:"Statistics aggregated to intervals, present only if the time range is requested."`
+}
+
+// RichListAddress contains one address of the rich list
+type RichListAddress struct {
+	Rank        int     `json:"rank" ts_doc:"Position of the address in the rich list, starting from 1."`
+	Address     string  `json:"address" ts_doc:"The address."`
+	BalanceSat  *Amount `json:"balance" ts_doc:"Balance of the address, for a contract the balance of the token (in satoshi or base units)."`
+	Txs         int     `json:"txs" ts_doc:"Number of transactions of the address, for a contract the number of token transfers."`
+	FirstHeight uint32  `json:"firstSeenHeight" ts_doc:"Height of the first block in which the address appears."`
+	LastHeight  uint32  `json:"lastSeenHeight" ts_doc:"Height of the last block in which the address appears."`
+}
+
+// RichList contains a page of the addresses with the highest balance
+type RichList struct {
+	Paging
+	ContractInfo   *bchain.ContractInfo `json:"contractInfo,omitempty" ts_doc:"Contract of the token holders, present only for Ethereum type coins."`
+	Addresses      []RichListAddress    `json:"addresses" ts_doc:"Addresses ordered from the highest balance."`
+	AddressAliases AddressAliasesMap    `json:"addressAliases,omitempty" ts_doc:"Aliases of the listed addresses."`
+}
+
+// TokenApproval is the current approval given by the address to a spender of a token contract
+type TokenApproval struct {
+	Contract    string                   `json:"contract" ts_doc:"Address of the token contract."`
+	Name        string                   `json:"name,omitempty" ts_doc:"Name of the token."`
+	Symbol      string                   `json:"symbol,omitempty" ts_doc:"Symbol of the token."`
+	Decimals    int                      `json:"decimals,omitempty" ts_doc:"Number of decimals of the token."`
+	Standard    bchain.TokenStandardName `json:"standard,omitempty" ts_type:"'' | 'ERC20' | 'ERC721' | 'ERC1155' | 'BEP20' | 'BEP721' | 'BEP1155'" ts_doc:"Standard of the token, if known."`
+	Spender     string                   `json:"spender" ts_doc:"Address allowed to spend the tokens."`
+	ForAll      bool                     `json:"forAll,omitempty" ts_doc:"True for the ERC721/ERC1155 approval of all tokens (ApprovalForAll), false for the ERC20 allowance."`
+	Allowance   *Amount                  `json:"allowance" ts_doc:"Allowed amount in the base units of the token, 1 for the ApprovalForAll."`
+	BlockHeight uint32                   `json:"blockHeight" ts_doc:"Height of the block with the last approval event."`
+	RevokeData  string                   `json:"revokeData" ts_doc:"Unsigned data of the transaction to the token contract revoking the approval."`
+	Refreshed   bool                     `json:"refreshed,omitempty" ts_doc:"True if the allowance was read from the token contract, set only with the refresh parameter."`
+}
+
+// TokenApprovals contains the current token approvals given by an address
+type TokenApprovals struct {
+	Address        string            `json:"address" ts_doc:"The owner of the tokens."`
+	Approvals      []TokenApproval   `json:"approvals" ts_doc:"Approvals with a non-zero allowance."`
+	AddressAliases AddressAliasesMap `json:"addressAliases,omitempty" ts_doc:"Aliases of the spenders."`
+}
+
+// NftCollectionItem is a token of a NFT collection with its owner
+type NftCollectionItem struct {
+	Id      *Amount `json:"id" ts_doc:"Token ID."`
+	Owner   string  `json:"owner" ts_doc:"Current owner of the token."`
+	Balance *Amount `json:"balance" ts_doc:"Number of the tokens held by the owner, always 1 for ERC721."`
+}
+
+// NftCollection contains a page of the tokens of a NFT collection
+type NftCollection struct {
+	Paging
+	ContractInfo   *bchain.ContractInfo `json:"contractInfo" ts_doc:"Contract of the collection."`
+	Tokens         []NftCollectionItem  `json:"tokens" ts_doc:"Tokens ordered by the token ID, an ERC1155 token is listed once for each owner."`
+	AddressAliases AddressAliasesMap    `json:"addressAliases,omitempty" ts_doc:"Aliases of the owners."`
+}
+
+// NftOwner is an owner of a NFT token
+type NftOwner struct {
+	Address string  `json:"address" ts_doc:"Address of the owner."`
+	Balance *Amount `json:"balance" ts_doc:"Number of the tokens held by the owner, always 1 for ERC721."`
+}
+
+// NftTransfer is a transfer of a NFT token
+type NftTransfer struct {
+	Txid        string  `json:"txid" ts_doc:"Transaction ID of the transfer."`
+	BlockHeight uint32  `json:"blockHeight" ts_doc:"Height of the block containing the transfer."`
+	From        string  `json:"from" ts_doc:"Previous owner, zero address for a mint."`
+	To          string  `json:"to" ts_doc:"New owner, zero address for a burn."`
+	Value       *Amount `json:"value" ts_doc:"Number of the transferred tokens, always 1 for ERC721."`
+}
+
+// NftToken contains the detail of a NFT token
+type NftToken struct {
+	ContractInfo   *bchain.ContractInfo `json:"contractInfo" ts_doc:"Contract of the collection."`
+	Id             *Amount              `json:"id" ts_doc:"Token ID."`
+	Owners         []NftOwner           `json:"owners" ts_doc:"Current owners of the token."`
+	URI            string               `json:"uri,omitempty" ts_doc:"Token URI returned by the contract."`
+	ImageURI       string               `json:"imageUri,omitempty" ts_doc:"URI of the image of the token taken from the metadata."`
+	Metadata       json.RawMessage      `json:"metadata,omitempty" ts_type:"any" ts_doc:"Metadata JSON downloaded from the token URI."`
+	MetadataTime   int64                `json:"metadataTime,omitempty" ts_doc:"Unix timestamp when the metadata were downloaded."`
+	Transfers      []NftTransfer        `json:"transfers" ts_doc:"Transfers of the token ordered from the newest (owner history)."`
+	AddressAliases AddressAliasesMap    `json:"addressAliases,omitempty" ts_doc:"Aliases of the owners."`
+}
+
+// InternalTransfersFilter specifies which internal transfers of an address are returned
+type InternalTransfersFilter struct {
+	// Contract is the counterparty of the transfers (the other side of the transfer than the address)
+	Contract string
+	// MinValue is the minimal value of the returned transfers, nil returns all transfers
+	MinValue *big.Int
+	// FromHeight is the lowest height of the returned transfers
+	FromHeight uint32
+	// ToHeight is the highest height of the returned transfers, the cursor of the next page
+	ToHeight uint32
+}
+
+// InternalTransfer is an internal value transfer or contract creation of an address
+type InternalTransfer struct {
+	Txid        string                                 `json:"txid" ts_doc:"Transaction ID containing the internal transfer."`
+	BlockHeight uint32                                 `json:"blockHeight" ts_doc:"Height of the block with the transaction."`
+	Type        bchain.EthereumInternalTransactionType `json:"type" ts_doc:"Type of internal transfer (CALL, CREATE, SELFDESTRUCT)."`
+	From        string                                 `json:"from" ts_doc:"Address from which the transfer originated, the creator for CREATE."`
+	To          string                                 `json:"to" ts_doc:"Address to which the transfer was sent, the created contract for CREATE."`
+	Value       *Amount                                `json:"value" ts_doc:"Value transferred internally (in Wei or base units)."`
+}
+
+// InternalTransfers contains a page of the internal transfers of an address
+type InternalTransfers struct {
+	Address         string             `json:"address" ts_doc:"The address of the internal transfers."`
+	Transfers       []InternalTransfer `json:"transfers" ts_doc:"Internal transfers ordered from the newest block."`
+	NextBlockHeight uint32             `json:"nextBlockHeight,omitempty" ts_doc:"Height of the block from which the next page starts, passed as the to parameter of the next request; not set on the last page."`
+	AddressAliases  AddressAliasesMap  `json:"addressAliases,omitempty" ts_doc:"Aliases of the counterparties."`
+}
+
+// SimulationTokenChange is a predicted change of the token balance of an address
+type SimulationTokenChange struct {
+	Standard bchain.TokenStandardName `json:"standard" ts_type:"'' | 'XPUBAddress' | 'ERC20' | 'ERC721' | 'ERC1155' | 'BEP20' | 'BEP721' | 'BEP1155'"`
+	Contract string                   `json:"contract" ts_doc:"Contract address of the token."`
+	Name     string                   `json:"name,omitempty" ts_doc:"Token name."`
+	Symbol   string                   `json:"symbol,omitempty" ts_doc:"Token symbol."`
+	Decimals int                      `json:"decimals,omitempty" ts_doc:"Number of decimals for this token (if applicable)."`
+	Id       *Amount                  `json:"id,omitempty" ts_doc:"Token ID of ERC721 and ERC1155 tokens."`
+	Delta    *Amount                  `json:"delta" ts_doc:"Predicted change of the balance (in base units), negative for a decrease."`
+}
+
+// SimulationBalanceChange contains the predicted changes of the balances of an address
+type SimulationBalanceChange struct {
+	Address string                  `json:"address" ts_doc:"Affected address."`
+	Delta   *Amount                 `json:"delta" ts_doc:"Predicted change of the native balance (in Wei) without the transaction fee, negative for a decrease."`
+	Tokens  []SimulationTokenChange `json:"tokens,omitempty" ts_doc:"Predicted changes of the token balances."`
+}
+
+// TransactionSimulation contains the predicted effects of a simulated transaction
+type TransactionSimulation struct {
+	Success           bool                       `json:"success" ts_doc:"True if the simulated transaction succeeds."`
+	GasUsed           uint64                     `json:"gasUsed" ts_doc:"Gas used by the simulated transaction."`
+	GasPrice          *Amount                    `json:"gasPrice,omitempty" ts_doc:"Effective gas price the transaction would pay if sent now, the gasPrice, min(maxFeePerGas, baseFee + maxPriorityFeePerGas) or the price suggested by the backend."`
+	Fee               *Amount                    `json:"fee,omitempty" ts_doc:"Predicted fee (gasUsed times gasPrice), included in the balance change of the sender."`
+	Error             string                     `json:"error,omitempty" ts_doc:"Error of the failed transaction."`
+	RevertReason      string                     `json:"revertReason,omitempty" ts_doc:"Decoded reason of the revert of the failed transaction, if known."`
+	CreatedContract   string                     `json:"createdContract,omitempty" ts_doc:"Address of the contract created by the transaction."`
+	BalanceChanges    []SimulationBalanceChange  `json:"balanceChanges" ts_doc:"Predicted changes of the balances per address including the fee paid by the sender, only the fee if the transaction fails."`
+	InternalTransfers []EthereumInternalTransfer `json:"internalTransfers,omitempty" ts_doc:"Predicted internal transfers."`
+	TokenTransfers    []TokenTransfer            `json:"tokenTransfers,omitempty" ts_doc:"Predicted token transfers."`
+	AddressAliases    AddressAliasesMap          `json:"addressAliases,omitempty" ts_doc:"Aliases of the affected addresses."`
+}
+
+// OpReturnOutput is a transaction output carrying OP_RETURN data
+type OpReturnOutput struct {
+	Txid   string `json:"txid" ts_doc:"Transaction ID containing the output."`
+	Vout   uint32 `json:"vout" ts_doc:"Index of the output in the transaction."`
+	Height uint32 `json:"height,omitempty" ts_doc:"Height of the block with the transaction, missing for mempool transactions."`
+	Data   string `json:"data" ts_doc:"Hex-encoded data pushed by the OP_RETURN script."`
+}
+
+// OpReturnOutputs contains a page of outputs with OP_RETURN data matching a prefix
+type OpReturnOutputs struct {
+	Paging
+	Prefix  string           `json:"prefix" ts_doc:"Hex-encoded prefix of the data."`
+	Outputs []OpReturnOutput `json:"outputs" ts_doc:"Matching outputs ordered from the newest block."`
+}
+
 // Blocks is list of blocks with paging information
 type Blocks struct {
 	Paging
@@ -591,6 +828,25 @@ type FiatTickers struct {
 	Tickers []FiatTicker `json:"tickers" ts_doc:"List of fiat tickers with timestamps and rates."`
 }
 
+// FiatCandle contains open, high, low and close fiat rates of one interval
+type FiatCandle struct {
+	Timestamp int64   `json:"ts" ts_doc:"Unix timestamp of the start of the interval."`
+	Open      float32 `json:"open" ts_doc:"Rate at the start of the interval (close rate of the previous sample)."`
+	High      float32 `json:"high" ts_doc:"Highest rate in the interval."`
+	Low       float32 `json:"low" ts_doc:"Lowest rate in the interval."`
+	Close     float32 `json:"close" ts_doc:"Last rate in the interval."`
+}
+
+// FiatCandles contains the list of candles of a currency or token
+type FiatCandles struct {
+	Currency string       `json:"currency" ts_doc:"Currency code of the rates (e.g. 'usd')."`
+	Token    string       `json:"token,omitempty" ts_doc:"Token contract address if the rates are of a token."`
+	Interval string       `json:"interval" ts_doc:"Interval of the candles: '5m', '1h' or '1d'."`
+	From     int64        `json:"from" ts_doc:"Unix timestamp of the start of the range, aligned to the interval."`
+	To       int64        `json:"to" ts_doc:"Unix timestamp of the end of the range (exclusive), aligned to the interval."`
+	Candles  []FiatCandle `json:"candles" ts_doc:"List of candles, intervals without rates are omitted."`
+}
+
 // AvailableVsCurrencies contains formatted data about available versus currencies for exchange rates
 type AvailableVsCurrencies struct {
 	Timestamp int64    `json:"ts,omitempty" ts_doc:"Timestamp for the available currency list."`
@@ -609,6 +865,7 @@ type Eip1559Fee struct {
 // Eip1559Fees
 type Eip1559Fees struct {
 	BaseFeePerGas              *Amount     `json:"baseFeePerGas,omitempty"`
+	BlobBaseFeePerGas          *Amount     `json:"blobBaseFeePerGas,omitempty" ts_doc:"Estimated base fee per blob gas of EIP-4844 blob transactions."`
 	Low                        *Eip1559Fee `json:"low,omitempty"`
 	Medium                     *Eip1559Fee `json:"medium,omitempty"`
 	High                       *Eip1559Fee `json:"high,omitempty"`
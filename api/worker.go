@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -13,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/golang/glog"
 	"github.com/juju/errors"
 	"github.com/trezor/blockbook/bchain"
@@ -203,10 +205,111 @@ func (w *Worker) GetTransaction(txid string, spendingTxs bool, specificJSON bool
 	if err != nil {
 		return nil, err
 	}
+	// the error from the traces of the internal transactions already contains the reason decoded during the sync,
+	// the transaction is replayed only if the error is not known
+	if tx.EthereumSpecific != nil && tx.EthereumSpecific.Status == eth.TxStatusFailure && tx.Blockheight > 0 && tx.EthereumSpecific.Error == "" {
+		tx.EthereumSpecific.RevertReason = w.getEthereumRevertReason(txid, uint32(tx.Blockheight))
+	}
 	tx.AddressAliases = w.getAddressAliases(addresses)
 	return tx, nil
 }
 
+const (
+	// maxCachedRevertReasons is the maximum number of the cached revert reasons, an arbitrary entry is evicted when the limit is reached
+	maxCachedRevertReasons = 10000
+	// revertReasonFailureTTL is the time for which the failed replay is not repeated,
+	// the replay of an older block fails on a node without the archive state
+	revertReasonFailureTTL = 5 * time.Minute
+)
+
+type cachedRevertReason struct {
+	height uint32
+	reason string
+	// expires is set for the failed replays
+	expires time.Time
+}
+
+var cachedRevertReasons map[string]cachedRevertReason
+var cachedRevertReasonsMux sync.Mutex
+
+func getCachedRevertReason(txid string, height uint32) (string, bool) {
+	cachedRevertReasonsMux.Lock()
+	defer cachedRevertReasonsMux.Unlock()
+	c, found := cachedRevertReasons[txid]
+	// the transaction can be moved to another block by a reorg
+	if !found || c.height != height {
+		return "", false
+	}
+	if !c.expires.IsZero() && time.Now().After(c.expires) {
+		delete(cachedRevertReasons, txid)
+		return "", false
+	}
+	return c.reason, true
+}
+
+// setCachedRevertReason caches the decoded reason, the failed replay is cached as the empty reason for revertReasonFailureTTL
+func setCachedRevertReason(txid string, height uint32, reason string, failed bool) {
+	cachedRevertReasonsMux.Lock()
+	defer cachedRevertReasonsMux.Unlock()
+	if cachedRevertReasons == nil {
+		cachedRevertReasons = make(map[string]cachedRevertReason)
+	}
+	if len(cachedRevertReasons) >= maxCachedRevertReasons {
+		for k := range cachedRevertReasons {
+			delete(cachedRevertReasons, k)
+			break
+		}
+	}
+	c := cachedRevertReason{height: height, reason: reason}
+	if failed {
+		c.expires = time.Now().Add(revertReasonFailureTTL)
+	}
+	cachedRevertReasons[txid] = c
+}
+
+// getEthereumRevertReason replays the failed transaction in the state of the previous block and decodes the reason of the revert,
+// the replay does not include the effects of the preceding transactions in the same block, therefore the reason is not guaranteed
+// the decoded reason (also the empty one) is cached, the failed call for a short time so that the requests do not wait for the backend repeatedly
+func (w *Worker) getEthereumRevertReason(txid string, height uint32) string {
+	if reason, found := getCachedRevertReason(txid, height); found {
+		return reason
+	}
+	bchainTx, _, err := w.txCache.GetTransaction(txid)
+	if err != nil {
+		return ""
+	}
+	data, err := w.chain.EthereumTypeGetRevertData(bchainTx, new(big.Int).SetUint64(uint64(height-1)))
+	if err != nil {
+		glog.V(1).Infof("EthereumTypeGetRevertData %v: %v", txid, err)
+		setCachedRevertReason(txid, height, "", true)
+		return ""
+	}
+	var reason string
+	// the replayed call did not revert or reverted without data
+	if len(data) > 2 {
+		var to string
+		if len(bchainTx.Vout) > 0 && len(bchainTx.Vout[0].ScriptPubKey.Addresses) > 0 {
+			to = bchainTx.Vout[0].ScriptPubKey.Addresses[0]
+		}
+		reason = w.decodeRevertReason(to, data)
+	}
+	setCachedRevertReason(txid, height, reason, false)
+	return reason
+}
+
+// decodeRevertReason decodes the revert data of a call of the contract using its ABI and the known four byte signatures
+func (w *Worker) decodeRevertReason(contract string, data string) string {
+	var signatures *[]bchain.FourByteSignature
+	if fourBytes := eth.GetSignatureFromData(data); fourBytes != 0 {
+		var err error
+		signatures, err = w.db.GetFourByteSignatures(fourBytes)
+		if err != nil {
+			glog.Errorf("GetFourByteSignatures(%v) error %v", fourBytes, err)
+		}
+	}
+	return eth.DecodeRevertReason(w.getContractABI(contract), signatures, data)
+}
+
 // GetRawTransaction gets raw transaction data in hex format from txid
 func (w *Worker) GetRawTransaction(txid string) (string, error) {
 	return w.chain.EthereumTypeGetRawTransaction(txid)
@@ -224,7 +327,28 @@ func (w *Worker) getTransaction(txid string, spendingTxs bool, specificJSON bool
 	return w.getTransactionFromBchainTx(bchainTx, height, spendingTxs, specificJSON, addresses)
 }
 
-func (w *Worker) getParsedEthereumInputData(data string) *bchain.EthereumParsedInputData {
+// getContractABI returns the parsed ABI of the contract uploaded by the operator, nil if there is none
+func (w *Worker) getContractABI(contract string) *abi.ABI {
+	if contract == "" {
+		return nil
+	}
+	contractABI, err := w.db.GetContractABIForAddress(contract)
+	if err != nil {
+		glog.Errorf("GetContractABIForAddress(%v) error %v", contract, err)
+		return nil
+	}
+	if contractABI == nil {
+		return nil
+	}
+	return contractABI.ParsedABI
+}
+
+// getParsedEthereumInputData parses the input data of the call of the contract to,
+// using with priority the ABI of the contract and then the known four byte signatures
+func (w *Worker) getParsedEthereumInputData(data string, to string) *bchain.EthereumParsedInputData {
+	if parsed := eth.ParseInputDataWithABI(w.getContractABI(to), data); parsed != nil {
+		return parsed
+	}
 	var err error
 	var signatures *[]bchain.FourByteSignature
 	fourBytes := eth.GetSignatureFromData(data)
@@ -241,6 +365,46 @@ func (w *Worker) getParsedEthereumInputData(data string) *bchain.EthereumParsedI
 	return eth.ParseInputData(signatures, data)
 }
 
+func (w *Worker) getParsedEthereumLogs(tx *bchain.Tx) []bchain.EthereumParsedLog {
+	return eth.ParseLogs(eth.GetEthereumTxLogs(tx), w.getContractABI, func(topic []byte) *[]bchain.EventSignature {
+		signatures, err := w.db.GetEventSignatures(topic)
+		if err != nil {
+			glog.Errorf("GetEventSignatures(%x) error %v", topic, err)
+			return nil
+		}
+		return signatures
+	})
+}
+
+// getEthereumUserOperations returns the ERC-4337 UserOperations executed in the transaction
+func (w *Worker) getEthereumUserOperations(tx *bchain.Tx, addresses map[string]struct{}) []EthereumUserOperation {
+	ops := eth.GetUserOperationsFromTx(tx)
+	if len(ops) == 0 {
+		return nil
+	}
+	r := make([]EthereumUserOperation, len(ops))
+	for i := range ops {
+		op := &ops[i]
+		r[i] = EthereumUserOperation{
+			EntryPoint:    op.EntryPoint,
+			UserOpHash:    op.UserOpHash,
+			Sender:        op.Sender,
+			Paymaster:     op.Paymaster,
+			Nonce:         op.Nonce.String(),
+			Success:       op.Success,
+			ActualGasCost: (*Amount)(&op.ActualGasCost),
+			ActualGasUsed: &op.ActualGasUsed,
+			CallData:      op.CallData,
+		}
+		if op.CallData != "" {
+			r[i].ParsedCallData = w.getParsedEthereumInputData(op.CallData, op.Sender)
+		}
+		aggregateAddress(addresses, op.Sender)
+		aggregateAddress(addresses, op.Paymaster)
+	}
+	return r
+}
+
 // getConfirmationETA returns confirmation ETA in seconds and blocks
 func (w *Worker) getConfirmationETA(tx *Tx) (int64, uint32) {
 	var etaBlocks uint32
@@ -438,7 +602,11 @@ func (w *Worker) getTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			}
 		}
 
-		parsedInputData := w.getParsedEthereumInputData(ethTxData.Data)
+		var to string
+		if len(bchainTx.Vout) > 0 && len(bchainTx.Vout[0].ScriptPubKey.Addresses) > 0 {
+			to = bchainTx.Vout[0].ScriptPubKey.Addresses[0]
+		}
+		parsedInputData := w.getParsedEthereumInputData(ethTxData.Data, to)
 
 		// mempool txs do not have fees yet
 		if ethTxData.GasUsed != nil {
@@ -446,6 +614,10 @@ func (w *Worker) getTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			if ethTxData.L1Fee != nil {
 				feesSat.Add(&feesSat, ethTxData.L1Fee)
 			}
+			if ethTxData.BlobGasUsed != nil && ethTxData.BlobGasPrice != nil {
+				var blobFee big.Int
+				feesSat.Add(&feesSat, blobFee.Mul(ethTxData.BlobGasUsed, ethTxData.BlobGasPrice))
+			}
 		}
 		if len(bchainTx.Vout) > 0 {
 			valOutSat = bchainTx.Vout[0].ValueSat
@@ -461,10 +633,16 @@ func (w *Worker) getTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			L1FeeScalar:          ethTxData.L1FeeScalar,
 			L1GasPrice:           (*Amount)(ethTxData.L1GasPrice),
 			L1GasUsed:            ethTxData.L1GasUsed,
+			MaxFeePerBlobGas:     (*Amount)(ethTxData.MaxFeePerBlobGas),
+			BlobGasUsed:          ethTxData.BlobGasUsed,
+			BlobGasPrice:         (*Amount)(ethTxData.BlobGasPrice),
+			BlobVersionedHashes:  ethTxData.BlobVersionedHashes,
 			Nonce:                ethTxData.Nonce,
 			Status:               ethTxData.Status,
 			Data:                 ethTxData.Data,
 			ParsedData:           parsedInputData,
+			ParsedLogs:           w.getParsedEthereumLogs(bchainTx),
+			UserOperations:       w.getEthereumUserOperations(bchainTx, addresses),
 		}
 		if internalData != nil {
 			ethSpecific.Type = internalData.Type
@@ -600,6 +778,8 @@ func (w *Worker) GetTransactionFromMempoolTx(mempoolTx *bchain.MempoolTx) (*Tx,
 			MaxPriorityFeePerGas: (*Amount)(ethTxData.MaxPriorityFeePerGas),
 			MaxFeePerGas:         (*Amount)(ethTxData.MaxFeePerGas),
 			BaseFeePerGas:        (*Amount)(ethTxData.BaseFeePerGas),
+			MaxFeePerBlobGas:     (*Amount)(ethTxData.MaxFeePerBlobGas),
+			BlobVersionedHashes:  ethTxData.BlobVersionedHashes,
 			GasUsed:              ethTxData.GasUsed,
 			Nonce:                ethTxData.Nonce,
 			Status:               ethTxData.Status,
@@ -965,7 +1145,7 @@ func computePaging(count, page, itemsOnPage int) (Paging, int, int, int) {
 	}, from, to, page
 }
 
-func (w *Worker) getEthereumContractBalance(addrDesc bchain.AddressDescriptor, index int, c *db.AddrContract, details AccountDetails, ticker *common.CurrencyRatesTicker, secondaryCoin string, erc20Balance *big.Int) (*Token, error) {
+func (w *Worker) getEthereumContractBalance(addrDesc bchain.AddressDescriptor, index int, c *db.AddrContract, details AccountDetails, erc20Balance *big.Int) (*Token, error) {
 	standard := bchain.EthereumTokenStandardMap[c.Standard]
 	ci, validContract, err := w.getContractDescriptorInfo(c.Contract, standard)
 	if err != nil {
@@ -996,21 +1176,6 @@ func (w *Worker) getEthereumContractBalance(addrDesc bchain.AddressDescriptor, i
 			}
 			if b != nil {
 				t.BalanceSat = (*Amount)(b)
-				if secondaryCoin != "" {
-					baseRate, found := w.GetContractBaseRate(ticker, t.Contract, 0)
-					if found {
-						value, err := strconv.ParseFloat(t.BalanceSat.DecimalString(t.Decimals), 64)
-						if err == nil {
-							t.BaseValue = value * baseRate
-							if ticker != nil {
-								secondaryRate, found := ticker.Rates[secondaryCoin]
-								if found {
-									t.SecondaryValue = t.BaseValue * float64(secondaryRate)
-								}
-							}
-						}
-					}
-				}
 			}
 		} else {
 			if len(c.Ids) > 0 {
@@ -1090,9 +1255,82 @@ func (w *Worker) GetContractBaseRate(ticker *common.CurrencyRatesTicker, token s
 	return float64(rate), found
 }
 
+// GetContractBaseRates returns the rates of the contracts in base coin, it is a batched version of GetContractBaseRate
+// the rates not found in the ticker are looked up in one ticker at the timestamp (zero timestamp means the current ticker),
+// the ticker is resolved once for all contracts; the contracts without a rate are not in the returned map
+func (w *Worker) GetContractBaseRates(ticker *common.CurrencyRatesTicker, tokens []string, timestamp int64) map[string]float64 {
+	rates := make(map[string]float64, len(tokens))
+	if ticker == nil {
+		return rates
+	}
+	var missing []string
+	for _, token := range tokens {
+		if _, done := rates[token]; done {
+			continue
+		}
+		if rate, found := ticker.GetTokenRate(token); found {
+			rates[token] = float64(rate)
+		} else {
+			missing = append(missing, token)
+		}
+	}
+	if len(missing) == 0 {
+		return rates
+	}
+	var t *common.CurrencyRatesTicker
+	if timestamp == 0 {
+		t = w.fiatRates.GetCurrentTicker("", "")
+	} else if w.db != nil {
+		var err error
+		date := time.Unix(timestamp, 0)
+		if t, err = w.db.FiatRatesFindTicker(&date, "", ""); err != nil {
+			glog.Error("GetContractBaseRates: FiatRatesFindTicker error ", err)
+			return rates
+		}
+	}
+	if t == nil || t == ticker {
+		return rates
+	}
+	for _, token := range missing {
+		if rate, found := t.GetTokenRate(token); found {
+			rates[token] = float64(rate)
+		}
+	}
+	return rates
+}
+
+// setTokensValues sets the values of the fungible tokens with balance in base coin and in the secondary coin
+// the rates of all tokens are resolved in one batch at the timestamp (zero means now), the tokens without a rate are flagged by NoRate
+func (w *Worker) setTokensValues(tokens []*Token, ticker *common.CurrencyRatesTicker, secondaryCoin string, timestamp int64) {
+	if ticker == nil || len(tokens) == 0 {
+		return
+	}
+	contracts := make([]string, len(tokens))
+	for i, t := range tokens {
+		contracts[i] = t.Contract
+	}
+	rates := w.GetContractBaseRates(ticker, contracts, timestamp)
+	secondaryRate, secondaryFound := ticker.Rates[secondaryCoin]
+	for _, t := range tokens {
+		baseRate, found := rates[t.Contract]
+		if !found {
+			t.NoRate = (*big.Int)(t.BalanceSat).Sign() != 0
+			continue
+		}
+		value, err := strconv.ParseFloat(t.BalanceSat.DecimalString(t.Decimals), 64)
+		if err == nil {
+			t.BaseValue = value * baseRate
+			if secondaryFound {
+				t.SecondaryValue = t.BaseValue * float64(secondaryRate)
+			}
+		}
+	}
+}
+
 type ethereumTypeAddressData struct {
 	tokens               Tokens
 	contractInfo         *bchain.ContractInfo
+	contractABI          *bchain.ContractABI
 	nonce                string
 	nonContractTxs       int
 	internalTxs          int
@@ -1185,16 +1423,28 @@ func (w *Worker) getEthereumTypeAddressBalances(addrDesc bchain.AddressDescripto
 				if erc20Balances != nil {
 					erc20Balance = erc20Balances[string(c.Contract)]
 				}
-				t, err := w.getEthereumContractBalance(addrDesc, i+db.ContractIndexOffset, c, details, ticker, secondaryCoin, erc20Balance)
+				t, err := w.getEthereumContractBalance(addrDesc, i+db.ContractIndexOffset, c, details, erc20Balance)
 				if err != nil {
 					return nil, nil, err
 				}
 				d.tokens[j] = *t
-				d.tokensBaseValue += t.BaseValue
-				d.tokensSecondaryValue += t.SecondaryValue
 				j++
 			}
 			d.tokens = d.tokens[:j]
+			if secondaryCoin != "" {
+				// only the fungible tokens have balance
+				valued := make([]*Token, 0, len(d.tokens))
+				for k := range d.tokens {
+					if t := &d.tokens[k]; t.BalanceSat != nil {
+						valued = append(valued, t)
+					}
+				}
+				w.setTokensValues(valued, ticker, secondaryCoin, 0)
+			}
+			for k := range d.tokens {
+				d.tokensBaseValue += d.tokens[k].BaseValue
+				d.tokensSecondaryValue += d.tokens[k].SecondaryValue
+			}
 			sort.Sort(d.tokens)
 		}
 		d.contractInfo, err = w.db.GetContractInfo(addrDesc, bchain.UnknownTokenStandard)
@@ -1207,6 +1457,10 @@ func (w *Worker) getEthereumTypeAddressBalances(addrDesc bchain.AddressDescripto
 				return nil, nil, err
 			}
 		}
+		d.contractABI, err = w.db.GetContractABI(addrDesc)
+		if err != nil {
+			return nil, nil, err
+		}
 		if filter.FromHeight == 0 && filter.ToHeight == 0 {
 			// compute total results for paging
 			if filter.Vout == AddressFilterVoutOff {
@@ -1489,6 +1743,7 @@ func (w *Worker) GetAddress(address string, page int, txsOnPage int, option Acco
 		totalSent = &ba.SentSat
 	}
 	var secondaryRate, totalSecondaryValue, totalBaseValue, secondaryValue float64
+	var secondaryCurrency string
 	if secondaryCoin != "" {
 		ticker := w.fiatRates.GetCurrentTicker("", "")
 		balance, err := strconv.ParseFloat((*Amount)(&ba.BalanceSat).DecimalString(w.chainParser.AmountDecimals()), 64)
@@ -1496,6 +1751,7 @@ func (w *Worker) GetAddress(address string, page int, txsOnPage int, option Acco
 			r, found := ticker.Rates[secondaryCoin]
 			if found {
 				secondaryRate = float64(r)
+				secondaryCurrency = secondaryCoin
 			}
 		}
 		secondaryValue = secondaryRate * balance
@@ -1521,12 +1777,14 @@ func (w *Worker) GetAddress(address string, page int, txsOnPage int, option Acco
 		Transactions:          txs,
 		Txids:                 txids,
 		Tokens:                ed.tokens,
+		SecondaryCurrency:     secondaryCurrency,
 		SecondaryValue:        secondaryValue,
 		TokensBaseValue:       ed.tokensBaseValue,
 		TokensSecondaryValue:  ed.tokensSecondaryValue,
 		TotalBaseValue:        totalBaseValue,
 		TotalSecondaryValue:   totalSecondaryValue,
 		ContractInfo:          ed.contractInfo,
+		ContractABI:           ed.contractABI,
 		Nonce:                 ed.nonce,
 		AddressAliases:        w.getAddressAliases(addresses),
 		StakingPools:          ed.stakingPools,
@@ -1539,6 +1797,368 @@ func (w *Worker) GetAddress(address string, page int, txsOnPage int, option Acco
 	return r, nil
 }
 
+// GetAddressAtBlock returns the point-in-time snapshot of an Ethereum type account at the end of the block given by height or hash:
+// the native balance, nonce and the balances of the fungible tokens the address held at that block
+// the candidate token contracts are taken from the addressContracts index, the balances are read by batched eth_calls at the block
+func (w *Worker) GetAddressAtBlock(address string, bid string, secondaryCoin string) (*Address, error) {
+	start := time.Now()
+	if w.chainType != bchain.ChainEthereumType {
+		return nil, NewAPIError("Parameter atBlock is supported only for Ethereum type coins", true)
+	}
+	addrDesc, address, err := w.getAddrDescAndNormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	hash := w.getBlockHashBlockID(bid)
+	if hash == "" {
+		return nil, NewAPIError("Block not found", true)
+	}
+	bh, err := w.chain.GetBlockHeader(hash)
+	if err != nil {
+		if err == bchain.ErrBlockNotFound {
+			return nil, NewAPIError("Block not found", true)
+		}
+		return nil, NewAPIError(fmt.Sprintf("Block not found, %v", err), true)
+	}
+	blockNumber := new(big.Int).SetUint64(uint64(bh.Height))
+	balance, err := w.chain.EthereumTypeGetBalanceAtBlock(addrDesc, blockNumber)
+	if err != nil {
+		return nil, errors.Annotatef(err, "EthereumTypeGetBalanceAtBlock %v %v", addrDesc, bh.Height)
+	}
+	nonce, err := w.chain.EthereumTypeGetNonceAtBlock(addrDesc, blockNumber)
+	if err != nil {
+		return nil, errors.Annotatef(err, "EthereumTypeGetNonceAtBlock %v %v", addrDesc, bh.Height)
+	}
+	ca, err := w.db.GetAddrDescContracts(addrDesc)
+	if err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Address not found, %v", err), true)
+	}
+	var tokens Tokens
+	if ca != nil {
+		contracts := make([]bchain.AddressDescriptor, 0, len(ca.Contracts))
+		for i := range ca.Contracts {
+			if ca.Contracts[i].Standard == bchain.FungibleToken {
+				contracts = append(contracts, ca.Contracts[i].Contract)
+			}
+		}
+		balances, err := w.chain.EthereumTypeGetErc20ContractBalancesAtBlock(addrDesc, contracts, blockNumber)
+		if err != nil {
+			glog.Warningf("EthereumTypeGetErc20ContractBalancesAtBlock addr %v: %v", addrDesc, err)
+			balances = nil
+		}
+		standard := bchain.EthereumTokenStandardMap[bchain.FungibleToken]
+		for i, contract := range contracts {
+			var b *big.Int
+			if len(balances) == len(contracts) {
+				b = balances[i]
+			}
+			if b == nil {
+				// the batch is not supported or failed for this contract, use a single call
+				b, err = w.chain.EthereumTypeGetErc20ContractBalanceAtBlock(addrDesc, contract, blockNumber)
+				if err != nil {
+					glog.Warningf("EthereumTypeGetErc20ContractBalanceAtBlock addr %v, contract %v, %v", addrDesc, contract, err)
+					continue
+				}
+			}
+			// the contracts used only after the block have zero balance, return only the tokens held at the block
+			if b == nil || b.Sign() == 0 {
+				continue
+			}
+			ci, _, err := w.getContractDescriptorInfo(contract, standard)
+			if err != nil {
+				return nil, errors.Annotatef(err, "getContractDescriptorInfo %v", contract)
+			}
+			tokens = append(tokens, Token{
+				Contract:   ci.Contract,
+				Name:       ci.Name,
+				Symbol:     ci.Symbol,
+				Type:       standard,
+				Standard:   standard,
+				Decimals:   ci.Decimals,
+				BalanceSat: (*Amount)(b),
+			})
+		}
+	}
+	var secondaryValue, tokensBaseValue, tokensSecondaryValue, totalBaseValue, totalSecondaryValue float64
+	var secondaryCurrency string
+	if secondaryCoin != "" {
+		// the values are computed using the rates valid at the time of the block
+		tickers, err := w.fiatRates.GetTickersForTimestamps([]int64{bh.Time}, "", "")
+		if err != nil {
+			glog.Errorf("Error finding ticker by date %v. Error: %v", bh.Time, err)
+		} else if tickers != nil && len(*tickers) > 0 && (*tickers)[0] != nil {
+			ticker := (*tickers)[0]
+			valued := make([]*Token, len(tokens))
+			for i := range tokens {
+				valued[i] = &tokens[i]
+			}
+			w.setTokensValues(valued, ticker, secondaryCoin, bh.Time)
+			for i := range tokens {
+				tokensBaseValue += tokens[i].BaseValue
+				tokensSecondaryValue += tokens[i].SecondaryValue
+			}
+			if r, found := ticker.Rates[secondaryCoin]; found {
+				secondaryCurrency = secondaryCoin
+				if value, err := strconv.ParseFloat((*Amount)(balance).DecimalString(w.chainParser.AmountDecimals()), 64); err == nil {
+					secondaryValue = value * float64(r)
+					totalBaseValue = value + tokensBaseValue
+					totalSecondaryValue = totalBaseValue * float64(r)
+				}
+			}
+		}
+	}
+	sort.Sort(tokens)
+	glog.Info("GetAddressAtBlock ", address, " at ", bh.Height, ", ", time.Since(start))
+	return &Address{
+		AddrStr:               address,
+		BalanceSat:            (*Amount)(balance),
+		UnconfirmedBalanceSat: &Amount{},
+		Nonce:                 strconv.FormatUint(nonce, 10),
+		Tokens:                tokens,
+		SecondaryCurrency:     secondaryCurrency,
+		SecondaryValue:        secondaryValue,
+		TokensBaseValue:       tokensBaseValue,
+		TokensSecondaryValue:  tokensSecondaryValue,
+		TotalBaseValue:        totalBaseValue,
+		TotalSecondaryValue:   totalSecondaryValue,
+		AtBlockHeight:         bh.Height,
+		AtBlockHash:           bh.Hash,
+		AtBlockTime:           bh.Time,
+	}, nil
+}
+
+// maxTokenApprovalRefreshCalls is the maximum number of the approvals of an address refreshed by calls of the token contracts
+const maxTokenApprovalRefreshCalls = 200
+
+// tokenApprovalRefreshWorkers is the number of the concurrent calls of the token contracts refreshing the approvals
+const tokenApprovalRefreshWorkers = 8
+
+// refreshTokenApprovals reads the current allowances of the approvals from the token contracts,
+// the most recent approvals are refreshed first, at most maxTokenApprovalRefreshCalls of them
+func (w *Worker) refreshTokenApprovals(addrDesc bchain.AddressDescriptor, items []db.AddrTokenApproval) []*big.Int {
+	values := make([]*big.Int, len(items))
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return items[order[i]].Height > items[order[j]].Height })
+	if len(order) > maxTokenApprovalRefreshCalls {
+		glog.Warning("GetTokenApprovals: ", len(order), " approvals, refreshing only ", maxTokenApprovalRefreshCalls, " most recent")
+		order = order[:maxTokenApprovalRefreshCalls]
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, tokenApprovalRefreshWorkers)
+	for _, i := range order {
+		item := &items[i]
+		contract, _, err := w.chainParser.GetAddressesFromAddrDesc(item.Contract)
+		if err != nil || len(contract) == 0 {
+			glog.Warning("GetTokenApprovals: unparsable contract descriptor ", item.Contract, ", error ", err)
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, contract string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			data, err := w.chain.EthereumTypeRpcCall(eth.TokenApprovalCallData(addrDesc, item.Spender, item.Kind == db.ApprovalForAll), contract, "")
+			if err != nil {
+				glog.Warningf("GetTokenApprovals: allowance call of contract %v failed: %v", contract, err)
+			} else {
+				values[i] = eth.ParseTokenApprovalCallResult(data)
+			}
+		}(i, contract[0])
+	}
+	wg.Wait()
+	return values
+}
+
+// GetTokenApprovals returns the current token approvals given by the address, optionally refreshed by calls of the token contracts
+func (w *Worker) GetTokenApprovals(address string, refresh bool) (*TokenApprovals, error) {
+	start := time.Now()
+	if w.chainType != bchain.ChainEthereumType {
+		return nil, NewAPIError("Token approvals are supported only for Ethereum type coins", true)
+	}
+	addrDesc, address, err := w.getAddrDescAndNormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	items, err := w.db.GetTokenApprovals(addrDesc)
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetTokenApprovals %v", addrDesc)
+	}
+	r := &TokenApprovals{
+		Address:   address,
+		Approvals: make([]TokenApproval, 0, len(items)),
+	}
+	var refreshed []*big.Int
+	if refresh {
+		refreshed = w.refreshTokenApprovals(addrDesc, items)
+	}
+	addresses := w.newAddressesMapForAliases()
+	for i := range items {
+		item := &items[i]
+		forAll := item.Kind == db.ApprovalForAll
+		value := &item.Value
+		if refreshed != nil && refreshed[i] != nil {
+			value = refreshed[i]
+			// the allowance was spent or revoked without an Approval event
+			if value.Sign() == 0 {
+				continue
+			}
+		}
+		standard := bchain.EthereumTokenStandardMap[bchain.FungibleToken]
+		if forAll {
+			standard = bchain.UnknownTokenStandard
+		}
+		ci, _, err := w.getContractDescriptorInfo(item.Contract, standard)
+		if err != nil {
+			return nil, errors.Annotatef(err, "getContractDescriptorInfo %v", item.Contract)
+		}
+		a := TokenApproval{
+			Contract:    ci.Contract,
+			Name:        ci.Name,
+			Symbol:      ci.Symbol,
+			Decimals:    ci.Decimals,
+			Standard:    ci.Standard,
+			ForAll:      forAll,
+			Allowance:   (*Amount)(value),
+			BlockHeight: item.Height,
+			RevokeData:  eth.TokenApprovalRevokeData(item.Spender, forAll),
+			Refreshed:   refreshed != nil && refreshed[i] != nil,
+		}
+		spender, _, err := w.chainParser.GetAddressesFromAddrDesc(item.Spender)
+		if err == nil && len(spender) > 0 {
+			a.Spender = spender[0]
+			if addresses != nil {
+				addresses[a.Spender] = struct{}{}
+			}
+		}
+		r.Approvals = append(r.Approvals, a)
+	}
+	r.AddressAliases = w.getAddressAliases(addresses)
+	glog.Info("GetTokenApprovals ", address, ", ", len(r.Approvals), " approvals, refresh ", refresh, ", ", time.Since(start))
+	return r, nil
+}
+
+// maxInternalTransfersScannedTxs is the maximum number of the transactions of an address scanned by a request for the internal transfers,
+// if reached, the page is returned incomplete with the cursor to continue the scan
+var maxInternalTransfersScannedTxs = 100000
+
+// GetInternalTransfers returns a page of the internal value transfers and contract creations of the address matching the filter,
+// the transactions of the address are scanned from the filter.ToHeight down and the scan stops when the page is full,
+// the transfers of a block are never split between pages and the height of the block with the next page is returned as the cursor
+func (w *Worker) GetInternalTransfers(address string, filter *InternalTransfersFilter, pageSize int) (*InternalTransfers, error) {
+	start := time.Now()
+	if w.chainType != bchain.ChainEthereumType {
+		return nil, NewAPIError("Internal transactions are supported only for Ethereum type coins", true)
+	}
+	if !eth.ProcessInternalTransactions {
+		return nil, NewAPIError("Internal transactions are not processed", true)
+	}
+	addrDesc, address, err := w.getAddrDescAndNormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	var contractDesc bchain.AddressDescriptor
+	if filter.Contract != "" {
+		if contractDesc, _, err = w.getAddrDescAndNormalizeAddress(filter.Contract); err != nil {
+			return nil, err
+		}
+	}
+	to := filter.ToHeight
+	if to == 0 {
+		to = maxUint32
+	}
+	if to < filter.FromHeight {
+		return nil, NewAPIError("Invalid block range", true)
+	}
+	transfers := make([]InternalTransfer, 0)
+	var scanned int
+	var lastHeight, nextHeight uint32
+	err = w.db.GetAddrDescTransactions(addrDesc, filter.FromHeight, to, func(txid string, height uint32, indexes []int32) error {
+		if height != lastHeight && (len(transfers) >= pageSize || scanned >= maxInternalTransfersScannedTxs) {
+			nextHeight = height
+			return &db.StopIteration{}
+		}
+		lastHeight = height
+		scanned++
+		internal := false
+		for _, index := range indexes {
+			if index < 0 {
+				index = ^index
+			}
+			if index == db.InternalTxIndexOffset {
+				internal = true
+				break
+			}
+		}
+		if !internal {
+			return nil
+		}
+		internalData, err := w.db.GetEthereumInternalData(txid)
+		if err != nil {
+			return err
+		}
+		if internalData == nil {
+			return nil
+		}
+		for i := range internalData.Transfers {
+			f := &internalData.Transfers[i]
+			fromDesc, err := w.chainParser.GetAddrDescFromAddress(f.From)
+			if err != nil {
+				return err
+			}
+			toDesc, err := w.chainParser.GetAddrDescFromAddress(f.To)
+			if err != nil {
+				return err
+			}
+			var counterparty bchain.AddressDescriptor
+			if bytes.Equal(addrDesc, fromDesc) {
+				counterparty = toDesc
+			} else if bytes.Equal(addrDesc, toDesc) {
+				counterparty = fromDesc
+			} else {
+				continue
+			}
+			if contractDesc != nil && !bytes.Equal(contractDesc, counterparty) {
+				continue
+			}
+			if filter.MinValue != nil && f.Value.Cmp(filter.MinValue) < 0 {
+				continue
+			}
+			transfers = append(transfers, InternalTransfer{
+				Txid:        txid,
+				BlockHeight: height,
+				Type:        f.Type,
+				From:        f.From,
+				To:          f.To,
+				Value:       (*Amount)(&f.Value),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetAddrDescTransactions %v", addrDesc)
+	}
+	r := &InternalTransfers{
+		Address:         address,
+		Transfers:       transfers,
+		NextBlockHeight: nextHeight,
+	}
+	addresses := w.newAddressesMapForAliases()
+	if addresses != nil {
+		for i := range r.Transfers {
+			addresses[r.Transfers[i].From] = struct{}{}
+			addresses[r.Transfers[i].To] = struct{}{}
+		}
+	}
+	r.AddressAliases = w.getAddressAliases(addresses)
+	glog.Info("GetInternalTransfers ", address, ", to ", filter.ToHeight, ", count ", len(r.Transfers), ", scanned ", scanned, ", next ", nextHeight, ", ", time.Since(start))
+	return r, nil
+}
+
 // Returns either the Amount or nil if the number is zero
 func amountOrNil(num *big.Int) *Amount {
 	if num.Cmp(big.NewInt(0)) == 0 {
@@ -1705,6 +2325,10 @@ func (w *Worker) balanceHistoryForTxid(addrDesc bchain.AddressDescriptor, txid s
 					// mempool txs do not have fees yet
 					if ethTxData.GasUsed != nil {
 						feesSat.Mul(ethTxData.GasPrice, ethTxData.GasUsed)
+						if ethTxData.BlobGasUsed != nil && ethTxData.BlobGasPrice != nil {
+							var blobFee big.Int
+							feesSat.Add(&feesSat, blobFee.Mul(ethTxData.BlobGasUsed, ethTxData.BlobGasPrice))
+						}
 					}
 					(*big.Int)(bh.SentSat).Add((*big.Int)(bh.SentSat), &feesSat)
 				}
@@ -1744,51 +2368,358 @@ func (w *Worker) setFiatRateToBalanceHistories(histories BalanceHistories, curre
 	return nil
 }
 
+// aggregateBalanceHistories aggregates the histories of the transactions in the range by groupBy and sets the fiat rates
+// and the gains, if requested (in that case bhs contain also the histories of the transactions before the range)
+func (w *Worker) aggregateBalanceHistories(bhs BalanceHistories, currencies []string, groupBy uint32, gains string, fromUnix, toUnix uint32) (BalanceHistories, *BalanceHistoryGains, error) {
+	inRange := bhs
+	if gains != "" {
+		inRange = make(BalanceHistories, 0, len(bhs))
+		for i := range bhs {
+			if bhs[i].Time >= fromUnix {
+				inRange = append(inRange, bhs[i])
+			}
+		}
+	}
+	bha := inRange.SortAndAggregate(groupBy)
+	if err := w.setFiatRateToBalanceHistories(bha, currencies); err != nil {
+		return nil, nil, err
+	}
+	if gains != "" {
+		g, err := w.setGainsToBalanceHistories(bha, bhs, currencies, gains, groupBy, fromUnix, toUnix)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bha, g, nil
+	}
+	return bha, nil, nil
+}
+
 // GetBalanceHistory returns history of balance for given address
-func (w *Worker) GetBalanceHistory(address string, fromTimestamp, toTimestamp int64, currencies []string, groupBy uint32) (BalanceHistories, error) {
+// if gains is fifo, lifo or avg, the realized gains in the currencies are computed using the given cost basis method
+// and the cost basis and unrealized gains of the balance at the end of the range are returned, otherwise the returned gains are nil
+func (w *Worker) GetBalanceHistory(address string, fromTimestamp, toTimestamp int64, currencies []string, groupBy uint32, gains string) (BalanceHistories, *BalanceHistoryGains, error) {
 	currencies = removeEmpty(currencies)
 	bhs := make(BalanceHistories, 0)
 	start := time.Now()
+	if gains != "" {
+		if err := validateGains(gains, currencies); err != nil {
+			return nil, nil, err
+		}
+	}
 	addrDesc, _, err := w.getAddrDescAndNormalizeAddress(address)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// do not get balance history for contracts
 	if w.chainType == bchain.ChainEthereumType {
 		ci, err := w.db.GetContractInfo(addrDesc, bchain.UnknownTokenStandard)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if ci != nil {
 			glog.Info("GetBalanceHistory ", address, " is a contract, skipping")
-			return nil, NewAPIError("GetBalanceHistory for a contract not allowed", true)
+			return nil, nil, NewAPIError("GetBalanceHistory for a contract not allowed", true)
 		}
 	}
 	fromUnix, fromHeight, toUnix, toHeight := w.balanceHistoryHeightsFromTo(fromTimestamp, toTimestamp)
 	if fromHeight >= toHeight {
-		return bhs, nil
+		return bhs, nil, nil
+	}
+	// the cost basis of the gains requires the whole history of the address
+	txsFromUnix, txsFromHeight := fromUnix, fromHeight
+	if gains != "" {
+		txsFromUnix, txsFromHeight = 0, 0
 	}
-	txs, err := w.getAddressTxids(addrDesc, false, &AddressFilter{Vout: AddressFilterVoutOff, FromHeight: fromHeight, ToHeight: toHeight}, maxInt)
+	txs, err := w.getAddressTxids(addrDesc, false, &AddressFilter{Vout: AddressFilterVoutOff, FromHeight: txsFromHeight, ToHeight: toHeight}, maxInt)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	selfAddrDesc := map[string]struct{}{string(addrDesc): {}}
 	for txi := len(txs) - 1; txi >= 0; txi-- {
-		bh, err := w.balanceHistoryForTxid(addrDesc, txs[txi], fromUnix, toUnix, selfAddrDesc)
+		bh, err := w.balanceHistoryForTxid(addrDesc, txs[txi], txsFromUnix, toUnix, selfAddrDesc)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if bh != nil {
 			bhs = append(bhs, *bh)
 		}
 	}
-	bha := bhs.SortAndAggregate(groupBy)
-	err = w.setFiatRateToBalanceHistories(bha, currencies)
+	bha, g, err := w.aggregateBalanceHistories(bhs, currencies, groupBy, gains, fromUnix, toUnix)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	glog.Info("GetBalanceHistory ", address, ", blocks ", fromHeight, "-", toHeight, ", count ", len(bha), ", ", time.Since(start))
-	return bha, nil
+	return bha, g, nil
+}
+
+func chainStatsCounters(cs, prev *db.ChainStats) ChainStatsCounters {
+	c := ChainStatsCounters{
+		Txs:         cs.Txs,
+		Outputs:     make(map[string]ChainStatsOutputs),
+		DustOutputs: cs.DustOutputs,
+		Utxos:       cs.Utxos,
+		UtxosSat:    (*Amount)(new(big.Int).Set(&cs.UtxosSat)),
+		DustUtxos:   cs.DustUtxos,
+	}
+	if prev != nil {
+		c.Txs -= prev.Txs
+		c.DustOutputs -= prev.DustOutputs
+	}
+	for i := range cs.Outputs {
+		o := ChainStatsOutputs{
+			Count:    cs.Outputs[i].Count,
+			ValueSat: (*Amount)(new(big.Int).Set(&cs.Outputs[i].ValueSat)),
+		}
+		if prev != nil {
+			o.Count -= prev.Outputs[i].Count
+			(*big.Int)(o.ValueSat).Sub((*big.Int)(o.ValueSat), &prev.Outputs[i].ValueSat)
+		}
+		if o.Count > 0 {
+			c.Outputs[db.ChainStatsScriptTypeNames[i]] = o
+		}
+	}
+	return c
+}
+
+func (w *Worker) getChainStatsHistory(fromHeight, toHeight uint32, groupBy uint32) ([]ChainStatsInterval, error) {
+	history := make([]ChainStatsInterval, 0)
+	var prev, last *db.ChainStats
+	var err error
+	if fromHeight > 0 {
+		prev, err = w.db.GetChainStats(fromHeight - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var interval ChainStatsInterval
+	appendInterval := func() {
+		if last != nil {
+			interval.ToHeight = last.Height
+			interval.ChainStatsCounters = chainStatsCounters(last, prev)
+			history = append(history, interval)
+			prev = last
+		}
+	}
+	err = w.db.IterateChainStats(fromHeight, toHeight-1, func(cs *db.ChainStats) (bool, error) {
+		t := uint32(cs.Time) - uint32(cs.Time)%groupBy
+		if last == nil || t != interval.Time {
+			appendInterval()
+			interval = ChainStatsInterval{Time: t, FromHeight: cs.Height}
+		}
+		last = cs
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	appendInterval()
+	return history, nil
+}
+
+// GetChainStats returns the chain statistics at the best block
+// if the time range is specified, returns also the statistics aggregated to intervals of groupBy seconds
+func (w *Worker) GetChainStats(fromTimestamp, toTimestamp int64, groupBy uint32) (*ChainStats, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Chain statistics are not supported", true)
+	}
+	start := time.Now()
+	bestHeight, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	cs, err := w.db.GetChainStats(bestHeight)
+	if err != nil {
+		return nil, err
+	}
+	if cs == nil {
+		return nil, NewAPIError("Chain statistics are not available", true)
+	}
+	r := &ChainStats{
+		StartHeight:        cs.StartHeight,
+		Height:             cs.Height,
+		Time:               cs.Time,
+		DustLimit:          (*Amount)(big.NewInt(db.ChainStatsDustLimit)),
+		ChainStatsCounters: chainStatsCounters(cs, nil),
+	}
+	if fromTimestamp != 0 || toTimestamp != 0 {
+		_, fromHeight, _, toHeight := w.balanceHistoryHeightsFromTo(fromTimestamp, toTimestamp)
+		if toHeight > bestHeight {
+			toHeight = bestHeight + 1
+		}
+		if fromHeight < toHeight {
+			r.History, err = w.getChainStatsHistory(fromHeight, toHeight, groupBy)
+			if err != nil {
+				return nil, err
+			}
+		}
+		glog.Info("GetChainStats blocks ", fromHeight, "-", toHeight, ", count ", len(r.History), ", ", time.Since(start))
+	}
+	return r, nil
+}
+
+// GetRichList returns a page of the addresses with the highest balance
+// for Ethereum type coins the contract must be specified and the holders of the token are returned
+func (w *Worker) GetRichList(contract string, page int, pageSize int) (*RichList, error) {
+	start := time.Now()
+	page--
+	if page < 0 {
+		page = 0
+	}
+	r := &RichList{}
+	var cd bchain.AddressDescriptor
+	var err error
+	if w.chainType == bchain.ChainEthereumType {
+		if contract == "" {
+			return nil, NewAPIError("Missing contract", true)
+		}
+		cd, err = w.chainParser.GetAddrDescFromAddress(contract)
+		if err != nil {
+			return nil, NewAPIError(fmt.Sprintf("Invalid contract, %v", err), true)
+		}
+		r.ContractInfo, _, err = w.getContractDescriptorInfo(cd, bchain.UnknownTokenStandard)
+		if err != nil {
+			return nil, err
+		}
+	} else if contract != "" {
+		return nil, NewAPIError("Contract is supported only by Ethereum type coins", true)
+	}
+	items, total, err := w.db.GetRichList(cd, page*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	var from int
+	r.Paging, from, _, _ = computePaging(total, page, pageSize)
+	if from != page*pageSize {
+		// requested page is out of range, return the last page
+		if items, _, err = w.db.GetRichList(cd, from, pageSize); err != nil {
+			return nil, err
+		}
+	}
+	r.Addresses = make([]RichListAddress, len(items))
+	addresses := make(map[string]struct{})
+	for i := range items {
+		item := &items[i]
+		a := &r.Addresses[i]
+		a.Rank = from + i + 1
+		a.BalanceSat = (*Amount)(&item.BalanceSat)
+		addrs, _, err := w.chainParser.GetAddressesFromAddrDesc(item.AddrDesc)
+		if err != nil {
+			glog.Warning("GetRichList: unparsable address descriptor ", item.AddrDesc, ", error ", err)
+		}
+		if len(addrs) > 0 {
+			a.Address = addrs[0]
+			addresses[a.Address] = struct{}{}
+		}
+		if w.chainType == bchain.ChainEthereumType {
+			acs, err := w.db.GetAddrDescContracts(item.AddrDesc)
+			if err != nil {
+				return nil, err
+			}
+			if acs != nil {
+				for j := range acs.Contracts {
+					if bytes.Equal(acs.Contracts[j].Contract, cd) {
+						a.Txs = int(acs.Contracts[j].Txs)
+						break
+					}
+				}
+			}
+		} else {
+			ab, err := w.db.GetAddrDescBalance(item.AddrDesc, db.AddressBalanceDetailNoUTXO)
+			if err != nil {
+				return nil, err
+			}
+			if ab != nil {
+				a.Txs = int(ab.Txs)
+			}
+		}
+		a.FirstHeight, a.LastHeight, err = w.db.GetAddrDescHeightRange(item.AddrDesc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.AddressAliases = w.getAddressAliases(addresses)
+	glog.Info("GetRichList ", contract, ", page ", page+1, ", count ", len(r.Addresses), ", ", time.Since(start))
+	return r, nil
+}
+
+// GetOpReturnOutputs returns a page of outputs with OP_RETURN data starting with the hex prefix in blocks in range fromHeight-toHeight
+func (w *Worker) GetOpReturnOutputs(prefix string, fromHeight, toHeight int, page int, pageSize int) (*OpReturnOutputs, error) {
+	start := time.Now()
+	if !w.db.HasOpReturnIndex() {
+		return nil, NewAPIError("OP_RETURN index is not enabled", true)
+	}
+	p, err := hex.DecodeString(prefix)
+	if err != nil || len(p) == 0 {
+		return nil, NewAPIError("Invalid prefix", true)
+	}
+	page--
+	if page < 0 {
+		page = 0
+	}
+	if fromHeight < 0 {
+		fromHeight = 0
+	}
+	to := maxUint32
+	if toHeight > 0 {
+		to = uint32(toHeight)
+	}
+	outputs, err := w.db.GetOpReturnOutputs(p, uint32(fromHeight), to)
+	if err != nil {
+		if err == db.ErrTooManyOpReturnOutputs {
+			return nil, NewAPIError(err.Error(), true)
+		}
+		return nil, err
+	}
+	r := &OpReturnOutputs{Prefix: hex.EncodeToString(p)}
+	var from, last int
+	r.Paging, from, last, _ = computePaging(len(outputs), page, pageSize)
+	r.Outputs = make([]OpReturnOutput, last-from)
+	for i := from; i < last; i++ {
+		r.Outputs[i-from] = opReturnOutputFromDb(&outputs[i])
+	}
+	glog.Info("GetOpReturnOutputs ", r.Prefix, ", page ", page+1, ", count ", len(r.Outputs), ", ", time.Since(start))
+	return r, nil
+}
+
+func opReturnOutputFromDb(o *db.OpReturnOutput) OpReturnOutput {
+	return OpReturnOutput{
+		Txid:   o.Txid,
+		Vout:   o.Vout,
+		Height: o.Height,
+		Data:   hex.EncodeToString(o.Data),
+	}
+}
+
+// GetOpReturnOutputsInBlock returns the outputs with OP_RETURN data in the block at the given height
+func (w *Worker) GetOpReturnOutputsInBlock(height uint32) ([]OpReturnOutput, error) {
+	outputs, err := w.db.GetOpReturnOutputsInBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]OpReturnOutput, len(outputs))
+	for i := range outputs {
+		r[i] = opReturnOutputFromDb(&outputs[i])
+	}
+	return r, nil
+}
+
+// GetOpReturnOutputsFromMempoolTx returns the outputs with OP_RETURN data of the mempool transaction
+func (w *Worker) GetOpReturnOutputsFromMempoolTx(tx *bchain.MempoolTx) []OpReturnOutput {
+	var r []OpReturnOutput
+	for i := range tx.Vout {
+		addrDesc, err := w.chainParser.GetAddrDescFromVout(&tx.Vout[i])
+		if err != nil {
+			continue
+		}
+		if data := db.OpReturnData(addrDesc); len(data) > 0 {
+			r = append(r, OpReturnOutput{
+				Txid: tx.Txid,
+				Vout: tx.Vout[i].N,
+				Data: hex.EncodeToString(data),
+			})
+		}
+	}
+	return r
 }
 
 func (w *Worker) waitForBackendSync() {
@@ -2127,6 +3058,64 @@ func (w *Worker) GetFiatRatesForBlockID(blockID string, currencies []string, tok
 	return &tickers.Tickers[0], nil
 }
 
+// defaultCandles is the number of candles returned if the start of the range is not specified
+const defaultCandles = 100
+
+// GetFiatRatesCandles returns open, high, low and close fiat rates in the intervals of the time range [from, to)
+// if to is 0, the current time is used, if from is 0, defaultCandles candles before to are returned
+func (w *Worker) GetFiatRatesCandles(currency string, token string, interval string, from, to int64) (*FiatCandles, error) {
+	if !w.is.HasFiatRates {
+		return nil, NewAPIError("Fiat rates are not available", true)
+	}
+	if currency == "" {
+		return nil, NewAPIError("Missing currency", true)
+	}
+	seconds, err := fiat.CandleIntervalSeconds(interval)
+	if err != nil {
+		return nil, NewAPIError(err.Error(), true)
+	}
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from == 0 {
+		from = to - defaultCandles*seconds
+	}
+	if from < 0 || to <= from {
+		return nil, NewAPIError("Invalid time range", true)
+	}
+	// the range is aligned in the same way as in fiat.GetCandles
+	from -= from % seconds
+	if to%seconds != 0 {
+		to += seconds - to%seconds
+	}
+	if (to-from)/seconds > fiat.MaxCandles {
+		return nil, NewAPIError(fmt.Sprintf("Too many candles, maximum is %d", fiat.MaxCandles), true)
+	}
+	candles, err := w.fiatRates.GetCandles(currency, token, interval, from, to)
+	if err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Error getting candles: %v", err), false)
+	}
+	result := &FiatCandles{
+		Currency: strings.ToLower(currency),
+		Token:    strings.ToLower(token),
+		Interval: interval,
+		From:     from,
+		To:       to,
+		Candles:  make([]FiatCandle, len(candles)),
+	}
+	for i := range candles {
+		c := &candles[i]
+		result.Candles[i] = FiatCandle{
+			Timestamp: c.Timestamp,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+		}
+	}
+	return result, nil
+}
+
 // GetAvailableVsCurrencies returns the list of available versus currencies for exchange rates
 func (w *Worker) GetAvailableVsCurrencies(timestamp int64, token string) (*AvailableVsCurrencies, error) {
 	tickers, err := w.fiatRates.GetTickersForTimestamps([]int64{timestamp}, "", token)
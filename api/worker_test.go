@@ -0,0 +1,276 @@
+//go:build unittest
+
+package api
+
+import (
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/eth"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+	"github.com/trezor/blockbook/fiat"
+	"github.com/trezor/blockbook/tests/dbtestdata"
+)
+
+func Test_setTokensValues(t *testing.T) {
+	// the fiat rates without the current ticker, the fallback lookup of the missing rates finds nothing
+	w := &Worker{fiatRates: &fiat.FiatRates{}}
+	ticker := &common.CurrencyRatesTicker{
+		Rates: map[string]float32{"usd": 2000, "eur": 1800},
+		TokenRates: map[string]float32{
+			"0x0d0f936ee4c93e25944694d6c121de94d9760f11": 0.5,
+		},
+	}
+	newToken := func(contract string, balance string) *Token {
+		b, _ := new(big.Int).SetString(balance, 10)
+		return &Token{Contract: contract, Decimals: 18, BalanceSat: (*Amount)(b)}
+	}
+	tests := []struct {
+		name          string
+		secondaryCoin string
+		wantBase      []float64
+		wantSecondary []float64
+		wantNoRate    []bool
+	}{
+		{
+			name:          "usd",
+			secondaryCoin: "usd",
+			wantBase:      []float64{1, 0, 0},
+			wantSecondary: []float64{2000, 0, 0},
+			wantNoRate:    []bool{false, true, false},
+		},
+		{
+			name:          "eur",
+			secondaryCoin: "eur",
+			wantBase:      []float64{1, 0, 0},
+			wantSecondary: []float64{1800, 0, 0},
+			wantNoRate:    []bool{false, true, false},
+		},
+		{
+			name:          "unknown secondary currency",
+			secondaryCoin: "xyz",
+			wantBase:      []float64{1, 0, 0},
+			wantSecondary: []float64{0, 0, 0},
+			wantNoRate:    []bool{false, true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := []*Token{
+				// checksummed address, the rates are stored lowercase
+				newToken("0x0d0F936Ee4c93e25944694D6C121de94D9760F11", "2000000000000000000"),
+				// balance without rate
+				newToken("0x4af4114F73d1c1C903aC9E0361b379D1291808A2", "1000075074"),
+				// zero balance without rate is not flagged
+				newToken("0xcdA9FC258358EcaA88845f19Af595e908bb7EfE9", "0"),
+			}
+			w.setTokensValues(tokens, ticker, tt.secondaryCoin, 0)
+			for i, tok := range tokens {
+				if math.Abs(tok.BaseValue-tt.wantBase[i]) > 1e-9 {
+					t.Errorf("token %d BaseValue = %v, want %v", i, tok.BaseValue, tt.wantBase[i])
+				}
+				if math.Abs(tok.SecondaryValue-tt.wantSecondary[i]) > 1e-6 {
+					t.Errorf("token %d SecondaryValue = %v, want %v", i, tok.SecondaryValue, tt.wantSecondary[i])
+				}
+				if tok.NoRate != tt.wantNoRate[i] {
+					t.Errorf("token %d NoRate = %v, want %v", i, tok.NoRate, tt.wantNoRate[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_GetContractBaseRates(t *testing.T) {
+	w := &Worker{fiatRates: &fiat.FiatRates{}}
+	ticker := &common.CurrencyRatesTicker{
+		TokenRates: map[string]float32{
+			"0x0d0f936ee4c93e25944694d6c121de94d9760f11": 0.25,
+		},
+	}
+	contracts := []string{
+		"0x0d0F936Ee4c93e25944694D6C121de94D9760F11",
+		"0x4af4114F73d1c1C903aC9E0361b379D1291808A2",
+		"0x0d0F936Ee4c93e25944694D6C121de94D9760F11",
+	}
+	got := w.GetContractBaseRates(ticker, contracts, 0)
+	if len(got) != 1 || got[contracts[0]] != 0.25 {
+		t.Errorf("GetContractBaseRates() = %v, want map[%s:0.25]", got, contracts[0])
+	}
+	if got := w.GetContractBaseRates(nil, contracts, 0); len(got) != 0 {
+		t.Errorf("GetContractBaseRates(nil) = %v, want empty", got)
+	}
+}
+
+func Test_cachedRevertReason(t *testing.T) {
+	txid := "0xrevertcachetest"
+	if _, found := getCachedRevertReason(txid, 100); found {
+		t.Fatal("unexpected cached reason")
+	}
+	setCachedRevertReason(txid, 100, "Error: insufficient balance", false)
+	if reason, found := getCachedRevertReason(txid, 100); !found || reason != "Error: insufficient balance" {
+		t.Errorf("getCachedRevertReason() = %v, %v", reason, found)
+	}
+	// the transaction moved to another block by a reorg is replayed again
+	if _, found := getCachedRevertReason(txid, 101); found {
+		t.Error("cached reason found for another height")
+	}
+	// the call which did not revert is cached as the empty reason
+	setCachedRevertReason(txid, 101, "", false)
+	if reason, found := getCachedRevertReason(txid, 101); !found || reason != "" {
+		t.Errorf("getCachedRevertReason() = %v, %v", reason, found)
+	}
+	// the failed replay is cached until it expires
+	setCachedRevertReason(txid, 102, "", true)
+	if reason, found := getCachedRevertReason(txid, 102); !found || reason != "" {
+		t.Errorf("getCachedRevertReason() = %v, %v", reason, found)
+	}
+	cachedRevertReasonsMux.Lock()
+	c := cachedRevertReasons[txid]
+	c.expires = time.Now().Add(-time.Second)
+	cachedRevertReasons[txid] = c
+	cachedRevertReasonsMux.Unlock()
+	if _, found := getCachedRevertReason(txid, 102); found {
+		t.Error("expired failed replay found")
+	}
+}
+
+// setupEthereumTypeWorker returns the worker over a db with the two test Ethereum type blocks
+func setupEthereumTypeWorker(t *testing.T) (*Worker, func()) {
+	parser := eth.NewEthereumParser(1, false)
+	chain, err := dbtestdata.NewFakeBlockChainEthereumType(parser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp, err := os.MkdirTemp("", "testdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := db.NewRocksDB(tmp, 100000, -1, parser, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := func() {
+		d.Close()
+		os.RemoveAll(tmp)
+	}
+	is, err := d.LoadInternalState(&common.Config{CoinName: "Fakecoin"})
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	d.SetInternalState(is)
+	block1 := dbtestdata.GetTestEthereumTypeBlock1(parser)
+	for i := uint32(0); i < block1.Height; i++ {
+		is.BlockTimes = append(is.BlockTimes, 0)
+	}
+	for _, block := range []*bchain.Block{block1, dbtestdata.GetTestEthereumTypeBlock2(parser)} {
+		if err := d.ConnectBlock(block); err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+	}
+	w, err := NewWorker(d, chain, nil, nil, nil, is, nil)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return w, cleanup
+}
+
+func TestWorker_GetInternalTransfers(t *testing.T) {
+	w, cleanup := setupEthereumTypeWorker(t)
+	defer cleanup()
+	eth.ProcessInternalTransactions = true
+	defer func() { eth.ProcessInternalTransactions = false }()
+
+	// the internal transfers of the address 9f are in the tx B1T2 of the block 4321000 and in the tx B2T2 of the block 4321001,
+	// the block 4321001 contains also the tx B2T1 of the address without internal transfers
+	type transfer struct {
+		txid   string
+		height uint32
+		value  int64
+	}
+	b1t2 := "0x" + dbtestdata.EthTxidB1T2
+	b2t2 := "0x" + dbtestdata.EthTxidB2T2
+	tests := []struct {
+		name       string
+		filter     InternalTransfersFilter
+		pageSize   int
+		maxScanned int
+		want       []transfer
+		wantNext   uint32
+	}{
+		{
+			name:     "all",
+			pageSize: 10,
+			want:     []transfer{{b2t2, 4321001, 1000010}, {b2t2, 4321001, 1000011}, {b1t2, 4321000, 1000000}, {b1t2, 4321000, 1000001}},
+		},
+		{
+			name:     "block not split",
+			pageSize: 1,
+			want:     []transfer{{b2t2, 4321001, 1000010}, {b2t2, 4321001, 1000011}},
+			wantNext: 4321000,
+		},
+		{
+			name:     "next page",
+			filter:   InternalTransfersFilter{ToHeight: 4321000},
+			pageSize: 1,
+			want:     []transfer{{b1t2, 4321000, 1000000}, {b1t2, 4321000, 1000001}},
+		},
+		{
+			name:       "scan cap",
+			pageSize:   10,
+			maxScanned: 1,
+			want:       []transfer{{b2t2, 4321001, 1000010}, {b2t2, 4321001, 1000011}},
+			wantNext:   4321000,
+		},
+		{
+			name:     "contract",
+			filter:   InternalTransfersFilter{Contract: "0x" + dbtestdata.EthAddrContract4a},
+			pageSize: 10,
+			want:     []transfer{{b2t2, 4321001, 1000011}, {b1t2, 4321000, 1000000}},
+		},
+		{
+			name:     "minValue",
+			filter:   InternalTransfersFilter{MinValue: big.NewInt(1000010)},
+			pageSize: 10,
+			want:     []transfer{{b2t2, 4321001, 1000010}, {b2t2, 4321001, 1000011}},
+		},
+		{
+			name:     "from height",
+			filter:   InternalTransfersFilter{FromHeight: 4321001, Contract: "0x" + dbtestdata.EthAddr4b},
+			pageSize: 10,
+			want:     []transfer{{b2t2, 4321001, 1000010}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.maxScanned > 0 {
+				saved := maxInternalTransfersScannedTxs
+				maxInternalTransfersScannedTxs = tt.maxScanned
+				defer func() { maxInternalTransfersScannedTxs = saved }()
+			}
+			filter := tt.filter
+			r, err := w.GetInternalTransfers("0x"+dbtestdata.EthAddr9f, &filter, tt.pageSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]transfer, len(r.Transfers))
+			for i := range r.Transfers {
+				got[i] = transfer{r.Transfers[i].Txid, r.Transfers[i].BlockHeight, (*big.Int)(r.Transfers[i].Value).Int64()}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetInternalTransfers() = %v, want %v", got, tt.want)
+			}
+			if r.NextBlockHeight != tt.wantNext {
+				t.Errorf("GetInternalTransfers() NextBlockHeight = %v, want %v", r.NextBlockHeight, tt.wantNext)
+			}
+		})
+	}
+}
@@ -573,6 +573,7 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 	totalReceived.Add(&data.balanceSat, &data.sentSat)
 
 	var secondaryValue float64
+	var secondaryCurrency string
 	if secondaryCoin != "" {
 		ticker := w.fiatRates.GetCurrentTicker("", "")
 		balance, err := strconv.ParseFloat((*Amount)(&data.balanceSat).DecimalString(w.chainParser.AmountDecimals()), 64)
@@ -581,6 +582,7 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 			if found {
 				secondaryRate := float64(r)
 				secondaryValue = secondaryRate * balance
+				secondaryCurrency = secondaryCoin
 			}
 		}
 	}
@@ -599,6 +601,7 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 		Txids:                 txids,
 		UsedTokens:            usedTokens,
 		Tokens:                tokens,
+		SecondaryCurrency:     secondaryCurrency,
 		SecondaryValue:        secondaryValue,
 		XPubAddresses:         xpubAddresses,
 		AddressAliases:        w.getAddressAliases(addresses),
@@ -653,25 +656,38 @@ func (w *Worker) GetXpubUtxo(xpub string, onlyConfirmed bool, gap int) (Utxos, e
 }
 
 // GetXpubBalanceHistory returns history of balance for given xpub
-func (w *Worker) GetXpubBalanceHistory(xpub string, fromTimestamp, toTimestamp int64, currencies []string, gap int, groupBy uint32) (BalanceHistories, error) {
+// if gains is fifo, lifo or avg, the realized gains in the currencies are computed using the given cost basis method
+// and the cost basis and unrealized gains of the balance at the end of the range are returned, otherwise the returned gains are nil
+func (w *Worker) GetXpubBalanceHistory(xpub string, fromTimestamp, toTimestamp int64, currencies []string, gap int, groupBy uint32, gains string) (BalanceHistories, *BalanceHistoryGains, error) {
+	currencies = removeEmpty(currencies)
 	bhs := make(BalanceHistories, 0)
 	start := time.Now()
+	if gains != "" {
+		if err := validateGains(gains, currencies); err != nil {
+			return nil, nil, err
+		}
+	}
 	fromUnix, fromHeight, toUnix, toHeight := w.balanceHistoryHeightsFromTo(fromTimestamp, toTimestamp)
 	if fromHeight >= toHeight {
-		return bhs, nil
+		return bhs, nil, nil
 	}
 	xd, err := w.chainParser.ParseXpub(xpub)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	// the cost basis of the gains requires the whole history of the xpub
+	txsFromUnix, txsFromHeight := fromUnix, fromHeight
+	if gains != "" {
+		txsFromUnix, txsFromHeight = 0, 0
 	}
 	data, _, inCache, err := w.getXpubData(xd, 0, 1, AccountDetailsTxidHistory, &AddressFilter{
 		Vout:          AddressFilterVoutOff,
 		OnlyConfirmed: true,
-		FromHeight:    fromHeight,
+		FromHeight:    txsFromHeight,
 		ToHeight:      toHeight,
 	}, gap)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	selfAddrDesc := make(map[string]struct{})
 	for _, da := range data.addresses {
@@ -684,9 +700,9 @@ func (w *Worker) GetXpubBalanceHistory(xpub string, fromTimestamp, toTimestamp i
 			ad := &da[i]
 			txids := ad.txids
 			for txi := len(txids) - 1; txi >= 0; txi-- {
-				bh, err := w.balanceHistoryForTxid(ad.addrDesc, txids[txi].txid, fromUnix, toUnix, selfAddrDesc)
+				bh, err := w.balanceHistoryForTxid(ad.addrDesc, txids[txi].txid, txsFromUnix, toUnix, selfAddrDesc)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				if bh != nil {
 					bhs = append(bhs, *bh)
@@ -694,11 +710,10 @@ func (w *Worker) GetXpubBalanceHistory(xpub string, fromTimestamp, toTimestamp i
 			}
 		}
 	}
-	bha := bhs.SortAndAggregate(groupBy)
-	err = w.setFiatRateToBalanceHistories(bha, currencies)
+	bha, g, err := w.aggregateBalanceHistories(bhs, currencies, groupBy, gains, fromUnix, toUnix)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	glog.Info("GetUtxoBalanceHistory ", xpub[:xpubLogPrefix], ", cache ", inCache, ", blocks ", fromHeight, "-", toHeight, ", count ", len(bha), ",  ", time.Since(start))
-	return bha, nil
+	return bha, g, nil
 }
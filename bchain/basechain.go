@@ -79,6 +79,26 @@ func (b *BaseChain) EthereumTypeGetErc20ContractBalances(addrDesc AddressDescrip
 	return nil, errors.New("not supported")
 }
 
+// EthereumTypeGetBalanceAtBlock is not supported
+func (b *BaseChain) EthereumTypeGetBalanceAtBlock(addrDesc AddressDescriptor, blockNumber *big.Int) (*big.Int, error) {
+	return nil, errors.New("not supported")
+}
+
+// EthereumTypeGetNonceAtBlock is not supported
+func (b *BaseChain) EthereumTypeGetNonceAtBlock(addrDesc AddressDescriptor, blockNumber *big.Int) (uint64, error) {
+	return 0, errors.New("not supported")
+}
+
+// EthereumTypeGetErc20ContractBalanceAtBlock is not supported
+func (b *BaseChain) EthereumTypeGetErc20ContractBalanceAtBlock(addrDesc, contractDesc AddressDescriptor, blockNumber *big.Int) (*big.Int, error) {
+	return nil, errors.New("not supported")
+}
+
+// EthereumTypeGetErc20ContractBalancesAtBlock is not supported
+func (b *BaseChain) EthereumTypeGetErc20ContractBalancesAtBlock(addrDesc AddressDescriptor, contractDescs []AddressDescriptor, blockNumber *big.Int) ([]*big.Int, error) {
+	return nil, errors.New("not supported")
+}
+
 // GetTokenURI returns URI of non fungible or multi token defined by token id
 func (p *BaseChain) GetTokenURI(contractDesc AddressDescriptor, tokenID *big.Int) (string, error) {
 	return "", errors.New("not supported")
@@ -97,6 +117,16 @@ func (b *BaseChain) EthereumTypeRpcCall(data, to, from string) (string, error) {
 	return "", errors.New("not supported")
 }
 
+// EthereumTypeGetRevertData is not supported
+func (b *BaseChain) EthereumTypeGetRevertData(tx *Tx, blockNumber *big.Int) (string, error) {
+	return "", errors.New("not supported")
+}
+
+// EthereumTypeSimulateTransaction is not supported
+func (b *BaseChain) EthereumTypeSimulateTransaction(params map[string]interface{}) (*EthereumSimulation, error) {
+	return nil, errors.New("not supported")
+}
+
 func (b *BaseChain) EthereumTypeGetRawTransaction(txid string) (string, error) {
 	return "", errors.New("not supported")
 }
@@ -290,6 +290,12 @@ func (p *BaseParser) IsAddrDescIndexable(addrDesc AddressDescriptor) bool {
 	return true
 }
 
+// IsAssetTx returns true if the transaction transfers a native asset of the chain instead of the coin
+// by default there are no native assets
+func (p *BaseParser) IsAssetTx(tx *Tx) bool {
+	return false
+}
+
 // ParseXpub is unsupported
 func (p *BaseParser) ParseXpub(xpub string) (*XpubDescriptor, error) {
 	return nil, errors.New("Not supported")
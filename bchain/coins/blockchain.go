@@ -355,6 +355,26 @@ func (c *blockChainWithMetrics) EthereumTypeGetErc20ContractBalances(addrDesc bc
 	return c.b.EthereumTypeGetErc20ContractBalances(addrDesc, contractDescs)
 }
 
+func (c *blockChainWithMetrics) EthereumTypeGetBalanceAtBlock(addrDesc bchain.AddressDescriptor, blockNumber *big.Int) (v *big.Int, err error) {
+	defer func(s time.Time) { c.observeRPCLatency("EthereumTypeGetBalanceAtBlock", s, err) }(time.Now())
+	return c.b.EthereumTypeGetBalanceAtBlock(addrDesc, blockNumber)
+}
+
+func (c *blockChainWithMetrics) EthereumTypeGetNonceAtBlock(addrDesc bchain.AddressDescriptor, blockNumber *big.Int) (v uint64, err error) {
+	defer func(s time.Time) { c.observeRPCLatency("EthereumTypeGetNonceAtBlock", s, err) }(time.Now())
+	return c.b.EthereumTypeGetNonceAtBlock(addrDesc, blockNumber)
+}
+
+func (c *blockChainWithMetrics) EthereumTypeGetErc20ContractBalanceAtBlock(addrDesc, contractDesc bchain.AddressDescriptor, blockNumber *big.Int) (v *big.Int, err error) {
+	defer func(s time.Time) { c.observeRPCLatency("EthereumTypeGetErc20ContractBalanceAtBlock", s, err) }(time.Now())
+	return c.b.EthereumTypeGetErc20ContractBalanceAtBlock(addrDesc, contractDesc, blockNumber)
+}
+
+func (c *blockChainWithMetrics) EthereumTypeGetErc20ContractBalancesAtBlock(addrDesc bchain.AddressDescriptor, contractDescs []bchain.AddressDescriptor, blockNumber *big.Int) (v []*big.Int, err error) {
+	defer func(s time.Time) { c.observeRPCLatency("EthereumTypeGetErc20ContractBalancesAtBlock", s, err) }(time.Now())
+	return c.b.EthereumTypeGetErc20ContractBalancesAtBlock(addrDesc, contractDescs, blockNumber)
+}
+
 // GetTokenURI returns URI of non fungible or multi token defined by token id
 func (c *blockChainWithMetrics) GetTokenURI(contractDesc bchain.AddressDescriptor, tokenID *big.Int) (v string, err error) {
 	defer func(s time.Time) { c.observeRPCLatency("GetTokenURI", s, err) }(time.Now())
@@ -376,6 +396,16 @@ func (c *blockChainWithMetrics) EthereumTypeRpcCall(data, to, from string) (v st
 	return c.b.EthereumTypeRpcCall(data, to, from)
 }
 
+func (c *blockChainWithMetrics) EthereumTypeGetRevertData(tx *bchain.Tx, blockNumber *big.Int) (v string, err error) {
+	defer func(s time.Time) { c.observeRPCLatency("EthereumTypeGetRevertData", s, err) }(time.Now())
+	return c.b.EthereumTypeGetRevertData(tx, blockNumber)
+}
+
+func (c *blockChainWithMetrics) EthereumTypeSimulateTransaction(params map[string]interface{}) (v *bchain.EthereumSimulation, err error) {
+	defer func(s time.Time) { c.observeRPCLatency("EthereumTypeSimulateTransaction", s, err) }(time.Now())
+	return c.b.EthereumTypeSimulateTransaction(params)
+}
+
 func (c *blockChainWithMetrics) EthereumTypeGetRawTransaction(txid string) (v string, err error) {
 	defer func(s time.Time) { c.observeRPCLatency("EthereumTypeGetRawTransaction", s, err) }(time.Now())
 	return c.b.EthereumTypeGetRawTransaction(txid)
@@ -170,6 +170,11 @@ func (p *CoordinateParser) ParseTxFromJson(msg json.RawMessage) (*bchain.Tx, err
 }
 
 
+// IsAssetTx returns true for asset transactions, regular coin transactions of Coordinate have version 2
+func (p *CoordinateParser) IsAssetTx(tx *bchain.Tx) bool {
+	return tx.Version != 2
+}
+
 // GetAddrDescForUnknownInput returns nil AddressDescriptor
 func (p *CoordinateParser) GetAddrDescForUnknownInput(tx *bchain.Tx, input int) bchain.AddressDescriptor {
 	var iTxid string
@@ -0,0 +1,234 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// ContractABIGetter returns the ABI of the contract uploaded by the operator, nil if there is none
+// it is set by the application when the database is opened, the ABI is then used to decode the revert reasons during the sync
+var ContractABIGetter func(contract string) (*bchain.ContractABI, error)
+
+func getContractABI(contract string) *abi.ABI {
+	if ContractABIGetter == nil || contract == "" {
+		return nil
+	}
+	contractABI, err := ContractABIGetter(contract)
+	if err != nil {
+		glog.Errorf("GetContractABI(%v) error %v", contract, err)
+		return nil
+	}
+	if contractABI == nil {
+		return nil
+	}
+	return contractABI.ParsedABI
+}
+
+// ParseContractABI parses the JSON ABI of the contract and sets it to ParsedABI
+func ParseContractABI(contractABI *bchain.ContractABI) error {
+	if len(contractABI.ABI) == 0 {
+		return errors.Errorf("contract %v: missing ABI", contractABI.Contract)
+	}
+	parsed, err := abi.JSON(bytes.NewReader(contractABI.ABI))
+	if err != nil {
+		return errors.Annotatef(err, "contract %v ABI", contractABI.Contract)
+	}
+	contractABI.ParsedABI = &parsed
+	return nil
+}
+
+// formatABIValue converts a value unpacked by the ABI to strings in the same format as processParam,
+// arrays are flattened to multiple values, tuples are returned as a single value in parentheses
+func formatABIValue(t *abi.Type, v interface{}) []string {
+	rv := reflect.ValueOf(v)
+	switch t.T {
+	case abi.SliceTy, abi.ArrayTy:
+		var values []string
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, formatABIValue(t.Elem, rv.Index(i).Interface())...)
+		}
+		return values
+	case abi.TupleTy:
+		fields := make([]string, len(t.TupleElems))
+		for i := range t.TupleElems {
+			values := formatABIValue(t.TupleElems[i], rv.Field(i).Interface())
+			if t.TupleElems[i].T == abi.SliceTy || t.TupleElems[i].T == abi.ArrayTy {
+				fields[i] = "[" + strings.Join(values, ", ") + "]"
+			} else {
+				fields[i] = strings.Join(values, ", ")
+			}
+		}
+		return []string{"(" + strings.Join(fields, ", ") + ")"}
+	case abi.AddressTy:
+		a := v.(ethcommon.Address)
+		return []string{EIP55Address(a.Bytes())}
+	case abi.BytesTy:
+		b := v.([]byte)
+		if len(b) == 0 {
+			return []string{""}
+		}
+		return []string{"0x" + hex.EncodeToString(b)}
+	case abi.FixedBytesTy, abi.HashTy, abi.FunctionTy:
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return []string{"0x" + hex.EncodeToString(b)}
+	}
+	// integers, big integers, bools and strings
+	return []string{fmt.Sprint(v)}
+}
+
+func formatABIArguments(args abi.Arguments) string {
+	types := make([]string, len(args))
+	for i := range args {
+		types[i] = args[i].Type.String()
+	}
+	return strings.Join(types, ", ")
+}
+
+// ParseInputDataWithABI parses transaction input data using the ABI of the called contract
+// returns nil if the method is not in the ABI or the data do not match its inputs
+func ParseInputDataWithABI(contractABI *abi.ABI, data string) (parsed *bchain.EthereumParsedInputData) {
+	if contractABI == nil || len(data) < 10 {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("ParseInputDataWithABI recovered from panic: ", r, ", ", data)
+			debug.PrintStack()
+			parsed = nil
+		}
+	}()
+	b, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil {
+		return nil
+	}
+	method, err := contractABI.MethodById(b[:4])
+	if err != nil {
+		return nil
+	}
+	values, err := method.Inputs.Unpack(b[4:])
+	if err != nil || len(values) != len(method.Inputs) {
+		return nil
+	}
+	parsed = &bchain.EthereumParsedInputData{
+		MethodId: data[:10],
+		Name:     decamel(method.RawName),
+		Function: method.RawName + "(" + formatABIArguments(method.Inputs) + ")",
+		Params:   make([]bchain.EthereumParsedInputParam, len(method.Inputs)),
+	}
+	for i := range method.Inputs {
+		in := &method.Inputs[i]
+		parsed.Params[i] = bchain.EthereumParsedInputParam{
+			Name:   in.Name,
+			Type:   in.Type.String(),
+			Values: formatABIValue(&in.Type, values[i]),
+		}
+	}
+	return parsed
+}
+
+// parseLogWithABI decodes the log using the event from the ABI of the contract which emitted the log
+func parseLogWithABI(contractABI *abi.ABI, l *bchain.RpcLog, p *bchain.EthereumParsedLog) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("ParseLogs recovered from panic: ", r, ", ", l)
+			debug.PrintStack()
+			p.Name, p.Event, p.Params = "", "", nil
+		}
+	}()
+	event, err := contractABI.EventByID(ethcommon.HexToHash(l.Topics[0]))
+	if err != nil || event.Anonymous {
+		return
+	}
+	indexed := 0
+	for i := range event.Inputs {
+		if event.Inputs[i].Indexed {
+			indexed++
+		}
+	}
+	if indexed != len(l.Topics)-1 {
+		return
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(l.Data, "0x"))
+	if err != nil {
+		return
+	}
+	values, err := event.Inputs.NonIndexed().Unpack(data)
+	if err != nil {
+		return
+	}
+	params := make([]bchain.EthereumParsedLogParam, len(event.Inputs))
+	t, d := 1, 0
+	for i := range event.Inputs {
+		in := &event.Inputs[i]
+		param := &params[i]
+		param.Name = in.Name
+		param.Type = in.Type.String()
+		if in.Indexed {
+			v, ok := decodeTopic(l.Topics[t], &in.Type)
+			if !ok {
+				return
+			}
+			param.Indexed = true
+			param.Values = v
+			t++
+		} else {
+			param.Values = formatABIValue(&in.Type, values[d])
+			d++
+		}
+	}
+	p.Name = event.RawName
+	p.Event = event.RawName + "(" + formatABIArguments(event.Inputs) + ")"
+	p.Params = params
+}
+
+// ParseErrorFromOutputWithABI decodes the revert reason from the output of the call,
+// custom errors are decoded using the ABI of the called contract, otherwise it falls back to ParseErrorFromOutput
+func ParseErrorFromOutputWithABI(contractABI *abi.ABI, output string) (reason string) {
+	if contractABI != nil {
+		reason = parseCustomErrorFromOutput(contractABI, output)
+		if reason != "" {
+			return reason
+		}
+	}
+	return ParseErrorFromOutput(output)
+}
+
+func parseCustomErrorFromOutput(contractABI *abi.ABI, output string) (reason string) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("ParseErrorFromOutputWithABI recovered from panic: ", r, ", ", output)
+			debug.PrintStack()
+			reason = ""
+		}
+	}()
+	b, err := hex.DecodeString(strings.TrimPrefix(output, "0x"))
+	if err != nil || len(b) < 4 {
+		return ""
+	}
+	var id [4]byte
+	copy(id[:], b)
+	e, err := contractABI.ErrorByID(id)
+	if err != nil {
+		return ""
+	}
+	values, err := e.Inputs.Unpack(b[4:])
+	if err != nil || len(values) != len(e.Inputs) {
+		return ""
+	}
+	args := make([]string, len(e.Inputs))
+	for i := range e.Inputs {
+		args[i] = strings.Join(formatABIValue(&e.Inputs[i].Type, values[i]), ", ")
+	}
+	return e.Name + "(" + strings.Join(args, ", ") + ")"
+}
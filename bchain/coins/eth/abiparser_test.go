@@ -0,0 +1,185 @@
+//go:build unittest
+
+package eth
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/trezor/blockbook/bchain"
+)
+
+const testContractABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"submitOrder","inputs":[{"name":"order","type":"tuple","components":[{"name":"maker","type":"address"},{"name":"amounts","type":"uint256[]"}]},{"name":"memo","type":"string"}],"outputs":[]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}
+]`
+
+func parseTestContractABI(t *testing.T) *abi.ABI {
+	contractABI := bchain.ContractABI{Contract: "0x1234567890123456789012345678901234567890", ABI: []byte(testContractABI)}
+	if err := ParseContractABI(&contractABI); err != nil {
+		t.Fatal(err)
+	}
+	return contractABI.ParsedABI
+}
+
+func TestParseContractABI(t *testing.T) {
+	if err := ParseContractABI(&bchain.ContractABI{Contract: "0x1234567890123456789012345678901234567890"}); err == nil {
+		t.Error("ParseContractABI() expected error for missing ABI")
+	}
+	if err := ParseContractABI(&bchain.ContractABI{Contract: "0x1234567890123456789012345678901234567890", ABI: []byte(`{"type":"function"`)}); err == nil {
+		t.Error("ParseContractABI() expected error for invalid ABI")
+	}
+}
+
+func TestParseInputDataWithABI(t *testing.T) {
+	contractABI := parseTestContractABI(t)
+	tests := []struct {
+		name string
+		data string
+		want *bchain.EthereumParsedInputData
+	}{
+		{
+			name: "transfer",
+			data: "0xa9059cbb000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f00000000000000000000000000000000000000000000000000000000000f4240",
+			want: &bchain.EthereumParsedInputData{
+				MethodId: "0xa9059cbb",
+				Name:     "Transfer",
+				Function: "transfer(address, uint256)",
+				Params: []bchain.EthereumParsedInputParam{
+					{Name: "to", Type: "address", Values: []string{"0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f"}},
+					{Name: "amount", Type: "uint256", Values: []string{"1000000"}},
+				},
+			},
+		},
+		{
+			name: "tuple",
+			data: "0x" + hex.EncodeToString(contractABI.Methods["submitOrder"].ID) +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000100" +
+				"000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"6869000000000000000000000000000000000000000000000000000000000000",
+			want: &bchain.EthereumParsedInputData{
+				Name:     "Submit Order",
+				Function: "submitOrder((address,uint256[]), string)",
+				Params: []bchain.EthereumParsedInputParam{
+					{Name: "order", Type: "(address,uint256[])", Values: []string{"(0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f, [1, 2])"}},
+					{Name: "memo", Type: "string", Values: []string{"hi"}},
+				},
+			},
+		},
+		{
+			name: "unknown method",
+			data: "0x095ea7b3000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f00000000000000000000000000000000000000000000000000000000000f4240",
+		},
+		{
+			name: "short data",
+			data: "0xa9059cbb000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want != nil {
+				tt.want.MethodId = tt.data[:10]
+			}
+			if got := ParseInputDataWithABI(contractABI, tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseInputDataWithABI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if got := ParseInputDataWithABI(nil, tests[0].data); got != nil {
+		t.Errorf("ParseInputDataWithABI(nil) = %+v, want nil", got)
+	}
+}
+
+func TestParseLogsWithABI(t *testing.T) {
+	contractABI := parseTestContractABI(t)
+	contract := "0x1234567890123456789012345678901234567890"
+	logs := []*bchain.RpcLog{
+		{
+			Address: contract,
+			Topics: []string{
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				"0x000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+				"0x0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+			},
+			Data: "0x00000000000000000000000000000000000000000000000000000000000f4240",
+		},
+		{
+			// log of another contract is not decoded by the ABI
+			Address: "0x4bda106325c335df99eab7fe363cac8a0ba2a24d",
+			Topics: []string{
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			},
+			Data: "0x",
+		},
+	}
+	getABI := func(address string) *abi.ABI {
+		if strings.EqualFold(address, contract) {
+			return contractABI
+		}
+		return nil
+	}
+	getSignatures := func(topic []byte) *[]bchain.EventSignature { return nil }
+	want := []bchain.EthereumParsedLog{
+		{
+			Address: contract,
+			Topic:   "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			Name:    "Transfer",
+			Event:   "Transfer(address, address, uint256)",
+			Params: []bchain.EthereumParsedLogParam{
+				{Name: "from", Type: "address", Indexed: true, Values: []string{"0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f"}},
+				{Name: "to", Type: "address", Indexed: true, Values: []string{"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D"}},
+				{Name: "value", Type: "uint256", Values: []string{"1000000"}},
+			},
+		},
+		{
+			Address: "0x4bda106325c335df99eab7fe363cac8a0ba2a24d",
+			Topic:   "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			Topics:  []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+			Data:    "0x",
+		},
+	}
+	if got := ParseLogs(logs, getABI, getSignatures); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLogs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseErrorFromOutputWithABI(t *testing.T) {
+	contractABI := parseTestContractABI(t)
+	customError := "0x" + hex.EncodeToString(contractABI.Errors["InsufficientBalance"].ID.Bytes()[:4]) +
+		"0000000000000000000000000000000000000000000000000000000000000064" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+	stringError := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000b" +
+		"4e6f7420616c6c6f776564000000000000000000000000000000000000000000"
+	tests := []struct {
+		name        string
+		contractABI *abi.ABI
+		output      string
+		want        string
+	}{
+		{"custom error", contractABI, customError, "InsufficientBalance(100, 1000)"},
+		{"custom error without ABI", nil, customError, ""},
+		{"error string", contractABI, stringError, "Not allowed"},
+		{"empty output", contractABI, "0x", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseErrorFromOutputWithABI(tt.contractABI, tt.output); got != tt.want {
+				t.Errorf("ParseErrorFromOutputWithABI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/trezor/blockbook/bchain"
+)
+
+const approvalEventSignature = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"       // Approval(address,address,uint256)
+const approvalForAllEventSignature = "0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31" // ApprovalForAll(address,address,bool)
+
+const erc20AllowanceMethodSignature = "0xdd62ed3e"    // allowance(address,address)
+const erc20ApproveMethodSignature = "0x095ea7b3"      // approve(address,uint256)
+const isApprovedForAllMethodSignature = "0xe985e9c5"  // isApprovedForAll(address,address)
+const setApprovalForAllMethodSignature = "0xa22cb465" // setApprovalForAll(address,bool)
+const zeroWord = "0000000000000000000000000000000000000000000000000000000000000000"
+
+func processApprovalEvent(l *bchain.RpcLog) *bchain.TokenApproval {
+	// ERC721 Approval event has the same signature as ERC20 but the token id is indexed, it approves only a single token and is not tracked
+	if len(l.Topics) != 3 || (l.Topics[0] != approvalEventSignature && l.Topics[0] != approvalForAllEventSignature) {
+		return nil
+	}
+	data := l.Data
+	if has0xPrefix(data) {
+		data = data[2:]
+	}
+	if len(data) != 64 {
+		return nil
+	}
+	owner, err := addressFromPaddedHex(l.Topics[1])
+	if err != nil {
+		return nil
+	}
+	spender, err := addressFromPaddedHex(l.Topics[2])
+	if err != nil {
+		return nil
+	}
+	a := bchain.TokenApproval{
+		Contract: EIP55AddressFromAddress(l.Address),
+		Owner:    owner,
+		Spender:  spender,
+		ForAll:   l.Topics[0] == approvalForAllEventSignature,
+	}
+	if _, ok := a.Value.SetString(data, 16); !ok {
+		return nil
+	}
+	if a.ForAll && a.Value.Sign() != 0 {
+		a.Value.SetInt64(1)
+	}
+	return &a
+}
+
+// GetTokenApprovalsFromTx returns the ERC20 Approval and ERC721/ERC1155 ApprovalForAll events of the transaction in the order of the logs
+func GetTokenApprovalsFromTx(tx *bchain.Tx) []bchain.TokenApproval {
+	var approvals []bchain.TokenApproval
+	for _, l := range GetEthereumTxLogs(tx) {
+		if a := processApprovalEvent(l); a != nil {
+			approvals = append(approvals, *a)
+		}
+	}
+	return approvals
+}
+
+func padAddressDescriptor(addrDesc bchain.AddressDescriptor) string {
+	a := hexutil.Encode(addrDesc)[2:]
+	return zeroWord[len(a):] + a
+}
+
+// TokenApprovalCallData returns the data of the call reading the current state of the approval from the contract,
+// allowance(owner, spender) for the ERC20 allowance, isApprovedForAll(owner, operator) for the ApprovalForAll
+func TokenApprovalCallData(owner, spender bchain.AddressDescriptor, forAll bool) string {
+	signature := erc20AllowanceMethodSignature
+	if forAll {
+		signature = isApprovedForAllMethodSignature
+	}
+	return signature + padAddressDescriptor(owner) + padAddressDescriptor(spender)
+}
+
+// TokenApprovalRevokeData returns the unsigned data of the transaction revoking the approval,
+// approve(spender, 0) for the ERC20 allowance, setApprovalForAll(operator, false) for the ApprovalForAll
+func TokenApprovalRevokeData(spender bchain.AddressDescriptor, forAll bool) string {
+	signature := erc20ApproveMethodSignature
+	if forAll {
+		signature = setApprovalForAllMethodSignature
+	}
+	return signature + padAddressDescriptor(spender) + zeroWord
+}
+
+// ParseTokenApprovalCallResult parses the result of the call returned by TokenApprovalCallData
+func ParseTokenApprovalCallResult(data string) *big.Int {
+	return parseSimpleNumericProperty(data)
+}
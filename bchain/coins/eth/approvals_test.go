@@ -0,0 +1,111 @@
+//go:build unittest
+
+package eth
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/trezor/blockbook/bchain"
+)
+
+func TestGetTokenApprovalsFromTx(t *testing.T) {
+	tx := &bchain.Tx{
+		CoinSpecificData: bchain.EthereumSpecificData{
+			Receipt: &bchain.RpcReceipt{
+				Logs: []*bchain.RpcLog{
+					{
+						// ERC20 Approval
+						Address: "0xdac17f958d2ee523a2206206994597c13d831ec7",
+						Topics: []string{
+							"0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+							"0x000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+							"0x0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+						},
+						Data: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+					},
+					{
+						// ERC721 Approval of a single token is not tracked
+						Address: "0xcda9fc258358ecaa88845f19af595e908bb7efe9",
+						Topics: []string{
+							"0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+							"0x000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+							"0x0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+							"0x0000000000000000000000000000000000000000000000000000000000000001",
+						},
+						Data: "0x",
+					},
+					{
+						// ApprovalForAll
+						Address: "0xcda9fc258358ecaa88845f19af595e908bb7efe9",
+						Topics: []string{
+							"0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31",
+							"0x000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+							"0x0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+						},
+						Data: "0x0000000000000000000000000000000000000000000000000000000000000001",
+					},
+				},
+			},
+		},
+	}
+	var maxUint256 big.Int
+	maxUint256.SetString("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 16)
+	want := []bchain.TokenApproval{
+		{
+			Contract: "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			Owner:    "0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f",
+			Spender:  "0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D",
+			Value:    maxUint256,
+		},
+		{
+			Contract: "0xcdA9FC258358EcaA88845f19Af595e908bb7EfE9",
+			Owner:    "0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f",
+			Spender:  "0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D",
+			ForAll:   true,
+			Value:    *big.NewInt(1),
+		},
+	}
+	if got := GetTokenApprovalsFromTx(tx); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTokenApprovalsFromTx() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTokenApprovalCallData(t *testing.T) {
+	owner := bchain.AddressDescriptor{0x55, 0x5e, 0xe1, 0x1f, 0xbd, 0xdc, 0x0e, 0x49, 0xa9, 0xba, 0xb3, 0x58, 0xa8, 0x94, 0x1a, 0xd9, 0x5f, 0xfd, 0xb4, 0x8f}
+	spender := bchain.AddressDescriptor{0x4b, 0xda, 0x10, 0x63, 0x25, 0xc3, 0x35, 0xdf, 0x99, 0xea, 0xb7, 0xfe, 0x36, 0x3c, 0xac, 0x8a, 0x0b, 0xa2, 0xa2, 0x4d}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "allowance",
+			got:  TokenApprovalCallData(owner, spender, false),
+			want: "0xdd62ed3e000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+		},
+		{
+			name: "isApprovedForAll",
+			got:  TokenApprovalCallData(owner, spender, true),
+			want: "0xe985e9c5000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+		},
+		{
+			name: "approve revoke",
+			got:  TokenApprovalRevokeData(spender, false),
+			want: "0x095ea7b30000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			name: "setApprovalForAll revoke",
+			got:  TokenApprovalRevokeData(spender, true),
+			want: "0xa22cb4650000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -236,6 +236,18 @@ func tryParseParams(data string, params []string, parsedParams []abi.Type) []bch
 	return parsed
 }
 
+func parseParameterType(p string) abi.Type {
+	if len(p) > 0 && p[0] == '(' {
+		// Tuple type is not supported for now
+		return abi.Type{T: abi.TupleTy}
+	}
+	t, err := abi.NewType(p, "", nil)
+	if err != nil {
+		return abi.Type{T: ErrorTy}
+	}
+	return t
+}
+
 // ParseInputData tries to parse transaction input data from known FourByteSignatures
 // as there may be multiple signatures for the same four bytes, it tries to match the input to the known parameters
 // it does not parse tuples for now
@@ -266,18 +278,7 @@ func ParseInputData(signatures *[]bchain.FourByteSignature, data string) *bchain
 				s.Function = s.Name + "(" + strings.Join(s.Parameters, ", ") + ")"
 				s.ParsedParameters = make([]abi.Type, len(s.Parameters))
 				for j := range s.Parameters {
-					var t abi.Type
-					if len(s.Parameters[j]) > 0 && s.Parameters[j][0] == '(' {
-						// Tuple type is not supported for now
-						t = abi.Type{T: abi.TupleTy}
-					} else {
-						var err error
-						t, err = abi.NewType(s.Parameters[j], "", nil)
-						if err != nil {
-							t = abi.Type{T: ErrorTy}
-						}
-					}
-					s.ParsedParameters[j] = t
+					s.ParsedParameters[j] = parseParameterType(s.Parameters[j])
 				}
 			}
 			parsedParams := tryParseParams(data, s.Parameters, s.ParsedParameters)
@@ -292,6 +293,176 @@ func ParseInputData(signatures *[]bchain.FourByteSignature, data string) *bchain
 	return &parsed
 }
 
+// decodeTopic decodes the value of an indexed parameter from the topic,
+// the indexed dynamic types (string, bytes, arrays and tuples) are stored as the keccak256 hash of their value
+func decodeTopic(topic string, t *abi.Type) ([]string, bool) {
+	if has0xPrefix(topic) {
+		topic = topic[2:]
+	}
+	if len(topic) != 64 {
+		return nil, false
+	}
+	switch t.T {
+	case abi.IntTy, abi.UintTy, abi.BoolTy, abi.AddressTy, abi.FixedBytesTy:
+		values, _, ok := processParam(topic, 0, 0, t, make([]bool, 1))
+		return values, ok
+	case ErrorTy:
+		return nil, false
+	}
+	return []string{"0x" + topic}, true
+}
+
+// decodeLogParams decodes the parameters of the event, the indexed parameters from the topics and the others from the data
+func decodeLogParams(s *bchain.EventSignature, indexed []bool, topics []string, data string) []bchain.EthereumParsedLogParam {
+	if has0xPrefix(data) {
+		data = data[2:]
+	}
+	var dataTypes []string
+	var dataParsedTypes []abi.Type
+	for j := range s.Inputs {
+		if !indexed[j] {
+			dataTypes = append(dataTypes, s.Inputs[j].Type)
+			dataParsedTypes = append(dataParsedTypes, s.ParsedInputs[j])
+		}
+	}
+	var dataValues []bchain.EthereumParsedInputParam
+	if len(dataTypes) > 0 {
+		if dataValues = tryParseParams(data, dataTypes, dataParsedTypes); dataValues == nil {
+			return nil
+		}
+	} else if len(data) > 0 {
+		return nil
+	}
+	params := make([]bchain.EthereumParsedLogParam, len(s.Inputs))
+	t, d := 1, 0
+	for j := range s.Inputs {
+		p := &params[j]
+		p.Name = s.Inputs[j].Name
+		p.Type = s.Inputs[j].Type
+		if indexed[j] {
+			values, ok := decodeTopic(topics[t], &s.ParsedInputs[j])
+			if !ok {
+				return nil
+			}
+			p.Indexed = true
+			p.Values = values
+			t++
+		} else {
+			p.Values = dataValues[d].Values
+			d++
+		}
+	}
+	return params
+}
+
+// tryParseLog tries to decode the log using the event signature
+// if it is not known which parameters are indexed, all combinations with the number of indexed parameters
+// given by the number of topics are tried, starting with the first parameters indexed, which is the most common case
+func tryParseLog(s *bchain.EventSignature, topics []string, data string) []bchain.EthereumParsedLogParam {
+	n, k := len(s.Inputs), len(topics)-1
+	if k > n {
+		return nil
+	}
+	indexed := make([]bool, n)
+	if !s.IndexedUnknown {
+		count := 0
+		for j := range s.Inputs {
+			if s.Inputs[j].Indexed {
+				indexed[j] = true
+				count++
+			}
+		}
+		if count != k {
+			return nil
+		}
+		return decodeLogParams(s, indexed, topics, data)
+	}
+	var try func(start, remaining int) []bchain.EthereumParsedLogParam
+	try = func(start, remaining int) []bchain.EthereumParsedLogParam {
+		if remaining == 0 {
+			return decodeLogParams(s, indexed, topics, data)
+		}
+		for j := start; j <= n-remaining; j++ {
+			indexed[j] = true
+			if params := try(j+1, remaining-1); params != nil {
+				return params
+			}
+			indexed[j] = false
+		}
+		return nil
+	}
+	return try(0, k)
+}
+
+// ParseLogs decodes the transaction logs, with priority using the ABI of the contract which emitted the log returned by getABI,
+// otherwise using the known event signatures returned by getSignatures for the topic0 of the log
+// the logs which cannot be decoded are returned with the raw topics and data, if no log can be decoded, nil is returned,
+// the raw logs are available in the receipt of the transaction
+func ParseLogs(logs []*bchain.RpcLog, getABI func(contract string) *abi.ABI, getSignatures func(topic []byte) *[]bchain.EventSignature) []bchain.EthereumParsedLog {
+	if len(logs) == 0 {
+		return nil
+	}
+	parsed := make([]bchain.EthereumParsedLog, len(logs))
+	decoded := false
+	for i, l := range logs {
+		p := &parsed[i]
+		p.Address = l.Address
+		if len(l.Topics) > 0 {
+			p.Topic = l.Topics[0]
+			if getABI != nil {
+				if contractABI := getABI(l.Address); contractABI != nil {
+					parseLogWithABI(contractABI, l, p)
+				}
+			}
+			topic, err := hex.DecodeString(strings.TrimPrefix(l.Topics[0], "0x"))
+			if p.Name == "" && err == nil && len(topic) == 32 {
+				if signatures := getSignatures(topic); signatures != nil {
+					parseLogWithSignatures(l, *signatures, p)
+				}
+			}
+		}
+		if p.Name == "" {
+			p.Topics = l.Topics
+			p.Data = l.Data
+		} else {
+			decoded = true
+		}
+	}
+	if !decoded {
+		return nil
+	}
+	return parsed
+}
+
+func parseLogWithSignatures(l *bchain.RpcLog, signatures []bchain.EventSignature, p *bchain.EthereumParsedLog) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("ParseLogs recovered from panic: ", r, ", ", l)
+			debug.PrintStack()
+		}
+	}()
+	for i := range signatures {
+		s := &signatures[i]
+		// if not yet done, set Event and parse parameter types from string to abi.Type
+		// the signatures are stored in cache
+		if s.Event == "" {
+			types := make([]string, len(s.Inputs))
+			s.ParsedInputs = make([]abi.Type, len(s.Inputs))
+			for j := range s.Inputs {
+				types[j] = s.Inputs[j].Type
+				s.ParsedInputs[j] = parseParameterType(s.Inputs[j].Type)
+			}
+			s.Event = s.Name + "(" + strings.Join(types, ", ") + ")"
+		}
+		if params := tryParseLog(s, l.Topics, l.Data); params != nil {
+			p.Name = s.Name
+			p.Event = s.Event
+			p.Params = params
+			return
+		}
+	}
+}
+
 // getEnsRecord processes transaction log entry and tries to parse ENS record from it
 func getEnsRecord(l *rpcLogWithTxHash) *bchain.AddressAliasRecord {
 	if len(l.Topics) == 3 && l.Topics[0] == nameRegisteredEventSignature && len(l.Data) >= 322 {
@@ -3,6 +3,7 @@
 package eth
 
 import (
+	"encoding/hex"
 	"reflect"
 	"testing"
 
@@ -503,3 +504,134 @@ func Test_getEnsRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLogs(t *testing.T) {
+	transferTopic := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	approvalForAllTopic := "0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31"
+	signatures := map[string]*[]bchain.EventSignature{
+		transferTopic: {
+			{
+				Name:           "Transfer",
+				Inputs:         []bchain.EventSignatureParam{{Type: "address"}, {Type: "address"}, {Type: "uint256"}},
+				IndexedUnknown: true,
+			},
+		},
+		approvalForAllTopic: {
+			{
+				Name: "ApprovalForAll",
+				Inputs: []bchain.EventSignatureParam{
+					{Name: "owner", Type: "address", Indexed: true},
+					{Name: "operator", Type: "address", Indexed: true},
+					{Name: "approved", Type: "bool"},
+				},
+			},
+		},
+	}
+	getSignatures := func(topic []byte) *[]bchain.EventSignature {
+		return signatures["0x"+hex.EncodeToString(topic)]
+	}
+	logs := []*bchain.RpcLog{
+		// ERC20 transfer, 2 indexed parameters
+		{
+			Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			Topics: []string{
+				transferTopic,
+				"0x0000000000000000000000004bbeeb066ed09b7aed07bf39eee0460dfa261520",
+				"0x000000000000000000000000f5d6a8e4c4f2e3d3f9a5a8ea6f1a22b6c1f8c0c1",
+			},
+			Data: "0x00000000000000000000000000000000000000000000000000000000000f4240",
+		},
+		// ERC721 transfer, 3 indexed parameters
+		{
+			Address: "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+			Topics: []string{
+				transferTopic,
+				"0x0000000000000000000000004bbeeb066ed09b7aed07bf39eee0460dfa261520",
+				"0x000000000000000000000000f5d6a8e4c4f2e3d3f9a5a8ea6f1a22b6c1f8c0c1",
+				"0x0000000000000000000000000000000000000000000000000000000000000d05",
+			},
+			Data: "0x",
+		},
+		{
+			Address: "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+			Topics: []string{
+				approvalForAllTopic,
+				"0x0000000000000000000000004bbeeb066ed09b7aed07bf39eee0460dfa261520",
+				"0x000000000000000000000000f5d6a8e4c4f2e3d3f9a5a8ea6f1a22b6c1f8c0c1",
+			},
+			Data: "0x0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		// unknown event
+		{
+			Address: "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+			Topics:  []string{"0x1111111111111111111111111111111111111111111111111111111111111111"},
+			Data:    "0x01",
+		},
+		// known topic, data does not match the signature
+		{
+			Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			Topics:  []string{transferTopic},
+			Data:    "0x",
+		},
+	}
+	from := "0x4bbeEB066eD09B7AEd07bF39EEe0460DFa261520"
+	to := "0xf5d6A8E4C4F2E3d3f9a5a8eA6f1A22b6c1F8c0c1"
+	want := []bchain.EthereumParsedLog{
+		{
+			Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			Topic:   transferTopic,
+			Name:    "Transfer",
+			Event:   "Transfer(address, address, uint256)",
+			Params: []bchain.EthereumParsedLogParam{
+				{Type: "address", Indexed: true, Values: []string{from}},
+				{Type: "address", Indexed: true, Values: []string{to}},
+				{Type: "uint256", Values: []string{"1000000"}},
+			},
+		},
+		{
+			Address: "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+			Topic:   transferTopic,
+			Name:    "Transfer",
+			Event:   "Transfer(address, address, uint256)",
+			Params: []bchain.EthereumParsedLogParam{
+				{Type: "address", Indexed: true, Values: []string{from}},
+				{Type: "address", Indexed: true, Values: []string{to}},
+				{Type: "uint256", Indexed: true, Values: []string{"3333"}},
+			},
+		},
+		{
+			Address: "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+			Topic:   approvalForAllTopic,
+			Name:    "ApprovalForAll",
+			Event:   "ApprovalForAll(address, address, bool)",
+			Params: []bchain.EthereumParsedLogParam{
+				{Name: "owner", Type: "address", Indexed: true, Values: []string{from}},
+				{Name: "operator", Type: "address", Indexed: true, Values: []string{to}},
+				{Name: "approved", Type: "bool", Values: []string{"true"}},
+			},
+		},
+		{
+			Address: "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+			Topic:   "0x1111111111111111111111111111111111111111111111111111111111111111",
+			Topics:  []string{"0x1111111111111111111111111111111111111111111111111111111111111111"},
+			Data:    "0x01",
+		},
+		{
+			Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			Topic:   transferTopic,
+			Topics:  []string{transferTopic},
+			Data:    "0x",
+		},
+	}
+	got := ParseLogs(logs, nil, getSignatures)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLogs() = %+v, want %+v", got, want)
+	}
+	if got := ParseLogs(nil, nil, getSignatures); got != nil {
+		t.Errorf("ParseLogs(nil) = %+v, want nil", got)
+	}
+	// the logs are omitted if none of them can be decoded
+	if got := ParseLogs(logs[len(logs)-2:], nil, getSignatures); got != nil {
+		t.Errorf("ParseLogs(undecoded) = %+v, want nil", got)
+	}
+}
@@ -292,6 +292,19 @@ func (p *EthereumParser) PackTx(tx *bchain.Tx, height uint32, blockTime int64) (
 			return nil, errors.Annotatef(err, "BaseFeePerGas %v", r.Tx.BaseFeePerGas)
 		}
 	}
+	if len(r.Tx.MaxFeePerBlobGas) > 0 {
+		if pt.Tx.MaxFeePerBlobGas, err = hexDecodeBig(r.Tx.MaxFeePerBlobGas); err != nil {
+			return nil, errors.Annotatef(err, "MaxFeePerBlobGas %v", r.Tx.MaxFeePerBlobGas)
+		}
+	}
+	if len(r.Tx.BlobVersionedHashes) > 0 {
+		pt.Tx.BlobVersionedHashes = make([][]byte, len(r.Tx.BlobVersionedHashes))
+		for i, h := range r.Tx.BlobVersionedHashes {
+			if pt.Tx.BlobVersionedHashes[i], err = hexDecode(h); err != nil {
+				return nil, errors.Annotatef(err, "BlobVersionedHashes %v", h)
+			}
+		}
+	}
 	// if pt.R, err = hexDecodeBig(r.R); err != nil {
 	// 	return nil, errors.Annotatef(err, "R %v", r.R)
 	// }
@@ -368,6 +381,16 @@ func (p *EthereumParser) PackTx(tx *bchain.Tx, height uint32, blockTime int64) (
 				return nil, errors.Annotatef(err, "L1GasUsed %v", r.Receipt.L1GasUsed)
 			}
 		}
+		if r.Receipt.BlobGasUsed != "" {
+			if pt.Receipt.BlobGasUsed, err = hexDecodeBig(r.Receipt.BlobGasUsed); err != nil {
+				return nil, errors.Annotatef(err, "BlobGasUsed %v", r.Receipt.BlobGasUsed)
+			}
+		}
+		if r.Receipt.BlobGasPrice != "" {
+			if pt.Receipt.BlobGasPrice, err = hexDecodeBig(r.Receipt.BlobGasPrice); err != nil {
+				return nil, errors.Annotatef(err, "BlobGasPrice %v", r.Receipt.BlobGasPrice)
+			}
+		}
 	}
 	return proto.Marshal(pt)
 }
@@ -403,6 +426,15 @@ func (p *EthereumParser) UnpackTx(buf []byte) (*bchain.Tx, uint32, error) {
 	if len(pt.Tx.BaseFeePerGas) > 0 {
 		rt.BaseFeePerGas = hexEncodeBig(pt.Tx.BaseFeePerGas)
 	}
+	if len(pt.Tx.MaxFeePerBlobGas) > 0 {
+		rt.MaxFeePerBlobGas = hexEncodeBig(pt.Tx.MaxFeePerBlobGas)
+	}
+	if len(pt.Tx.BlobVersionedHashes) > 0 {
+		rt.BlobVersionedHashes = make([]string, len(pt.Tx.BlobVersionedHashes))
+		for i, h := range pt.Tx.BlobVersionedHashes {
+			rt.BlobVersionedHashes[i] = hexutil.Encode(h)
+		}
+	}
 	var rr *bchain.RpcReceipt
 	if pt.Receipt != nil {
 		rr = &bchain.RpcReceipt{
@@ -437,6 +469,12 @@ func (p *EthereumParser) UnpackTx(buf []byte) (*bchain.Tx, uint32, error) {
 		if len(pt.Receipt.L1GasUsed) > 0 {
 			rr.L1GasUsed = hexEncodeBig(pt.Receipt.L1GasUsed)
 		}
+		if len(pt.Receipt.BlobGasUsed) > 0 {
+			rr.BlobGasUsed = hexEncodeBig(pt.Receipt.BlobGasUsed)
+		}
+		if len(pt.Receipt.BlobGasPrice) > 0 {
+			rr.BlobGasPrice = hexEncodeBig(pt.Receipt.BlobGasPrice)
+		}
 	}
 	// TODO handle internal transactions
 	tx, err := p.ethTxToTx(&rt, rr, nil, int64(pt.BlockTime), 0, false)
@@ -545,6 +583,10 @@ type EthereumTxData struct {
 	L1FeeScalar          string   `json:"l1FeeScalar,omitempty"`
 	L1GasPrice           *big.Int `json:"l1GasPrice,omitempty"`
 	L1GasUsed            *big.Int `json:"L1GasUsed,omitempty"`
+	MaxFeePerBlobGas     *big.Int `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes  []string `json:"blobVersionedHashes,omitempty"`
+	BlobGasUsed          *big.Int `json:"blobGasUsed,omitempty"`
+	BlobGasPrice         *big.Int `json:"blobGasPrice,omitempty"`
 	Data                 string   `json:"data"`
 }
 
@@ -553,6 +595,15 @@ func GetEthereumTxData(tx *bchain.Tx) *EthereumTxData {
 	return GetEthereumTxDataFromSpecificData(tx.CoinSpecificData)
 }
 
+// GetEthereumTxLogs returns the logs from the receipt of the transaction, nil for the mempool transactions
+func GetEthereumTxLogs(tx *bchain.Tx) []*bchain.RpcLog {
+	csd, ok := tx.CoinSpecificData.(bchain.EthereumSpecificData)
+	if ok && csd.Receipt != nil {
+		return csd.Receipt.Logs
+	}
+	return nil
+}
+
 // GetEthereumTxDataFromSpecificData returns EthereumTxData from coinSpecificData
 func GetEthereumTxDataFromSpecificData(coinSpecificData interface{}) *EthereumTxData {
 	etd := EthereumTxData{Status: TxStatusPending}
@@ -565,6 +616,8 @@ func GetEthereumTxDataFromSpecificData(coinSpecificData interface{}) *EthereumTx
 			etd.MaxPriorityFeePerGas, _ = hexutil.DecodeBig(csd.Tx.MaxPriorityFeePerGas)
 			etd.MaxFeePerGas, _ = hexutil.DecodeBig(csd.Tx.MaxFeePerGas)
 			etd.BaseFeePerGas, _ = hexutil.DecodeBig(csd.Tx.BaseFeePerGas)
+			etd.MaxFeePerBlobGas, _ = hexutil.DecodeBig(csd.Tx.MaxFeePerBlobGas)
+			etd.BlobVersionedHashes = csd.Tx.BlobVersionedHashes
 			etd.Data = csd.Tx.Payload
 		}
 		if csd.Receipt != nil {
@@ -581,6 +634,8 @@ func GetEthereumTxDataFromSpecificData(coinSpecificData interface{}) *EthereumTx
 			etd.L1GasPrice, _ = hexutil.DecodeBig(csd.Receipt.L1GasPrice)
 			etd.L1GasUsed, _ = hexutil.DecodeBig(csd.Receipt.L1GasUsed)
 			etd.L1FeeScalar = csd.Receipt.L1FeeScalar
+			etd.BlobGasUsed, _ = hexutil.DecodeBig(csd.Receipt.BlobGasUsed)
+			etd.BlobGasPrice, _ = hexutil.DecodeBig(csd.Receipt.BlobGasPrice)
 		}
 	}
 	return &etd
@@ -380,6 +380,41 @@ func TestEthereumParser_UnpackTx(t *testing.T) {
 	}
 }
 
+func TestEthereumParser_PackUnpackBlobTx(t *testing.T) {
+	tx := testTx1
+	csd := tx.CoinSpecificData.(bchain.EthereumSpecificData)
+	rt := *csd.Tx
+	rt.MaxFeePerBlobGas = "0x3b9aca00"
+	rt.BlobVersionedHashes = []string{
+		"0x01a915e4d060149eb4365960e6a7a45f334393093061116b197e3240065ff2d8",
+		"0x0167a26c6eb16dd6b5de18f2b9c5d7e4b6d4f0a1c8e9b7e2f4d6a8c0b2e4f6a8",
+	}
+	rr := *csd.Receipt
+	rr.BlobGasUsed = "0x40000"
+	rr.BlobGasPrice = "0x1"
+	tx.CoinSpecificData = bchain.EthereumSpecificData{Tx: &rt, Receipt: &rr}
+	p := NewEthereumParser(1, false)
+	b, err := p.PackTx(&tx, 4321000, 1534858022)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := p.UnpackTx(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := got.CoinSpecificData.(bchain.EthereumSpecificData)
+	if !reflect.DeepEqual(gs.Tx, &rt) {
+		t.Errorf("EthereumParser.UnpackTx() gs.Tx got = %+v, want %+v", gs.Tx, &rt)
+	}
+	if !reflect.DeepEqual(gs.Receipt, &rr) {
+		t.Errorf("EthereumParser.UnpackTx() gs.Receipt got = %+v, want %+v", gs.Receipt, &rr)
+	}
+	etd := GetEthereumTxData(got)
+	if etd.MaxFeePerBlobGas.Int64() != 1000000000 || etd.BlobGasUsed.Int64() != 262144 || etd.BlobGasPrice.Int64() != 1 || len(etd.BlobVersionedHashes) != 2 {
+		t.Errorf("GetEthereumTxData() = %+v", etd)
+	}
+}
+
 func TestEthereumParser_GetEthereumTxData(t *testing.T) {
 	tests := []struct {
 		name string
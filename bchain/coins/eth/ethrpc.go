@@ -59,6 +59,7 @@ type Configuration struct {
 	ConsensusNodeVersionURL         string `json:"consensusNodeVersion"`
 	DisableMempoolSync              bool   `json:"disableMempoolSync,omitempty"`
 	Eip1559Fees                     bool   `json:"eip1559Fees,omitempty"`
+	Eip4844BlobFees                 bool   `json:"eip4844BlobFees,omitempty"`
 	AlternativeEstimateFee          string `json:"alternative_estimate_fee,omitempty"`
 	AlternativeEstimateFeeParams    string `json:"alternative_estimate_fee_params,omitempty"`
 }
@@ -92,6 +93,9 @@ type EthereumRPC struct {
 	stakingPoolContracts      []string
 	alternativeFeeProvider    alternativeFeeProviderInterface
 	alternativeSendTxProvider *AlternativeSendTxProvider
+	blobBaseFeeLock           sync.Mutex
+	blobBaseFee               *big.Int
+	blobBaseFeeHeight         uint64
 }
 
 // ProcessInternalTransactions specifies if internal transactions are processed
@@ -825,7 +829,7 @@ type rpcTraceResult struct {
 	Result rpcCallTrace `json:"result"`
 }
 
-func (b *EthereumRPC) getCreationContractInfo(contract string, height uint32) *bchain.ContractInfo {
+func (b *EthereumRPC) getCreationContractInfo(contract string, creator string, txid string, height uint32) *bchain.ContractInfo {
 	// do not fetch fetchContractInfo in sync, it slows it down
 	// the contract will be fetched only when asked by a client
 	// ci, err := b.fetchContractInfo(contract)
@@ -837,10 +841,12 @@ func (b *EthereumRPC) getCreationContractInfo(contract string, height uint32) *b
 	ci.Standard = bchain.UnhandledTokenStandard
 	ci.Type = bchain.UnhandledTokenStandard
 	ci.CreatedInBlock = height
+	ci.CreatedBy = creator
+	ci.CreationTx = txid
 	return ci
 }
 
-func (b *EthereumRPC) processCallTrace(call *rpcCallTrace, d *bchain.EthereumInternalData, contracts []bchain.ContractInfo, blockHeight uint32) []bchain.ContractInfo {
+func (b *EthereumRPC) processCallTrace(call *rpcCallTrace, d *bchain.EthereumInternalData, contracts []bchain.ContractInfo, blockHeight uint32, txid string) []bchain.ContractInfo {
 	value, err := hexutil.DecodeBig(call.Value)
 	if err != nil {
 		value = new(big.Int)
@@ -852,7 +858,7 @@ func (b *EthereumRPC) processCallTrace(call *rpcCallTrace, d *bchain.EthereumInt
 			From:  call.From,
 			To:    call.To, // new contract address
 		})
-		contracts = append(contracts, *b.getCreationContractInfo(call.To, blockHeight))
+		contracts = append(contracts, *b.getCreationContractInfo(call.To, call.From, txid, blockHeight))
 	} else if call.Type == "SELFDESTRUCT" {
 		d.Transfers = append(d.Transfers, bchain.EthereumInternalTransfer{
 			Type:  bchain.SELFDESTRUCT,
@@ -875,7 +881,7 @@ func (b *EthereumRPC) processCallTrace(call *rpcCallTrace, d *bchain.EthereumInt
 		d.Error = call.Error
 	}
 	for i := range call.Calls {
-		contracts = b.processCallTrace(&call.Calls[i], d, contracts, blockHeight)
+		contracts = b.processCallTrace(&call.Calls[i], d, contracts, blockHeight, txid)
 	}
 	return contracts
 }
@@ -921,12 +927,12 @@ func (b *EthereumRPC) getInternalDataForBlock(ctx context.Context, blockHash str
 			if r.Type == "CREATE" || r.Type == "CREATE2" {
 				d.Type = bchain.CREATE
 				d.Contract = r.To
-				contracts = append(contracts, *b.getCreationContractInfo(d.Contract, blockHeight))
+				contracts = append(contracts, *b.getCreationContractInfo(d.Contract, r.From, transactions[i].Hash, blockHeight))
 			} else if r.Type == "SELFDESTRUCT" {
 				d.Type = bchain.SELFDESTRUCT
 			}
 			for j := range r.Calls {
-				contracts = b.processCallTrace(&r.Calls[j], d, contracts, blockHeight)
+				contracts = b.processCallTrace(&r.Calls[j], d, contracts, blockHeight, transactions[i].Hash)
 			}
 			if r.Error != "" {
 				baseError := PackInternalTransactionError(r.Error)
@@ -935,7 +941,7 @@ func (b *EthereumRPC) getInternalDataForBlock(ctx context.Context, blockHash str
 					// glog.Infof("Internal Data Error %d %s: unknown base error %s", n, transactions[i].Hash, baseError)
 					baseError = strings.ToUpper(baseError[:1]) + baseError[1:] + ". "
 				}
-				outputError := ParseErrorFromOutput(r.Output)
+				outputError := decodeRevertReasonInSync(transactions[i].To, r.Output)
 				if len(outputError) > 0 {
 					d.Error = baseError + strings.ToUpper(outputError[:1]) + outputError[1:]
 				} else {
@@ -1106,7 +1112,8 @@ func (b *EthereumRPC) GetTransaction(txid string) (*bchain.Tx, error) {
 			return nil, err
 		}
 	}
-	if *tx == (bchain.RpcTransaction{}) {
+	// the backend returns null for an unknown transaction
+	if tx.Hash == "" {
 		b.removeTransactionFromMempool(txid)
 		return nil, bchain.ErrTxNotFound
 	}
@@ -1262,7 +1269,14 @@ func (b *EthereumRPC) EthereumTypeGetEip1559Fees() (*bchain.Eip1559Fees, error)
 	}
 	// if there is an alternative provider, use it
 	if b.alternativeFeeProvider != nil {
-		return b.alternativeFeeProvider.GetEip1559Fees()
+		fees, err := b.alternativeFeeProvider.GetEip1559Fees()
+		if err != nil || fees == nil {
+			return fees, err
+		}
+		// the alternative providers do not estimate the blob fees, the returned fees are cached, do not modify them
+		f := *fees
+		f.BlobBaseFeePerGas = b.getBlobBaseFee()
+		return &f, nil
 	}
 
 	// otherwise use algorithm from here https://docs.alchemy.com/docs/how-to-build-a-gas-fee-estimator-using-eip-1559
@@ -1278,10 +1292,11 @@ func (b *EthereumRPC) EthereumTypeGetEip1559Fees() (*bchain.Eip1559Fees, error)
 	var fees bchain.Eip1559Fees
 
 	type history struct {
-		OldestBlock   string     `json:"oldestBlock"`
-		Reward        [][]string `json:"reward"`
-		BaseFeePerGas []string   `json:"baseFeePerGas"`
-		GasUsedRatio  []float64  `json:"gasUsedRatio"`
+		OldestBlock       string     `json:"oldestBlock"`
+		Reward            [][]string `json:"reward"`
+		BaseFeePerGas     []string   `json:"baseFeePerGas"`
+		GasUsedRatio      []float64  `json:"gasUsedRatio"`
+		BaseFeePerBlobGas []string   `json:"baseFeePerBlobGas"`
 	}
 	var h history
 	percentiles := []int{
@@ -1303,6 +1318,13 @@ func (b *EthereumRPC) EthereumTypeGetEip1559Fees() (*bchain.Eip1559Fees, error)
 	hs, _ := json.Marshal(h)
 	baseFee, _ := hexutil.DecodeUint64(h.BaseFeePerGas[blocks-1])
 	fees.BaseFeePerGas = big.NewInt(int64(baseFee))
+	// the backends supporting EIP-4844 return also the blob base fees, the last one is for the pending block
+	if b.ChainConfig.Eip4844BlobFees && len(h.BaseFeePerBlobGas) > 0 {
+		fees.BlobBaseFeePerGas, _ = hexutil.DecodeBig(h.BaseFeePerBlobGas[len(h.BaseFeePerBlobGas)-1])
+	}
+	if fees.BlobBaseFeePerGas == nil || fees.BlobBaseFeePerGas.Sign() == 0 {
+		fees.BlobBaseFeePerGas = b.getBlobBaseFee()
+	}
 	maxBasePriorityFee := maxPriorityFeePerGas.ToInt().Int64()
 	glog.Info("eth_maxPriorityFeePerGas ", maxPriorityFeePerGas)
 	glog.Info("eth_feeHistory ", string(hs))
@@ -1332,6 +1354,32 @@ func (b *EthereumRPC) EthereumTypeGetEip1559Fees() (*bchain.Eip1559Fees, error)
 	return &fees, err
 }
 
+// getBlobBaseFee returns the blob base fee of the next block, nil if the chain is not configured for EIP-4844 blob transactions
+// the fee changes only with a new block, it is cached for the best block
+func (b *EthereumRPC) getBlobBaseFee() *big.Int {
+	if !b.ChainConfig.Eip4844BlobFees {
+		return nil
+	}
+	var height uint64
+	if h, err := b.getBestHeader(); err == nil && h != nil && h.Number() != nil {
+		height = h.Number().Uint64()
+	}
+	b.blobBaseFeeLock.Lock()
+	defer b.blobBaseFeeLock.Unlock()
+	if b.blobBaseFee != nil && height != 0 && b.blobBaseFeeHeight == height {
+		return b.blobBaseFee
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), b.Timeout)
+	defer cancel()
+	var blobBaseFee hexutil.Big
+	if err := b.RPC.CallContext(ctx, &blobBaseFee, "eth_blobBaseFee"); err != nil {
+		glog.V(1).Info("eth_blobBaseFee error ", err)
+		return nil
+	}
+	b.blobBaseFee, b.blobBaseFeeHeight = blobBaseFee.ToInt(), height
+	return b.blobBaseFee
+}
+
 // SendRawTransaction sends raw transaction
 func (b *EthereumRPC) SendRawTransaction(hex string, disableAlternativeRPC bool) (string, error) {
 	var txid string
@@ -1388,6 +1436,20 @@ func (b *EthereumRPC) EthereumTypeGetBalance(addrDesc bchain.AddressDescriptor)
 	return b.Client.BalanceAt(ctx, addrDesc, nil)
 }
 
+// EthereumTypeGetBalanceAtBlock returns balance of an address at the end of the given block, requires an archive node for older blocks
+func (b *EthereumRPC) EthereumTypeGetBalanceAtBlock(addrDesc bchain.AddressDescriptor, blockNumber *big.Int) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.Timeout)
+	defer cancel()
+	return b.Client.BalanceAt(ctx, addrDesc, blockNumber)
+}
+
+// EthereumTypeGetNonceAtBlock returns nonce of an address at the end of the given block, requires an archive node for older blocks
+func (b *EthereumRPC) EthereumTypeGetNonceAtBlock(addrDesc bchain.AddressDescriptor, blockNumber *big.Int) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.Timeout)
+	defer cancel()
+	return b.Client.NonceAt(ctx, addrDesc, blockNumber)
+}
+
 // EthereumTypeGetNonce returns current balance of an address
 func (b *EthereumRPC) EthereumTypeGetNonce(addrDesc bchain.AddressDescriptor) (uint64, error) {
 	var result string
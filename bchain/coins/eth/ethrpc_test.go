@@ -0,0 +1,63 @@
+//go:build unittest
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/trezor/blockbook/bchain"
+)
+
+type mockBlobFeeRPC struct {
+	calls int
+}
+
+func (m *mockBlobFeeRPC) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (bchain.EVMClientSubscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBlobFeeRPC) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "eth_blobBaseFee" {
+		return errors.New("unexpected method " + method)
+	}
+	m.calls++
+	*result.(*hexutil.Big) = hexutil.Big(*big.NewInt(int64(m.calls)))
+	return nil
+}
+
+func (m *mockBlobFeeRPC) Close() {}
+
+func TestEthereumRPC_getBlobBaseFee(t *testing.T) {
+	rpc := &mockBlobFeeRPC{}
+	b := &EthereumRPC{
+		RPC:            rpc,
+		Timeout:        time.Second,
+		ChainConfig:    &Configuration{},
+		bestHeader:     &EthereumHeader{Header: &types.Header{Number: big.NewInt(100)}},
+		bestHeaderTime: time.Now(),
+	}
+	// the chains without blob transactions do not query the backend
+	if fee := b.getBlobBaseFee(); fee != nil || rpc.calls != 0 {
+		t.Fatalf("getBlobBaseFee() = %v, %d calls, want nil, 0 calls", fee, rpc.calls)
+	}
+	b.ChainConfig.Eip4844BlobFees = true
+	for i := 0; i < 3; i++ {
+		if fee := b.getBlobBaseFee(); fee == nil || fee.Int64() != 1 {
+			t.Fatalf("getBlobBaseFee() = %v, want 1", fee)
+		}
+	}
+	if rpc.calls != 1 {
+		t.Errorf("eth_blobBaseFee called %d times in one block, want 1", rpc.calls)
+	}
+	// the fee is queried again for the next block
+	b.bestHeader = &EthereumHeader{Header: &types.Header{Number: big.NewInt(101)}}
+	if fee := b.getBlobBaseFee(); fee == nil || fee.Int64() != 2 {
+		t.Errorf("getBlobBaseFee() = %v, want 2", fee)
+	}
+}
@@ -0,0 +1,138 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// Panic(uint256)
+const panicOutputSignature = "4e487b71"
+
+// panic codes of the solidity compiler, https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicCodes = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "conversion to invalid enum value",
+	0x22: "incorrectly encoded storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "too much memory allocated",
+	0x51: "call of zero-initialized internal function",
+}
+
+// FourByteSignaturesGetter returns the known four byte signatures, nil if there are none
+// it is set by the application when the database is opened, the signatures are then used to decode the custom errors during the sync
+var FourByteSignaturesGetter func(fourBytes uint32) (*[]bchain.FourByteSignature, error)
+
+// ParsePanicFromOutput extracts the Panic(uint256) error of the solidity compiler from the output
+func ParsePanicFromOutput(output string) string {
+	if has0xPrefix(output) {
+		output = output[2:]
+	}
+	if len(output) != 8+64 || output[:8] != panicOutputSignature {
+		return ""
+	}
+	code, ok := new(big.Int).SetString(output[8:], 16)
+	if !ok {
+		return ""
+	}
+	if code.IsUint64() {
+		if description, found := panicCodes[code.Uint64()]; found {
+			return fmt.Sprintf("Panic(0x%02x): %s", code.Uint64(), description)
+		}
+	}
+	return "Panic(0x" + code.Text(16) + ")"
+}
+
+// parseCustomErrorWithSignatures decodes the custom error by the known four byte signatures
+func parseCustomErrorWithSignatures(signatures *[]bchain.FourByteSignature, output string) string {
+	if signatures == nil {
+		return ""
+	}
+	if !has0xPrefix(output) {
+		output = "0x" + output
+	}
+	parsed := ParseInputData(signatures, output)
+	if parsed == nil || parsed.Function == "" {
+		return ""
+	}
+	name := parsed.Function
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	args := make([]string, len(parsed.Params))
+	for i := range parsed.Params {
+		args[i] = strings.Join(parsed.Params[i].Values, ", ")
+	}
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// DecodeRevertReason decodes the revert data of a failed call, the custom errors are decoded with priority by the ABI of the called contract,
+// then the Error(string) and Panic(uint256) errors and at last the custom errors matching the known four byte signatures
+func DecodeRevertReason(contractABI *abi.ABI, signatures *[]bchain.FourByteSignature, output string) string {
+	if reason := ParseErrorFromOutputWithABI(contractABI, output); reason != "" {
+		return reason
+	}
+	if reason := ParsePanicFromOutput(output); reason != "" {
+		return reason
+	}
+	return parseCustomErrorWithSignatures(signatures, output)
+}
+
+// decodeRevertReasonInSync decodes the revert reason using the ABI and four byte signatures getters set by the application
+func decodeRevertReasonInSync(contract string, output string) string {
+	var signatures *[]bchain.FourByteSignature
+	if FourByteSignaturesGetter != nil {
+		if fourBytes := GetSignatureFromData(output); fourBytes != 0 {
+			signatures, _ = FourByteSignaturesGetter(fourBytes)
+		}
+	}
+	return DecodeRevertReason(getContractABI(contract), signatures, output)
+}
+
+// EthereumTypeGetRevertData replays the transaction by eth_call in the state of the given block and returns the revert data,
+// empty string is returned if the replayed call does not revert, requires an archive node for older blocks
+func (b *EthereumRPC) EthereumTypeGetRevertData(tx *bchain.Tx, blockNumber *big.Int) (string, error) {
+	csd, ok := tx.CoinSpecificData.(bchain.EthereumSpecificData)
+	if !ok || csd.Tx == nil {
+		return "", errors.New("Missing ethereum specific data")
+	}
+	args := map[string]interface{}{
+		"from": csd.Tx.From,
+		"data": csd.Tx.Payload,
+	}
+	if csd.Tx.To != "" {
+		args["to"] = csd.Tx.To
+	}
+	if csd.Tx.Value != "" {
+		args["value"] = csd.Tx.Value
+	}
+	if csd.Tx.GasLimit != "" {
+		args["gas"] = csd.Tx.GasLimit
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), b.Timeout)
+	defer cancel()
+	var r string
+	err := b.RPC.CallContext(ctx, &r, "eth_call", args, bchain.ToBlockNumArg(blockNumber))
+	if err == nil {
+		return "", nil
+	}
+	if de, ok := err.(rpc.DataError); ok {
+		if data, ok := de.ErrorData().(string); ok {
+			if _, err := hexutil.Decode(data); err == nil {
+				return data, nil
+			}
+		}
+	}
+	return "", err
+}
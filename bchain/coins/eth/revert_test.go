@@ -0,0 +1,69 @@
+//go:build unittest
+
+package eth
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/trezor/blockbook/bchain"
+)
+
+func TestParsePanicFromOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"overflow", "0x4e487b710000000000000000000000000000000000000000000000000000000000000011", "Panic(0x11): arithmetic underflow or overflow"},
+		{"division by zero", "4e487b710000000000000000000000000000000000000000000000000000000000000012", "Panic(0x12): division or modulo by zero"},
+		{"unknown code", "0x4e487b7100000000000000000000000000000000000000000000000000000000000000ff", "Panic(0xff)"},
+		{"error string", "0x08c379a00000000000000000000000000000000000000000000000000000000000000011", ""},
+		{"short", "0x4e487b71", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParsePanicFromOutput(tt.output); got != tt.want {
+				t.Errorf("ParsePanicFromOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	contractABI := parseTestContractABI(t)
+	customError := "0x" + hex.EncodeToString(contractABI.Errors["InsufficientBalance"].ID.Bytes()[:4]) +
+		"0000000000000000000000000000000000000000000000000000000000000064" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+	signatures := &[]bchain.FourByteSignature{
+		{
+			Name:       "InsufficientBalance",
+			Parameters: []string{"uint256", "uint256"},
+		},
+	}
+	tests := []struct {
+		name        string
+		contractABI *abi.ABI
+		signatures  *[]bchain.FourByteSignature
+		output      string
+		want        string
+	}{
+		{"custom error by ABI", contractABI, nil, customError, "InsufficientBalance(100, 1000)"},
+		{"custom error by signatures", nil, signatures, customError, "InsufficientBalance(100, 1000)"},
+		{"unknown custom error", nil, nil, customError, ""},
+		{"panic", nil, nil, "0x4e487b710000000000000000000000000000000000000000000000000000000000000001", "Panic(0x01): assertion failed"},
+		{"error string", nil, signatures, "0x08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"000000000000000000000000000000000000000000000000000000000000000b" +
+			"4e6f7420616c6c6f776564000000000000000000000000000000000000000000", "Not allowed"},
+		{"empty output", contractABI, signatures, "0x", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeRevertReason(tt.contractABI, tt.signatures, tt.output); got != tt.want {
+				t.Errorf("DecodeRevertReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
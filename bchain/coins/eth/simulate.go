@@ -0,0 +1,172 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// the transaction fields passed to debug_traceCall, the values are hex encoded as in eth_call
+var simulationCallFields = []string{"from", "to", "data", "value", "gas", "gasPrice", "maxFeePerGas", "maxPriorityFeePerGas"}
+
+// rpcSimulationTrace is the call frame returned by debug_traceCall with the callTracer and the withLog option
+type rpcSimulationTrace struct {
+	// CREATE, CREATE2, SELFDESTRUCT, CALL, CALLCODE, DELEGATECALL, STATICCALL
+	Type    string               `json:"type"`
+	From    string               `json:"from"`
+	To      string               `json:"to"`
+	Value   string               `json:"value"`
+	GasUsed string               `json:"gasUsed"`
+	Output  string               `json:"output"`
+	Error   string               `json:"error"`
+	Logs    []*bchain.RpcLog     `json:"logs"`
+	Calls   []rpcSimulationTrace `json:"calls"`
+}
+
+// processSimulationTrace collects the value transfers and logs of the internal calls,
+// the failed calls are reverted together with their subcalls and are skipped
+func processSimulationTrace(call *rpcSimulationTrace, s *bchain.EthereumSimulation, logs []*bchain.RpcLog) []*bchain.RpcLog {
+	logs = append(logs, call.Logs...)
+	for i := range call.Calls {
+		c := &call.Calls[i]
+		if c.Error != "" {
+			continue
+		}
+		value, err := hexutil.DecodeBig(c.Value)
+		if err != nil {
+			value = new(big.Int)
+		}
+		t := bchain.CALL
+		switch c.Type {
+		case "CREATE", "CREATE2":
+			t = bchain.CREATE
+		case "SELFDESTRUCT":
+			t = bchain.SELFDESTRUCT
+		case "DELEGATECALL", "STATICCALL", "CALLCODE":
+			// the value of these calls stays in the context of the caller
+			value.SetInt64(0)
+		}
+		if value.BitLen() > 0 || t == bchain.CREATE {
+			s.Transfers = append(s.Transfers, bchain.EthereumInternalTransfer{
+				Type:  t,
+				From:  EIP55AddressFromAddress(c.From),
+				To:    EIP55AddressFromAddress(c.To),
+				Value: *value,
+			})
+		}
+		logs = processSimulationTrace(c, s, logs)
+	}
+	return logs
+}
+
+// simulationFromTrace converts the trace of the simulated transaction to the predicted effects of the transaction
+func simulationFromTrace(trace *rpcSimulationTrace) (*bchain.EthereumSimulation, error) {
+	s := &bchain.EthereumSimulation{
+		From:   EIP55AddressFromAddress(trace.From),
+		To:     EIP55AddressFromAddress(trace.To),
+		Error:  trace.Error,
+		Output: trace.Output,
+	}
+	if trace.Type == "CREATE" || trace.Type == "CREATE2" {
+		s.Type = bchain.CREATE
+	}
+	if value, err := hexutil.DecodeBig(trace.Value); err == nil {
+		s.Value = *value
+	}
+	if trace.GasUsed != "" {
+		gasUsed, err := hexutil.DecodeUint64(trace.GasUsed)
+		if err != nil {
+			return nil, errors.Annotatef(err, "gasUsed %v", trace.GasUsed)
+		}
+		s.GasUsed = gasUsed
+	}
+	if s.Error != "" {
+		return s, nil
+	}
+	logs := processSimulationTrace(trace, s, nil)
+	tokenTransfers, err := contractGetTransfersFromLog(logs)
+	if err != nil {
+		return nil, err
+	}
+	s.TokenTransfers = tokenTransfers
+	return s, nil
+}
+
+// EthereumTypeSimulateTransaction simulates the transaction by debug_traceCall in the state of the latest block,
+// the state can be modified by the optional stateOverrides parameter in the format of the eth_call state override set
+func (b *EthereumRPC) EthereumTypeSimulateTransaction(params map[string]interface{}) (*bchain.EthereumSimulation, error) {
+	args := make(map[string]interface{})
+	for _, f := range simulationCallFields {
+		if v, ok := GetStringFromMap(f, params); ok && len(v) > 0 {
+			args[f] = v
+		}
+	}
+	if _, ok := args["from"]; !ok {
+		return nil, errors.New("Missing parameter from")
+	}
+	config := map[string]interface{}{
+		"tracer":       "callTracer",
+		"tracerConfig": map[string]interface{}{"withLog": true},
+	}
+	if overrides, ok := params["stateOverrides"]; ok && overrides != nil {
+		config["stateOverrides"] = overrides
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), b.Timeout)
+	defer cancel()
+	var trace rpcSimulationTrace
+	if err := b.RPC.CallContext(ctx, &trace, "debug_traceCall", args, "latest", config); err != nil {
+		return nil, err
+	}
+	sim, err := simulationFromTrace(&trace)
+	if err != nil {
+		return nil, err
+	}
+	if sim.GasPrice, err = b.simulationGasPrice(ctx, args); err != nil {
+		glog.Warning("EthereumTypeSimulateTransaction: cannot get the gas price, ", err)
+	}
+	return sim, nil
+}
+
+// simulationGasPrice returns the effective gas price the transaction would pay in the next block,
+// the gasPrice of the legacy transaction, min(maxFeePerGas, baseFee + maxPriorityFeePerGas) of the EIP-1559 transaction
+// or the gas price suggested by the backend if the transaction does not specify the price
+func (b *EthereumRPC) simulationGasPrice(ctx context.Context, args map[string]interface{}) (*big.Int, error) {
+	if v, ok := args["gasPrice"].(string); ok {
+		return hexutil.DecodeBig(v)
+	}
+	maxFee, ok := args["maxFeePerGas"].(string)
+	if !ok {
+		var price hexutil.Big
+		if err := b.RPC.CallContext(ctx, &price, "eth_gasPrice"); err != nil {
+			return nil, err
+		}
+		return price.ToInt(), nil
+	}
+	price, err := hexutil.DecodeBig(maxFee)
+	if err != nil {
+		return nil, err
+	}
+	var head struct {
+		BaseFeePerGas *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := b.RPC.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, err
+	}
+	if head.BaseFeePerGas == nil {
+		return price, nil
+	}
+	tip := new(big.Int)
+	if v, ok := args["maxPriorityFeePerGas"].(string); ok {
+		if tip, err = hexutil.DecodeBig(v); err != nil {
+			return nil, err
+		}
+	}
+	if p := tip.Add(tip, head.BaseFeePerGas.ToInt()); p.Cmp(price) < 0 {
+		price = p
+	}
+	return price, nil
+}
@@ -0,0 +1,188 @@
+//go:build unittest
+
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/trezor/blockbook/bchain"
+)
+
+const testSimulationTrace = `{
+	"type": "CALL",
+	"from": "0x4bda106325c335df99eab7fe363cac8a0ba2a24d",
+	"to": "0x0d0f936ee4c93e25944694d6c121de94d9760f11",
+	"value": "0xde0b6b3a7640000",
+	"gasUsed": "0x1d4c0",
+	"calls": [
+		{
+			"type": "CALL",
+			"from": "0x0d0f936ee4c93e25944694d6c121de94d9760f11",
+			"to": "0x4af4114f73d1c1c903ac9e0361b379d1291808a2",
+			"value": "0x0",
+			"logs": [
+				{
+					"address": "0x4af4114f73d1c1c903ac9e0361b379d1291808a2",
+					"topics": [
+						"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+						"0x0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+						"0x000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f"
+					],
+					"data": "0x00000000000000000000000000000000000000000000000000000000000003e8"
+				}
+			]
+		},
+		{
+			"type": "CALL",
+			"from": "0x0d0f936ee4c93e25944694d6c121de94d9760f11",
+			"to": "0x7b62eb7fe80350dc7ec945c0b73242cb9877fb1b",
+			"value": "0x2386f26fc10000"
+		},
+		{
+			"type": "DELEGATECALL",
+			"from": "0x0d0f936ee4c93e25944694d6c121de94d9760f11",
+			"to": "0x555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+			"value": "0xde0b6b3a7640000"
+		},
+		{
+			"type": "CALL",
+			"from": "0x0d0f936ee4c93e25944694d6c121de94d9760f11",
+			"to": "0x555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+			"value": "0x1",
+			"error": "execution reverted",
+			"logs": [
+				{
+					"address": "0x4af4114f73d1c1c903ac9e0361b379d1291808a2",
+					"topics": [
+						"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+						"0x000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+						"0x0000000000000000000000007b62eb7fe80350dc7ec945c0b73242cb9877fb1b"
+					],
+					"data": "0x0000000000000000000000000000000000000000000000000000000000000001"
+				}
+			]
+		},
+		{
+			"type": "CREATE2",
+			"from": "0x0d0f936ee4c93e25944694d6c121de94d9760f11",
+			"to": "0x555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+			"value": "0x0"
+		}
+	]
+}`
+
+func TestSimulationFromTrace(t *testing.T) {
+	var trace rpcSimulationTrace
+	if err := json.Unmarshal([]byte(testSimulationTrace), &trace); err != nil {
+		t.Fatal(err)
+	}
+	got, err := simulationFromTrace(&trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != bchain.CALL || got.From != "0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D" || got.To != "0x0d0F936Ee4c93e25944694D6C121de94D9760F11" ||
+		got.Value.String() != "1000000000000000000" || got.GasUsed != 120000 || got.Error != "" {
+		t.Errorf("simulationFromTrace() = %+v", got)
+	}
+	wantTransfers := []struct {
+		t     bchain.EthereumInternalTransactionType
+		from  string
+		to    string
+		value string
+	}{
+		{bchain.CALL, "0x0d0F936Ee4c93e25944694D6C121de94D9760F11", "0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b", "10000000000000000"},
+		{bchain.CREATE, "0x0d0F936Ee4c93e25944694D6C121de94D9760F11", "0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f", "0"},
+	}
+	if len(got.Transfers) != len(wantTransfers) {
+		t.Fatalf("simulationFromTrace() transfers = %+v", got.Transfers)
+	}
+	for i, w := range wantTransfers {
+		tr := &got.Transfers[i]
+		if tr.Type != w.t || tr.From != w.from || tr.To != w.to || tr.Value.String() != w.value {
+			t.Errorf("simulationFromTrace() transfer %d = %+v, want %+v", i, tr, w)
+		}
+	}
+	if len(got.TokenTransfers) != 1 {
+		t.Fatalf("simulationFromTrace() token transfers = %+v", got.TokenTransfers)
+	}
+	tt := got.TokenTransfers[0]
+	if tt.Standard != bchain.FungibleToken || tt.Contract != "0x4af4114F73d1c1C903aC9E0361b379D1291808A2" ||
+		tt.From != "0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D" || tt.To != "0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f" || tt.Value.String() != "1000" {
+		t.Errorf("simulationFromTrace() token transfer = %+v", tt)
+	}
+}
+
+func TestSimulationFromTrace_Reverted(t *testing.T) {
+	trace := rpcSimulationTrace{
+		Type:    "CALL",
+		From:    "0x4bda106325c335df99eab7fe363cac8a0ba2a24d",
+		To:      "0x0d0f936ee4c93e25944694d6c121de94d9760f11",
+		GasUsed: "0x5f5e",
+		Error:   "execution reverted",
+		Output:  "0x4e487b710000000000000000000000000000000000000000000000000000000000000011",
+		Calls: []rpcSimulationTrace{
+			{Type: "CALL", From: "0x0d0f936ee4c93e25944694d6c121de94d9760f11", To: "0x7b62eb7fe80350dc7ec945c0b73242cb9877fb1b", Value: "0x1"},
+		},
+	}
+	got, err := simulationFromTrace(&trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Error != "execution reverted" || got.Output != trace.Output || got.GasUsed != 24414 || len(got.Transfers) != 0 || len(got.TokenTransfers) != 0 {
+		t.Errorf("simulationFromTrace() = %+v", got)
+	}
+}
+
+type mockGasPriceRPC struct {
+	baseFee string
+}
+
+func (m *mockGasPriceRPC) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (bchain.EVMClientSubscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockGasPriceRPC) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	switch method {
+	case "eth_gasPrice":
+		return json.Unmarshal([]byte(`"0x3b9aca00"`), result)
+	case "eth_getBlockByNumber":
+		if m.baseFee == "" {
+			return json.Unmarshal([]byte(`{"number":"0x64"}`), result)
+		}
+		return json.Unmarshal([]byte(`{"number":"0x64","baseFeePerGas":"`+m.baseFee+`"}`), result)
+	}
+	return errors.New("unexpected method " + method)
+}
+
+func (m *mockGasPriceRPC) Close() {}
+
+func TestEthereumRPC_simulationGasPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseFee string
+		args    map[string]interface{}
+		want    int64
+	}{
+		{"legacy", "0x64", map[string]interface{}{"gasPrice": "0x2540be400"}, 10000000000},
+		{"suggested", "0x64", map[string]interface{}{}, 1000000000},
+		{"base fee and tip", "0x3e8", map[string]interface{}{"maxFeePerGas": "0x7d0", "maxPriorityFeePerGas": "0x64"}, 1100},
+		{"capped by max fee", "0x3e8", map[string]interface{}{"maxFeePerGas": "0x41a", "maxPriorityFeePerGas": "0x64"}, 1050},
+		{"no tip", "0x3e8", map[string]interface{}{"maxFeePerGas": "0x7d0"}, 1000},
+		{"no base fee", "", map[string]interface{}{"maxFeePerGas": "0x7d0"}, 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &EthereumRPC{RPC: &mockGasPriceRPC{baseFee: tt.baseFee}}
+			got, err := b.simulationGasPrice(context.Background(), tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Int64() != tt.want {
+				t.Errorf("simulationGasPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
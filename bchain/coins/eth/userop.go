@@ -0,0 +1,175 @@
+package eth
+
+import (
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// UserOperationEvent(bytes32 indexed userOpHash, address indexed sender, address indexed paymaster, uint256 nonce, bool success, uint256 actualGasCost, uint256 actualGasUsed)
+const userOperationEventSignature = "0x49628fd1471006c1482da88028e9ce4dbb080b815c9b0344d39e5a8e6ec1419f"
+
+const handleOpsV06MethodSignature = "0x1fad948c" // handleOps((address,uint256,bytes,bytes,uint256,uint256,uint256,uint256,uint256,bytes,bytes)[],address)
+const handleOpsV07MethodSignature = "0x765e827f" // handleOps((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes)[],address)
+
+// entryPointAddresses are the lowercase addresses of the deployed ERC-4337 EntryPoint contracts (v0.6, v0.7 and v0.8)
+// the UserOperationEvent logs emitted by other contracts are ignored, anybody can emit a log with the same signature
+var entryPointAddresses = map[string]struct{}{
+	"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789": {},
+	"0x0000000071727de22e5e9d8baf0edac6f37da032": {},
+	"0x4337084d9e255ff0702461cf8895ce9e3b5ff108": {},
+}
+
+const handleOpsABI = `[
+	{"type":"function","name":"handleOps","inputs":[{"name":"ops","type":"tuple[]","components":[
+		{"name":"sender","type":"address"},{"name":"nonce","type":"uint256"},{"name":"initCode","type":"bytes"},{"name":"callData","type":"bytes"},
+		{"name":"callGasLimit","type":"uint256"},{"name":"verificationGasLimit","type":"uint256"},{"name":"preVerificationGas","type":"uint256"},
+		{"name":"maxFeePerGas","type":"uint256"},{"name":"maxPriorityFeePerGas","type":"uint256"},{"name":"paymasterAndData","type":"bytes"},{"name":"signature","type":"bytes"}]},
+		{"name":"beneficiary","type":"address"}],"outputs":[]},
+	{"type":"function","name":"handleOps","inputs":[{"name":"ops","type":"tuple[]","components":[
+		{"name":"sender","type":"address"},{"name":"nonce","type":"uint256"},{"name":"initCode","type":"bytes"},{"name":"callData","type":"bytes"},
+		{"name":"accountGasLimits","type":"bytes32"},{"name":"preVerificationGas","type":"uint256"},{"name":"gasFees","type":"bytes32"},
+		{"name":"paymasterAndData","type":"bytes"},{"name":"signature","type":"bytes"}]},
+		{"name":"beneficiary","type":"address"}],"outputs":[]}
+]`
+
+var parsedHandleOpsABI abi.ABI
+
+func init() {
+	var err error
+	parsedHandleOpsABI, err = abi.JSON(strings.NewReader(handleOpsABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// IsEntryPointAddress returns true if the address is one of the known ERC-4337 EntryPoint contracts
+func IsEntryPointAddress(address string) bool {
+	_, found := entryPointAddresses[strings.ToLower(address)]
+	return found
+}
+
+func processUserOperationEvent(l *bchain.RpcLog) *bchain.EthereumUserOperation {
+	if len(l.Topics) != 4 || l.Topics[0] != userOperationEventSignature || !IsEntryPointAddress(l.Address) {
+		return nil
+	}
+	data := l.Data
+	if has0xPrefix(data) {
+		data = data[2:]
+	}
+	if len(data) != 4*64 {
+		return nil
+	}
+	sender, err := addressFromPaddedHex(l.Topics[2])
+	if err != nil {
+		return nil
+	}
+	paymaster, err := addressFromPaddedHex(l.Topics[3])
+	if err != nil {
+		return nil
+	}
+	if paymaster == (ethcommon.Address{}).String() {
+		paymaster = ""
+	}
+	op := bchain.EthereumUserOperation{
+		EntryPoint: EIP55AddressFromAddress(l.Address),
+		UserOpHash: l.Topics[1],
+		Sender:     sender,
+		Paymaster:  paymaster,
+	}
+	var ok bool
+	if _, ok = op.Nonce.SetString(data[:64], 16); !ok {
+		return nil
+	}
+	var success big.Int
+	if _, ok = success.SetString(data[64:128], 16); !ok {
+		return nil
+	}
+	op.Success = success.Sign() != 0
+	if _, ok = op.ActualGasCost.SetString(data[128:192], 16); !ok {
+		return nil
+	}
+	if _, ok = op.ActualGasUsed.SetString(data[192:256], 16); !ok {
+		return nil
+	}
+	return &op
+}
+
+type userOperationCall struct {
+	sender   ethcommon.Address
+	nonce    *big.Int
+	callData []byte
+}
+
+// parseHandleOpsCallData decodes the UserOperations bundled in the input data of the handleOps call of the EntryPoint
+func parseHandleOpsCallData(data string) (calls []userOperationCall) {
+	if len(data) < 10 || (data[:10] != handleOpsV06MethodSignature && data[:10] != handleOpsV07MethodSignature) {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("parseHandleOpsCallData recovered from panic: ", r, ", ", data)
+			debug.PrintStack()
+			calls = nil
+		}
+	}()
+	b, err := hex.DecodeString(data[2:])
+	if err != nil {
+		return nil
+	}
+	method, err := parsedHandleOpsABI.MethodById(b[:4])
+	if err != nil {
+		return nil
+	}
+	values, err := method.Inputs.Unpack(b[4:])
+	if err != nil || len(values) != 2 {
+		return nil
+	}
+	ops := reflect.ValueOf(values[0])
+	calls = make([]userOperationCall, ops.Len())
+	for i := range calls {
+		op := ops.Index(i)
+		calls[i] = userOperationCall{
+			sender:   op.FieldByName("Sender").Interface().(ethcommon.Address),
+			nonce:    op.FieldByName("Nonce").Interface().(*big.Int),
+			callData: op.FieldByName("CallData").Interface().([]byte),
+		}
+	}
+	return calls
+}
+
+// GetUserOperationsFromTx returns the ERC-4337 UserOperations executed in the transaction, detected by the UserOperationEvent logs
+// of the EntryPoint contract, the call data of the operations are decoded from the transaction input data if it is a handleOps call
+func GetUserOperationsFromTx(tx *bchain.Tx) []bchain.EthereumUserOperation {
+	var ops []bchain.EthereumUserOperation
+	for _, l := range GetEthereumTxLogs(tx) {
+		if op := processUserOperationEvent(l); op != nil {
+			ops = append(ops, *op)
+		}
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	csd, ok := tx.CoinSpecificData.(bchain.EthereumSpecificData)
+	if ok && csd.Tx != nil && IsEntryPointAddress(csd.Tx.To) {
+		calls := parseHandleOpsCallData(csd.Tx.Payload)
+		for i := range ops {
+			op := &ops[i]
+			for j := range calls {
+				c := &calls[j]
+				if strings.EqualFold(c.sender.Hex(), op.Sender) && c.nonce.Cmp(&op.Nonce) == 0 {
+					op.CallData = "0x" + hex.EncodeToString(c.callData)
+					break
+				}
+			}
+		}
+	}
+	return ops
+}
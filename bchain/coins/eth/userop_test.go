@@ -0,0 +1,102 @@
+//go:build unittest
+
+package eth
+
+import (
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/trezor/blockbook/bchain"
+)
+
+type testPackedUserOperation struct {
+	Sender             ethcommon.Address
+	Nonce              *big.Int
+	InitCode           []byte
+	CallData           []byte
+	AccountGasLimits   [32]byte
+	PreVerificationGas *big.Int
+	GasFees            [32]byte
+	PaymasterAndData   []byte
+	Signature          []byte
+}
+
+func TestGetUserOperationsFromTx(t *testing.T) {
+	entryPoint := "0x0000000071727de22e5e9d8baf0edac6f37da032"
+	sender := ethcommon.HexToAddress("0x555ee11fbddc0e49a9bab358a8941ad95ffdb48f")
+	callData, _ := hex.DecodeString("a9059cbb0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d00000000000000000000000000000000000000000000000000000000000f4240")
+	payload, err := parsedHandleOpsABI.Pack("handleOps0", []testPackedUserOperation{
+		{
+			Sender:             sender,
+			Nonce:              big.NewInt(7),
+			CallData:           callData,
+			PreVerificationGas: big.NewInt(50000),
+		},
+	}, ethcommon.HexToAddress("0x4bda106325c335df99eab7fe363cac8a0ba2a24d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := []*bchain.RpcLog{
+		{
+			Address: entryPoint,
+			Topics: []string{
+				userOperationEventSignature,
+				"0x8a7b4c0e1e0a2f0a4b4e1c7f4f3b6c3e3f9d1a2b3c4d5e6f708192a3b4c5d6e7",
+				"0x000000000000000000000000555ee11fbddc0e49a9bab358a8941ad95ffdb48f",
+				"0x0000000000000000000000000000000000000000000000000000000000000000",
+			},
+			Data: "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000007" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"00000000000000000000000000000000000000000000000000005af3107a4000" +
+				"000000000000000000000000000000000000000000000000000000000001d4c0",
+		},
+		{
+			// the event emitted by a contract which is not an EntryPoint is ignored
+			Address: "0x4bda106325c335df99eab7fe363cac8a0ba2a24d",
+			Topics: []string{
+				userOperationEventSignature,
+				"0x8a7b4c0e1e0a2f0a4b4e1c7f4f3b6c3e3f9d1a2b3c4d5e6f708192a3b4c5d6e7",
+				"0x0000000000000000000000004bda106325c335df99eab7fe363cac8a0ba2a24d",
+				"0x0000000000000000000000000000000000000000000000000000000000000000",
+			},
+			Data: "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000001",
+		},
+	}
+	tx := &bchain.Tx{
+		CoinSpecificData: bchain.EthereumSpecificData{
+			Tx: &bchain.RpcTransaction{
+				To:      entryPoint,
+				Payload: "0x" + hex.EncodeToString(payload),
+			},
+			Receipt: &bchain.RpcReceipt{Logs: logs},
+		},
+	}
+	want := []bchain.EthereumUserOperation{
+		{
+			EntryPoint:    "0x0000000071727De22E5E9d8BAf0edAc6f37da032",
+			UserOpHash:    "0x8a7b4c0e1e0a2f0a4b4e1c7f4f3b6c3e3f9d1a2b3c4d5e6f708192a3b4c5d6e7",
+			Sender:        "0x555Ee11FBDDc0E49A9bAB358A8941AD95fFDB48f",
+			Nonce:         *big.NewInt(7),
+			Success:       true,
+			ActualGasCost: *big.NewInt(100000000000000),
+			ActualGasUsed: *big.NewInt(120000),
+			CallData:      "0x" + hex.EncodeToString(callData),
+		},
+	}
+	got := GetUserOperationsFromTx(tx)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUserOperationsFromTx() = %+v, want %+v", got, want)
+	}
+	tx.CoinSpecificData = bchain.EthereumSpecificData{Tx: &bchain.RpcTransaction{To: entryPoint}, Receipt: &bchain.RpcReceipt{Logs: logs[1:]}}
+	if got := GetUserOperationsFromTx(tx); got != nil {
+		t.Errorf("GetUserOperationsFromTx() = %+v, want nil", got)
+	}
+}
@@ -348,9 +348,15 @@ type BlockChain interface {
 	EthereumTypeGetEip1559Fees() (*Eip1559Fees, error)
 	EthereumTypeGetErc20ContractBalance(addrDesc, contractDesc AddressDescriptor) (*big.Int, error)
 	EthereumTypeGetErc20ContractBalances(addrDesc AddressDescriptor, contractDescs []AddressDescriptor) ([]*big.Int, error)
+	EthereumTypeGetBalanceAtBlock(addrDesc AddressDescriptor, blockNumber *big.Int) (*big.Int, error)
+	EthereumTypeGetNonceAtBlock(addrDesc AddressDescriptor, blockNumber *big.Int) (uint64, error)
+	EthereumTypeGetErc20ContractBalanceAtBlock(addrDesc, contractDesc AddressDescriptor, blockNumber *big.Int) (*big.Int, error)
+	EthereumTypeGetErc20ContractBalancesAtBlock(addrDesc AddressDescriptor, contractDescs []AddressDescriptor, blockNumber *big.Int) ([]*big.Int, error)
 	EthereumTypeGetSupportedStakingPools() []string
 	EthereumTypeGetStakingPoolsData(addrDesc AddressDescriptor) ([]StakingPoolData, error)
 	EthereumTypeRpcCall(data, to, from string) (string, error)
+	EthereumTypeGetRevertData(tx *Tx, blockNumber *big.Int) (string, error)
+	EthereumTypeSimulateTransaction(params map[string]interface{}) (*EthereumSimulation, error)
 	EthereumTypeGetRawTransaction(txid string) (string, error)
 	GetTokenURI(contractDesc AddressDescriptor, tokenID *big.Int) (string, error)
 }
@@ -390,6 +396,7 @@ type BlockChainParser interface {
 	PackTx(tx *Tx, height uint32, blockTime int64) ([]byte, error)
 	UnpackTx(buf []byte) (*Tx, uint32, error)
 	GetAddrDescForUnknownInput(tx *Tx, input int) AddressDescriptor
+	IsAssetTx(tx *Tx) bool
 	// blocks
 	PackBlockHash(hash string) ([]byte, error)
 	UnpackBlockHash(buf []byte) (string, error)
@@ -1,6 +1,7 @@
 package bchain
 
 import (
+	"encoding/json"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -27,6 +28,7 @@ type FourByteSignature struct {
 
 // EthereumParsedInputParam contains data about a contract function parameter
 type EthereumParsedInputParam struct {
+	Name   string   `json:"name,omitempty" ts_doc:"Parameter name, known only if the input data were decoded by the contract ABI."`
 	Type   string   `json:"type" ts_doc:"Parameter type (e.g. 'uint256')."`
 	Values []string `json:"values,omitempty" ts_doc:"List of stringified parameter values."`
 }
@@ -39,6 +41,67 @@ type EthereumParsedInputData struct {
 	Params   []EthereumParsedInputParam `json:"params,omitempty" ts_doc:"List of parsed parameters for this function call."`
 }
 
+// EventSignatureParam is a parameter of a contract event
+type EventSignatureParam struct {
+	Name    string `json:"name,omitempty" ts_doc:"Parameter name, empty if not known."`
+	Type    string `json:"type" ts_doc:"Parameter type (e.g. 'uint256')."`
+	Indexed bool   `json:"indexed,omitempty" ts_doc:"True if the parameter is stored in the topics of the log."`
+}
+
+// EventSignature contains the ABI of a contract event identified by the hash of its signature (topic0 of the log)
+type EventSignature struct {
+	// stored in DB
+	Name   string                `json:"name" ts_doc:"Event name."`
+	Inputs []EventSignatureParam `json:"inputs" ts_doc:"Event parameters."`
+	// IndexedUnknown is set for the signatures without the information which parameters are indexed (e.g. from 4byte.directory)
+	IndexedUnknown bool `json:"indexedUnknown,omitempty" ts_doc:"True if it is not known which parameters are indexed."`
+	// processed from DB data and stored only in cache
+	Event        string     `json:"-" ts_doc:"Reconstructed event definition string (e.g. 'Transfer(address,address,uint256)')."`
+	ParsedInputs []abi.Type `json:"-" ts_doc:"ABI-parsed parameter types (cached for efficiency)."`
+}
+
+// EthereumParsedLogParam contains a decoded parameter of a contract event log
+type EthereumParsedLogParam struct {
+	Name    string   `json:"name,omitempty" ts_doc:"Parameter name, if known."`
+	Type    string   `json:"type" ts_doc:"Parameter type (e.g. 'uint256')."`
+	Indexed bool     `json:"indexed,omitempty" ts_doc:"True if the parameter was decoded from the topics of the log."`
+	Values  []string `json:"values,omitempty" ts_doc:"List of stringified parameter values, indexed dynamic types are returned as their keccak256 hash."`
+}
+
+// EthereumParsedLog contains a contract event log, decoded if the event signature is known
+type EthereumParsedLog struct {
+	Address string                   `json:"address" ts_doc:"Address of the contract which emitted the log."`
+	Topic   string                   `json:"topic,omitempty" ts_doc:"First topic of the log (hash of the event signature)."`
+	Name    string                   `json:"name,omitempty" ts_doc:"Event name if the log was decoded."`
+	Event   string                   `json:"event,omitempty" ts_doc:"Full event signature (including parameter types) if the log was decoded."`
+	Params  []EthereumParsedLogParam `json:"params,omitempty" ts_doc:"Decoded parameters of the event."`
+	Topics  []string                 `json:"topics,omitempty" ts_doc:"Raw topics of the log which was not decoded."`
+	Data    string                   `json:"data,omitempty" ts_doc:"Raw data of the log which was not decoded."`
+}
+
+// EthereumUserOperation contains data about an ERC-4337 UserOperation executed by the EntryPoint contract
+type EthereumUserOperation struct {
+	EntryPoint    string  `json:"entryPoint" ts_doc:"Address of the EntryPoint contract which executed the UserOperation."`
+	UserOpHash    string  `json:"userOpHash" ts_doc:"Hash of the UserOperation."`
+	Sender        string  `json:"sender" ts_doc:"Address of the smart account which sent the UserOperation."`
+	Paymaster     string  `json:"paymaster,omitempty" ts_doc:"Address of the paymaster which paid for the UserOperation, empty if paid by the sender."`
+	Nonce         big.Int `json:"nonce" ts_doc:"Nonce of the UserOperation."`
+	Success       bool    `json:"success" ts_doc:"True if the execution of the UserOperation succeeded."`
+	ActualGasCost big.Int `json:"actualGasCost" ts_doc:"Actual cost of the UserOperation (in Wei)."`
+	ActualGasUsed big.Int `json:"actualGasUsed" ts_doc:"Actual gas used by the UserOperation."`
+	CallData      string  `json:"callData,omitempty" ts_doc:"Data of the call of the smart account, decoded from the handleOps call."`
+}
+
+// TokenApproval is an ERC20 Approval or ERC721/ERC1155 ApprovalForAll event emitted by a token contract
+type TokenApproval struct {
+	Contract string
+	Owner    string
+	Spender  string
+	// ForAll is set for the ApprovalForAll events, Value is then 1 if the operator is approved, 0 if revoked
+	ForAll bool
+	Value  big.Int
+}
+
 // EthereumInternalTransactionType - type of ethereum transaction from internal data
 type EthereumInternalTransactionType int
 
@@ -57,6 +120,22 @@ type EthereumInternalData struct {
 	Error     string                          `ts_doc:"Error message if something went wrong while processing."`
 }
 
+// EthereumSimulation contains the predicted effects of a transaction simulated in the state of the latest block
+type EthereumSimulation struct {
+	Type    EthereumInternalTransactionType // CALL or CREATE
+	From    string
+	To      string // called address or the address of the created contract
+	Value   big.Int
+	GasUsed uint64
+	// effective gas price paid by the transaction if it was sent now, nil if it cannot be determined
+	GasPrice *big.Int
+	Error    string // error of the simulated transaction, empty if the transaction succeeds
+	Output   string // output of the transaction, revert data if the transaction fails
+	// internal value transfers and token transfers of the successful calls, empty if the transaction fails
+	Transfers      []EthereumInternalTransfer
+	TokenTransfers TokenTransfers
+}
+
 // ContractInfo contains info about a contract
 type ContractInfo struct {
 	// Deprecated: Use Standard instead.
@@ -68,6 +147,21 @@ type ContractInfo struct {
 	Decimals          int               `json:"decimals" ts_doc:"Number of decimal places, if applicable."`
 	CreatedInBlock    uint32            `json:"createdInBlock,omitempty" ts_doc:"Block height where contract was first created."`
 	DestructedInBlock uint32            `json:"destructedInBlock,omitempty" ts_doc:"Block height where contract was destroyed (if any)."`
+	CreatedBy         string            `json:"createdBy,omitempty" ts_doc:"Address which created the contract, known only if the internal transactions are processed."`
+	CreationTx        string            `json:"creationTx,omitempty" ts_doc:"Transaction which created the contract, known only if the internal transactions are processed."`
+}
+
+// ContractABI contains the ABI and the verified source metadata of a contract, uploaded by the operator of Blockbook
+type ContractABI struct {
+	Contract        string          `json:"contract" ts_doc:"Smart contract address."`
+	ABI             json.RawMessage `json:"abi" ts_doc:"JSON ABI of the contract."`
+	ContractName    string          `json:"contractName,omitempty" ts_doc:"Name of the contract in the verified source code."`
+	CompilerVersion string          `json:"compilerVersion,omitempty" ts_doc:"Version of the compiler used to compile the verified source code."`
+	License         string          `json:"license,omitempty" ts_doc:"License of the verified source code."`
+	SourceURL       string          `json:"sourceUrl,omitempty" ts_doc:"URL of the verified source code."`
+	Updated         int64           `json:"updated,omitempty" ts_doc:"Unix timestamp of the last upload of the ABI."`
+	// processed from DB data and stored only in cache
+	ParsedABI *abi.ABI `json:"-" ts_doc:"Parsed ABI (cached for efficiency)."`
 }
 
 // Ethereum token standard names
@@ -104,15 +198,18 @@ type RpcTransaction struct {
 	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
 	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
 	BaseFeePerGas        string `json:"baseFeePerGas,omitempty"`
-	GasLimit             string `json:"gas" ts_doc:"Maximum gas allowed for this transaction."`
-	To                   string `json:"to" ts_doc:"Recipient address if not a contract creation. Empty if it's contract creation."`
-	Value                string `json:"value" ts_doc:"Amount of Ether (in Wei) sent in this transaction."`
-	Payload              string `json:"input" ts_doc:"Hex-encoded input data for contract calls."`
-	Hash                 string `json:"hash" ts_doc:"Transaction hash."`
-	BlockNumber          string `json:"blockNumber" ts_doc:"Block number where this transaction was included, if mined."`
-	BlockHash            string `json:"blockHash,omitempty" ts_doc:"Hash of the block in which this transaction was included, if mined."`
-	From                 string `json:"from" ts_doc:"Sender's address derived by the backend."`
-	TransactionIndex     string `json:"transactionIndex" ts_doc:"Index of the transaction within the block, if mined."`
+	MaxFeePerBlobGas     string `json:"maxFeePerBlobGas,omitempty" ts_doc:"Maximum fee per blob gas of the EIP-4844 blob transaction."`
+	// BlobVersionedHashes are the versioned hashes of the blobs of the EIP-4844 blob transaction
+	BlobVersionedHashes []string `json:"blobVersionedHashes,omitempty" ts_doc:"Versioned hashes of the blobs of the EIP-4844 blob transaction."`
+	GasLimit            string   `json:"gas" ts_doc:"Maximum gas allowed for this transaction."`
+	To                  string   `json:"to" ts_doc:"Recipient address if not a contract creation. Empty if it's contract creation."`
+	Value               string   `json:"value" ts_doc:"Amount of Ether (in Wei) sent in this transaction."`
+	Payload             string   `json:"input" ts_doc:"Hex-encoded input data for contract calls."`
+	Hash                string   `json:"hash" ts_doc:"Transaction hash."`
+	BlockNumber         string   `json:"blockNumber" ts_doc:"Block number where this transaction was included, if mined."`
+	BlockHash           string   `json:"blockHash,omitempty" ts_doc:"Hash of the block in which this transaction was included, if mined."`
+	From                string   `json:"from" ts_doc:"Sender's address derived by the backend."`
+	TransactionIndex    string   `json:"transactionIndex" ts_doc:"Index of the transaction within the block, if mined."`
 	// Signature values - ignored
 	// V string `json:"v"`
 	// R string `json:"r"`
@@ -128,13 +225,15 @@ type RpcLog struct {
 
 // RpcReceipt is returned by eth_getTransactionReceipt
 type RpcReceipt struct {
-	GasUsed     string    `json:"gasUsed" ts_doc:"Amount of gas actually used by the transaction."`
-	Status      string    `json:"status" ts_doc:"Transaction execution status (0x0 = fail, 0x1 = success)."`
-	Logs        []*RpcLog `json:"logs" ts_doc:"Array of log entries generated by this transaction."`
-	L1Fee       string    `json:"l1Fee,omitempty" ts_doc:"Additional Layer 1 fee, if on a rollup network."`
-	L1FeeScalar string    `json:"l1FeeScalar,omitempty" ts_doc:"Fee scaling factor for L1 fees on some L2s."`
-	L1GasPrice  string    `json:"l1GasPrice,omitempty" ts_doc:"Gas price used on L1 for the rollup network."`
-	L1GasUsed   string    `json:"l1GasUsed,omitempty" ts_doc:"Amount of L1 gas used by the transaction, if any."`
+	GasUsed      string    `json:"gasUsed" ts_doc:"Amount of gas actually used by the transaction."`
+	Status       string    `json:"status" ts_doc:"Transaction execution status (0x0 = fail, 0x1 = success)."`
+	Logs         []*RpcLog `json:"logs" ts_doc:"Array of log entries generated by this transaction."`
+	L1Fee        string    `json:"l1Fee,omitempty" ts_doc:"Additional Layer 1 fee, if on a rollup network."`
+	L1FeeScalar  string    `json:"l1FeeScalar,omitempty" ts_doc:"Fee scaling factor for L1 fees on some L2s."`
+	L1GasPrice   string    `json:"l1GasPrice,omitempty" ts_doc:"Gas price used on L1 for the rollup network."`
+	L1GasUsed    string    `json:"l1GasUsed,omitempty" ts_doc:"Amount of L1 gas used by the transaction, if any."`
+	BlobGasUsed  string    `json:"blobGasUsed,omitempty" ts_doc:"Amount of blob gas used by the EIP-4844 blob transaction."`
+	BlobGasPrice string    `json:"blobGasPrice,omitempty" ts_doc:"Price of the blob gas paid by the EIP-4844 blob transaction."`
 }
 
 // EthereumSpecificData contains data specific to Ethereum transactions
@@ -181,6 +280,7 @@ type Eip1559Fee struct {
 // Eip1559Fees
 type Eip1559Fees struct {
 	BaseFeePerGas              *big.Int    `json:"baseFeePerGas,omitempty"`
+	BlobBaseFeePerGas          *big.Int    `json:"blobBaseFeePerGas,omitempty"`
 	Low                        *Eip1559Fee `json:"low,omitempty"`
 	Medium                     *Eip1559Fee `json:"medium,omitempty"`
 	High                       *Eip1559Fee `json:"high,omitempty"`
@@ -23,6 +23,8 @@ export interface EthereumInternalTransfer {
     value: string;
 }
 export interface EthereumParsedInputParam {
+    /** Parameter name, known only if the input data were decoded by the contract ABI. */
+    name?: string;
     /** Parameter type (e.g. 'uint256'). */
     type: string;
     /** List of stringified parameter values. */
@@ -38,6 +40,54 @@ export interface EthereumParsedInputData {
     /** List of parsed parameters for this function call. */
     params?: EthereumParsedInputParam[];
 }
+export interface EthereumParsedLogParam {
+    /** Parameter name, if known. */
+    name?: string;
+    /** Parameter type (e.g. 'uint256'). */
+    type: string;
+    /** True if the parameter was decoded from the topics of the log. */
+    indexed?: boolean;
+    /** List of stringified parameter values, indexed dynamic types are returned as their keccak256 hash. */
+    values?: string[];
+}
+export interface EthereumParsedLog {
+    /** Address of the contract which emitted the log. */
+    address: string;
+    /** First topic of the log (hash of the event signature). */
+    topic?: string;
+    /** Event name if the log was decoded. */
+    name?: string;
+    /** Full event signature (including parameter types) if the log was decoded. */
+    event?: string;
+    /** Decoded parameters of the event. */
+    params?: EthereumParsedLogParam[];
+    /** Raw topics of the log which was not decoded. */
+    topics?: string[];
+    /** Raw data of the log which was not decoded. */
+    data?: string;
+}
+export interface EthereumUserOperation {
+    /** Address of the EntryPoint contract which executed the UserOperation. */
+    entryPoint: string;
+    /** Hash of the UserOperation. */
+    userOpHash: string;
+    /** Address of the smart account which sent the UserOperation. */
+    sender: string;
+    /** Address of the paymaster which paid for the UserOperation, empty if paid by the sender. */
+    paymaster?: string;
+    /** Nonce of the UserOperation (decimal string, the upper 192 bits are the nonce key). */
+    nonce: string;
+    /** True if the execution of the UserOperation succeeded. */
+    success: boolean;
+    /** Actual cost of the UserOperation paid to the bundler (in Wei). */
+    actualGasCost: string;
+    /** Actual gas used by the UserOperation. */
+    actualGasUsed: number;
+    /** Data of the call of the smart account, if decoded from the handleOps call. */
+    callData?: string;
+    /** Decoded call data of the smart account (function name, params, etc.). */
+    parsedCallData?: EthereumParsedInputData;
+}
 export interface EthereumSpecific {
     /** High-level type of the Ethereum tx (e.g., 'call', 'create'). */
     type?: number;
@@ -47,6 +97,8 @@ export interface EthereumSpecific {
     status: number;
     /** Error encountered during execution, if any. */
     error?: string;
+    /** Decoded reason of the revert of the failed transaction without the error from the traces, returned only in the transaction detail. */
+    revertReason?: string;
     /** Transaction nonce (sequential number from the sender). */
     nonce: number;
     /** Maximum gas allowed by the sender for this transaction. */
@@ -66,12 +118,24 @@ export interface EthereumSpecific {
     l1GasPrice?: string;
     /** Amount of gas used in L1 for this tx, if applicable. */
     l1GasUsed?: number;
+    /** Maximum fee per blob gas of the EIP-4844 blob transaction. */
+    maxFeePerBlobGas?: string;
+    /** Amount of blob gas used by the EIP-4844 blob transaction. */
+    blobGasUsed?: number;
+    /** Price per blob gas paid by the EIP-4844 blob transaction. */
+    blobGasPrice?: string;
+    /** Versioned hashes of the blobs of the EIP-4844 blob transaction. */
+    blobVersionedHashes?: string[];
     /** Hex-encoded input data for the transaction. */
     data?: string;
     /** Decoded transaction data (function name, params, etc.). */
     parsedData?: EthereumParsedInputData;
+    /** Logs of the transaction, decoded by the known event signatures, omitted if no log can be decoded. */
+    parsedLogs?: EthereumParsedLog[];
     /** List of internal (sub-call) transfers. */
     internalTransfers?: EthereumInternalTransfer[];
+    /** ERC-4337 UserOperations executed in the transaction. */
+    userOperations?: EthereumUserOperation[];
 }
 export interface MultiTokenValue {
     /** Token ID (for ERC1155). */
@@ -242,6 +306,26 @@ export interface ContractInfo {
     createdInBlock?: number;
     /** Block height where contract was destroyed (if any). */
     destructedInBlock?: number;
+    /** Address which created the contract, known only if the internal transactions are processed. */
+    createdBy?: string;
+    /** Transaction which created the contract, known only if the internal transactions are processed. */
+    creationTx?: string;
+}
+export interface ContractABI {
+    /** Smart contract address. */
+    contract: string;
+    /** JSON ABI of the contract. */
+    abi: any;
+    /** Name of the contract in the verified source code. */
+    contractName?: string;
+    /** Version of the compiler used to compile the verified source code. */
+    compilerVersion?: string;
+    /** License of the verified source code. */
+    license?: string;
+    /** URL of the verified source code. */
+    sourceUrl?: string;
+    /** Unix timestamp of the last upload of the ABI. */
+    updated?: number;
 }
 export interface Token {
     /** @deprecated: Use standard instead. */
@@ -265,6 +349,8 @@ export interface Token {
     baseValue?: number;
     /** Value in a secondary currency (e.g. fiat), if available. */
     secondaryValue?: number;
+    /** True if the token has a balance but there is no fiat rate for it, its value is not included in the totals. */
+    noRate?: boolean;
     /** List of token IDs (for ERC721, each ID is a unique collectible). */
     ids?: string[];
     /** Multiple ERC1155 token balances (id + value). */
@@ -315,6 +401,8 @@ export interface Address {
     usedTokens?: number;
     /** List of tokens associated with this address. */
     tokens?: Token[];
+    /** Secondary currency (e.g. 'usd') of the secondary values, set if the rate of the currency is available. */
+    secondaryCurrency?: string;
     /** Total value of the address in secondary currency (e.g. fiat). */
     secondaryValue?: number;
     /** Sum of token values in base currency. */
@@ -327,12 +415,20 @@ export interface Address {
     totalSecondaryValue?: number;
     /** Extra info if the address is a contract (ABI, type). */
     contractInfo?: ContractInfo;
+    /** ABI and verified source metadata of the contract, if uploaded by the operator. */
+    contractAbi?: ContractABI;
     /** @deprecated: replaced by contractInfo */
     erc20Contract?: ContractInfo;
     /** Aliases assigned to this address. */
     addressAliases?: { [key: string]: AddressAlias };
     /** List of staking pool data if address interacts with staking. */
     stakingPools?: StakingPool[];
+    /** Height of the block of the point-in-time snapshot of the account, set only if requested by atBlock. */
+    atBlockHeight?: number;
+    /** Hash of the block of the point-in-time snapshot of the account, set only if requested by atBlock. */
+    atBlockHash?: string;
+    /** Time of the block of the point-in-time snapshot of the account, set only if requested by atBlock. */
+    atBlockTime?: number;
 }
 export interface Utxo {
     /** Transaction ID in which this UTXO was created. */
@@ -369,6 +465,287 @@ export interface BalanceHistory {
     rates?: { [key: string]: number };
     /** Transaction ID if the time corresponds to a specific tx. */
     txid?: string;
+    /** Realized gains of the amounts sent in this interval by currency, if gains were requested. */
+    realizedGains?: { [key: string]: number };
+}
+export interface BalanceHistoryGains {
+    /** Unix timestamp of the valuation, the end of the range or the current time. */
+    time: number;
+    /** Exchange rates used for the valuation, -1 if not available. */
+    rates: { [key: string]: number };
+    /** Cost basis of the balance by currency. */
+    costBasis: { [key: string]: number };
+    /** Unrealized gains of the balance by currency. */
+    unrealizedGains: { [key: string]: number };
+}
+export interface BalanceHistoryWithGains {
+    /** Balance history aggregated by the requested interval, with the realized gains. */
+    histories: BalanceHistory[];
+    /** Cost basis and unrealized gains of the balance at the end of the range. */
+    gains: BalanceHistoryGains;
+}
+export interface ChainStatsOutputs {
+    /** Number of outputs. */
+    count: number;
+    /** Total value of the outputs (in satoshi or base units). */
+    value: string;
+}
+export interface ChainStatsInterval {
+    /** Unix timestamp of the start of the interval. */
+    time: number;
+    /** Height of the first block in the interval. */
+    fromHeight: number;
+    /** Height of the last block in the interval. */
+    toHeight: number;
+    /** Number of transactions. */
+    txs: number;
+    /** Created outputs by script type (p2pk, p2pkh, p2sh, p2wpkh, p2wsh, p2tr, opreturn, asset, nonstandard). */
+    outputs: { [key: string]: ChainStatsOutputs };
+    /** Number of created outputs with value lower than the dust limit. */
+    dustOutputs: number;
+    /** Size of the UTXO set at the end of the period. */
+    utxos: number;
+    /** Total value of the UTXO set at the end of the period (in satoshi or base units). */
+    utxosValue: string;
+    /** Number of unspent outputs with value lower than the dust limit at the end of the period. */
+    dustUtxos: number;
+}
+export interface ChainStats {
+    /** Height from which the statistics are accumulated, 0 means from the genesis block. */
+    startHeight: number;
+    /** Height of the block of the statistics. */
+    height: number;
+    /** Unix timestamp of the block of the statistics. */
+    time: number;
+    /** Value under which an output is counted as dust (in satoshi or base units). */
+    dustLimit: string;
+    /** Number of transactions. */
+    txs: number;
+    /** Created outputs by script type (p2pk, p2pkh, p2sh, p2wpkh, p2wsh, p2tr, opreturn, asset, nonstandard). */
+    outputs: { [key: string]: ChainStatsOutputs };
+    /** Number of created outputs with value lower than the dust limit. */
+    dustOutputs: number;
+    /** Size of the UTXO set at the end of the period. */
+    utxos: number;
+    /** Total value of the UTXO set at the end of the period (in satoshi or base units). */
+    utxosValue: string;
+    /** Number of unspent outputs with value lower than the dust limit at the end of the period. */
+    dustUtxos: number;
+    /** Statistics aggregated to intervals, present only if the time range is requested. */
+    history?: ChainStatsInterval[];
+}
+export interface RichListAddress {
+    /** Position of the address in the rich list, starting from 1. */
+    rank: number;
+    /** The address. */
+    address: string;
+    /** Balance of the address, for a contract the balance of the token (in satoshi or base units). */
+    balance: string;
+    /** Number of transactions of the address, for a contract the number of token transfers. */
+    txs: number;
+    /** Height of the first block in which the address appears. */
+    firstSeenHeight: number;
+    /** Height of the last block in which the address appears. */
+    lastSeenHeight: number;
+}
+export interface RichList {
+    /** Current page index. */
+    page?: number;
+    /** Total number of pages available. */
+    totalPages?: number;
+    /** Number of items returned on this page. */
+    itemsOnPage?: number;
+    /** Contract of the token holders, present only for Ethereum type coins. */
+    contractInfo?: ContractInfo;
+    /** Addresses ordered from the highest balance. */
+    addresses: RichListAddress[];
+    /** Aliases of the listed addresses. */
+    addressAliases?: { [key: string]: AddressAlias };
+}
+export interface TokenApproval {
+    /** Address of the token contract. */
+    contract: string;
+    /** Name of the token. */
+    name?: string;
+    /** Symbol of the token. */
+    symbol?: string;
+    /** Number of decimals of the token. */
+    decimals?: number;
+    /** Standard of the token, if known. */
+    standard?: '' | 'ERC20' | 'ERC721' | 'ERC1155' | 'BEP20' | 'BEP721' | 'BEP1155';
+    /** Address allowed to spend the tokens. */
+    spender: string;
+    /** True for the ERC721/ERC1155 approval of all tokens (ApprovalForAll), false for the ERC20 allowance. */
+    forAll?: boolean;
+    /** Allowed amount in the base units of the token, 1 for the ApprovalForAll. */
+    allowance: string;
+    /** Height of the block with the last approval event. */
+    blockHeight: number;
+    /** Unsigned data of the transaction to the token contract revoking the approval. */
+    revokeData: string;
+    /** True if the allowance was read from the token contract, set only with the refresh parameter. */
+    refreshed?: boolean;
+}
+export interface TokenApprovals {
+    /** The owner of the tokens. */
+    address: string;
+    /** Approvals with a non-zero allowance. */
+    approvals: TokenApproval[];
+    /** Aliases of the spenders. */
+    addressAliases?: { [key: string]: AddressAlias };
+}
+export interface NftCollectionItem {
+    /** Token ID. */
+    id: string;
+    /** Current owner of the token. */
+    owner: string;
+    /** Number of the tokens held by the owner, always 1 for ERC721. */
+    balance: string;
+}
+export interface NftCollection {
+    /** Current page index. */
+    page?: number;
+    /** Total number of pages available. */
+    totalPages?: number;
+    /** Number of items returned on this page. */
+    itemsOnPage?: number;
+    /** Contract of the collection. */
+    contractInfo: ContractInfo;
+    /** Tokens ordered by the token ID, an ERC1155 token is listed once for each owner. */
+    tokens: NftCollectionItem[];
+    /** Aliases of the owners. */
+    addressAliases?: { [key: string]: AddressAlias };
+}
+export interface NftOwner {
+    /** Address of the owner. */
+    address: string;
+    /** Number of the tokens held by the owner, always 1 for ERC721. */
+    balance: string;
+}
+export interface NftTransfer {
+    /** Transaction ID of the transfer. */
+    txid: string;
+    /** Height of the block containing the transfer. */
+    blockHeight: number;
+    /** Previous owner, zero address for a mint. */
+    from: string;
+    /** New owner, zero address for a burn. */
+    to: string;
+    /** Number of the transferred tokens, always 1 for ERC721. */
+    value: string;
+}
+export interface NftToken {
+    /** Contract of the collection. */
+    contractInfo: ContractInfo;
+    /** Token ID. */
+    id: string;
+    /** Current owners of the token. */
+    owners: NftOwner[];
+    /** Token URI returned by the contract. */
+    uri?: string;
+    /** URI of the image of the token taken from the metadata. */
+    imageUri?: string;
+    /** Metadata JSON downloaded from the token URI. */
+    metadata?: any;
+    /** Unix timestamp when the metadata were downloaded. */
+    metadataTime?: number;
+    /** Transfers of the token ordered from the newest (owner history). */
+    transfers: NftTransfer[];
+    /** Aliases of the owners. */
+    addressAliases?: { [key: string]: AddressAlias };
+}
+export interface InternalTransfer {
+    /** Transaction ID containing the internal transfer. */
+    txid: string;
+    /** Height of the block with the transaction. */
+    blockHeight: number;
+    /** Type of internal transfer (CALL, CREATE, SELFDESTRUCT). */
+    type: number;
+    /** Address from which the transfer originated, the creator for CREATE. */
+    from: string;
+    /** Address to which the transfer was sent, the created contract for CREATE. */
+    to: string;
+    /** Value transferred internally (in Wei or base units). */
+    value: string;
+}
+export interface InternalTransfers {
+    /** The address of the internal transfers. */
+    address: string;
+    /** Internal transfers ordered from the newest block. */
+    transfers: InternalTransfer[];
+    /** Height of the block from which the next page starts, passed as the to parameter of the next request; not set on the last page. */
+    nextBlockHeight?: number;
+    /** Aliases of the counterparties. */
+    addressAliases?: { [key: string]: AddressAlias };
+}
+export interface SimulationTokenChange {
+    standard: '' | 'XPUBAddress' | 'ERC20' | 'ERC721' | 'ERC1155' | 'BEP20' | 'BEP721' | 'BEP1155';
+    /** Contract address of the token. */
+    contract: string;
+    /** Token name. */
+    name?: string;
+    /** Token symbol. */
+    symbol?: string;
+    /** Number of decimals for this token (if applicable). */
+    decimals: number;
+    /** Token ID of ERC721 and ERC1155 tokens. */
+    id?: string;
+    /** Predicted change of the balance (in base units), negative for a decrease. */
+    delta: string;
+}
+export interface SimulationBalanceChange {
+    /** Affected address. */
+    address: string;
+    /** Predicted change of the native balance (in Wei) without the transaction fee, negative for a decrease. */
+    delta: string;
+    /** Predicted changes of the token balances. */
+    tokens?: SimulationTokenChange[];
+}
+export interface TransactionSimulation {
+    /** True if the simulated transaction succeeds. */
+    success: boolean;
+    /** Gas used by the simulated transaction. */
+    gasUsed: number;
+    /** Effective gas price the transaction would pay if sent now, the gasPrice, min(maxFeePerGas, baseFee + maxPriorityFeePerGas) or the price suggested by the backend. */
+    gasPrice?: string;
+    /** Predicted fee (gasUsed times gasPrice), included in the balance change of the sender. */
+    fee?: string;
+    /** Error of the failed transaction. */
+    error?: string;
+    /** Decoded reason of the revert of the failed transaction, if known. */
+    revertReason?: string;
+    /** Address of the contract created by the transaction. */
+    createdContract?: string;
+    /** Predicted changes of the balances per address including the fee paid by the sender, only the fee if the transaction fails. */
+    balanceChanges: SimulationBalanceChange[];
+    /** Predicted internal transfers. */
+    internalTransfers?: EthereumInternalTransfer[];
+    /** Predicted token transfers. */
+    tokenTransfers?: TokenTransfer[];
+    /** Aliases of the affected addresses. */
+    addressAliases?: { [key: string]: AddressAlias };
+}
+export interface OpReturnOutput {
+    /** Transaction ID containing the output. */
+    txid: string;
+    /** Index of the output in the transaction. */
+    vout: number;
+    /** Height of the block with the transaction, missing for mempool transactions. */
+    height?: number;
+    /** Hex-encoded data pushed by the OP_RETURN script. */
+    data: string;
+}
+export interface OpReturnOutputs {
+    /** Current page index. */
+    page?: number;
+    /** Total number of pages available. */
+    totalPages?: number;
+    /** Number of items returned on this page. */
+    itemsOnPage?: number;
+    /** Hex-encoded prefix of the data. */
+    prefix: string;
+    /** Matching outputs ordered from the newest block. */
+    outputs: OpReturnOutput[];
 }
 export interface BlockInfo {
     Hash: string;
@@ -553,6 +930,32 @@ export interface AvailableVsCurrencies {
     /** Error message, if any, when fetching the available currencies. */
     error?: string;
 }
+export interface FiatCandle {
+    /** Unix timestamp of the start of the interval. */
+    ts: number;
+    /** Rate at the start of the interval (close rate of the previous sample). */
+    open: number;
+    /** Highest rate in the interval. */
+    high: number;
+    /** Lowest rate in the interval. */
+    low: number;
+    /** Last rate in the interval. */
+    close: number;
+}
+export interface FiatCandles {
+    /** Currency code of the rates (e.g. 'usd'). */
+    currency: string;
+    /** Token contract address if the rates are of a token. */
+    token?: string;
+    /** Interval of the candles: '5m', '1h' or '1d'. */
+    interval: string;
+    /** Unix timestamp of the start of the range, aligned to the interval. */
+    from: number;
+    /** Unix timestamp of the end of the range (exclusive), aligned to the interval. */
+    to: number;
+    /** List of candles, intervals without rates are omitted. */
+    candles: FiatCandle[];
+}
 export interface WsReq {
     /** Unique request identifier. */
     id: string;
@@ -568,6 +971,7 @@ export interface WsReq {
         | 'getTransactionSpecific'
         | 'estimateFee'
         | 'sendTransaction'
+        | 'simulateTransaction'
         | 'subscribeNewBlock'
         | 'unsubscribeNewBlock'
         | 'subscribeNewTransaction'
@@ -576,10 +980,13 @@ export interface WsReq {
         | 'unsubscribeAddresses'
         | 'subscribeFiatRates'
         | 'unsubscribeFiatRates'
+        | 'subscribeOpReturn'
+        | 'unsubscribeOpReturn'
         | 'ping'
         | 'getCurrentFiatRates'
         | 'getFiatRatesForTimestamps'
         | 'getFiatRatesTickersList'
+        | 'getFiatRatesCandles'
         | 'getMempoolFilters';
     /** Parameters for the requested method in raw JSON format. */
     params: any;
@@ -695,6 +1102,8 @@ export interface WsBalanceHistoryReq {
     gap?: number;
     /** Size of each aggregated time window in seconds. */
     groupBy?: number;
+    /** Cost basis method of the realized and unrealized gains: 'fifo', 'lifo' or 'avg'. Requires currencies. */
+    gains?: string;
 }
 export interface WsTransactionReq {
     /** Transaction ID to retrieve details for. */
@@ -725,6 +1134,8 @@ export interface Eip1559Fee {
 }
 export interface Eip1559Fees {
     baseFeePerGas?: string;
+    /** Estimated base fee per blob gas of EIP-4844 blob transactions. */
+    blobBaseFeePerGas?: string;
     low?: Eip1559Fee;
     medium?: Eip1559Fee;
     high?: Eip1559Fee;
@@ -757,6 +1168,20 @@ export interface WsSendTransactionReq {
     /** Use alternative RPC method to broadcast transaction. */
     disableAlternativeRPC?: boolean;
 }
+export interface WsSimulateTransactionReq {
+    /** Transaction fields as in eth_call (hex encoded values) with an optional state override set. */
+    tx: {
+        from: string;
+        to?: string;
+        data?: string;
+        value?: string;
+        gas?: string;
+        gasPrice?: string;
+        maxFeePerGas?: string;
+        maxPriorityFeePerGas?: string;
+        stateOverrides?: { [address: string]: any };
+    };
+}
 export interface WsSubscribeAddressesReq {
     /** List of addresses to subscribe for updates (e.g., new transactions). */
     addresses: string[];
@@ -767,6 +1192,10 @@ export interface WsSubscribeFiatRatesReq {
     /** List of token symbols or IDs to get fiat rates for. */
     tokens?: string[];
 }
+export interface WsSubscribeOpReturnReq {
+    /** List of hex-encoded prefixes of the OP_RETURN data. */
+    prefixes: string[];
+}
 export interface WsCurrentFiatRatesReq {
     /** List of fiat currencies, e.g. ['USD','EUR']. */
     currencies?: string[];
@@ -787,6 +1216,18 @@ export interface WsFiatRatesTickersListReq {
     /** Token symbol or ID if asking for token-specific fiat rates. */
     token?: string;
 }
+export interface WsFiatRatesCandlesReq {
+    /** Fiat currency code, e.g. 'usd'. */
+    currency: string;
+    /** Token contract address if asking for token fiat rates. */
+    token?: string;
+    /** Interval of the candles: '5m', '1h' (default) or '1d'. */
+    interval?: string;
+    /** Unix timestamp of the start of the range, default 100 intervals before 'to'. */
+    from?: number;
+    /** Unix timestamp of the end of the range, default current time. */
+    to?: number;
+}
 export interface WsMempoolFiltersReq {
     /** Type of script we are filtering for (e.g., P2PKH, P2SH). */
     scriptType: string;
@@ -807,6 +1248,55 @@ export interface WsRpcCallRes {
     /** Hex-encoded return data from the call. */
     data: string;
 }
+export interface JSONRPCReq {
+    /** JSON-RPC protocol version, must be '2.0'. */
+    jsonrpc: '2.0';
+    /** Request identifier, a request without id is a notification and gets no response. */
+    id?: string | number | null;
+    /** Requested method name, the subscription methods are not available. */
+    method:
+        | 'getAccountInfo'
+        | 'getInfo'
+        | 'getBlockHash'
+        | 'getBlock'
+        | 'getAccountUtxo'
+        | 'getBalanceHistory'
+        | 'getTransaction'
+        | 'getTransactionSpecific'
+        | 'estimateFee'
+        | 'longTermFeeRate'
+        | 'sendTransaction'
+        | 'simulateTransaction'
+        | 'ping'
+        | 'getCurrentFiatRates'
+        | 'getFiatRatesForTimestamps'
+        | 'getFiatRatesTickersList'
+        | 'getFiatRatesCandles'
+        | 'getMempoolFilters'
+        | 'getBlockFilter'
+        | 'getBlockFiltersBatch'
+        | 'rpcCall';
+    /** Named parameters of the method, the same as in the websocket request. */
+    params?: any;
+}
+export interface JSONRPCError {
+    /** Error code, -32700 to -32600 are the protocol errors, -32000 error of the method, -32001 API key error, -32002 rate limit error. */
+    code: number;
+    /** Error message. */
+    message: string;
+    /** Additional data, retryAfter in seconds for the rate limit error. */
+    data?: { retryAfter?: number };
+}
+export interface JSONRPCRes {
+    /** JSON-RPC protocol version. */
+    jsonrpc: '2.0';
+    /** Corresponding request identifier, null if it could not be determined. */
+    id: string | number | null;
+    /** Result of the method, the same as the data of the websocket response. */
+    result?: any;
+    /** Error, if the request failed. */
+    error?: JSONRPCError;
+}
 export interface MempoolTxidFilterEntries {
     /** Map of txid to filter data (hex-encoded). */
     entries?: { [key: string]: string };
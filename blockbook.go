@@ -21,6 +21,7 @@ import (
 	"github.com/trezor/blockbook/api"
 	"github.com/trezor/blockbook/bchain"
 	"github.com/trezor/blockbook/bchain/coins"
+	"github.com/trezor/blockbook/bchain/coins/eth"
 	"github.com/trezor/blockbook/common"
 	"github.com/trezor/blockbook/db"
 	"github.com/trezor/blockbook/fiat"
@@ -67,6 +68,8 @@ var (
 
 	publicBinding = flag.String("public", "", "public http server binding [address]:port[/path] (default no public server)")
 
+	grpcBinding = flag.String("grpc", "", "gRPC server binding [address]:port (default no gRPC server)")
+
 	certFiles = flag.String("certfile", "", "to enable SSL specify path to certificate files without extension, expecting <certfile>.crt and <certfile>.key (default no SSL)")
 
 	explorerURL = flag.String("explorer", "", "address of blockchain explorer")
@@ -75,10 +78,19 @@ var (
 
 	enableSubNewTx = flag.Bool("enablesubnewtx", false, "enable support for subscribing to all new transactions")
 
+	requireAPIKey = flag.Bool("requireapikey", false, "reject the requests to the public interfaces without an API key")
+
+	enableWebhooks = flag.Bool("webhooks", false, "enable delivery of address and block events to the webhooks managed by the internal server")
+
 	computeColumnStats  = flag.Bool("computedbstats", false, "compute column stats and exit")
 	computeFeeStatsFlag = flag.Bool("computefeestats", false, "compute fee stats for blocks in blockheight-blockuntil range and exit")
 	dbStatsPeriodHours  = flag.Int("dbstatsperiod", 24, "period of db stats collection in hours, 0 disables stats collection")
 
+	importFiatRates      = flag.String("importfiatrates", "", "import fiat rates from CSV or JSON (.json extension) file and exit")
+	exportFiatRates      = flag.String("exportfiatrates", "", "export fiat rates to CSV or JSON (.json extension) file, - for standard output, and exit")
+	fiatRatesGranularity = flag.String("fiatratesgranularity", fiat.GranularityDaily, "granularity of imported or exported fiat rates: daily, hourly or 5min")
+	fiatRatesOverwrite   = flag.Bool("fiatratesoverwrite", false, "imported fiat rates overwrite the rates stored in the database")
+
 	// resync index at least each resyncIndexPeriodMs (could be more often if invoked by message from ZeroMQ)
 	resyncIndexPeriodMs = flag.Int("resyncindexperiod", 935093, "resync index period in milliseconds")
 
@@ -86,6 +98,8 @@ var (
 	resyncMempoolPeriodMs = flag.Int("resyncmempoolperiod", 60017, "resync mempool period in milliseconds")
 
 	extendedIndex = flag.Bool("extendedindex", false, "if true, create index of input txids and spending transactions")
+
+	pipelinedSync = flag.Bool("pipelinedsync", true, "use the pipelined initial sync with adaptive concurrency for Ethereum type coins, if false the bulk sync is used")
 )
 
 var (
@@ -200,6 +214,12 @@ func mainWithExitCode() int {
 		glog.Info("shutdown: rocksdb close finished")
 	}()
 
+	if chain.GetChainParser().GetChainType() == bchain.ChainEthereumType {
+		// decode the revert reasons by the contract ABIs uploaded by the operator and by the known four byte signatures
+		eth.ContractABIGetter = index.GetContractABIForAddress
+		eth.FourByteSignaturesGetter = index.GetFourByteSignatures
+	}
+
 	internalState, err = newInternalState(config, index, *enableSubNewTx)
 	if err != nil {
 		glog.Error("internalState: ", err)
@@ -236,6 +256,12 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
+	// bulk connect interrupted by a crash can continue from the last durable checkpoint
+	if _, err = index.ResumeFromBulkCheckpoint(); err != nil {
+		glog.Error("internalState: ", err)
+		return exitCodeFatal
+	}
+
 	if internalState.DbState != common.DbStateClosed {
 		if internalState.DbState == common.DbStateInconsistent {
 			glog.Error("internalState: database is in inconsistent state and cannot be used")
@@ -265,7 +291,25 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
-	syncWorker, err = db.NewSyncWorker(index, chain, *syncWorkers, *syncChunk, *blockFrom, *dryRun, chanOsSignal, metrics, internalState)
+	if *importFiatRates != "" {
+		if _, err = fiat.ImportTickers(index, *importFiatRates, *fiatRatesGranularity, *fiatRatesOverwrite); err != nil {
+			glog.Error("importFiatRates: ", err)
+			return exitCodeFatal
+		}
+		return exitCodeOK
+	}
+
+	if *exportFiatRates != "" {
+		if _, err = fiat.ExportTickers(index, *exportFiatRates, *fiatRatesGranularity); err != nil {
+			glog.Error("exportFiatRates: ", err)
+			return exitCodeFatal
+		}
+		return exitCodeOK
+	}
+
+	syncWorkerConfig := db.DefaultSyncWorkerConfig()
+	syncWorkerConfig.Pipeline.Disabled = !*pipelinedSync
+	syncWorker, err = db.NewSyncWorkerWithConfig(index, chain, *syncWorkers, *syncChunk, *blockFrom, *dryRun, chanOsSignal, metrics, internalState, &syncWorkerConfig)
 	if err != nil {
 		glog.Errorf("NewSyncWorker %v", err)
 		return exitCodeFatal
@@ -302,18 +346,35 @@ func mainWithExitCode() int {
 		glog.Error("blockbookAppInfoMetric ", err)
 	}
 
+	var apiKeys *server.APIKeys
+	if *internalBinding != "" || *publicBinding != "" || *grpcBinding != "" {
+		if apiKeys, err = server.NewAPIKeys(index, metrics, *requireAPIKey); err != nil {
+			glog.Error("apiKeys ", err)
+			return exitCodeFatal
+		}
+	}
+
+	var webhooks *server.Webhooks
+	if *enableWebhooks {
+		if webhooks, err = server.NewWebhooks(index, chain, mempool, txCache, metrics, internalState, fiatRates); err != nil {
+			glog.Error("webhooks ", err)
+			return exitCodeFatal
+		}
+	}
+
 	var internalServer *server.InternalServer
 	if *internalBinding != "" {
-		internalServer, err = startInternalServer()
+		internalServer, err = startInternalServer(apiKeys)
 		if err != nil {
 			glog.Error("internal server: ", err)
 			return exitCodeFatal
 		}
+		internalServer.SetWebhooks(webhooks)
 	}
 
 	var publicServer *server.PublicServer
 	if *publicBinding != "" {
-		publicServer, err = startPublicServer()
+		publicServer, err = startPublicServer(apiKeys)
 		if err != nil {
 			glog.Error("public server: ", err)
 			return exitCodeFatal
@@ -361,6 +422,31 @@ func mainWithExitCode() int {
 		publicServer.ConnectFullPublicInterface()
 	}
 
+	var grpcServer *server.GRPCServer
+	if *grpcBinding != "" {
+		var rateLimiter *server.RateLimiter
+		if publicServer != nil {
+			rateLimiter = publicServer.RateLimiter()
+		}
+		grpcServer, err = startGRPCServer(apiKeys, rateLimiter)
+		if err != nil {
+			glog.Error("grpc server: ", err)
+			return exitCodeFatal
+		}
+		callbacksOnNewBlock = append(callbacksOnNewBlock, grpcServer.OnNewBlock)
+		callbacksOnNewTx = append(callbacksOnNewTx, grpcServer.OnNewTx)
+		callbacksOnNewFiatRatesTicker = append(callbacksOnNewFiatRatesTicker, grpcServer.OnNewFiatRatesTicker)
+	}
+
+	if webhooks != nil {
+		if err = webhooks.Start(); err != nil {
+			glog.Error("webhooks: ", err)
+			return exitCodeFatal
+		}
+		callbacksOnNewBlock = append(callbacksOnNewBlock, webhooks.OnNewBlock)
+		callbacksOnNewTxAddr = append(callbacksOnNewTxAddr, webhooks.OnNewTxAddr)
+	}
+
 	if *blockFrom >= 0 {
 		if *blockUntil < 0 {
 			*blockUntil = *blockFrom
@@ -382,7 +468,10 @@ func mainWithExitCode() int {
 	if internalServer != nil || publicServer != nil || chain != nil {
 		// start fiat rates downloader only if not shutting down immediately
 		initDownloaders(index, chain, config)
-		waitForSignalAndShutdown(internalServer, publicServer, chain, shutdownSigCh, 10*time.Second)
+		waitForSignalAndShutdown(internalServer, publicServer, grpcServer, chain, shutdownSigCh, 10*time.Second)
+	}
+	if webhooks != nil {
+		webhooks.Stop()
 	}
 
 	// Always stop periodic state storage to prevent writes during shutdown.
@@ -421,11 +510,12 @@ func getBlockChainWithRetry(coin string, configFile string, pushHandler func(bch
 	}
 }
 
-func startInternalServer() (*server.InternalServer, error) {
+func startInternalServer(apiKeys *server.APIKeys) (*server.InternalServer, error) {
 	internalServer, err := server.NewInternalServer(*internalBinding, *certFiles, index, chain, mempool, txCache, metrics, internalState, fiatRates)
 	if err != nil {
 		return nil, err
 	}
+	internalServer.SetAPIKeys(apiKeys)
 	go func() {
 		err = internalServer.Run()
 		if err != nil {
@@ -440,12 +530,13 @@ func startInternalServer() (*server.InternalServer, error) {
 	return internalServer, nil
 }
 
-func startPublicServer() (*server.PublicServer, error) {
+func startPublicServer(apiKeys *server.APIKeys) (*server.PublicServer, error) {
 	// start public server in limited functionality, extend it after sync is finished by calling ConnectFullPublicInterface
 	publicServer, err := server.NewPublicServer(*publicBinding, *certFiles, index, chain, mempool, txCache, *explorerURL, metrics, internalState, fiatRates, *debugMode)
 	if err != nil {
 		return nil, err
 	}
+	publicServer.SetAPIKeys(apiKeys)
 	go func() {
 		err = publicServer.Run()
 		if err != nil {
@@ -460,6 +551,25 @@ func startPublicServer() (*server.PublicServer, error) {
 	return publicServer, err
 }
 
+func startGRPCServer(apiKeys *server.APIKeys, rateLimiter *server.RateLimiter) (*server.GRPCServer, error) {
+	grpcServer, err := server.NewGRPCServer(*grpcBinding, *certFiles, index, chain, mempool, txCache, metrics, internalState, fiatRates)
+	if err != nil {
+		return nil, err
+	}
+	grpcServer.SetAPIKeys(apiKeys)
+	if rateLimiter != nil {
+		grpcServer.SetRateLimiter(rateLimiter)
+	}
+	go func() {
+		if err := grpcServer.Run(); err != nil {
+			glog.Error("grpc server: ", err)
+		} else {
+			glog.Info("grpc server: closed")
+		}
+	}()
+	return grpcServer, nil
+}
+
 func performRollback() error {
 	bestHeight, bestHash, err := index.GetBestBlock()
 	if err != nil {
@@ -537,6 +647,13 @@ func newInternalState(config *common.Config, d *db.RocksDB, enableSubNewTx bool)
 		glog.Info("WsGetAccountInfoLimit enabled with limit ", is.WsGetAccountInfoLimit)
 		is.WsLimitExceedingIPs = make(map[string]int)
 	}
+	is.RateLimits = config.RateLimits
+	if is.RateLimits != nil {
+		glog.Infof("Rate limits enabled with rate %v and burst %v", is.RateLimits.Rate, is.RateLimits.Burst)
+	}
+	is.IpfsGateway = config.IpfsGateway
+	is.NftMetadataHosts = config.NftMetadataHosts
+	is.SimulateTransaction = config.SimulateTransaction
 	return is, nil
 }
 
@@ -683,7 +800,7 @@ func pushSynchronizationHandler(nt bchain.NotificationType) {
 	}
 }
 
-func waitForSignalAndShutdown(internal *server.InternalServer, public *server.PublicServer, chain bchain.BlockChain, shutdownSig <-chan os.Signal, timeout time.Duration) {
+func waitForSignalAndShutdown(internal *server.InternalServer, public *server.PublicServer, grpcServer *server.GRPCServer, chain bchain.BlockChain, shutdownSig <-chan os.Signal, timeout time.Duration) {
 	// Read the first OS signal from the dedicated channel to avoid races with worker shutdown paths.
 	sig := <-shutdownSig
 	common.SetInShutdown()
@@ -709,6 +826,12 @@ func waitForSignalAndShutdown(internal *server.InternalServer, public *server.Pu
 		}
 	}
 
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			glog.Error("grpc server: shutdown error: ", err)
+		}
+	}
+
 	if chain != nil {
 		if err := chain.Shutdown(ctx); err != nil {
 			glog.Error("rpc: shutdown error: ", err)
@@ -745,4 +868,14 @@ func initDownloaders(db *db.RocksDB, chain bchain.BlockChain, config *common.Con
 
 	}
 
+	if config.EventSignatures != "" && chain.GetChainParser().GetChainType() == bchain.ChainEthereumType {
+		esd, err := fourbyte.NewEventSignaturesDownloader(db, config.EventSignatures)
+		if err != nil {
+			glog.Errorf("NewEventSignaturesDownloader Init error: %v", err)
+		} else {
+			glog.Infof("Starting EventSignatures downloader...")
+			go esd.Run()
+		}
+	}
+
 }
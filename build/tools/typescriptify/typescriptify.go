@@ -30,6 +30,15 @@ func main() {
 	t.Add(api.Address{})
 	t.Add(api.Utxo{})
 	t.Add(api.BalanceHistory{})
+	t.Add(api.BalanceHistoryWithGains{})
+	t.Add(api.ChainStats{})
+	t.Add(api.RichList{})
+	t.Add(api.TokenApprovals{})
+	t.Add(api.NftCollection{})
+	t.Add(api.NftToken{})
+	t.Add(api.InternalTransfers{})
+	t.Add(api.TransactionSimulation{})
+	t.Add(api.OpReturnOutputs{})
 	t.Add(api.Blocks{})
 	t.Add(api.Block{})
 	t.Add(api.BlockRaw{})
@@ -37,6 +46,8 @@ func main() {
 	t.Add(api.FiatTicker{})
 	t.Add(api.FiatTickers{})
 	t.Add(api.AvailableVsCurrencies{})
+	t.Add(api.FiatCandle{})
+	t.Add(api.FiatCandles{})
 
 	// Websocket specific
 	t.Add(server.WsReq{})
@@ -56,14 +67,22 @@ func main() {
 	t.Add(server.WsEstimateFeeRes{})
 	t.Add(server.WsLongTermFeeRateRes{})
 	t.Add(server.WsSendTransactionReq{})
+	t.Add(server.WsSimulateTransactionReq{})
 	t.Add(server.WsSubscribeAddressesReq{})
 	t.Add(server.WsSubscribeFiatRatesReq{})
+	t.Add(server.WsSubscribeOpReturnReq{})
 	t.Add(server.WsCurrentFiatRatesReq{})
 	t.Add(server.WsFiatRatesForTimestampsReq{})
 	t.Add(server.WsFiatRatesTickersListReq{})
+	t.Add(server.WsFiatRatesCandlesReq{})
 	t.Add(server.WsMempoolFiltersReq{})
 	t.Add(server.WsRpcCallReq{})
 	t.Add(server.WsRpcCallRes{})
+
+	// JSON-RPC specific
+	t.Add(server.JSONRPCReq{})
+	t.Add(server.JSONRPCError{})
+	t.Add(server.JSONRPCRes{})
 	t.Add(bchain.MempoolTxidFilterEntries{})
 
 	err := t.ConvertToFile("blockbook-api.ts")
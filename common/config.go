@@ -9,17 +9,34 @@ import (
 
 // Config struct
 type Config struct {
-	CoinName                string `json:"coin_name"`
-	CoinShortcut            string `json:"coin_shortcut"`
-	CoinLabel               string `json:"coin_label"`
-	Network                 string `json:"network"`
-	FourByteSignatures      string `json:"fourByteSignatures"`
-	FiatRates               string `json:"fiat_rates"`
-	FiatRatesParams         string `json:"fiat_rates_params"`
-	FiatRatesVsCurrencies   string `json:"fiat_rates_vs_currencies"`
-	BlockGolombFilterP      uint8  `json:"block_golomb_filter_p"`
-	BlockFilterScripts      string `json:"block_filter_scripts"`
-	BlockFilterUseZeroedKey bool   `json:"block_filter_use_zeroed_key"`
+	CoinName                string      `json:"coin_name"`
+	CoinShortcut            string      `json:"coin_shortcut"`
+	CoinLabel               string      `json:"coin_label"`
+	Network                 string      `json:"network"`
+	FourByteSignatures      string      `json:"fourByteSignatures"`
+	EventSignatures         string      `json:"eventSignatures"`
+	FiatRates               string      `json:"fiat_rates"`
+	FiatRatesParams         string      `json:"fiat_rates_params"`
+	FiatRatesVsCurrencies   string      `json:"fiat_rates_vs_currencies"`
+	BlockGolombFilterP      uint8       `json:"block_golomb_filter_p"`
+	BlockFilterScripts      string      `json:"block_filter_scripts"`
+	BlockFilterUseZeroedKey bool        `json:"block_filter_use_zeroed_key"`
+	OpReturnIndex           bool        `json:"opreturn_index"`
+	RateLimits              *RateLimits `json:"rate_limits,omitempty"`
+	IpfsGateway             string      `json:"ipfs_gateway,omitempty"`
+	NftMetadataHosts        []string    `json:"nft_metadata_hosts,omitempty"`
+	SimulateTransaction     string      `json:"simulate_transaction,omitempty"`
+}
+
+// RateLimits configures the cost based rate limiting of the requests to the public interfaces,
+// each client (IP address or API key) has a token bucket replenished by Rate cost units per second up to Burst units
+type RateLimits struct {
+	Rate  float64 `json:"rate"`
+	Burst float64 `json:"burst"`
+	// Costs override the base costs of the websocket/socket.io methods and REST endpoints
+	Costs map[string]float64 `json:"costs,omitempty"`
+	// KeyBurstSeconds is the capacity of the bucket of an API key with its own rate limit in seconds of the rate, default 10
+	KeyBurstSeconds float64 `json:"key_burst_seconds,omitempty"`
 }
 
 // GetConfig loads and parses the config file and returns Config struct
@@ -50,6 +50,13 @@ type BackendInfo struct {
 	Consensus        interface{} `json:"consensus,omitempty" ts_doc:"Additional chain-specific consensus data."`
 }
 
+// BulkCheckpoint is the last durable point of an unfinished bulk connect
+type BulkCheckpoint struct {
+	Height uint32    `json:"height" ts_doc:"Height of the last block stored in the checkpoint."`
+	Hash   string    `json:"hash" ts_doc:"Hash of the last block stored in the checkpoint, empty if no block was stored yet."`
+	Time   time.Time `json:"time" ts_doc:"Timestamp when the checkpoint was stored."`
+}
+
 // InternalState contains the data of the internal state
 type InternalState struct {
 	mux sync.Mutex `ts_doc:"Mutex for synchronized access to the internal state."`
@@ -63,6 +70,8 @@ type InternalState struct {
 	DbState       uint32 `json:"dbState" ts_doc:"State of the database (closed=0, open=1, inconsistent=2)."`
 	ExtendedIndex bool   `json:"extendedIndex" ts_doc:"Indicates if an extended indexing strategy is used."`
 
+	BulkCheckpoint *BulkCheckpoint `json:"bulkCheckpoint,omitempty" ts_doc:"Last durable checkpoint of an unfinished bulk sync, not set if no bulk sync is in progress."`
+
 	LastStore time.Time `json:"lastStore" ts_doc:"Time when the internal state was last stored/persisted."`
 
 	// true if application is with flag --sync
@@ -100,9 +109,23 @@ type InternalState struct {
 	BlockFilterScripts      string `json:"block_filter_scripts" ts_doc:"Scripts included in block filters (e.g., 'p2pkh,p2sh')."`
 	BlockFilterUseZeroedKey bool   `json:"block_filter_use_zeroed_key" ts_doc:"If true, uses a zeroed key for building block filters."`
 
+	// optional indexes
+	OpReturnIndex bool `json:"opreturn_index" ts_doc:"If true, the data of OP_RETURN outputs are indexed."`
+
 	// allowed number of fetched accounts over websocket
 	WsGetAccountInfoLimit int            `json:"-" ts_doc:"Limit of how many getAccountInfo calls can be made via WS (not exposed)."`
 	WsLimitExceedingIPs   map[string]int `json:"-" ts_doc:"Tracks IP addresses exceeding the WS limit (not exposed)."`
+
+	// cost based rate limiting of the public interfaces
+	RateLimits *RateLimits `json:"-" ts_doc:"Configuration of the cost based rate limiting (not exposed)."`
+
+	// gateway resolving the ipfs:// URIs of the NFT metadata
+	IpfsGateway string `json:"-" ts_doc:"Gateway used to resolve the ipfs:// URIs of the NFT metadata (not exposed)."`
+	// hosts other than the ipfs gateway from which the NFT metadata can be downloaded
+	NftMetadataHosts []string `json:"-" ts_doc:"Hosts other than the IPFS gateway allowed for the download of the NFT metadata (not exposed)."`
+
+	// access to the transaction simulation, "public", "apikey" or disabled
+	SimulateTransaction string `json:"-" ts_doc:"Access to the transaction simulation: public, apikey or disabled (not exposed)."`
 }
 
 // StartedSync signals start of synchronization
@@ -144,6 +167,35 @@ func (is *InternalState) GetSyncState() (bool, uint32, time.Time, time.Time) {
 	return is.IsSynchronized, is.BestHeight, is.LastSync, is.StartSync
 }
 
+// SetBulkCheckpoint records the last durable block of the bulk connect
+func (is *InternalState) SetBulkCheckpoint(height uint32, hash string) {
+	is.mux.Lock()
+	defer is.mux.Unlock()
+	is.BulkCheckpoint = &BulkCheckpoint{
+		Height: height,
+		Hash:   hash,
+		Time:   time.Now().UTC(),
+	}
+}
+
+// ClearBulkCheckpoint removes the bulk connect checkpoint after the bulk connect finished
+func (is *InternalState) ClearBulkCheckpoint() {
+	is.mux.Lock()
+	defer is.mux.Unlock()
+	is.BulkCheckpoint = nil
+}
+
+// GetBulkCheckpoint returns a copy of the bulk connect checkpoint or nil if bulk connect is not in progress
+func (is *InternalState) GetBulkCheckpoint() *BulkCheckpoint {
+	is.mux.Lock()
+	defer is.mux.Unlock()
+	if is.BulkCheckpoint == nil {
+		return nil
+	}
+	c := *is.BulkCheckpoint
+	return &c
+}
+
 // StartedMempoolSync signals start of mempool synchronization
 func (is *InternalState) StartedMempoolSync() {
 	is.mux.Lock()
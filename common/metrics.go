@@ -36,6 +36,18 @@ type Metrics struct {
 	SocketIOPendingRequests  *prometheus.GaugeVec
 	XPubCacheSize            prometheus.Gauge
 	CoingeckoRequests        *prometheus.CounterVec
+	SyncPipelineBlocks       *prometheus.CounterVec
+	SyncPipelineConcurrency  prometheus.Gauge
+	SyncPipelineInflight     prometheus.Gauge
+	SyncPipelineQueued       prometheus.Gauge
+	SyncPipelineWait         *prometheus.CounterVec
+	SyncPipelineThrottled    *prometheus.CounterVec
+	APIKeyRequests           *prometheus.CounterVec
+	RateLimitedRequests      *prometheus.CounterVec
+	JSONRPCRequests          *prometheus.CounterVec
+	GRPCRequests             *prometheus.CounterVec
+	GRPCSubscriptions        *prometheus.GaugeVec
+	WebhookDeliveries        *prometheus.CounterVec
 }
 
 // Labels represents a collection of label name -> value mappings.
@@ -264,6 +276,99 @@ func GetMetrics(coin string) (*Metrics, error) {
 		},
 		[]string{"endpoint", "status"},
 	)
+	metrics.SyncPipelineBlocks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_sync_pipeline_blocks",
+			Help:        "Total number of blocks processed by pipelined sync by stage (fetched, connected)",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"stage"},
+	)
+	metrics.SyncPipelineConcurrency = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_sync_pipeline_concurrency",
+			Help:        "Current limit of concurrent block fetches in pipelined sync",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.SyncPipelineInflight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_sync_pipeline_inflight",
+			Help:        "Number of block fetches in progress in pipelined sync",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.SyncPipelineQueued = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_sync_pipeline_queued",
+			Help:        "Number of fetched blocks waiting to be connected in order in pipelined sync",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.SyncPipelineWait = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_sync_pipeline_wait",
+			Help:        "Total time spent waiting in pipelined sync by reason (window - backpressure of the writer, throttle - backend throttling) (in milliseconds)",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"reason"},
+	)
+	metrics.SyncPipelineThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_sync_pipeline_throttled",
+			Help:        "Number of backend requests throttled in pipelined sync by reason (ratelimit, timeout)",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"reason"},
+	)
+	metrics.APIKeyRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_api_key_requests",
+			Help:        "Number of requests with an API key by key name, interface and status",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"key", "interface", "status"},
+	)
+	metrics.RateLimitedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_rate_limited_requests",
+			Help:        "Number of requests rejected by the rate limiter by method and interface",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method", "interface"},
+	)
+	metrics.JSONRPCRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_jsonrpc_requests",
+			Help:        "Total number of JSON-RPC requests by method and status",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method", "status"},
+	)
+	metrics.GRPCRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_grpc_requests",
+			Help:        "Total number of gRPC requests by method and status",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method", "status"},
+	)
+	metrics.GRPCSubscriptions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_grpc_subscriptions",
+			Help:        "Number of gRPC subscription streams by method",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method"},
+	)
+	metrics.WebhookDeliveries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_webhook_deliveries",
+			Help:        "Total number of webhook delivery attempts by event and status",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"event", "status"},
+	)
 
 	v := reflect.ValueOf(metrics)
 	for i := 0; i < v.NumField(); i++ {
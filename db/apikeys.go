@@ -0,0 +1,49 @@
+package db
+
+import (
+	"encoding/json"
+)
+
+const apiKeysKey = "ApiKeys"
+
+// APIKey defines the access of a client identified by the key to the public interfaces
+type APIKey struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// RateLimit is the number of cost units per second replenished to the token bucket of the key, 0 means the default limits
+	RateLimit float64 `json:"rateLimit,omitempty"`
+	// DailyQuota is the maximum number of requests per UTC day, 0 means unlimited
+	DailyQuota int64 `json:"dailyQuota,omitempty"`
+	// AllowedMethods are the names of the allowed websocket/socket.io methods and REST endpoints, empty means all
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// AllowedOrigins are the allowed values of the Origin header, empty means all
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	Disabled       bool     `json:"disabled,omitempty"`
+}
+
+// GetAPIKeys returns the stored API keys
+func (d *RocksDB) GetAPIKeys() ([]APIKey, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(apiKeysKey))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	data := val.Data()
+	if data == nil {
+		return nil, nil
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// StoreAPIKeys replaces the stored API keys
+func (d *RocksDB) StoreAPIKeys(keys []APIKey) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return d.db.PutCF(d.wo, d.cfh[cfDefault], []byte(apiKeysKey), data)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/juju/errors"
 	"github.com/linxGnu/grocksdb"
 	"github.com/trezor/blockbook/bchain"
 )
@@ -13,166 +14,162 @@ import (
 // it speeds up the import in two ways:
 // 1) balances and txAddresses are modified several times during the import, there is a chance that the modifications are done before write to DB
 // 2) rocksdb seems to handle better fewer larger batches than continuous stream of smaller batches
+// the cached data are flushed in checkpoints, each checkpoint is written in one atomic batch together with the heights
+// of the connected blocks and the checkpoint record in the internal state
+// the data written between checkpoints (addresses, filters, internal data, blockTxs) are idempotent,
+// therefore after a crash the sync can resume from the last checkpoint, which is the best block in db
 
 type bulkAddresses struct {
 	bi        BlockInfo
 	addresses addressesMap
 }
 
-// BulkConnect is used to connect blocks in bulk, faster but if interrupted inconsistent way
+// BulkConnect is used to connect blocks in bulk, faster but the data are durable only at checkpoints
 type BulkConnect struct {
 	d                  *RocksDB
 	chainType          bchain.ChainType
 	bulkAddresses      []bulkAddresses
 	bulkAddressesCount int
+	pendingHeights     []BlockInfo
+	chainStats         []*ChainStats
 	ethBlockTxs        []ethBlockTx
 	txAddressesMap     map[string]*TxAddresses
 	blockFilters       map[string][]byte
+	opReturns          map[string][]byte
 	balances           map[string]*AddrBalance
 	addressContracts   map[string]*unpackedAddrContracts
+	approvals          map[string][]byte
 	height             uint32
+	hash               string
+	checkpointBlocks   int
 }
 
 const (
-	maxBulkAddresses          = 80000
-	maxBulkTxAddresses        = 500000
-	partialStoreAddresses     = maxBulkTxAddresses / 10
-	maxBulkBalances           = 700000
-	partialStoreBalances      = maxBulkBalances / 10
-	maxBulkAddrContracts      = 1200000
-	partialStoreAddrContracts = maxBulkAddrContracts / 10
-	maxBlockFilters           = 1000
+	maxBulkAddresses     = 80000
+	maxBulkTxAddresses   = 500000
+	maxBulkBalances      = 700000
+	maxBulkAddrContracts = 1200000
+	maxBlockFilters      = 1000
+	maxBulkOpReturns     = 200000
+	maxBulkApprovals     = 500000
+	// maxBulkCheckpointBlocks bounds the number of blocks connected between checkpoints
+	maxBulkCheckpointBlocks = 10000
 )
 
-// InitBulkConnect initializes bulk connect and switches DB to inconsistent state
+// InitBulkConnect initializes bulk connect and records the current best block as the first checkpoint
 func (d *RocksDB) InitBulkConnect() (*BulkConnect, error) {
+	if d.is == nil {
+		return nil, errors.New("Internal state not created")
+	}
+	height, hash, err := d.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
 	b := &BulkConnect{
 		d:                d,
 		chainType:        d.chainParser.GetChainType(),
 		txAddressesMap:   make(map[string]*TxAddresses),
 		balances:         make(map[string]*AddrBalance),
 		addressContracts: make(map[string]*unpackedAddrContracts),
+		approvals:        make(map[string][]byte),
 		blockFilters:     make(map[string][]byte),
+		opReturns:        make(map[string][]byte),
+		height:           height,
+		hash:             hash,
+		checkpointBlocks: maxBulkCheckpointBlocks,
 	}
-	if err := d.SetInconsistentState(true); err != nil {
+	d.is.SetBulkCheckpoint(height, hash)
+	if err := d.storeState(d.is); err != nil {
 		return nil, err
 	}
-	glog.Info("rocksdb: bulk connect init, db set to inconsistent state")
+	glog.Info("rocksdb: bulk connect init, checkpoint at height ", height)
 	return b, nil
 }
 
-func (b *BulkConnect) storeTxAddresses(wb *grocksdb.WriteBatch, all bool) (int, int, error) {
-	var txm map[string]*TxAddresses
-	var sp int
-	if all {
-		txm = b.txAddressesMap
-		b.txAddressesMap = make(map[string]*TxAddresses)
-	} else {
-		txm = make(map[string]*TxAddresses)
-		for k, a := range b.txAddressesMap {
-			// store all completely spent transactions, they will not be modified again
-			r := true
-			for _, o := range a.Outputs {
-				if o.Spent == false {
-					r = false
-					break
-				}
-			}
-			if r {
-				txm[k] = a
-				delete(b.txAddressesMap, k)
-			}
-		}
-		sp = len(txm)
-		// store some other random transactions if necessary
-		if len(txm) < partialStoreAddresses {
-			for k, a := range b.txAddressesMap {
-				txm[k] = a
-				delete(b.txAddressesMap, k)
-				if len(txm) >= partialStoreAddresses {
-					break
-				}
-			}
+// storeBulkAddresses stores the addresses of the cached blocks, the heights are stored at the next checkpoint
+func (b *BulkConnect) storeBulkAddresses(wb *grocksdb.WriteBatch) error {
+	for _, ba := range b.bulkAddresses {
+		if err := b.d.storeAddresses(wb, ba.bi.Height, ba.addresses); err != nil {
+			return err
 		}
+		b.pendingHeights = append(b.pendingHeights, ba.bi)
 	}
-	if err := b.d.storeTxAddresses(wb, txm); err != nil {
-		return 0, 0, err
-	}
-	return len(txm), sp, nil
+	b.bulkAddressesCount = 0
+	b.bulkAddresses = b.bulkAddresses[:0]
+	return nil
 }
 
-func (b *BulkConnect) parallelStoreTxAddresses(c chan error, all bool) {
-	defer close(c)
+// checkpoint flushes all cached data and records the checkpoint in one atomic write batch
+func (b *BulkConnect) checkpoint() error {
 	start := time.Now()
 	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
-	count, sp, err := b.storeTxAddresses(wb, all)
-	if err != nil {
-		c <- err
-		return
+	txAddresses, balances, addressContracts := len(b.txAddressesMap), len(b.balances), len(b.addressContracts)
+	if err := b.d.storeTxAddresses(wb, b.txAddressesMap); err != nil {
+		return err
 	}
-	if err := b.d.WriteBatch(wb); err != nil {
-		c <- err
-		return
+	if err := b.d.storeBalances(wb, b.balances); err != nil {
+		return err
 	}
-	glog.Info("rocksdb: height ", b.height, ", stored ", count, " (", sp, " spent) txAddresses, ", len(b.txAddressesMap), " remaining, done in ", time.Since(start))
-	c <- nil
-}
-
-func (b *BulkConnect) storeBalances(wb *grocksdb.WriteBatch, all bool) (int, error) {
-	var bal map[string]*AddrBalance
-	if all {
-		bal = b.balances
-		b.balances = make(map[string]*AddrBalance)
-	} else {
-		bal = make(map[string]*AddrBalance)
-		// store some random balances
-		for k, a := range b.balances {
-			bal[k] = a
-			delete(b.balances, k)
-			if len(bal) >= partialStoreBalances {
-				break
-			}
-		}
+	if err := b.d.storeUnpackedAddressContracts(wb, b.addressContracts); err != nil {
+		return err
 	}
-	if err := b.d.storeBalances(wb, bal); err != nil {
-		return 0, err
+	if err := b.d.storeInternalDataEthereumType(wb, b.ethBlockTxs); err != nil {
+		return err
 	}
-	return len(bal), nil
-}
-
-func (b *BulkConnect) parallelStoreBalances(c chan error, all bool) {
-	defer close(c)
-	start := time.Now()
-	wb := grocksdb.NewWriteBatch()
-	defer wb.Destroy()
-	count, err := b.storeBalances(wb, all)
-	if err != nil {
-		c <- err
-		return
+	b.d.storeUserOperationsEthereumType(wb, b.ethBlockTxs)
+	b.d.storeNftTransfersEthereumType(wb, b.ethBlockTxs)
+	b.d.storeApprovalsEthereumType(wb, b.approvals)
+	if b.chainType == bchain.ChainEthereumType {
+		// large address contracts are kept in the db cache, they must be stored together with the checkpoint
+		b.d.storeAddrContractsCacheToBatch(wb)
 	}
-	if err := b.d.WriteBatch(wb); err != nil {
-		c <- err
-		return
+	if err := b.storeBulkAddresses(wb); err != nil {
+		return err
 	}
-	glog.Info("rocksdb: height ", b.height, ", stored ", count, " balances, ", len(b.balances), " remaining, done in ", time.Since(start))
-	c <- nil
-}
-
-func (b *BulkConnect) storeBulkAddresses(wb *grocksdb.WriteBatch) error {
-	for _, ba := range b.bulkAddresses {
-		if err := b.d.storeAddresses(wb, ba.bi.Height, ba.addresses); err != nil {
-			return err
-		}
-		if err := b.d.writeHeight(wb, ba.bi.Height, &ba.bi, opInsert); err != nil {
+	if err := b.storeBulkBlockFilters(wb); err != nil {
+		return err
+	}
+	b.storeBulkOpReturns(wb)
+	for i := range b.pendingHeights {
+		bi := &b.pendingHeights[i]
+		if err := b.d.writeHeight(wb, bi.Height, bi, opInsert); err != nil {
 			return err
 		}
 	}
-	b.bulkAddressesCount = 0
-	b.bulkAddresses = b.bulkAddresses[:0]
+	for _, cs := range b.chainStats {
+		b.d.storeChainStats(wb, cs)
+	}
+	heights := len(b.pendingHeights)
+	b.d.is.SetBulkCheckpoint(b.height, b.hash)
+	if err := b.d.storeStateToBatch(wb, b.d.is); err != nil {
+		return err
+	}
+	if err := b.d.WriteBatch(wb); err != nil {
+		return err
+	}
+	b.txAddressesMap = make(map[string]*TxAddresses)
+	b.balances = make(map[string]*AddrBalance)
+	b.addressContracts = make(map[string]*unpackedAddrContracts)
+	b.approvals = make(map[string][]byte)
+	b.ethBlockTxs = b.ethBlockTxs[:0]
+	b.pendingHeights = b.pendingHeights[:0]
+	b.chainStats = b.chainStats[:0]
+	glog.Info("rocksdb: checkpoint at height ", b.height, ", stored ", heights, " blocks, ", txAddresses, " txAddresses, ", balances, " balances, ", addressContracts, " addressContracts, done in ", time.Since(start))
 	return nil
 }
 
+// needsCheckpoint checks if the cached data reached the limits
+func (b *BulkConnect) needsCheckpoint() bool {
+	return len(b.txAddressesMap) > maxBulkTxAddresses || len(b.balances) > maxBulkBalances ||
+		len(b.addressContracts) > maxBulkAddrContracts || len(b.approvals) > maxBulkApprovals || len(b.pendingHeights)+len(b.bulkAddresses) >= b.checkpointBlocks
+}
+
+func (b *BulkConnect) storeBulkOpReturns(wb *grocksdb.WriteBatch) {
+	b.d.storeOpReturns(wb, b.opReturns)
+	b.opReturns = make(map[string][]byte)
+}
+
 func (b *BulkConnect) storeBulkBlockFilters(wb *grocksdb.WriteBatch) error {
 	for blockHash, blockFilter := range b.blockFilters {
 		if err := b.d.storeBlockFilter(wb, blockHash, blockFilter); err != nil {
@@ -192,22 +189,14 @@ func (b *BulkConnect) connectBlockBitcoinType(block *bchain.Block, storeBlockTxs
 	} else if gf != nil && !gf.Enabled {
 		gf = nil
 	}
-	if err := b.d.processAddressesBitcoinType(block, addresses, b.txAddressesMap, b.balances, gf); err != nil {
+	cs, err := b.d.chainStatsForBlock(block)
+	if err != nil {
 		return err
 	}
-	var storeAddressesChan, storeBalancesChan chan error
-	var sa bool
-	if len(b.txAddressesMap) > maxBulkTxAddresses || len(b.balances) > maxBulkBalances {
-		sa = true
-		if len(b.txAddressesMap)+partialStoreAddresses > maxBulkTxAddresses {
-			storeAddressesChan = make(chan error)
-			go b.parallelStoreTxAddresses(storeAddressesChan, false)
-		}
-		if len(b.balances)+partialStoreBalances > maxBulkBalances {
-			storeBalancesChan = make(chan error)
-			go b.parallelStoreBalances(storeBalancesChan, false)
-		}
+	if err := b.d.processAddressesBitcoinType(block, addresses, b.txAddressesMap, b.balances, gf, cs); err != nil {
+		return err
 	}
+	b.chainStats = append(b.chainStats, cs)
 	b.bulkAddresses = append(b.bulkAddresses, bulkAddresses{
 		bi: BlockInfo{
 			Hash:   block.Hash,
@@ -222,13 +211,18 @@ func (b *BulkConnect) connectBlockBitcoinType(block *bchain.Block, storeBlockTxs
 	if gf != nil {
 		b.blockFilters[block.BlockHeader.Hash] = gf.Compute()
 	}
+	if b.d.HasOpReturnIndex() {
+		if err := b.d.opReturnRows(block, b.txAddressesMap, b.opReturns); err != nil {
+			return err
+		}
+	}
 	// open WriteBatch only if going to write
-	if sa || b.bulkAddressesCount > maxBulkAddresses || storeBlockTxs || len(b.blockFilters) > maxBlockFilters {
+	if b.bulkAddressesCount > maxBulkAddresses || storeBlockTxs || len(b.blockFilters) > maxBlockFilters || len(b.opReturns) > maxBulkOpReturns {
 		start := time.Now()
 		wb := grocksdb.NewWriteBatch()
 		defer wb.Destroy()
 		bac := b.bulkAddressesCount
-		if sa || b.bulkAddressesCount > maxBulkAddresses {
+		if b.bulkAddressesCount > maxBulkAddresses {
 			if err := b.storeBulkAddresses(wb); err != nil {
 				return err
 			}
@@ -243,6 +237,9 @@ func (b *BulkConnect) connectBlockBitcoinType(block *bchain.Block, storeBlockTxs
 				return err
 			}
 		}
+		if len(b.opReturns) > maxBulkOpReturns {
+			b.storeBulkOpReturns(wb)
+		}
 		if err := b.d.WriteBatch(wb); err != nil {
 			return err
 		}
@@ -250,59 +247,13 @@ func (b *BulkConnect) connectBlockBitcoinType(block *bchain.Block, storeBlockTxs
 			glog.Info("rocksdb: height ", b.height, ", stored ", bac, " addresses, done in ", time.Since(start))
 		}
 	}
-	if storeAddressesChan != nil {
-		if err := <-storeAddressesChan; err != nil {
-			return err
-		}
-	}
-	if storeBalancesChan != nil {
-		if err := <-storeBalancesChan; err != nil {
-			return err
-		}
+	// the blocks close to the tip can be disconnected, make them durable immediately
+	if storeBlockTxs || b.needsCheckpoint() {
+		return b.checkpoint()
 	}
 	return nil
 }
 
-func (b *BulkConnect) storeAddressContracts(wb *grocksdb.WriteBatch, all bool) (int, error) {
-	var ac map[string]*unpackedAddrContracts
-	if all {
-		ac = b.addressContracts
-		b.addressContracts = make(map[string]*unpackedAddrContracts)
-	} else {
-		ac = make(map[string]*unpackedAddrContracts)
-		// store some random address contracts
-		for k, a := range b.addressContracts {
-			ac[k] = a
-			delete(b.addressContracts, k)
-			if len(ac) >= partialStoreAddrContracts {
-				break
-			}
-		}
-	}
-	if err := b.d.storeUnpackedAddressContracts(wb, ac); err != nil {
-		return 0, err
-	}
-	return len(ac), nil
-}
-
-func (b *BulkConnect) parallelStoreAddressContracts(c chan error, all bool) {
-	defer close(c)
-	start := time.Now()
-	wb := grocksdb.NewWriteBatch()
-	defer wb.Destroy()
-	count, err := b.storeAddressContracts(wb, all)
-	if err != nil {
-		c <- err
-		return
-	}
-	if err := b.d.WriteBatch(wb); err != nil {
-		c <- err
-		return
-	}
-	glog.Info("rocksdb: height ", b.height, ", stored ", count, " addressContracts, ", len(b.addressContracts), " remaining, done in ", time.Since(start))
-	c <- nil
-}
-
 func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTxs bool) error {
 	addresses := make(addressesMap)
 	blockTxs, err := b.d.processAddressesEthereumType(block, addresses, b.addressContracts)
@@ -310,12 +261,9 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 		return err
 	}
 	b.ethBlockTxs = append(b.ethBlockTxs, blockTxs...)
-	var storeAddrContracts chan error
-	var sa bool
-	if len(b.addressContracts) > maxBulkAddrContracts {
-		sa = true
-		storeAddrContracts = make(chan error)
-		go b.parallelStoreAddressContracts(storeAddrContracts, false)
+	approvalsUndo, err := b.d.processApprovalsEthereumType(block, b.approvals)
+	if err != nil {
+		return err
 	}
 	b.bulkAddresses = append(b.bulkAddresses, bulkAddresses{
 		bi: BlockInfo{
@@ -329,12 +277,12 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 	})
 	b.bulkAddressesCount += len(addresses)
 	// open WriteBatch only if going to write
-	if sa || b.bulkAddressesCount > maxBulkAddresses || storeBlockTxs {
+	if b.bulkAddressesCount > maxBulkAddresses || storeBlockTxs {
 		start := time.Now()
 		wb := grocksdb.NewWriteBatch()
 		defer wb.Destroy()
 		bac := b.bulkAddressesCount
-		if sa || b.bulkAddressesCount > maxBulkAddresses {
+		if b.bulkAddressesCount > maxBulkAddresses {
 			if err = b.storeBulkAddresses(wb); err != nil {
 				return err
 			}
@@ -342,6 +290,8 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 		if err = b.d.storeInternalDataEthereumType(wb, b.ethBlockTxs); err != nil {
 			return err
 		}
+		b.d.storeUserOperationsEthereumType(wb, b.ethBlockTxs)
+		b.d.storeNftTransfersEthereumType(wb, b.ethBlockTxs)
 		b.ethBlockTxs = b.ethBlockTxs[:0]
 		if err = b.d.storeBlockSpecificDataEthereumType(wb, block); err != nil {
 			return err
@@ -350,6 +300,7 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 			if err = b.d.storeAndCleanupBlockTxsEthereumType(wb, block, blockTxs); err != nil {
 				return err
 			}
+			b.d.storeApprovalsUndoEthereumType(wb, block.Height, approvalsUndo)
 		}
 		if err = b.d.WriteBatch(wb); err != nil {
 			return err
@@ -371,10 +322,9 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 			}
 		}
 	}
-	if storeAddrContracts != nil {
-		if err := <-storeAddrContracts; err != nil {
-			return err
-		}
+	// the blocks close to the tip can be disconnected, make them durable immediately
+	if storeBlockTxs || b.needsCheckpoint() {
+		return b.checkpoint()
 	}
 	return nil
 }
@@ -382,6 +332,7 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 // ConnectBlock connects block in bulk mode
 func (b *BulkConnect) ConnectBlock(block *bchain.Block, storeBlockTxs bool) error {
 	b.height = block.Height
+	b.hash = block.Hash
 	if b.chainType == bchain.ChainBitcoinType {
 		return b.connectBlockBitcoinType(block, storeBlockTxs)
 	} else if b.chainType == bchain.ChainEthereumType {
@@ -391,53 +342,14 @@ func (b *BulkConnect) ConnectBlock(block *bchain.Block, storeBlockTxs bool) erro
 	return b.d.ConnectBlock(block)
 }
 
-// Close flushes the cached data and switches DB from inconsistent state open
+// Close flushes the cached data in the last checkpoint and clears the checkpoint from the internal state
 // after Close, the BulkConnect cannot be used
 func (b *BulkConnect) Close() error {
 	glog.Info("rocksdb: bulk connect closing")
-	start := time.Now()
-	var storeTxAddressesChan, storeBalancesChan, storeAddressContractsChan chan error
-	if b.chainType == bchain.ChainBitcoinType {
-		storeTxAddressesChan = make(chan error)
-		go b.parallelStoreTxAddresses(storeTxAddressesChan, true)
-		storeBalancesChan = make(chan error)
-		go b.parallelStoreBalances(storeBalancesChan, true)
-	} else if b.chainType == bchain.ChainEthereumType {
-		storeAddressContractsChan = make(chan error)
-		go b.parallelStoreAddressContracts(storeAddressContractsChan, true)
-	}
-	wb := grocksdb.NewWriteBatch()
-	defer wb.Destroy()
-	if err := b.d.storeInternalDataEthereumType(wb, b.ethBlockTxs); err != nil {
-		return err
-	}
-	b.ethBlockTxs = b.ethBlockTxs[:0]
-	bac := b.bulkAddressesCount
-	if err := b.storeBulkAddresses(wb); err != nil {
+	if err := b.checkpoint(); err != nil {
 		return err
 	}
-	if err := b.storeBulkBlockFilters(wb); err != nil {
-		return err
-	}
-	if err := b.d.WriteBatch(wb); err != nil {
-		return err
-	}
-	glog.Info("rocksdb: height ", b.height, ", stored ", bac, " addresses, done in ", time.Since(start))
-	if storeTxAddressesChan != nil {
-		if err := <-storeTxAddressesChan; err != nil {
-			return err
-		}
-	}
-	if storeBalancesChan != nil {
-		if err := <-storeBalancesChan; err != nil {
-			return err
-		}
-	}
-	if storeAddressContractsChan != nil {
-		if err := <-storeAddressContractsChan; err != nil {
-			return err
-		}
-	}
+	b.d.is.ClearBulkCheckpoint()
 	if err := b.d.SetInconsistentState(false); err != nil {
 		return err
 	}
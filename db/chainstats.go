@@ -0,0 +1,300 @@
+package db
+
+import (
+	"math/big"
+
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/martinboehm/btcd/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// chain statistics
+// for each block a row with cumulative counters is stored in the chainStats column, the key is the block height
+// the values for a single block or an interval of blocks are obtained as a difference of two rows
+// the counters are accumulated from the first block connected with the chainStats column present,
+// for databases created before the chainStats column was introduced the StartHeight is not zero
+
+// ChainStatsScriptType is the type of the output script in the chain statistics
+type ChainStatsScriptType int
+
+// ChainStatsScriptType enumeration, new types must be appended to keep the stored data compatible
+const (
+	ChainStatsNonStandard = ChainStatsScriptType(iota)
+	ChainStatsP2PK
+	ChainStatsP2PKH
+	ChainStatsP2SH
+	ChainStatsP2WPKH
+	ChainStatsP2WSH
+	ChainStatsP2TR
+	ChainStatsOpReturn
+	ChainStatsAsset
+	chainStatsScriptTypes
+)
+
+// ChainStatsScriptTypeNames are the names of ChainStatsScriptType used in the API
+var ChainStatsScriptTypeNames = [chainStatsScriptTypes]string{"nonstandard", "p2pk", "p2pkh", "p2sh", "p2wpkh", "p2wsh", "p2tr", "opreturn", "asset"}
+
+// ChainStatsDustLimit is the value in base units under which a spendable output is counted as dust
+const ChainStatsDustLimit = 546
+
+// ChainStatsOutputs contains the number and value of outputs
+type ChainStatsOutputs struct {
+	Count    uint64
+	ValueSat big.Int
+}
+
+// ChainStats contains cumulative chain statistics at the given height
+type ChainStats struct {
+	Height      uint32
+	Time        int64
+	StartHeight uint32
+	Txs         uint64
+	Outputs     [chainStatsScriptTypes]ChainStatsOutputs
+	DustOutputs uint64
+	Utxos       uint64
+	UtxosSat    big.Int
+	DustUtxos   uint64
+}
+
+func (cs *ChainStats) copy() *ChainStats {
+	r := *cs
+	for i := range cs.Outputs {
+		r.Outputs[i].ValueSat = *new(big.Int).Set(&cs.Outputs[i].ValueSat)
+	}
+	r.UtxosSat = *new(big.Int).Set(&cs.UtxosSat)
+	return &r
+}
+
+func isChainStatsDust(valueSat *big.Int) bool {
+	// zero value outputs are typically not coin outputs (for example assets), they are not counted as dust
+	return valueSat.Sign() > 0 && valueSat.Cmp(big.NewInt(ChainStatsDustLimit)) < 0
+}
+
+// addOutput adds a new output to the statistics
+func (cs *ChainStats) addOutput(t ChainStatsScriptType, valueSat *big.Int) {
+	o := &cs.Outputs[t]
+	o.Count++
+	o.ValueSat.Add(&o.ValueSat, valueSat)
+	// OP_RETURN outputs are not spendable, they are not part of the utxo set
+	if t == ChainStatsOpReturn {
+		return
+	}
+	cs.Utxos++
+	cs.UtxosSat.Add(&cs.UtxosSat, valueSat)
+	if isChainStatsDust(valueSat) {
+		cs.DustOutputs++
+		cs.DustUtxos++
+	}
+}
+
+// spendOutput removes a spent output from the utxo set statistics
+func (cs *ChainStats) spendOutput(valueSat *big.Int) {
+	if cs.Utxos > 0 {
+		cs.Utxos--
+	}
+	cs.UtxosSat.Sub(&cs.UtxosSat, valueSat)
+	if cs.UtxosSat.Sign() < 0 {
+		// can happen only if the statistics do not start from the genesis block
+		cs.UtxosSat.SetInt64(0)
+	}
+	if isChainStatsDust(valueSat) && cs.DustUtxos > 0 {
+		cs.DustUtxos--
+	}
+}
+
+// chainStatsScriptType returns the type of the output script, the address descriptor of bitcoin type coins is the output script
+func chainStatsScriptType(addrDesc bchain.AddressDescriptor) ChainStatsScriptType {
+	l := len(addrDesc)
+	switch {
+	case l == 0:
+		return ChainStatsNonStandard
+	case addrDesc[0] == txscript.OP_RETURN:
+		return ChainStatsOpReturn
+	case l == 25 && addrDesc[0] == txscript.OP_DUP && addrDesc[1] == txscript.OP_HASH160 && addrDesc[2] == txscript.OP_DATA_20 &&
+		addrDesc[23] == txscript.OP_EQUALVERIFY && addrDesc[24] == txscript.OP_CHECKSIG:
+		return ChainStatsP2PKH
+	case l == 23 && addrDesc[0] == txscript.OP_HASH160 && addrDesc[1] == txscript.OP_DATA_20 && addrDesc[22] == txscript.OP_EQUAL:
+		return ChainStatsP2SH
+	case l == 22 && addrDesc[0] == txscript.OP_0 && addrDesc[1] == txscript.OP_DATA_20:
+		return ChainStatsP2WPKH
+	case l == 34 && addrDesc[0] == txscript.OP_0 && addrDesc[1] == txscript.OP_DATA_32:
+		return ChainStatsP2WSH
+	case l == 34 && addrDesc[0] == txscript.OP_1 && addrDesc[1] == txscript.OP_DATA_32:
+		return ChainStatsP2TR
+	case l == 35 && addrDesc[0] == txscript.OP_DATA_33 && addrDesc[34] == txscript.OP_CHECKSIG,
+		l == 67 && addrDesc[0] == txscript.OP_DATA_65 && addrDesc[66] == txscript.OP_CHECKSIG:
+		return ChainStatsP2PK
+	}
+	return ChainStatsNonStandard
+}
+
+func packChainStats(cs *ChainStats) []byte {
+	buf := make([]byte, 0, 256)
+	varBuf := make([]byte, maxPackedBigintBytes)
+	l := packVarint(int(cs.Time), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(cs.StartHeight), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(cs.Txs), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(len(cs.Outputs)), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	for i := range cs.Outputs {
+		l = packVaruint(uint(cs.Outputs[i].Count), varBuf)
+		buf = append(buf, varBuf[:l]...)
+		l = packBigint(&cs.Outputs[i].ValueSat, varBuf)
+		buf = append(buf, varBuf[:l]...)
+	}
+	l = packVaruint(uint(cs.DustOutputs), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(cs.Utxos), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packBigint(&cs.UtxosSat, varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(cs.DustUtxos), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	return buf
+}
+
+func unpackChainStats(height uint32, buf []byte) (*ChainStats, error) {
+	if len(buf) < 4 {
+		return nil, errors.New("Invalid chain stats")
+	}
+	cs := ChainStats{Height: height}
+	t, l := unpackVarint(buf)
+	cs.Time = int64(t)
+	buf = buf[l:]
+	u, l := unpackVaruint(buf)
+	cs.StartHeight = uint32(u)
+	buf = buf[l:]
+	u, l = unpackVaruint(buf)
+	cs.Txs = uint64(u)
+	buf = buf[l:]
+	types, l := unpackVaruint(buf)
+	buf = buf[l:]
+	for i := 0; i < int(types); i++ {
+		if len(buf) < 2 {
+			return nil, errors.New("Invalid chain stats")
+		}
+		count, l := unpackVaruint(buf)
+		buf = buf[l:]
+		value, l := unpackBigint(buf)
+		buf = buf[l:]
+		// ignore unknown script types stored by a newer version
+		if i < len(cs.Outputs) {
+			cs.Outputs[i].Count = uint64(count)
+			cs.Outputs[i].ValueSat = value
+		}
+	}
+	if len(buf) < 4 {
+		return nil, errors.New("Invalid chain stats")
+	}
+	u, l = unpackVaruint(buf)
+	cs.DustOutputs = uint64(u)
+	buf = buf[l:]
+	u, l = unpackVaruint(buf)
+	cs.Utxos = uint64(u)
+	buf = buf[l:]
+	cs.UtxosSat, l = unpackBigint(buf)
+	buf = buf[l:]
+	u, _ = unpackVaruint(buf)
+	cs.DustUtxos = uint64(u)
+	return &cs, nil
+}
+
+// loadChainStats caches the statistics of the best block in the db,
+// the following blocks then take the statistics of the previous block from the cache without reading the db
+func (d *RocksDB) loadChainStats() error {
+	d.chainStats = nil
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil
+	}
+	height, hash, err := d.GetBestBlock()
+	if err != nil || hash == "" {
+		return err
+	}
+	cs, err := d.GetChainStats(height)
+	if err != nil {
+		return err
+	}
+	if cs == nil {
+		// the db was created before the chainStats column, the statistics start at the next block
+		cs = &ChainStats{Height: height, StartHeight: height + 1}
+	}
+	d.chainStats = cs
+	return nil
+}
+
+// chainStatsForBlock returns the statistics of the previous block, which are to be updated by the block
+func (d *RocksDB) chainStatsForBlock(block *bchain.Block) (*ChainStats, error) {
+	var prev *ChainStats
+	if d.chainStats != nil && block.Height > 0 && d.chainStats.Height == block.Height-1 {
+		prev = d.chainStats
+	} else if block.Height > 0 {
+		var err error
+		if prev, err = d.GetChainStats(block.Height - 1); err != nil {
+			return nil, err
+		}
+	}
+	var cs *ChainStats
+	if prev == nil {
+		cs = &ChainStats{StartHeight: block.Height}
+	} else {
+		cs = prev.copy()
+	}
+	cs.Height = block.Height
+	cs.Time = block.Time
+	cs.Txs += uint64(len(block.Txs))
+	d.chainStats = cs
+	return cs, nil
+}
+
+func (d *RocksDB) storeChainStats(wb *grocksdb.WriteBatch, cs *ChainStats) {
+	wb.PutCF(d.cfh[cfChainStats], packUint(cs.Height), packChainStats(cs))
+}
+
+// GetChainStats returns cumulative chain statistics at the given height or nil if not found
+func (d *RocksDB) GetChainStats(height uint32) (*ChainStats, error) {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil, errors.New("Not supported")
+	}
+	val, err := d.db.GetCF(d.ro, d.cfh[cfChainStats], packUint(height))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	return unpackChainStats(height, buf)
+}
+
+// IterateChainStats calls fn for cumulative chain statistics of blocks in range lower-higher
+// iteration stops when fn returns false or error
+func (d *RocksDB) IterateChainStats(lower, higher uint32, fn func(cs *ChainStats) (bool, error)) error {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return errors.New("Not supported")
+	}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfChainStats])
+	defer it.Close()
+	for it.Seek(packUint(lower)); it.Valid(); it.Next() {
+		height := unpackUint(it.Key().Data())
+		if height > higher {
+			break
+		}
+		cs, err := unpackChainStats(height, it.Value().Data())
+		if err != nil {
+			return err
+		}
+		cont, err := fn(cs)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+//go:build unittest
+
+package db
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/trezor/blockbook/tests/dbtestdata"
+)
+
+func TestChainStatsScriptType(t *testing.T) {
+	tests := []struct {
+		name     string
+		addrDesc string
+		want     ChainStatsScriptType
+	}{
+		{"empty", "", ChainStatsNonStandard},
+		{"p2pk compressed", "21020e46e79a2a8d12b9b5d12c7a91adb4e454edfae43c0a0cb805427d2ac7613fd9ac", ChainStatsP2PK},
+		{"p2pk uncompressed", "410411db93e1dcdb8a016b49840f8c53bc1eb68a382e97b1482ecad7b148a6909a5cb2e0eaddfb84ccf9744464f82e160bfa9b8b64f9d4c03f999b8643f656b412a3ac", ChainStatsP2PK},
+		{"p2pkh", "76a914010d39800f86122416e28f485029acf77507169288ac", ChainStatsP2PKH},
+		{"p2sh", "a9142a227e0a5c6ef4bfba7a1f98e4e3ab1a1c4ad76c87", ChainStatsP2SH},
+		{"p2wpkh", "00145fe1ed7d2b4b7e04f6b8a3cb8a0c6b0c4e2f7a11", ChainStatsP2WPKH},
+		{"p2wsh", "00204ecd0c4ee9a3b8f6c4ea6f3e4fb3c20e4ea1a0e2dc5f3a5f2b4d6cb2a7b1e0f1", ChainStatsP2WSH},
+		{"p2tr", "51205a2c1b0e6f5d7a0e2b4c9d8f1e3a5b7c9d1e3f5a7b9c1d3e5f7a9b1c3d5e7f9a", ChainStatsP2TR},
+		{"op_return", "6a072020f1686f6a20", ChainStatsOpReturn},
+		{"multisig", "5121020e46e79a2a8d12b9b5d12c7a91adb4e454edfae43c0a0cb805427d2ac7613fd951ae", ChainStatsNonStandard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chainStatsScriptType(hexToBytes(tt.addrDesc)); got != tt.want {
+				t.Errorf("chainStatsScriptType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainStats_addOutput_spendOutput(t *testing.T) {
+	cs := &ChainStats{}
+	cs.addOutput(ChainStatsP2WPKH, big.NewInt(100000))
+	cs.addOutput(ChainStatsP2PKH, big.NewInt(330))
+	cs.addOutput(ChainStatsOpReturn, big.NewInt(0))
+	cs.addOutput(ChainStatsAsset, big.NewInt(0))
+	if cs.Utxos != 3 || cs.UtxosSat.Cmp(big.NewInt(100330)) != 0 || cs.DustOutputs != 1 || cs.DustUtxos != 1 {
+		t.Fatalf("after addOutput got %+v", cs)
+	}
+	if cs.Outputs[ChainStatsOpReturn].Count != 1 || cs.Outputs[ChainStatsP2WPKH].ValueSat.Cmp(big.NewInt(100000)) != 0 {
+		t.Fatalf("after addOutput got outputs %+v", cs.Outputs)
+	}
+	c := cs.copy()
+	cs.spendOutput(big.NewInt(330))
+	if cs.Utxos != 2 || cs.UtxosSat.Cmp(big.NewInt(100000)) != 0 || cs.DustOutputs != 1 || cs.DustUtxos != 0 {
+		t.Fatalf("after spendOutput got %+v", cs)
+	}
+	if c.Utxos != 3 || c.UtxosSat.Cmp(big.NewInt(100330)) != 0 || c.DustUtxos != 1 {
+		t.Fatalf("copy modified by spendOutput %+v", c)
+	}
+}
+
+func TestPackUnpackChainStats(t *testing.T) {
+	cs := &ChainStats{
+		Height:      225494,
+		Time:        1534859988,
+		StartHeight: 225493,
+		Txs:         6,
+		DustOutputs: 1,
+		Utxos:       9,
+		DustUtxos:   1,
+	}
+	cs.Outputs[ChainStatsP2PKH] = ChainStatsOutputs{Count: 3, ValueSat: *big.NewInt(1234567890123)}
+	cs.Outputs[ChainStatsP2SH] = ChainStatsOutputs{Count: 5, ValueSat: *big.NewInt(98765)}
+	cs.Outputs[ChainStatsOpReturn] = ChainStatsOutputs{Count: 2}
+	cs.UtxosSat = *big.NewInt(1234567988888)
+	buf := packChainStats(cs)
+	got, err := unpackChainStats(cs.Height, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, cs) {
+		t.Errorf("unpackChainStats() = %+v, want %+v", got, cs)
+	}
+	if _, err := unpackChainStats(cs.Height, buf[:3]); err == nil {
+		t.Error("unpackChainStats() of truncated data expected error")
+	}
+}
+
+func TestRocksDB_ChainStats_BitcoinType(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+	cs1, err := d.GetChainStats(block1.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs1 == nil || cs1.StartHeight != block1.Height || cs1.Time != block1.Time || cs1.Txs != uint64(len(block1.Txs)) {
+		t.Fatalf("GetChainStats(%d) = %+v", block1.Height, cs1)
+	}
+	// the inputs of the block 1 are not known, all created spendable outputs are in the utxo set
+	var outputs, utxos uint64
+	for i := range cs1.Outputs {
+		outputs += cs1.Outputs[i].Count
+		if ChainStatsScriptType(i) != ChainStatsOpReturn {
+			utxos += cs1.Outputs[i].Count
+		}
+	}
+	var vouts uint64
+	for i := range block1.Txs {
+		vouts += uint64(len(block1.Txs[i].Vout))
+	}
+	if outputs != vouts || cs1.Utxos != utxos {
+		t.Fatalf("GetChainStats(%d) outputs %d, want %d, utxos %d, want %d", block1.Height, outputs, vouts, cs1.Utxos, utxos)
+	}
+
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+	cs2, err := d.GetChainStats(block2.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs2 == nil || cs2.StartHeight != block1.Height || cs2.Txs != uint64(len(block1.Txs)+len(block2.Txs)) {
+		t.Fatalf("GetChainStats(%d) = %+v", block2.Height, cs2)
+	}
+	var heights []uint32
+	if err := d.IterateChainStats(0, block2.Height, func(cs *ChainStats) (bool, error) {
+		heights = append(heights, cs.Height)
+		return true, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(heights, []uint32{block1.Height, block2.Height}) {
+		t.Fatalf("IterateChainStats heights %v", heights)
+	}
+
+	if err := d.DisconnectBlockRangeBitcoinType(block2.Height, block2.Height); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := d.GetChainStats(block2.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs != nil {
+		t.Fatalf("GetChainStats(%d) after disconnect = %+v, want nil", block2.Height, cs)
+	}
+	cs, err = d.GetChainStats(block1.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cs, cs1) {
+		t.Fatalf("GetChainStats(%d) after disconnect = %+v, want %+v", block1.Height, cs, cs1)
+	}
+}
@@ -168,6 +168,30 @@ func (d *RocksDB) FiatRatesGetAllTickers(fn func(ticker *common.CurrencyRatesTic
 	return nil
 }
 
+// FiatRatesGetTickersInRange iterates over FiatRates data with the timestamp in the range [from, to)
+func (d *RocksDB) FiatRatesGetTickersInRange(from, to *time.Time, fn func(ticker *common.CurrencyRatesTicker) error) error {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfFiatRates])
+	defer it.Close()
+
+	toFormatted := to.UTC().Format(FiatRatesTimeFormat)
+	for it.Seek([]byte(from.UTC().Format(FiatRatesTimeFormat))); it.Valid(); it.Next() {
+		if string(it.Key().Data()) >= toFormatted {
+			break
+		}
+		ticker, err := getTickerFromIterator(it, "", "")
+		if err != nil {
+			return err
+		}
+		if ticker == nil {
+			return errors.New("FiatRatesGetTickersInRange got nil ticker")
+		}
+		if err = fn(ticker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // FiatRatesFindLastTicker gets the last FiatRates record, of the base currency, vsCurrency or the token if specified
 func (d *RocksDB) FiatRatesFindLastTicker(vsCurrency string, token string) (*common.CurrencyRatesTicker, error) {
 	it := d.db.NewIteratorCF(d.ro, d.cfh[cfFiatRates])
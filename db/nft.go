@@ -0,0 +1,342 @@
+package db
+
+import (
+	"bytes"
+	"math/big"
+	"time"
+
+	vlq "github.com/bsm/go-vlq"
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/eth"
+)
+
+// NFT collections
+// the nftHolders column contains keys contract+id+owner of the ERC721 and ERC1155 tokens, the value is the balance of the ERC1155 token
+// (empty for ERC721), the index is updated from the address contracts in the same write batch, as the contractHolders index of the rich list
+// the nftTransfers column contains the transfers of the tokens, keys contract+id+height+txid+index, values from+to+value
+// the nftMetadata column caches the metadata fetched from the token URI, keys contract+id
+
+// MaxNftItems is the maximum number of holders of a collection which can be listed
+const MaxNftItems = 10000
+
+// MaxNftTransfers is the maximum number of the returned transfers of a token
+const MaxNftTransfers = 1000
+
+// NftHolder is the owner of a token of a collection
+type NftHolder struct {
+	Id      big.Int
+	Owner   bchain.AddressDescriptor
+	Balance big.Int
+}
+
+// NftTransfer is a transfer of a token of a collection
+type NftTransfer struct {
+	Txid   string
+	Height uint32
+	From   bchain.AddressDescriptor
+	To     bchain.AddressDescriptor
+	Value  big.Int
+}
+
+// NftMetadata is the cached metadata of a token
+type NftMetadata struct {
+	URI      string
+	ImageURI string
+	Metadata string
+	Time     int64
+}
+
+func packNftKey(contract bchain.AddressDescriptor, id *big.Int) []byte {
+	varBuf := make([]byte, maxPackedBigintBytes)
+	l := packBigint(id, varBuf)
+	key := make([]byte, 0, eth.EthereumTypeAddressDescriptorLen+l)
+	key = appendAddress(key, contract)
+	return append(key, varBuf[:l]...)
+}
+
+func (b *unpackedBigInt) packed() []byte {
+	if b.Value != nil {
+		varBuf := make([]byte, maxPackedBigintBytes)
+		l := packBigint(b.Value, varBuf)
+		return varBuf[:l]
+	}
+	return b.Slice
+}
+
+// nftPackedHolders returns the packed balances of the ERC721 and ERC1155 tokens of the address contracts in a map with keys contract+packed id
+func nftPackedHolders(acs *unpackedAddrContracts) map[string][]byte {
+	holders := make(map[string][]byte)
+	if acs == nil {
+		return holders
+	}
+	for i := range acs.Contracts {
+		ac := &acs.Contracts[i]
+		if ac.Standard == bchain.NonFungibleToken {
+			for j := range ac.Ids {
+				holders[string(ac.Contract)+string(ac.Ids[j].packed())] = []byte{}
+			}
+		} else if ac.Standard == bchain.MultiToken {
+			for j := range ac.MultiTokenValues {
+				m := &ac.MultiTokenValues[j]
+				holders[string(ac.Contract)+string(m.Id.packed())] = m.Value.packed()
+			}
+		}
+	}
+	return holders
+}
+
+// updateNftHolders updates the nftHolders index from the previously stored packed address contracts to the new state
+func (d *RocksDB) updateNftHolders(wb *grocksdb.WriteBatch, addrDesc bchain.AddressDescriptor, oldPacked []byte, acs *unpackedAddrContracts) error {
+	var oldAcs *unpackedAddrContracts
+	if len(oldPacked) > 0 {
+		var err error
+		if oldAcs, err = partiallyUnpackAddrContracts(oldPacked); err != nil {
+			return err
+		}
+	}
+	old := nftPackedHolders(oldAcs)
+	for k, v := range nftPackedHolders(acs) {
+		if ov, found := old[k]; !found || !bytes.Equal(ov, v) {
+			wb.PutCF(d.cfh[cfNftHolders], append([]byte(k), addrDesc...), v)
+		}
+		delete(old, k)
+	}
+	// tokens transferred from the address
+	for k := range old {
+		wb.DeleteCF(d.cfh[cfNftHolders], append([]byte(k), addrDesc...))
+	}
+	return nil
+}
+
+func unpackNftHolderKey(key []byte) (*NftHolder, error) {
+	if len(key) <= 2*eth.EthereumTypeAddressDescriptorLen {
+		return nil, errors.New("Invalid nft holder key")
+	}
+	key = key[eth.EthereumTypeAddressDescriptorLen:]
+	l := packedBigintLen(key)
+	if len(key) != l+eth.EthereumTypeAddressDescriptorLen {
+		return nil, errors.New("Invalid nft holder key")
+	}
+	id, _ := unpackBigint(key)
+	return &NftHolder{
+		Id:    id,
+		Owner: append(bchain.AddressDescriptor(nil), key[l:]...),
+	}, nil
+}
+
+func (d *RocksDB) getNftHolders(prefix []byte, offset, count int) ([]NftHolder, int, error) {
+	items := make([]NftHolder, 0, count)
+	total := 0
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfNftHolders])
+	defer it.Close()
+	for it.Seek(prefix); it.Valid() && total < MaxNftItems; it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		if total >= offset && len(items) < count {
+			item, err := unpackNftHolderKey(key)
+			if err != nil {
+				return nil, 0, err
+			}
+			if val := it.Value().Data(); len(val) > 0 {
+				item.Balance, _ = unpackBigint(val)
+			} else {
+				item.Balance.SetInt64(1)
+			}
+			items = append(items, *item)
+		}
+		total++
+	}
+	return items, total, nil
+}
+
+// GetNftHolders returns the tokens of the collection with their owners ordered by the token id
+// offset items are skipped, at most count items are returned together with the total number of items limited to MaxNftItems
+func (d *RocksDB) GetNftHolders(contract bchain.AddressDescriptor, offset, count int) ([]NftHolder, int, error) {
+	if len(contract) != eth.EthereumTypeAddressDescriptorLen {
+		return nil, 0, errors.New("Invalid contract")
+	}
+	return d.getNftHolders(contract, offset, count)
+}
+
+// GetNftTokenHolders returns the current owners of the token
+func (d *RocksDB) GetNftTokenHolders(contract bchain.AddressDescriptor, id *big.Int) ([]NftHolder, error) {
+	if len(contract) != eth.EthereumTypeAddressDescriptorLen {
+		return nil, errors.New("Invalid contract")
+	}
+	items, _, err := d.getNftHolders(packNftKey(contract, id), 0, MaxNftItems)
+	return items, err
+}
+
+func packNftTransferKey(contract bchain.AddressDescriptor, id *big.Int, height uint32, btxID []byte, index int) []byte {
+	key := packNftKey(contract, id)
+	key = append(key, packUint(height)...)
+	key = append(key, btxID...)
+	varBuf := make([]byte, vlq.MaxLen64)
+	l := packVaruint(uint(index), varBuf)
+	return append(key, varBuf[:l]...)
+}
+
+func packNftTransferValue(from, to bchain.AddressDescriptor, value *big.Int) []byte {
+	buf := make([]byte, 0, 2*eth.EthereumTypeAddressDescriptorLen+maxPackedBigintBytes)
+	buf = appendAddress(buf, from)
+	buf = appendAddress(buf, to)
+	varBuf := make([]byte, maxPackedBigintBytes)
+	l := packBigint(value, varBuf)
+	return append(buf, varBuf[:l]...)
+}
+
+var nftTransferValueOne = big.NewInt(1)
+
+// forEachNftTransfer calls f for each ERC721 and ERC1155 token transferred by the contract transfers of the tx
+func forEachNftTransfer(blockTx *ethBlockTx, f func(c *ethBlockTxContract, index int, id, value *big.Int)) {
+	for j := range blockTx.contracts {
+		c := &blockTx.contracts[j]
+		if c.transferStandard == bchain.NonFungibleToken {
+			f(c, j, &c.value, nftTransferValueOne)
+		} else if c.transferStandard == bchain.MultiToken {
+			for k := range c.idValues {
+				f(c, j, &c.idValues[k].Id, &c.idValues[k].Value)
+			}
+		}
+	}
+}
+
+func (d *RocksDB) storeNftTransfersEthereumType(wb *grocksdb.WriteBatch, blockTxs []ethBlockTx) {
+	for i := range blockTxs {
+		blockTx := &blockTxs[i]
+		forEachNftTransfer(blockTx, func(c *ethBlockTxContract, index int, id, value *big.Int) {
+			wb.PutCF(d.cfh[cfNftTransfers], packNftTransferKey(c.contract, id, blockTx.height, blockTx.btxID, index), packNftTransferValue(c.from, c.to, value))
+		})
+	}
+}
+
+func (d *RocksDB) disconnectNftTransfersEthereumType(wb *grocksdb.WriteBatch, height uint32, blockTx *ethBlockTx) {
+	forEachNftTransfer(blockTx, func(c *ethBlockTxContract, index int, id, value *big.Int) {
+		wb.DeleteCF(d.cfh[cfNftTransfers], packNftTransferKey(c.contract, id, height, blockTx.btxID, index))
+	})
+}
+
+// GetNftTransfers returns the transfers of the token from the newest, at most MaxNftTransfers transfers are returned
+func (d *RocksDB) GetNftTransfers(contract bchain.AddressDescriptor, id *big.Int) ([]NftTransfer, error) {
+	if len(contract) != eth.EthereumTypeAddressDescriptorLen {
+		return nil, errors.New("Invalid contract")
+	}
+	prefix := packNftKey(contract, id)
+	keyLen := len(prefix) + packedHeightBytes + eth.EthereumTypeTxidLen
+	// seek behind the last possible key of the token
+	last := append(append([]byte(nil), prefix...), 0xff, 0xff, 0xff, 0xff, 0xff)
+	var transfers []NftTransfer
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfNftTransfers])
+	defer it.Close()
+	for it.SeekForPrev(last); it.Valid() && len(transfers) < MaxNftTransfers; it.Prev() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		val := it.Value().Data()
+		if len(key) <= keyLen || len(val) <= 2*eth.EthereumTypeAddressDescriptorLen {
+			glog.Error("rocksdb: Inconsistent data in nftTransfers")
+			return nil, errors.New("Inconsistent data in nftTransfers")
+		}
+		txid, err := d.chainParser.UnpackTxid(key[len(prefix)+packedHeightBytes : keyLen])
+		if err != nil {
+			return nil, err
+		}
+		t := NftTransfer{
+			Txid:   txid,
+			Height: unpackUint(key[len(prefix) : len(prefix)+packedHeightBytes]),
+			From:   append(bchain.AddressDescriptor(nil), val[:eth.EthereumTypeAddressDescriptorLen]...),
+			To:     append(bchain.AddressDescriptor(nil), val[eth.EthereumTypeAddressDescriptorLen:2*eth.EthereumTypeAddressDescriptorLen]...),
+		}
+		t.Value, _ = unpackBigint(val[2*eth.EthereumTypeAddressDescriptorLen:])
+		transfers = append(transfers, t)
+	}
+	return transfers, nil
+}
+
+func packNftMetadata(m *NftMetadata) []byte {
+	varBuf := make([]byte, vlq.MaxLen64)
+	l := packVaruint(uint(m.Time), varBuf)
+	buf := append([]byte(nil), varBuf[:l]...)
+	buf = append(buf, packString(m.URI)...)
+	buf = append(buf, packString(m.ImageURI)...)
+	return append(buf, packString(m.Metadata)...)
+}
+
+func unpackNftMetadata(buf []byte) *NftMetadata {
+	var m NftMetadata
+	t, l := unpackVaruint(buf)
+	m.Time = int64(t)
+	buf = buf[l:]
+	m.URI, l = unpackString(buf)
+	buf = buf[l:]
+	m.ImageURI, l = unpackString(buf)
+	buf = buf[l:]
+	m.Metadata, _ = unpackString(buf)
+	return &m
+}
+
+// GetNftMetadata returns the cached metadata of the token or nil if not cached
+func (d *RocksDB) GetNftMetadata(contract bchain.AddressDescriptor, id *big.Int) (*NftMetadata, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfNftMetadata], packNftKey(contract, id))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	return unpackNftMetadata(buf), nil
+}
+
+// StoreNftMetadata stores the metadata of the token to the cache
+func (d *RocksDB) StoreNftMetadata(contract bchain.AddressDescriptor, id *big.Int, m *NftMetadata) error {
+	return d.db.PutCF(d.wo, d.cfh[cfNftMetadata], packNftKey(contract, id), packNftMetadata(m))
+}
+
+// buildNftHolderIndex creates the nftHolders index from the stored address contracts, it is used when the column is added to an existing db
+func (d *RocksDB) buildNftHolderIndex() error {
+	glog.Info("buildNftHolderIndex: starting")
+	start := time.Now()
+	var rows, indexed int
+	// do not use cache
+	ro := grocksdb.NewDefaultReadOptions()
+	ro.SetFillCache(false)
+	defer ro.Destroy()
+	it := d.db.NewIteratorCF(ro, d.cfh[cfAddressContracts])
+	defer it.Close()
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		addrDesc := append(bchain.AddressDescriptor(nil), it.Key().Data()...)
+		acs, err := partiallyUnpackAddrContracts(it.Value().Data())
+		if err != nil {
+			glog.Error("buildNftHolderIndex: address ", addrDesc, ", error ", err)
+			continue
+		}
+		if err := d.updateNftHolders(wb, addrDesc, nil, acs); err != nil {
+			return err
+		}
+		rows++
+		if wb.Count() > 100000 {
+			indexed += wb.Count()
+			if err := d.WriteBatch(wb); err != nil {
+				return err
+			}
+			wb.Clear()
+			glog.Info("buildNftHolderIndex: processed ", rows, " rows")
+		}
+	}
+	indexed += wb.Count()
+	if err := d.WriteBatch(wb); err != nil {
+		return err
+	}
+	glog.Info("buildNftHolderIndex: finished, processed ", rows, " rows, indexed ", indexed, " tokens in ", time.Since(start))
+	return nil
+}
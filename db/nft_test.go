@@ -0,0 +1,116 @@
+//go:build unittest
+
+package db
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/trezor/blockbook/tests/dbtestdata"
+)
+
+func TestPackUnpackNftMetadata(t *testing.T) {
+	m := &NftMetadata{
+		URI:      "https://ipfs.io/ipfs/QmeSjSinHpPnmXmspMjwiXyN6zS4E9zccariGR3jxcaWtq/1",
+		ImageURI: "https://ipfs.io/ipfs/QmSg9bPzW9anFYc3wWU5KnvymwkxQTpmqcRSfYj7UmiBa7/1.png",
+		Metadata: `{"name":"Token 1","image":"ipfs://QmSg9bPzW9anFYc3wWU5KnvymwkxQTpmqcRSfYj7UmiBa7/1.png"}`,
+		Time:     1700000000,
+	}
+	if got := unpackNftMetadata(packNftMetadata(m)); !reflect.DeepEqual(got, m) {
+		t.Errorf("unpackNftMetadata() = %+v, want %+v", got, m)
+	}
+}
+
+type nftHolderWant struct {
+	id      int64
+	owner   string
+	balance int64
+}
+
+func verifyNftHolders(t *testing.T, d *RocksDB, contract string, want []nftHolderWant) {
+	got, total, err := d.GetNftHolders(hexToBytes(contract), 0, MaxNftItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != len(want) || len(got) != len(want) {
+		t.Fatalf("GetNftHolders(%v) returned %d items, total %d, want %d", contract, len(got), total, len(want))
+	}
+	for i := range want {
+		if got[i].Id.Int64() != want[i].id || !bytes.Equal(got[i].Owner, hexToBytes(want[i].owner)) || got[i].Balance.Int64() != want[i].balance {
+			t.Errorf("GetNftHolders(%v)[%d] = %v %x %v, want %+v", contract, i, got[i].Id.String(), got[i].Owner, got[i].Balance.String(), want[i])
+		}
+	}
+}
+
+func TestRocksDB_Nft_EthereumType(t *testing.T) {
+	d := setupRocksDB(t, &testEthereumParser{
+		EthereumParser: ethereumTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	block1 := dbtestdata.GetTestEthereumTypeBlock1(d.chainParser)
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+	block2 := dbtestdata.GetTestEthereumTypeBlock2(d.chainParser)
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+	verifyNftHolders(t, d, dbtestdata.EthAddrContractCd, []nftHolderWant{
+		{1, dbtestdata.EthAddr7b, 1},
+	})
+	verifyNftHolders(t, d, dbtestdata.EthAddrContract6f, []nftHolderWant{
+		{150, dbtestdata.EthAddr3e, 1},
+		{1776, dbtestdata.EthAddr5d, 1},
+		{1898, dbtestdata.EthAddr5d, 10},
+	})
+	holders, err := d.GetNftTokenHolders(hexToBytes(dbtestdata.EthAddrContract6f), big.NewInt(1898))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != 1 || !bytes.Equal(holders[0].Owner, hexToBytes(dbtestdata.EthAddr5d)) {
+		t.Errorf("GetNftTokenHolders() = %+v", holders)
+	}
+	transfers, err := d.GetNftTransfers(hexToBytes(dbtestdata.EthAddrContractCd), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 1 || transfers[0].Txid != "0x"+dbtestdata.EthTxidB2T3 || transfers[0].Height != block2.Height ||
+		!bytes.Equal(transfers[0].From, hexToBytes(dbtestdata.EthAddr83)) || !bytes.Equal(transfers[0].To, hexToBytes(dbtestdata.EthAddr7b)) ||
+		transfers[0].Value.Int64() != 1 {
+		t.Errorf("GetNftTransfers() = %+v", transfers)
+	}
+
+	// the holders and transfers of the disconnected block are removed
+	if err := d.DisconnectBlockRangeEthereumType(block2.Height, block2.Height); err != nil {
+		t.Fatal(err)
+	}
+	verifyNftHolders(t, d, dbtestdata.EthAddrContractCd, nil)
+	verifyNftHolders(t, d, dbtestdata.EthAddrContract6f, nil)
+	if err := checkColumn(d, cfNftTransfers, []keyPair{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the index rebuilt from the address contracts must be the same
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+	holders, _, err = d.GetNftHolders(hexToBytes(dbtestdata.EthAddrContract6f), 0, MaxNftItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range holders {
+		d.db.DeleteCF(d.wo, d.cfh[cfNftHolders], append(packNftKey(hexToBytes(dbtestdata.EthAddrContract6f), &holders[i].Id), holders[i].Owner...))
+	}
+	verifyNftHolders(t, d, dbtestdata.EthAddrContract6f, nil)
+	if err := d.buildNftHolderIndex(); err != nil {
+		t.Fatal(err)
+	}
+	verifyNftHolders(t, d, dbtestdata.EthAddrContract6f, []nftHolderWant{
+		{150, dbtestdata.EthAddr3e, 1},
+		{1776, dbtestdata.EthAddr5d, 1},
+		{1898, dbtestdata.EthAddr5d, 10},
+	})
+}
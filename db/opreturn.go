@@ -0,0 +1,252 @@
+package db
+
+import (
+	"bytes"
+	"sort"
+
+	vlq "github.com/bsm/go-vlq"
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/martinboehm/btcd/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// OP_RETURN index
+// the opReturn column contains the data carried by OP_RETURN outputs, it is created only if enabled by the opreturn_index option
+// the key is the beginning of the data (at most opReturnIndexedDataLen bytes)+height+txid, so that the outputs can be searched by a data prefix
+// the value is the list of outputs of the transaction with the same beginning of data, each as vout+full data
+// the rows are idempotent, in bulk connect they can be written outside of the checkpoints
+
+// opReturnIndexedDataLen is the maximum length of the data stored in the key of the index
+const opReturnIndexedDataLen = 40
+
+// MaxOpReturnOutputs is the maximum number of the outputs returned by a search in the OP_RETURN index
+const MaxOpReturnOutputs = 10000
+
+// ErrTooManyOpReturnOutputs is returned if the search matches more than MaxOpReturnOutputs outputs
+var ErrTooManyOpReturnOutputs = errors.New("Too many matching outputs, use longer prefix or shorter block range")
+
+// OpReturnOutput is an output with OP_RETURN data
+type OpReturnOutput struct {
+	Txid   string
+	Vout   uint32
+	Height uint32
+	Data   []byte
+}
+
+// OpReturnData returns the data pushed by the OP_RETURN script or nil if the address descriptor is not an OP_RETURN script
+func OpReturnData(addrDesc bchain.AddressDescriptor) []byte {
+	if len(addrDesc) < 2 || addrDesc[0] != txscript.OP_RETURN {
+		return nil
+	}
+	pushes, err := txscript.PushedData(addrDesc[1:])
+	if err != nil {
+		return nil
+	}
+	var data []byte
+	for _, p := range pushes {
+		data = append(data, p...)
+	}
+	return data
+}
+
+// HasOpReturnIndex returns true if the DB indexes OP_RETURN data
+func (d *RocksDB) HasOpReturnIndex() bool {
+	return d.is != nil && d.is.OpReturnIndex && d.chainParser.GetChainType() == bchain.ChainBitcoinType
+}
+
+func packOpReturnKey(data []byte, height uint32, btxID []byte) []byte {
+	if len(data) > opReturnIndexedDataLen {
+		data = data[:opReturnIndexedDataLen]
+	}
+	key := make([]byte, 0, len(data)+packedHeightBytes+len(btxID))
+	key = append(key, data...)
+	key = append(key, packUint(height)...)
+	return append(key, btxID...)
+}
+
+func packOpReturnOutputs(outputs []OpReturnOutput) []byte {
+	buf := make([]byte, 0, 64)
+	varBuf := make([]byte, vlq.MaxLen64)
+	for i := range outputs {
+		l := packVaruint(uint(outputs[i].Vout), varBuf)
+		buf = append(buf, varBuf[:l]...)
+		l = packVaruint(uint(len(outputs[i].Data)), varBuf)
+		buf = append(buf, varBuf[:l]...)
+		buf = append(buf, outputs[i].Data...)
+	}
+	return buf
+}
+
+func unpackOpReturnOutputs(buf []byte) ([]OpReturnOutput, error) {
+	var outputs []OpReturnOutput
+	for len(buf) > 0 {
+		vout, l := unpackVaruint(buf)
+		buf = buf[l:]
+		dl, l := unpackVaruint(buf)
+		buf = buf[l:]
+		if int(dl) > len(buf) {
+			return nil, errors.New("Invalid OP_RETURN outputs")
+		}
+		outputs = append(outputs, OpReturnOutput{
+			Vout: uint32(vout),
+			Data: append([]byte(nil), buf[:dl]...),
+		})
+		buf = buf[dl:]
+	}
+	return outputs, nil
+}
+
+// opReturnRows adds to rows the rows of the OP_RETURN index for the transactions of the block
+func (d *RocksDB) opReturnRows(block *bchain.Block, txAddressesMap map[string]*TxAddresses, rows map[string][]byte) error {
+	for i := range block.Txs {
+		btxID, err := d.chainParser.PackTxid(block.Txs[i].Txid)
+		if err != nil {
+			return err
+		}
+		ta, found := txAddressesMap[string(btxID)]
+		if !found {
+			continue
+		}
+		for key, outputs := range opReturnOutputsOfTx(block.Height, btxID, ta) {
+			rows[key] = packOpReturnOutputs(outputs)
+		}
+	}
+	return nil
+}
+
+// opReturnOutputsOfTx groups the OP_RETURN outputs of the transaction by the key in the index
+func opReturnOutputsOfTx(height uint32, btxID []byte, ta *TxAddresses) map[string][]OpReturnOutput {
+	var outputs map[string][]OpReturnOutput
+	for i := range ta.Outputs {
+		data := OpReturnData(ta.Outputs[i].AddrDesc)
+		if len(data) == 0 {
+			continue
+		}
+		if outputs == nil {
+			outputs = make(map[string][]OpReturnOutput)
+		}
+		key := string(packOpReturnKey(data, height, btxID))
+		outputs[key] = append(outputs[key], OpReturnOutput{Vout: uint32(i), Data: data})
+	}
+	return outputs
+}
+
+func (d *RocksDB) storeOpReturns(wb *grocksdb.WriteBatch, rows map[string][]byte) {
+	for key, val := range rows {
+		wb.PutCF(d.cfh[cfOpReturn], []byte(key), val)
+	}
+}
+
+func (d *RocksDB) disconnectOpReturns(wb *grocksdb.WriteBatch, height uint32, btxID []byte, ta *TxAddresses) {
+	for key := range opReturnOutputsOfTx(height, btxID, ta) {
+		wb.DeleteCF(d.cfh[cfOpReturn], []byte(key))
+	}
+}
+
+// GetOpReturnOutputs returns the outputs with OP_RETURN data starting with the prefix in blocks in range lower-higher
+// the outputs are sorted by height descending, if there are more than MaxOpReturnOutputs matching outputs, ErrTooManyOpReturnOutputs is returned
+func (d *RocksDB) GetOpReturnOutputs(prefix []byte, lower, higher uint32) ([]OpReturnOutput, error) {
+	if !d.HasOpReturnIndex() {
+		return nil, errors.New("OP_RETURN index is not enabled")
+	}
+	if len(prefix) == 0 {
+		return nil, errors.New("Missing prefix")
+	}
+	seek := prefix
+	if len(seek) > opReturnIndexedDataLen {
+		seek = seek[:opReturnIndexedDataLen]
+	}
+	txidLen := d.chainParser.PackedTxidLen()
+	// if the prefix covers the whole indexed data, all matching keys have the same data and are sorted by height,
+	// the search can start at the lower height and stop after the higher height
+	// shorter prefixes match keys with different data, in which the heights are not ordered
+	fullKey := len(seek) == opReturnIndexedDataLen
+	start := seek
+	if fullKey {
+		start = packOpReturnKey(seek, lower, nil)
+	}
+	var outputs []OpReturnOutput
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfOpReturn])
+	defer it.Close()
+	for it.Seek(start); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, seek) {
+			break
+		}
+		dl := len(key) - packedHeightBytes - txidLen
+		if dl < 0 {
+			glog.Error("GetOpReturnOutputs: invalid key ", key)
+			continue
+		}
+		height := unpackUint(key[dl : dl+packedHeightBytes])
+		if height > higher && fullKey {
+			break
+		}
+		if height < lower || height > higher {
+			continue
+		}
+		txid, err := d.chainParser.UnpackTxid(key[dl+packedHeightBytes:])
+		if err != nil {
+			return nil, err
+		}
+		vouts, err := unpackOpReturnOutputs(it.Value().Data())
+		if err != nil {
+			return nil, err
+		}
+		for i := range vouts {
+			if !bytes.HasPrefix(vouts[i].Data, prefix) {
+				continue
+			}
+			if len(outputs) >= MaxOpReturnOutputs {
+				return nil, ErrTooManyOpReturnOutputs
+			}
+			vouts[i].Txid = txid
+			vouts[i].Height = height
+			outputs = append(outputs, vouts[i])
+		}
+	}
+	sort.SliceStable(outputs, func(i, j int) bool {
+		if outputs[i].Height != outputs[j].Height {
+			return outputs[i].Height > outputs[j].Height
+		}
+		if outputs[i].Txid != outputs[j].Txid {
+			return outputs[i].Txid < outputs[j].Txid
+		}
+		return outputs[i].Vout < outputs[j].Vout
+	})
+	return outputs, nil
+}
+
+// GetOpReturnOutputsInBlock returns the outputs with OP_RETURN data in the block, the block must be one of the last blocks, for which the block txs are stored
+func (d *RocksDB) GetOpReturnOutputsInBlock(height uint32) ([]OpReturnOutput, error) {
+	if !d.HasOpReturnIndex() {
+		return nil, errors.New("OP_RETURN index is not enabled")
+	}
+	blockTxs, err := d.getBlockTxs(height)
+	if err != nil {
+		return nil, err
+	}
+	var outputs []OpReturnOutput
+	for i := range blockTxs {
+		ta, err := d.getTxAddresses(blockTxs[i].btxID)
+		if err != nil {
+			return nil, err
+		}
+		if ta == nil {
+			continue
+		}
+		txid, err := d.chainParser.UnpackTxid(blockTxs[i].btxID)
+		if err != nil {
+			return nil, err
+		}
+		for j := range ta.Outputs {
+			data := OpReturnData(ta.Outputs[j].AddrDesc)
+			if len(data) > 0 {
+				outputs = append(outputs, OpReturnOutput{Txid: txid, Vout: uint32(j), Height: height, Data: data})
+			}
+		}
+	}
+	return outputs, nil
+}
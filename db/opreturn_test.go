@@ -0,0 +1,119 @@
+//go:build unittest
+
+package db
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/trezor/blockbook/tests/dbtestdata"
+)
+
+func TestOpReturnData(t *testing.T) {
+	tests := []struct {
+		name     string
+		addrDesc string
+		want     string
+	}{
+		{"empty", "", ""},
+		{"p2pkh", "76a914010d39800f86122416e28f485029acf77507169288ac", ""},
+		{"bare OP_RETURN", "6a", ""},
+		{"one push", "6a072020f1686f6a20", "2020f1686f6a20"},
+		{"pushdata1", "6a4c0401020304", "01020304"},
+		{"two pushes", "6a026f6d0401020304", "6f6d01020304"},
+		{"invalid push", "6a0701", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OpReturnData(hexToBytes(tt.addrDesc))
+			if !bytes.Equal(got, hexToBytes(tt.want)) {
+				t.Errorf("OpReturnData() = %x, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackUnpackOpReturnOutputs(t *testing.T) {
+	outputs := []OpReturnOutput{
+		{Vout: 1, Data: hexToBytes("6f6d6e69000000000000001f")},
+		{Vout: 300, Data: hexToBytes("6f6d")},
+	}
+	got, err := unpackOpReturnOutputs(packOpReturnOutputs(outputs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, outputs) {
+		t.Errorf("unpackOpReturnOutputs() = %+v, want %+v", got, outputs)
+	}
+	if _, err := unpackOpReturnOutputs(hexToBytes("010a6f6d")); err == nil {
+		t.Error("unpackOpReturnOutputs() of truncated data expected error")
+	}
+}
+
+func TestRocksDB_OpReturn_BitcoinType(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if _, err := d.GetOpReturnOutputs(hexToBytes("2020"), 0, math.MaxUint32); err == nil {
+		t.Fatal("GetOpReturnOutputs() with disabled index expected error")
+	}
+	d.is.OpReturnIndex = true
+
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+	want := []OpReturnOutput{
+		{Txid: dbtestdata.TxidB2T1, Vout: 2, Height: block2.Height, Data: hexToBytes("2020f1686f6a20")},
+	}
+	got, err := d.GetOpReturnOutputs(hexToBytes("2020f1"), 0, math.MaxUint32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetOpReturnOutputs() = %+v, want %+v", got, want)
+	}
+	got, err = d.GetOpReturnOutputsInBlock(block2.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetOpReturnOutputsInBlock() = %+v, want %+v", got, want)
+	}
+	// not matching prefix and height range
+	for _, q := range []struct {
+		prefix        string
+		lower, higher uint32
+	}{
+		{"2021", 0, math.MaxUint32},
+		{"2020f1686f6a2000", 0, math.MaxUint32},
+		{"2020", 0, block1.Height},
+	} {
+		got, err = d.GetOpReturnOutputs(hexToBytes(q.prefix), q.lower, q.higher)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("GetOpReturnOutputs(%v, %d, %d) = %+v, want none", q.prefix, q.lower, q.higher, got)
+		}
+	}
+
+	if err := d.DisconnectBlockRangeBitcoinType(block2.Height, block2.Height); err != nil {
+		t.Fatal(err)
+	}
+	got, err = d.GetOpReturnOutputs(hexToBytes("20"), 0, math.MaxUint32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("GetOpReturnOutputs() after disconnect = %+v, want none", got)
+	}
+}
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"bytes"
+	"math/big"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/eth"
+)
+
+// rich list
+// the balanceIndex column (Bitcoin type) contains keys balance+addrDesc, the contractHolders column (Ethereum type)
+// contains keys contract+balance+addrDesc of the fungible tokens, the values are empty
+// the balance in the key is a packed big int with all bytes complemented, so that the keys are ordered from the highest balance
+// the indexes are updated in the same write batch as the balances, the previous balance is read from the db (Bitcoin type)
+// or taken from the packed address contracts loaded from the db (Ethereum type)
+
+// MaxRichListItems is the maximum number of items which can be listed in the rich list
+const MaxRichListItems = 10000
+
+// RichListItem is one item of the rich list
+type RichListItem struct {
+	AddrDesc   bchain.AddressDescriptor
+	BalanceSat big.Int
+}
+
+func packBalanceIndexKey(prefix []byte, packedBalance []byte, addrDesc bchain.AddressDescriptor) []byte {
+	key := make([]byte, 0, len(prefix)+len(packedBalance)+len(addrDesc))
+	key = append(key, prefix...)
+	for _, b := range packedBalance {
+		key = append(key, ^b)
+	}
+	return append(key, addrDesc...)
+}
+
+func unpackBalanceIndexKey(key []byte, prefixLen int) (*RichListItem, error) {
+	if len(key) <= prefixLen {
+		return nil, errors.New("Invalid balance index key")
+	}
+	key = key[prefixLen:]
+	l := int(^key[0]) + 1
+	if len(key) < l {
+		return nil, errors.New("Invalid balance index key")
+	}
+	packedBalance := make([]byte, l)
+	for i := range packedBalance {
+		packedBalance[i] = ^key[i]
+	}
+	balance, _ := unpackBigint(packedBalance)
+	return &RichListItem{
+		AddrDesc:   append(bchain.AddressDescriptor(nil), key[l:]...),
+		BalanceSat: balance,
+	}, nil
+}
+
+func isPackedBigintZero(packed []byte) bool {
+	return len(packed) == 0 || packed[0] == 0
+}
+
+// updateBalanceIndex replaces the key with the old balance by the key with the new balance, zero balances are not indexed
+func (d *RocksDB) updateBalanceIndex(wb *grocksdb.WriteBatch, cf int, prefix []byte, addrDesc bchain.AddressDescriptor, oldPackedBalance, newPackedBalance []byte) {
+	if bytes.Equal(oldPackedBalance, newPackedBalance) {
+		return
+	}
+	if !isPackedBigintZero(oldPackedBalance) {
+		wb.DeleteCF(d.cfh[cf], packBalanceIndexKey(prefix, oldPackedBalance, addrDesc))
+	}
+	if !isPackedBigintZero(newPackedBalance) {
+		wb.PutCF(d.cfh[cf], packBalanceIndexKey(prefix, newPackedBalance, addrDesc), []byte{})
+	}
+}
+
+// packedBalanceFromAddrBalance returns the packed balance from the packed AddrBalance or nil if the data is invalid
+func packedBalanceFromAddrBalance(buf []byte) []byte {
+	// 3 is minimum length of addrBalance - 1 byte txs, 1 byte sent, 1 byte balance
+	if len(buf) < 3 {
+		return nil
+	}
+	_, l := unpackVaruint(buf)
+	l += packedBigintLen(buf[l:])
+	if l >= len(buf) {
+		return nil
+	}
+	bl := packedBigintLen(buf[l:])
+	if l+bl > len(buf) {
+		return nil
+	}
+	return append([]byte(nil), buf[l:l+bl]...)
+}
+
+// fungibleTokenPackedBalances returns packed balances of the fungible tokens of the packed address contracts
+func fungibleTokenPackedBalances(packed []byte) (map[string][]byte, error) {
+	balances := make(map[string][]byte)
+	if len(packed) == 0 {
+		return balances, nil
+	}
+	acs, err := partiallyUnpackAddrContracts(packed)
+	if err != nil {
+		return nil, err
+	}
+	for i := range acs.Contracts {
+		ac := &acs.Contracts[i]
+		if ac.Standard == bchain.FungibleToken {
+			balances[string(ac.Contract)] = ac.Value.Slice
+		}
+	}
+	return balances, nil
+}
+
+// updateContractHolders updates the contractHolders index from the previously stored packed address contracts to the new state
+func (d *RocksDB) updateContractHolders(wb *grocksdb.WriteBatch, addrDesc bchain.AddressDescriptor, oldPacked []byte, acs *unpackedAddrContracts) error {
+	old, err := fungibleTokenPackedBalances(oldPacked)
+	if err != nil {
+		return err
+	}
+	if acs != nil {
+		varBuf := make([]byte, maxPackedBigintBytes)
+		for i := range acs.Contracts {
+			ac := &acs.Contracts[i]
+			if ac.Standard != bchain.FungibleToken {
+				continue
+			}
+			newPacked := ac.Value.Slice
+			if ac.Value.Value != nil {
+				l := packBigint(ac.Value.Value, varBuf)
+				newPacked = varBuf[:l]
+			}
+			contract := string(ac.Contract)
+			d.updateBalanceIndex(wb, cfContractHolders, ac.Contract, addrDesc, old[contract], newPacked)
+			delete(old, contract)
+		}
+	}
+	// contracts removed from the address
+	for contract, oldPackedBalance := range old {
+		d.updateBalanceIndex(wb, cfContractHolders, []byte(contract), addrDesc, oldPackedBalance, nil)
+	}
+	return nil
+}
+
+func (d *RocksDB) getStoredPackedAddrContracts(addrDesc bchain.AddressDescriptor) ([]byte, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfAddressContracts], addrDesc)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	return append([]byte(nil), val.Data()...), nil
+}
+
+// GetRichList returns the addresses with the highest balance, for Ethereum type coins the holders of the contract with the highest balance
+// offset items are skipped, at most count items are returned together with the total number of items limited to MaxRichListItems
+func (d *RocksDB) GetRichList(contract bchain.AddressDescriptor, offset, count int) ([]RichListItem, int, error) {
+	var cf int
+	switch d.chainParser.GetChainType() {
+	case bchain.ChainBitcoinType:
+		cf = cfBalanceIndex
+		contract = nil
+	case bchain.ChainEthereumType:
+		if len(contract) != eth.EthereumTypeAddressDescriptorLen {
+			return nil, 0, errors.New("Invalid contract")
+		}
+		cf = cfContractHolders
+	default:
+		return nil, 0, errors.New("Unknown chain type")
+	}
+	items := make([]RichListItem, 0, count)
+	total := 0
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cf])
+	defer it.Close()
+	for it.Seek(contract); it.Valid() && total < MaxRichListItems; it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, contract) {
+			break
+		}
+		if total >= offset && len(items) < count {
+			item, err := unpackBalanceIndexKey(key, len(contract))
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, *item)
+		}
+		total++
+	}
+	return items, total, nil
+}
+
+// GetAddrDescHeightRange returns the heights of the first and the last block in which the address appears, zeros if not found
+func (d *RocksDB) GetAddrDescHeightRange(addrDesc bchain.AddressDescriptor) (uint32, uint32, error) {
+	var first, last uint32
+	found := false
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfAddresses])
+	defer it.Close()
+	// the heights are stored as binary complement, the last height is in the first key of the address
+	keyLen := len(addrDesc) + packedHeightBytes
+	for it.Seek(addrDesc); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, addrDesc) {
+			break
+		}
+		if len(key) == keyLen {
+			last = ^unpackUint(key[len(addrDesc):])
+			found = true
+			break
+		}
+	}
+	if !found {
+		return 0, 0, nil
+	}
+	for it.SeekForPrev(packAddressKey(addrDesc, 0)); it.Valid(); it.Prev() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, addrDesc) {
+			break
+		}
+		if len(key) == keyLen {
+			first = ^unpackUint(key[len(addrDesc):])
+			break
+		}
+	}
+	return first, last, nil
+}
+
+// buildBalanceIndex creates the rich list index from the stored balances, it is used when the index column is added to an existing db
+func (d *RocksDB) buildBalanceIndex() error {
+	var cf, cfIndex int
+	if d.chainParser.GetChainType() == bchain.ChainBitcoinType {
+		cf, cfIndex = cfAddressBalance, cfBalanceIndex
+	} else {
+		cf, cfIndex = cfAddressContracts, cfContractHolders
+	}
+	glog.Info("buildBalanceIndex: starting")
+	start := time.Now()
+	var rows, indexed int
+	// do not use cache
+	ro := grocksdb.NewDefaultReadOptions()
+	ro.SetFillCache(false)
+	defer ro.Destroy()
+	it := d.db.NewIteratorCF(ro, d.cfh[cf])
+	defer it.Close()
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		addrDesc := append(bchain.AddressDescriptor(nil), it.Key().Data()...)
+		buf := it.Value().Data()
+		if cf == cfAddressBalance {
+			d.updateBalanceIndex(wb, cfIndex, nil, addrDesc, nil, packedBalanceFromAddrBalance(buf))
+		} else {
+			balances, err := fungibleTokenPackedBalances(append([]byte(nil), buf...))
+			if err != nil {
+				glog.Error("buildBalanceIndex: address ", addrDesc, ", error ", err)
+				continue
+			}
+			for contract, packedBalance := range balances {
+				d.updateBalanceIndex(wb, cfIndex, []byte(contract), addrDesc, nil, packedBalance)
+			}
+		}
+		rows++
+		if wb.Count() > 100000 {
+			indexed += wb.Count()
+			if err := d.WriteBatch(wb); err != nil {
+				return err
+			}
+			wb.Clear()
+			glog.Info("buildBalanceIndex: processed ", rows, " rows")
+		}
+	}
+	indexed += wb.Count()
+	if err := d.WriteBatch(wb); err != nil {
+		return err
+	}
+	glog.Info("buildBalanceIndex: finished, processed ", rows, " rows, indexed ", indexed, " balances in ", time.Since(start))
+	return nil
+}
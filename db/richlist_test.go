@@ -0,0 +1,173 @@
+//go:build unittest
+
+package db
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/tests/dbtestdata"
+)
+
+func TestPackUnpackBalanceIndexKey(t *testing.T) {
+	prefix := hexToBytes("0d0e0f")
+	addrDesc := hexToBytes("76a914010d39800f86122416e28f485029acf77507169288ac")
+	balances := []int64{1, 255, 256, 1234567, 9876543210}
+	varBuf := make([]byte, maxPackedBigintBytes)
+	var keys [][]byte
+	for _, b := range balances {
+		l := packBigint(big.NewInt(b), varBuf)
+		key := packBalanceIndexKey(prefix, varBuf[:l], addrDesc)
+		item, err := unpackBalanceIndexKey(key, len(prefix))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.BalanceSat.Int64() != b || !bytes.Equal(item.AddrDesc, addrDesc) {
+			t.Errorf("unpackBalanceIndexKey() = %v %v, want %v %v", item.BalanceSat.String(), item.AddrDesc, b, addrDesc)
+		}
+		keys = append(keys, key)
+	}
+	// the keys must be ordered from the highest balance
+	for i := 1; i < len(keys); i++ {
+		if bytes.Compare(keys[i-1], keys[i]) <= 0 {
+			t.Errorf("key of balance %d is not ordered before key of balance %d", balances[i], balances[i-1])
+		}
+	}
+	if _, err := unpackBalanceIndexKey(prefix, len(prefix)); err == nil {
+		t.Error("unpackBalanceIndexKey() of empty key expected error")
+	}
+}
+
+func TestPackedBalanceFromAddrBalance(t *testing.T) {
+	ab := &AddrBalance{
+		Txs:        7,
+		SentSat:    *big.NewInt(1234),
+		BalanceSat: *big.NewInt(98765432),
+	}
+	buf := packAddrBalance(ab, nil, make([]byte, maxPackedBigintBytes))
+	got := packedBalanceFromAddrBalance(buf)
+	if balance, _ := unpackBigint(got); balance.Cmp(&ab.BalanceSat) != 0 {
+		t.Errorf("packedBalanceFromAddrBalance() = %v, want %v", balance.String(), ab.BalanceSat.String())
+	}
+	if got := packedBalanceFromAddrBalance(buf[:2]); got != nil {
+		t.Errorf("packedBalanceFromAddrBalance() of truncated data = %v, want nil", got)
+	}
+}
+
+// verifyRichList checks that the rich list contains all addresses with positive balance ordered by the balance
+func verifyRichList(t *testing.T, d *RocksDB) {
+	var want []RichListItem
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfAddressBalance])
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		ab, err := unpackAddrBalance(it.Value().Data(), d.chainParser.PackedTxidLen(), AddressBalanceDetailNoUTXO)
+		if err != nil {
+			it.Close()
+			t.Fatal(err)
+		}
+		if ab.BalanceSat.Sign() > 0 {
+			want = append(want, RichListItem{
+				AddrDesc:   append(bchain.AddressDescriptor(nil), it.Key().Data()...),
+				BalanceSat: ab.BalanceSat,
+			})
+		}
+	}
+	it.Close()
+	got, total, err := d.GetRichList(nil, 0, MaxRichListItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != len(want) || len(got) != len(want) {
+		t.Fatalf("GetRichList() returned %d items, total %d, want %d", len(got), total, len(want))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1].BalanceSat.Cmp(&got[i].BalanceSat) < 0 {
+			t.Fatalf("GetRichList() not ordered at %d: %v < %v", i, got[i-1].BalanceSat.String(), got[i].BalanceSat.String())
+		}
+	}
+	sortItems := func(items []RichListItem) {
+		sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i].AddrDesc, items[j].AddrDesc) < 0 })
+	}
+	sortItems(want)
+	sorted := append([]RichListItem(nil), got...)
+	sortItems(sorted)
+	for i := range want {
+		if !bytes.Equal(sorted[i].AddrDesc, want[i].AddrDesc) || sorted[i].BalanceSat.Cmp(&want[i].BalanceSat) != 0 {
+			t.Fatalf("GetRichList() item %v %v, want %v %v", sorted[i].AddrDesc, sorted[i].BalanceSat.String(), want[i].AddrDesc, want[i].BalanceSat.String())
+		}
+	}
+	// paging
+	if len(got) > 2 {
+		page, _, err := d.GetRichList(nil, 1, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) != 2 || !bytes.Equal(page[0].AddrDesc, got[1].AddrDesc) || !bytes.Equal(page[1].AddrDesc, got[2].AddrDesc) {
+			t.Fatalf("GetRichList(1, 2) = %v, want %v", page, got[1:3])
+		}
+	}
+}
+
+func TestRocksDB_RichList_BitcoinType(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+	verifyRichList(t, d)
+
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+	verifyRichList(t, d)
+
+	for _, tt := range []struct {
+		address     string
+		first, last uint32
+	}{
+		{dbtestdata.Addr5, block1.Height, block2.Height},
+		{dbtestdata.AddrA, block2.Height, block2.Height},
+	} {
+		first, last, err := d.GetAddrDescHeightRange(addressToAddrDesc(tt.address, d.chainParser))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != tt.first || last != tt.last {
+			t.Errorf("GetAddrDescHeightRange(%s) = %d, %d, want %d, %d", tt.address, first, last, tt.first, tt.last)
+		}
+	}
+
+	if err := d.DisconnectBlockRangeBitcoinType(block2.Height, block2.Height); err != nil {
+		t.Fatal(err)
+	}
+	verifyRichList(t, d)
+
+	// the index rebuilt from the balances must be the same
+	items, _, err := d.GetRichList(nil, 0, MaxRichListItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range items {
+		d.db.DeleteCF(d.wo, d.cfh[cfBalanceIndex], packBalanceIndexKey(nil, packedBalanceBytes(&items[i].BalanceSat), items[i].AddrDesc))
+	}
+	if _, total, _ := d.GetRichList(nil, 0, MaxRichListItems); total != 0 {
+		t.Fatalf("GetRichList() after delete total %d, want 0", total)
+	}
+	if err := d.buildBalanceIndex(); err != nil {
+		t.Fatal(err)
+	}
+	verifyRichList(t, d)
+}
+
+func packedBalanceBytes(b *big.Int) []byte {
+	varBuf := make([]byte, maxPackedBigintBytes)
+	l := packBigint(b, varBuf)
+	return varBuf[:l]
+}
@@ -22,7 +22,7 @@ import (
 	"github.com/trezor/blockbook/common"
 )
 
-const dbVersion = 7
+const dbVersion = 8
 
 const packedHeightBytes = 4
 const maxAddrDescLen = 1024
@@ -76,6 +76,7 @@ type RocksDB struct {
 	connectBlockMux       sync.Mutex
 	addrContractsCacheMux sync.Mutex
 	addrContractsCache    map[string]*unpackedAddrContracts
+	chainStats            *ChainStats
 }
 
 const (
@@ -89,6 +90,9 @@ const (
 	cfAddressBalance
 	cfTxAddresses
 	cfBlockFilter
+	cfChainStats
+	cfBalanceIndex
+	cfOpReturn
 
 	__break__
 
@@ -101,6 +105,13 @@ const (
 
 	// TODO move to common section
 	cfAddressAliases
+	cfContractHolders
+	cfEventSignatures
+	cfUserOperations
+	cfApprovals
+	cfNftHolders
+	cfNftTransfers
+	cfNftMetadata
 )
 
 // common columns
@@ -108,8 +119,8 @@ var cfNames []string
 var cfBaseNames = []string{"default", "height", "addresses", "blockTxs", "transactions", "fiatRates"}
 
 // type specific columns
-var cfNamesBitcoinType = []string{"addressBalance", "txAddresses", "blockFilter"}
-var cfNamesEthereumType = []string{"addressContracts", "internalData", "contracts", "functionSignatures", "blockInternalDataErrors", "addressAliases"}
+var cfNamesBitcoinType = []string{"addressBalance", "txAddresses", "blockFilter", "chainStats", "balanceIndex", "opReturn"}
+var cfNamesEthereumType = []string{"addressContracts", "internalData", "contracts", "functionSignatures", "blockInternalDataErrors", "addressAliases", "contractHolders", "eventSignatures", "userOperations", "approvals", "nftHolders", "nftTransfers", "nftMetadata"}
 
 func openDB(path string, c *grocksdb.Cache, openFiles int) (*grocksdb.DB, []*grocksdb.ColumnFamilyHandle, error) {
 	// opts with bloom filter
@@ -154,7 +165,7 @@ func NewRocksDB(path string, cacheSize, maxOpenFiles int, parser bchain.BlockCha
 	}
 	wo := grocksdb.NewDefaultWriteOptions()
 	ro := grocksdb.NewDefaultReadOptions()
-	r := &RocksDB{path, db, wo, ro, cfh, parser, nil, metrics, c, maxOpenFiles, connectBlockStats{}, extendedIndex, sync.Mutex{}, sync.Mutex{}, make(map[string]*unpackedAddrContracts)}
+	r := &RocksDB{path, db, wo, ro, cfh, parser, nil, metrics, c, maxOpenFiles, connectBlockStats{}, extendedIndex, sync.Mutex{}, sync.Mutex{}, make(map[string]*unpackedAddrContracts), nil}
 	if chainType == bchain.ChainEthereumType {
 		go r.periodicStoreAddrContractsCache()
 	}
@@ -372,9 +383,21 @@ func (d *RocksDB) ConnectBlock(block *bchain.Block) error {
 		} else if gf != nil && !gf.Enabled {
 			gf = nil
 		}
-		if err := d.processAddressesBitcoinType(block, addresses, txAddressesMap, balances, gf); err != nil {
+		cs, err := d.chainStatsForBlock(block)
+		if err != nil {
+			return err
+		}
+		if err := d.processAddressesBitcoinType(block, addresses, txAddressesMap, balances, gf, cs); err != nil {
 			return err
 		}
+		d.storeChainStats(wb, cs)
+		if d.HasOpReturnIndex() {
+			opReturns := make(map[string][]byte)
+			if err := d.opReturnRows(block, txAddressesMap, opReturns); err != nil {
+				return err
+			}
+			d.storeOpReturns(wb, opReturns)
+		}
 		if err := d.storeTxAddresses(wb, txAddressesMap); err != nil {
 			return err
 		}
@@ -402,6 +425,15 @@ func (d *RocksDB) ConnectBlock(block *bchain.Block) error {
 		if err := d.storeInternalDataEthereumType(wb, blockTxs); err != nil {
 			return err
 		}
+		d.storeUserOperationsEthereumType(wb, blockTxs)
+		d.storeNftTransfersEthereumType(wb, blockTxs)
+		approvals := make(map[string][]byte)
+		approvalsUndo, err := d.processApprovalsEthereumType(block, approvals)
+		if err != nil {
+			return err
+		}
+		d.storeApprovalsEthereumType(wb, approvals)
+		d.storeApprovalsUndoEthereumType(wb, block.Height, approvalsUndo)
 		if err = d.storeBlockSpecificDataEthereumType(wb, block); err != nil {
 			return err
 		}
@@ -495,6 +527,8 @@ type AddrBalance struct {
 	BalanceSat big.Int
 	Utxos      []Utxo
 	utxosMap   map[string]int
+	// packed balance stored in db when the balance was loaded for update, nil for a new address
+	storedBalance []byte
 }
 
 // ReceivedSat computes received amount from total balance and sent amount
@@ -620,7 +654,7 @@ func (d *RocksDB) GetAndResetConnectBlockStats() string {
 	return s
 }
 
-func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses addressesMap, txAddressesMap map[string]*TxAddresses, balances map[string]*AddrBalance, gf *bchain.GolombFilter) error {
+func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses addressesMap, txAddressesMap map[string]*TxAddresses, balances map[string]*AddrBalance, gf *bchain.GolombFilter, cs *ChainStats) error {
 	blockTxIDs := make([][]byte, len(block.Txs))
 	blockTxAddresses := make([]*TxAddresses, len(block.Txs))
 	// first process all outputs so that inputs can refer to txs in this block
@@ -642,11 +676,19 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 		ta.Outputs = make([]TxOutput, len(tx.Vout))
 		txAddressesMap[string(btxID)] = &ta
 		blockTxAddresses[txi] = &ta
+		isAssetTx := d.chainParser.IsAssetTx(tx)
 		for i := range tx.Vout {
 			output := &tx.Vout[i]
 			tao := &ta.Outputs[i]
 			tao.ValueSat = output.ValueSat
 			addrDesc, err := d.chainParser.GetAddrDescFromVout(output)
+			if isAssetTx {
+				cs.addOutput(ChainStatsAsset, &output.ValueSat)
+			} else if err != nil || len(addrDesc) > maxAddrDescLen {
+				cs.addOutput(ChainStatsNonStandard, &output.ValueSat)
+			} else {
+				cs.addOutput(chainStatsScriptType(addrDesc), &output.ValueSat)
+			}
 			if err != nil || len(addrDesc) == 0 || len(addrDesc) > maxAddrDescLen {
 				if err != nil {
 					// do not log ErrAddressMissing, transactions can be without to address (for example eth contracts)
@@ -666,7 +708,7 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 				strAddrDesc := string(addrDesc)
 				balance, e := balances[strAddrDesc]
 				if !e {
-					balance, err = d.GetAddrDescBalance(addrDesc, addressBalanceDetailUTXOIndexed)
+					balance, err = d.getAddrDescBalanceForUpdate(addrDesc, addressBalanceDetailUTXOIndexed)
 					if err != nil {
 						return err
 					}
@@ -734,6 +776,8 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 			spentOutput := &ita.Outputs[int(input.Vout)]
 			if spentOutput.Spent {
 				glog.Warningf("rocksdb: height %d, tx %v, input tx %v vout %v is double spend", block.Height, tx.Txid, input.Txid, input.Vout)
+			} else {
+				cs.spendOutput(&spentOutput.ValueSat)
 			}
 			if gf != nil {
 				gf.AddAddrDesc(spentOutput.AddrDesc, tx)
@@ -760,7 +804,7 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 				strAddrDesc := string(spentOutput.AddrDesc)
 				balance, e := balances[strAddrDesc]
 				if !e {
-					balance, err = d.GetAddrDescBalance(spentOutput.AddrDesc, addressBalanceDetailUTXOIndexed)
+					balance, err = d.getAddrDescBalanceForUpdate(spentOutput.AddrDesc, addressBalanceDetailUTXOIndexed)
 					if err != nil {
 						return err
 					}
@@ -854,12 +898,21 @@ func (d *RocksDB) storeBalances(wb *grocksdb.WriteBatch, abm map[string]*AddrBal
 	buf := make([]byte, 1024)
 	varBuf := make([]byte, maxPackedBigintBytes)
 	for addrDesc, ab := range abm {
+		ad := bchain.AddressDescriptor(addrDesc)
 		// balance with 0 transactions is removed from db - happens on disconnect
-		if ab == nil || ab.Txs <= 0 {
-			wb.DeleteCF(d.cfh[cfAddressBalance], bchain.AddressDescriptor(addrDesc))
+		// the rich list index contains the stored balance, it must be replaced together with the balance
+		if ab == nil {
+			wb.DeleteCF(d.cfh[cfAddressBalance], ad)
+		} else if ab.Txs <= 0 {
+			wb.DeleteCF(d.cfh[cfAddressBalance], ad)
+			d.updateBalanceIndex(wb, cfBalanceIndex, nil, ad, ab.storedBalance, nil)
+			ab.storedBalance = nil
 		} else {
 			buf = packAddrBalance(ab, buf, varBuf)
-			wb.PutCF(d.cfh[cfAddressBalance], bchain.AddressDescriptor(addrDesc), buf)
+			wb.PutCF(d.cfh[cfAddressBalance], ad, buf)
+			l := packBigint(&ab.BalanceSat, varBuf)
+			d.updateBalanceIndex(wb, cfBalanceIndex, nil, ad, ab.storedBalance, varBuf[:l])
+			ab.storedBalance = append(ab.storedBalance[:0], varBuf[:l]...)
 		}
 	}
 	return nil
@@ -967,6 +1020,26 @@ func (d *RocksDB) GetAddrDescBalance(addrDesc bchain.AddressDescriptor, detail A
 	return unpackAddrBalance(buf, d.chainParser.PackedTxidLen(), detail)
 }
 
+// getAddrDescBalanceForUpdate returns the balance like GetAddrDescBalance, the balance keeps the stored packed balance
+// so that storeBalances can replace the row of the rich list index without reading the balance again
+func (d *RocksDB) getAddrDescBalanceForUpdate(addrDesc bchain.AddressDescriptor, detail AddressBalanceDetail) (*AddrBalance, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfAddressBalance], addrDesc)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) < 3 {
+		return nil, nil
+	}
+	ab, err := unpackAddrBalance(buf, d.chainParser.PackedTxidLen(), detail)
+	if err != nil {
+		return nil, err
+	}
+	ab.storedBalance = packedBalanceFromAddrBalance(buf)
+	return ab, nil
+}
+
 // GetAddressBalance returns address balance for an address or nil if address not found
 func (d *RocksDB) GetAddressBalance(address string, detail AddressBalanceDetail) (*AddrBalance, error) {
 	addrDesc, err := d.chainParser.GetAddrDescFromAddress(address)
@@ -1612,7 +1685,7 @@ func (d *RocksDB) disconnectBlock(height uint32, blockTxs []blockTxs) error {
 		s := string(addrDesc)
 		b, fb := balances[s]
 		if !fb {
-			b, err = d.GetAddrDescBalance(addrDesc, addressBalanceDetailUTXOIndexed)
+			b, err = d.getAddrDescBalanceForUpdate(addrDesc, addressBalanceDetailUTXOIndexed)
 			if err != nil {
 				return nil, err
 			}
@@ -1669,6 +1742,9 @@ func (d *RocksDB) disconnectBlock(height uint32, blockTxs []blockTxs) error {
 		if err := d.disconnectTxAddressesOutputs(wb, btxID, txa, getAddressBalance, addressFoundInTx); err != nil {
 			return err
 		}
+		if d.HasOpReturnIndex() {
+			d.disconnectOpReturns(wb, height, btxID, txa)
+		}
 	}
 	for a := range blockAddressesTxs {
 		key := packAddressKey([]byte(a), height)
@@ -1677,6 +1753,7 @@ func (d *RocksDB) disconnectBlock(height uint32, blockTxs []blockTxs) error {
 	key := packUint(height)
 	wb.DeleteCF(d.cfh[cfBlockTxs], key)
 	wb.DeleteCF(d.cfh[cfHeight], key)
+	wb.DeleteCF(d.cfh[cfChainStats], key)
 	d.storeTxAddresses(wb, txAddressesToUpdate)
 	d.storeBalancesDisconnect(wb, balances)
 	for s := range txsToDelete {
@@ -1711,6 +1788,9 @@ func (d *RocksDB) DisconnectBlockRangeBitcoinType(lower uint32, higher uint32) e
 		}
 	}
 	d.is.RemoveLastBlockTimes(int(higher-lower) + 1)
+	if err := d.loadChainStats(); err != nil {
+		return err
+	}
 	glog.Infof("rocksdb: blocks %d-%d disconnected", lower, higher)
 	return nil
 }
@@ -1959,6 +2039,18 @@ func (d *RocksDB) migrateVersion6To7(sc, nc *common.InternalStateColumn) error {
 	return nil
 }
 
+func (d *RocksDB) migrateVersion7To8(sc, nc *common.InternalStateColumn) error {
+	// DB v8 stores the EIP-4844 blob fields in the ethereum type transactions,
+	// the cached transactions are cleared and fetched again from the backend
+	if d.chainParser.GetChainType() == bchain.ChainEthereumType {
+		if nc.Name == "transactions" {
+			d.db.DeleteRangeCF(d.wo, d.cfh[cfTransactions], []byte{0}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+		}
+		glog.Infof("Column %s migrated from v%d to v%d", nc.Name, sc.Version, dbVersion)
+	}
+	return nil
+}
+
 func (d *RocksDB) checkColumns(is *common.InternalState) ([]common.InternalStateColumn, error) {
 	// make sure that column stats match the columns
 	sc := is.DbColumns
@@ -1966,8 +2058,10 @@ func (d *RocksDB) checkColumns(is *common.InternalState) ([]common.InternalState
 	for i := 0; i < len(nc); i++ {
 		nc[i].Name = cfNames[i]
 		nc[i].Version = dbVersion
+		found := false
 		for j := 0; j < len(sc); j++ {
 			if sc[j].Name == nc[i].Name {
+				found = true
 				// check the version of the column, if it does not match, the db is not compatible
 				if sc[j].Version != dbVersion {
 					if sc[j].Version == 5 && dbVersion == 6 {
@@ -1975,11 +2069,20 @@ func (d *RocksDB) checkColumns(is *common.InternalState) ([]common.InternalState
 						if err != nil {
 							return nil, err
 						}
-					} else if sc[j].Version == 6 && dbVersion == 7 {
+					} else if sc[j].Version == 6 && dbVersion == 8 {
 						err := d.migrateVersion6To7(&sc[j], &nc[i])
 						if err != nil {
 							return nil, err
 						}
+						err = d.migrateVersion7To8(&sc[j], &nc[i])
+						if err != nil {
+							return nil, err
+						}
+					} else if sc[j].Version == 7 && dbVersion == 8 {
+						err := d.migrateVersion7To8(&sc[j], &nc[i])
+						if err != nil {
+							return nil, err
+						}
 					} else {
 						return nil, errors.Errorf("DB version %v of column '%v' does not match the required version %v. DB is not compatible.", sc[j].Version, sc[j].Name, dbVersion)
 					}
@@ -1991,6 +2094,17 @@ func (d *RocksDB) checkColumns(is *common.InternalState) ([]common.InternalState
 				break
 			}
 		}
+		// the rich list index added to an existing db must be built from the stored balances
+		if !found && len(sc) > 0 && (nc[i].Name == "balanceIndex" || nc[i].Name == "contractHolders") {
+			if err := d.buildBalanceIndex(); err != nil {
+				return nil, err
+			}
+		}
+		if !found && len(sc) > 0 && nc[i].Name == "nftHolders" {
+			if err := d.buildNftHolderIndex(); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return nc, nil
 }
@@ -2013,6 +2127,7 @@ func (d *RocksDB) LoadInternalState(config *common.Config) (*common.InternalStat
 			BlockGolombFilterP:      config.BlockGolombFilterP,
 			BlockFilterScripts:      config.BlockFilterScripts,
 			BlockFilterUseZeroedKey: config.BlockFilterUseZeroedKey,
+			OpReturnIndex:           config.OpReturnIndex,
 		}
 	} else {
 		is, err = common.UnpackInternalState(data)
@@ -2038,6 +2153,9 @@ func (d *RocksDB) LoadInternalState(config *common.Config) (*common.InternalStat
 		if is.BlockFilterUseZeroedKey != config.BlockFilterUseZeroedKey {
 			return nil, errors.Errorf("BlockFilterUseZeroedKey does not match. DB BlockFilterUseZeroedKey %v, config BlockFilterUseZeroedKey  %v", is.BlockFilterUseZeroedKey, config.BlockFilterUseZeroedKey)
 		}
+		if is.OpReturnIndex != config.OpReturnIndex {
+			return nil, errors.Errorf("OpReturnIndex does not match. DB OpReturnIndex %v, config OpReturnIndex %v", is.OpReturnIndex, config.OpReturnIndex)
+		}
 	}
 	nc, err := d.checkColumns(is)
 	if err != nil {
@@ -2067,6 +2185,10 @@ func (d *RocksDB) LoadInternalState(config *common.Config) (*common.InternalStat
 		glog.Infof("loaded %d address alias records", recordsCount)
 	}
 
+	if err := d.loadChainStats(); err != nil {
+		return nil, err
+	}
+
 	is.CoinShortcut = config.CoinShortcut
 	if config.CoinLabel == "" {
 		is.CoinLabel = config.CoinName
@@ -2122,6 +2244,39 @@ func (d *RocksDB) storeState(is *common.InternalState) error {
 	return d.db.PutCF(d.wo, d.cfh[cfDefault], []byte(internalStateKey), buf)
 }
 
+// storeStateToBatch adds the internal state to the write batch, to be stored atomically with other data
+func (d *RocksDB) storeStateToBatch(wb *grocksdb.WriteBatch, is *common.InternalState) error {
+	buf, err := is.Pack()
+	if err != nil {
+		return err
+	}
+	wb.PutCF(d.cfh[cfDefault], []byte(internalStateKey), buf)
+	return nil
+}
+
+// ResumeFromBulkCheckpoint checks if the db was left by an interrupted bulk connect and if so,
+// verifies that the best block matches the last checkpoint and clears the checkpoint
+// the sync then continues from the checkpoint
+func (d *RocksDB) ResumeFromBulkCheckpoint() (bool, error) {
+	if d.is == nil {
+		return false, errors.New("Internal state not created")
+	}
+	c := d.is.GetBulkCheckpoint()
+	if c == nil {
+		return false, nil
+	}
+	height, hash, err := d.GetBestBlock()
+	if err != nil {
+		return false, err
+	}
+	if height != c.Height || hash != c.Hash {
+		return false, errors.Errorf("Best block %d %s does not match bulk connect checkpoint %d %s", height, hash, c.Height, c.Hash)
+	}
+	glog.Warning("rocksdb: bulk connect was interrupted, resuming from checkpoint at height ", c.Height, " ", c.Hash, " stored at ", c.Time)
+	d.is.ClearBulkCheckpoint()
+	return true, d.storeState(d.is)
+}
+
 func (d *RocksDB) computeColumnSize(col int, stopCompute chan os.Signal) (int64, int64, int64, error) {
 	var rows, keysSum, valuesSum int64
 	var seekKey []byte
@@ -2340,6 +2495,7 @@ func (d *RocksDB) FixUtxos(stop chan os.Signal) error {
 				errorsCount++
 				continue
 			}
+			ba.storedBalance = packedBalanceFromAddrBalance(buf)
 			fixed, reordered, err := d.fixUtxo(addrDesc, ba)
 			if err != nil {
 				errorsCount++
@@ -3,6 +3,7 @@ package db
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"math/big"
 	"os"
 	"sort"
@@ -511,10 +512,13 @@ type ethInternalData struct {
 }
 
 type ethBlockTx struct {
-	btxID        []byte
-	from, to     bchain.AddressDescriptor
-	contracts    []ethBlockTxContract
-	internalData *ethInternalData
+	btxID         []byte
+	from, to      bchain.AddressDescriptor
+	contracts     []ethBlockTxContract
+	internalData  *ethInternalData
+	userOpSenders []bchain.AddressDescriptor
+	// height is not stored in blockTxs, it is used to index the nft transfers
+	height uint32
 }
 
 func (d *RocksDB) processBaseTxData(blockTx *ethBlockTx, tx *bchain.Tx, addresses addressesMap, addressContracts map[string]*unpackedAddrContracts) error {
@@ -677,6 +681,35 @@ func (d *RocksDB) processContractTransfers(blockTx *ethBlockTx, tx *bchain.Tx, a
 	return nil
 }
 
+// processUserOperations indexes the transaction for the senders (smart accounts) of the ERC-4337 UserOperations executed in the transaction,
+// the tx is indexed as a non contract transaction of the sender, the same way as the transactions sent by the address itself
+func (d *RocksDB) processUserOperations(blockTx *ethBlockTx, tx *bchain.Tx, addresses addressesMap, addressContracts map[string]*unpackedAddrContracts) error {
+	ops := eth.GetUserOperationsFromTx(tx)
+	for i := range ops {
+		sender, err := d.chainParser.GetAddrDescFromAddress(ops[i].Sender)
+		if err != nil {
+			glog.Warningf("rocksdb: processUserOperations %v, tx %v, user operation %d", err, tx.Txid, i)
+			continue
+		}
+		counted := bytes.Equal(sender, blockTx.from) || bytes.Equal(sender, blockTx.to)
+		for _, s := range blockTx.userOpSenders {
+			if bytes.Equal(sender, s) {
+				counted = true
+				break
+			}
+		}
+		if counted {
+			// the sender is already indexed in this tx, do not count the tx again
+			continue
+		}
+		if err = d.addToAddressesAndContractsEthereumType(sender, blockTx.btxID, transferFrom, nil, nil, true, addresses, addressContracts); err != nil {
+			return err
+		}
+		blockTx.userOpSenders = append(blockTx.userOpSenders, sender)
+	}
+	return nil
+}
+
 func (d *RocksDB) processAddressesEthereumType(block *bchain.Block, addresses addressesMap, addressContracts map[string]*unpackedAddrContracts) ([]ethBlockTx, error) {
 	blockTxs := make([]ethBlockTx, len(block.Txs))
 	for txi := range block.Txs {
@@ -687,6 +720,7 @@ func (d *RocksDB) processAddressesEthereumType(block *bchain.Block, addresses ad
 		}
 		blockTx := &blockTxs[txi]
 		blockTx.btxID = btxID
+		blockTx.height = block.Height
 		if err = d.processBaseTxData(blockTx, tx, addresses, addressContracts); err != nil {
 			return nil, err
 		}
@@ -701,6 +735,10 @@ func (d *RocksDB) processAddressesEthereumType(block *bchain.Block, addresses ad
 		if err = d.processContractTransfers(blockTx, tx, addresses, addressContracts); err != nil {
 			return nil, err
 		}
+		// index the senders of the user operations
+		if err = d.processUserOperations(blockTx, tx, addresses, addressContracts); err != nil {
+			return nil, err
+		}
 	}
 	return blockTxs, nil
 }
@@ -913,6 +951,88 @@ func (d *RocksDB) StoreFourByteSignature(wb *grocksdb.WriteBatch, fourBytes uint
 	return nil
 }
 
+// event signatures are stored in the eventSignatures column
+// the key is the topic (32 bytes hash of the event signature) and id, the value is the event ABI in JSON
+func packEventSignatureKey(topic []byte, id uint32) []byte {
+	key := make([]byte, 0, len(topic)+4)
+	key = append(key, topic...)
+	key = append(key, packUint(id)...)
+	return key
+}
+
+// GetEventSignature gets the event signature of given topic and id
+func (d *RocksDB) GetEventSignature(topic []byte, id uint32) (*bchain.EventSignature, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfEventSignatures], packEventSignatureKey(topic, id))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	var signature bchain.EventSignature
+	if err := json.Unmarshal(buf, &signature); err != nil {
+		return nil, err
+	}
+	return &signature, nil
+}
+
+// maxCachedEventSignatures is the maximum number of the cached topics including the topics without a known signature,
+// an arbitrary entry is evicted when the limit is reached
+const maxCachedEventSignatures = 10000
+
+var cachedEventSignatures = make(map[string]*[]bchain.EventSignature)
+var cachedEventSignaturesMux sync.Mutex
+
+// GetEventSignatures gets all event signatures of given topic
+// (there may be more signatures with the same topic, differing in the indexed parameters)
+func (d *RocksDB) GetEventSignatures(topic []byte) (*[]bchain.EventSignature, error) {
+	cachedEventSignaturesMux.Lock()
+	signatures, found := cachedEventSignatures[string(topic)]
+	cachedEventSignaturesMux.Unlock()
+	if !found {
+		retval := []bchain.EventSignature{}
+		it := d.db.NewIteratorCF(d.ro, d.cfh[cfEventSignatures])
+		defer it.Close()
+		for it.Seek(topic); it.Valid(); it.Next() {
+			current := it.Key().Data()
+			if !bytes.HasPrefix(current, topic) {
+				break
+			}
+			var signature bchain.EventSignature
+			if err := json.Unmarshal(it.Value().Data(), &signature); err != nil {
+				return nil, err
+			}
+			retval = append(retval, signature)
+		}
+		cachedEventSignaturesMux.Lock()
+		if len(cachedEventSignatures) >= maxCachedEventSignatures {
+			for k := range cachedEventSignatures {
+				delete(cachedEventSignatures, k)
+				break
+			}
+		}
+		cachedEventSignatures[string(topic)] = &retval
+		cachedEventSignaturesMux.Unlock()
+		return &retval, nil
+	}
+	return signatures, nil
+}
+
+// StoreEventSignature stores the event signature in DB
+func (d *RocksDB) StoreEventSignature(wb *grocksdb.WriteBatch, topic []byte, id uint32, signature *bchain.EventSignature) error {
+	buf, err := json.Marshal(signature)
+	if err != nil {
+		return err
+	}
+	wb.PutCF(d.cfh[cfEventSignatures], packEventSignatureKey(topic, id), buf)
+	cachedEventSignaturesMux.Lock()
+	delete(cachedEventSignatures, string(topic))
+	cachedEventSignaturesMux.Unlock()
+	return nil
+}
+
 // GetEthereumInternalData gets transaction internal data from DB
 func (d *RocksDB) GetEthereumInternalData(txid string) (*bchain.EthereumInternalData, error) {
 	btxID, err := d.chainParser.PackTxid(txid)
@@ -945,6 +1065,211 @@ func (d *RocksDB) storeInternalDataEthereumType(wb *grocksdb.WriteBatch, blockTx
 	return nil
 }
 
+// storeUserOperationsEthereumType stores the senders of the user operations of the transactions, they are needed to disconnect the block
+func (d *RocksDB) storeUserOperationsEthereumType(wb *grocksdb.WriteBatch, blockTxs []ethBlockTx) {
+	for i := range blockTxs {
+		blockTx := &blockTxs[i]
+		if len(blockTx.userOpSenders) > 0 {
+			buf := make([]byte, 0, len(blockTx.userOpSenders)*eth.EthereumTypeAddressDescriptorLen)
+			for _, sender := range blockTx.userOpSenders {
+				buf = appendAddress(buf, sender)
+			}
+			wb.PutCF(d.cfh[cfUserOperations], blockTx.btxID, buf)
+		}
+	}
+}
+
+func (d *RocksDB) getUserOperationSenders(btxID []byte) ([]bchain.AddressDescriptor, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfUserOperations], btxID)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf)%eth.EthereumTypeAddressDescriptorLen != 0 {
+		glog.Error("rocksdb: Inconsistent data in userOperations ", hex.EncodeToString(buf))
+		return nil, errors.New("Inconsistent data in userOperations")
+	}
+	senders := make([]bchain.AddressDescriptor, 0, len(buf)/eth.EthereumTypeAddressDescriptorLen)
+	for i := 0; i < len(buf); i += eth.EthereumTypeAddressDescriptorLen {
+		senders = append(senders, append(bchain.AddressDescriptor(nil), buf[i:i+eth.EthereumTypeAddressDescriptorLen]...))
+	}
+	return senders, nil
+}
+
+// TokenApprovalKind distinguishes the ERC20 allowance and the ERC721/ERC1155 ApprovalForAll
+type TokenApprovalKind byte
+
+const (
+	// Erc20Allowance is set by the ERC20 Approval event
+	Erc20Allowance TokenApprovalKind = 0
+	// ApprovalForAll is set by the ERC721/ERC1155 ApprovalForAll event
+	ApprovalForAll TokenApprovalKind = 1
+)
+
+const packedApprovalKeyLen = 3*eth.EthereumTypeAddressDescriptorLen + 1
+
+// AddrTokenApproval is the latest non-zero approval given by an address to a spender of a token contract
+type AddrTokenApproval struct {
+	Contract bchain.AddressDescriptor
+	Spender  bchain.AddressDescriptor
+	Kind     TokenApprovalKind
+	Value    big.Int
+	Height   uint32
+}
+
+func packApprovalKey(owner, contract, spender bchain.AddressDescriptor, kind TokenApprovalKind) []byte {
+	buf := make([]byte, 0, packedApprovalKeyLen)
+	buf = appendAddress(buf, owner)
+	buf = appendAddress(buf, contract)
+	buf = appendAddress(buf, spender)
+	return append(buf, byte(kind))
+}
+
+func packApprovalValue(value *big.Int, height uint32) []byte {
+	buf := make([]byte, vlq.MaxLen64+maxPackedBigintBytes)
+	l := packVaruint(uint(height), buf)
+	l += packBigint(value, buf[l:])
+	return buf[:l]
+}
+
+func unpackApprovalValue(buf []byte) (big.Int, uint32) {
+	height, l := unpackVaruint(buf)
+	value, _ := unpackBigint(buf[l:])
+	return value, uint32(height)
+}
+
+// processApprovalsEthereumType applies the approval events of the block to the pending approvals,
+// an empty value in the pending approvals means that the approval was revoked and is deleted from db
+// it returns the undo data with the previous values of the approvals changed in the block, they are needed to disconnect the block
+func (d *RocksDB) processApprovalsEthereumType(block *bchain.Block, approvals map[string][]byte) ([]byte, error) {
+	var undo []byte
+	changed := make(map[string]struct{})
+	varBuf := make([]byte, vlq.MaxLen64)
+	for txi := range block.Txs {
+		for _, a := range eth.GetTokenApprovalsFromTx(&block.Txs[txi]) {
+			owner, err := d.chainParser.GetAddrDescFromAddress(a.Owner)
+			if err != nil {
+				continue
+			}
+			contract, err := d.chainParser.GetAddrDescFromAddress(a.Contract)
+			if err != nil {
+				continue
+			}
+			spender, err := d.chainParser.GetAddrDescFromAddress(a.Spender)
+			if err != nil {
+				continue
+			}
+			kind := Erc20Allowance
+			if a.ForAll {
+				kind = ApprovalForAll
+			}
+			key := packApprovalKey(owner, contract, spender, kind)
+			strKey := string(key)
+			if _, found := changed[strKey]; !found {
+				changed[strKey] = struct{}{}
+				prev, found := approvals[strKey]
+				if !found {
+					val, err := d.db.GetCF(d.ro, d.cfh[cfApprovals], key)
+					if err != nil {
+						return nil, err
+					}
+					prev = append([]byte(nil), val.Data()...)
+					val.Free()
+				}
+				undo = append(undo, key...)
+				l := packVaruint(uint(len(prev)), varBuf)
+				undo = append(undo, varBuf[:l]...)
+				undo = append(undo, prev...)
+			}
+			if a.Value.Sign() == 0 {
+				approvals[strKey] = []byte{}
+			} else {
+				approvals[strKey] = packApprovalValue(&a.Value, block.Height)
+			}
+		}
+	}
+	return undo, nil
+}
+
+func (d *RocksDB) storeApprovalsEthereumType(wb *grocksdb.WriteBatch, approvals map[string][]byte) {
+	for key, val := range approvals {
+		if len(val) == 0 {
+			wb.DeleteCF(d.cfh[cfApprovals], []byte(key))
+		} else {
+			wb.PutCF(d.cfh[cfApprovals], []byte(key), val)
+		}
+	}
+}
+
+// storeApprovalsUndoEthereumType stores the previous values of the approvals changed in the block under the block height key
+func (d *RocksDB) storeApprovalsUndoEthereumType(wb *grocksdb.WriteBatch, height uint32, undo []byte) {
+	if len(undo) > 0 {
+		wb.PutCF(d.cfh[cfApprovals], packUint(height), undo)
+	}
+}
+
+// disconnectApprovalsEthereumType restores the approvals changed in the block from the undo data
+func (d *RocksDB) disconnectApprovalsEthereumType(wb *grocksdb.WriteBatch, height uint32) error {
+	key := packUint(height)
+	val, err := d.db.GetCF(d.ro, d.cfh[cfApprovals], key)
+	if err != nil {
+		return err
+	}
+	defer val.Free()
+	buf := val.Data()
+	for len(buf) > 0 {
+		if len(buf) < packedApprovalKeyLen {
+			glog.Error("rocksdb: Inconsistent approvals undo data at height ", height)
+			return errors.New("Inconsistent approvals undo data")
+		}
+		approvalKey := buf[:packedApprovalKeyLen]
+		buf = buf[packedApprovalKeyLen:]
+		l, ll := unpackVaruint(buf)
+		buf = buf[ll:]
+		if int(l) > len(buf) {
+			glog.Error("rocksdb: Inconsistent approvals undo data at height ", height)
+			return errors.New("Inconsistent approvals undo data")
+		}
+		if l == 0 {
+			wb.DeleteCF(d.cfh[cfApprovals], approvalKey)
+		} else {
+			wb.PutCF(d.cfh[cfApprovals], approvalKey, buf[:l])
+		}
+		buf = buf[l:]
+	}
+	wb.DeleteCF(d.cfh[cfApprovals], key)
+	return nil
+}
+
+// GetTokenApprovals returns the current non-zero approvals given by the address
+func (d *RocksDB) GetTokenApprovals(addrDesc bchain.AddressDescriptor) ([]AddrTokenApproval, error) {
+	if len(addrDesc) != eth.EthereumTypeAddressDescriptorLen {
+		return nil, nil
+	}
+	var approvals []AddrTokenApproval
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfApprovals])
+	defer it.Close()
+	for it.Seek(addrDesc); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, addrDesc) {
+			break
+		}
+		if len(key) != packedApprovalKeyLen {
+			continue
+		}
+		value, height := unpackApprovalValue(it.Value().Data())
+		approvals = append(approvals, AddrTokenApproval{
+			Contract: append(bchain.AddressDescriptor(nil), key[eth.EthereumTypeAddressDescriptorLen:2*eth.EthereumTypeAddressDescriptorLen]...),
+			Spender:  append(bchain.AddressDescriptor(nil), key[2*eth.EthereumTypeAddressDescriptorLen:3*eth.EthereumTypeAddressDescriptorLen]...),
+			Kind:     TokenApprovalKind(key[3*eth.EthereumTypeAddressDescriptorLen]),
+			Value:    value,
+			Height:   height,
+		})
+	}
+	return approvals, nil
+}
+
 var cachedContracts = make(map[string]*bchain.ContractInfo)
 var cachedContractsMux sync.Mutex
 
@@ -986,6 +1311,57 @@ func unpackContractInfo(buf []byte) (*bchain.ContractInfo, error) {
 	return &contractInfo, nil
 }
 
+// the creator of the contract and the creation transaction are stored in the contracts column under the key
+// contract address descriptor + contractCreatorKeySuffix, the value is the creator address descriptor + packed txid
+const contractCreatorKeySuffix = "creator"
+
+func packContractCreatorKey(contract bchain.AddressDescriptor) []byte {
+	key := make([]byte, 0, len(contract)+len(contractCreatorKeySuffix))
+	key = append(key, contract...)
+	return append(key, contractCreatorKeySuffix...)
+}
+
+func (d *RocksDB) packContractCreator(contractInfo *bchain.ContractInfo) ([]byte, error) {
+	creator, err := d.chainParser.GetAddrDescFromAddress(contractInfo.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	btxID, err := d.chainParser.PackTxid(contractInfo.CreationTx)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, len(creator)+len(btxID))
+	buf = append(buf, creator...)
+	return append(buf, btxID...), nil
+}
+
+func (d *RocksDB) unpackContractCreator(buf []byte, contractInfo *bchain.ContractInfo) error {
+	if len(buf) != eth.EthereumTypeAddressDescriptorLen+eth.EthereumTypeTxidLen {
+		return errors.New("Invalid contract creator data")
+	}
+	addresses, _, err := d.chainParser.GetAddressesFromAddrDesc(buf[:eth.EthereumTypeAddressDescriptorLen])
+	if err != nil {
+		return err
+	}
+	if len(addresses) > 0 {
+		contractInfo.CreatedBy = addresses[0]
+	}
+	contractInfo.CreationTx, err = d.chainParser.UnpackTxid(buf[eth.EthereumTypeAddressDescriptorLen:])
+	return err
+}
+
+func (d *RocksDB) getContractCreator(contract bchain.AddressDescriptor, contractInfo *bchain.ContractInfo) error {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfContracts], packContractCreatorKey(contract))
+	if err != nil {
+		return err
+	}
+	defer val.Free()
+	if len(val.Data()) == 0 {
+		return nil
+	}
+	return d.unpackContractCreator(val.Data(), contractInfo)
+}
+
 func (d *RocksDB) GetContractInfoForAddress(address string) (*bchain.ContractInfo, error) {
 	contract, err := d.chainParser.GetAddrDescFromAddress(address)
 	if err != nil || contract == nil {
@@ -1016,6 +1392,9 @@ func (d *RocksDB) GetContractInfo(contract bchain.AddressDescriptor, standardFro
 		if len(addresses) > 0 {
 			contractInfo.Contract = addresses[0]
 		}
+		if err = d.getContractCreator(contract, contractInfo); err != nil {
+			glog.Warning("GetContractInfo ", contractInfo.Contract, ": ", err)
+		}
 		// if the standard is specified and stored contractInfo has unknown standard, set and store it
 		if standardFromContext != bchain.UnknownTokenStandard && contractInfo.Standard == bchain.UnknownTokenStandard {
 			contractInfo.Standard = standardFromContext
@@ -1062,6 +1441,14 @@ func (d *RocksDB) storeContractInfo(wb *grocksdb.WriteBatch, contractInfo *bchai
 			contractInfo = storedCI
 		}
 		wb.PutCF(d.cfh[cfContracts], key, packContractInfo(contractInfo))
+		if contractInfo.CreatedBy != "" && contractInfo.CreationTx != "" {
+			creator, err := d.packContractCreator(contractInfo)
+			if err != nil {
+				glog.Warning("storeContractInfo ", contractInfo.Contract, ": ", err)
+			} else {
+				wb.PutCF(d.cfh[cfContracts], packContractCreatorKey(key), creator)
+			}
+		}
 		cacheKey := string(key)
 		cachedContractsMux.Lock()
 		delete(cachedContracts, cacheKey)
@@ -1070,6 +1457,100 @@ func (d *RocksDB) storeContractInfo(wb *grocksdb.WriteBatch, contractInfo *bchai
 	return nil
 }
 
+// contract ABIs are stored in the contracts column under the key contract address descriptor + contractABIKeySuffix,
+// which does not collide with the contract info stored under the contract address descriptor
+const contractABIKeySuffix = "abi"
+
+var cachedContractABIs = make(map[string]*bchain.ContractABI)
+var cachedContractABIsMux sync.Mutex
+
+func packContractABIKey(contract bchain.AddressDescriptor) []byte {
+	key := make([]byte, 0, len(contract)+len(contractABIKeySuffix))
+	key = append(key, contract...)
+	return append(key, contractABIKeySuffix...)
+}
+
+// GetContractABIForAddress returns the ABI of the contract uploaded by the operator or nil if there is none
+func (d *RocksDB) GetContractABIForAddress(address string) (*bchain.ContractABI, error) {
+	contract, err := d.chainParser.GetAddrDescFromAddress(address)
+	if err != nil || contract == nil {
+		return nil, err
+	}
+	return d.GetContractABI(contract)
+}
+
+// GetContractABI returns the ABI of the contract from cache or DB or nil if there is none
+func (d *RocksDB) GetContractABI(contract bchain.AddressDescriptor) (*bchain.ContractABI, error) {
+	cacheKey := string(contract)
+	cachedContractABIsMux.Lock()
+	contractABI, found := cachedContractABIs[cacheKey]
+	cachedContractABIsMux.Unlock()
+	if found {
+		return contractABI, nil
+	}
+	val, err := d.db.GetCF(d.ro, d.cfh[cfContracts], packContractABIKey(contract))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	contractABI = &bchain.ContractABI{}
+	if err = json.Unmarshal(buf, contractABI); err != nil {
+		return nil, errors.Annotatef(err, "contract %v ABI", contract)
+	}
+	addresses, _, _ := d.chainParser.GetAddressesFromAddrDesc(contract)
+	if len(addresses) > 0 {
+		contractABI.Contract = addresses[0]
+	}
+	if err = eth.ParseContractABI(contractABI); err != nil {
+		return nil, err
+	}
+	cachedContractABIsMux.Lock()
+	cachedContractABIs[cacheKey] = contractABI
+	cachedContractABIsMux.Unlock()
+	return contractABI, nil
+}
+
+// StoreContractABI validates the ABI of the contract and stores it in DB, overwriting the previously stored ABI
+func (d *RocksDB) StoreContractABI(contractABI *bchain.ContractABI) error {
+	key, err := d.chainParser.GetAddrDescFromAddress(contractABI.Contract)
+	if err != nil {
+		return err
+	}
+	if err = eth.ParseContractABI(contractABI); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(contractABI)
+	if err != nil {
+		return err
+	}
+	if err = d.db.PutCF(d.wo, d.cfh[cfContracts], packContractABIKey(key), buf); err != nil {
+		return err
+	}
+	cachedContractABIsMux.Lock()
+	delete(cachedContractABIs, string(key))
+	cachedContractABIsMux.Unlock()
+	return nil
+}
+
+// DeleteContractABI removes the ABI of the contract from DB
+func (d *RocksDB) DeleteContractABI(address string) error {
+	key, err := d.chainParser.GetAddrDescFromAddress(address)
+	if err != nil {
+		return err
+	}
+	if err = d.db.DeleteCF(d.wo, d.cfh[cfContracts], packContractABIKey(key)); err != nil {
+		return err
+	}
+	cachedContractABIsMux.Lock()
+	delete(cachedContractABIs, string(key))
+	cachedContractABIsMux.Unlock()
+	return nil
+}
+
 func packBlockTx(buf []byte, blockTx *ethBlockTx) []byte {
 	varBuf := make([]byte, maxPackedBigintBytes)
 	buf = append(buf, blockTx.btxID...)
@@ -1111,6 +1592,11 @@ func (d *RocksDB) storeAndCleanupBlockTxsEthereumType(wb *grocksdb.WriteBatch, b
 	}
 	key := packUint(block.Height)
 	wb.PutCF(d.cfh[cfBlockTxs], key, buf)
+	// the approvals undo data are needed only for the blocks which can be disconnected
+	keep := d.chainParser.KeepBlockAddresses()
+	if block.Height > uint32(keep) {
+		wb.DeleteCF(d.cfh[cfApprovals], packUint(block.Height-uint32(keep)))
+	}
 	return d.cleanupBlockTxs(wb, block)
 }
 
@@ -1456,8 +1942,20 @@ func (d *RocksDB) disconnectBlockTxsEthereumType(wb *grocksdb.WriteBatch, height
 				}
 			}
 		}
+		// user operations
+		senders, err := d.getUserOperationSenders(blockTx.btxID)
+		if err != nil {
+			return err
+		}
+		for _, sender := range senders {
+			if err := d.disconnectAddress(blockTx.btxID, false, sender, nil, addresses, contracts); err != nil {
+				return err
+			}
+		}
 		wb.DeleteCF(d.cfh[cfTransactions], blockTx.btxID)
 		wb.DeleteCF(d.cfh[cfInternalData], blockTx.btxID)
+		wb.DeleteCF(d.cfh[cfUserOperations], blockTx.btxID)
+		d.disconnectNftTransfersEthereumType(wb, height, blockTx)
 	}
 	for a := range addresses {
 		key := packAddressKey([]byte(a), height)
@@ -1488,6 +1986,9 @@ func (d *RocksDB) DisconnectBlockRangeEthereumType(lower uint32, higher uint32)
 		if err := d.disconnectBlockTxsEthereumType(wb, height, blocks[height-lower], contracts); err != nil {
 			return err
 		}
+		if err := d.disconnectApprovalsEthereumType(wb, height); err != nil {
+			return err
+		}
 		key := packUint(height)
 		wb.DeleteCF(d.cfh[cfBlockTxs], key)
 		wb.DeleteCF(d.cfh[cfHeight], key)
@@ -1806,29 +2307,60 @@ func packUnpackedAddrContracts(acs *unpackedAddrContracts) []byte {
 
 func (d *RocksDB) storeUnpackedAddressContracts(wb *grocksdb.WriteBatch, acm map[string]*unpackedAddrContracts) error {
 	for addrDesc, acs := range acm {
+		ad := bchain.AddressDescriptor(addrDesc)
 		// address with 0 contracts is removed from db - happens on disconnect
 		if acs == nil || (acs.NonContractTxs == 0 && acs.InternalTxs == 0 && len(acs.Contracts) == 0) {
-			wb.DeleteCF(d.cfh[cfAddressContracts], bchain.AddressDescriptor(addrDesc))
+			stored, err := d.getStoredPackedAddrContracts(ad)
+			if err != nil {
+				return err
+			}
+			if err := d.updateContractHolders(wb, ad, stored, nil); err != nil {
+				return err
+			}
+			if err := d.updateNftHolders(wb, ad, stored, nil); err != nil {
+				return err
+			}
+			wb.DeleteCF(d.cfh[cfAddressContracts], ad)
 		} else {
 			// do not store large address contracts found in cache
 			if _, found := d.addrContractsCache[addrDesc]; !found {
 				buf := packUnpackedAddrContracts(acs)
-				wb.PutCF(d.cfh[cfAddressContracts], bchain.AddressDescriptor(addrDesc), buf)
+				// acs.Packed holds the stored data, from which the contract holders index was built
+				if err := d.updateContractHolders(wb, ad, acs.Packed, acs); err != nil {
+					return err
+				}
+				if err := d.updateNftHolders(wb, ad, acs.Packed, acs); err != nil {
+					return err
+				}
+				wb.PutCF(d.cfh[cfAddressContracts], ad, buf)
+				acs.Packed = buf
 			}
 		}
 	}
 	return nil
 }
 
-func (d *RocksDB) writeContractsCache() {
-	wb := grocksdb.NewWriteBatch()
-	defer wb.Destroy()
+// storeAddrContractsCacheToBatch adds the cached address contracts to the write batch
+func (d *RocksDB) storeAddrContractsCacheToBatch(wb *grocksdb.WriteBatch) {
 	d.addrContractsCacheMux.Lock()
+	defer d.addrContractsCacheMux.Unlock()
 	for addrDesc, acs := range d.addrContractsCache {
 		buf := packUnpackedAddrContracts(acs)
+		if err := d.updateContractHolders(wb, bchain.AddressDescriptor(addrDesc), acs.Packed, acs); err != nil {
+			glog.Error("storeAddrContractsCacheToBatch: address ", addrDesc, ", error ", err)
+		}
+		if err := d.updateNftHolders(wb, bchain.AddressDescriptor(addrDesc), acs.Packed, acs); err != nil {
+			glog.Error("storeAddrContractsCacheToBatch: address ", addrDesc, ", error ", err)
+		}
 		wb.PutCF(d.cfh[cfAddressContracts], bchain.AddressDescriptor(addrDesc), buf)
+		acs.Packed = buf
 	}
-	d.addrContractsCacheMux.Unlock()
+}
+
+func (d *RocksDB) writeContractsCache() {
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	d.storeAddrContractsCacheToBatch(wb)
 	if err := d.WriteBatch(wb); err != nil {
 		glog.Error("writeContractsCache: failed to store addrContractsCache: ", err)
 	}
@@ -1848,6 +2380,10 @@ func (d *RocksDB) periodicStoreAddrContractsCache() {
 	for {
 		<-timer.C
 		timer.Reset(period)
+		// during bulk connect the cache is stored in the checkpoints
+		if d.is != nil && d.is.GetBulkCheckpoint() != nil {
+			continue
+		}
 		d.storeAddrContractsCache()
 	}
 }
@@ -3,7 +3,9 @@
 package db
 
 import (
+	"bytes"
 	"encoding/hex"
+	"fmt"
 	"math/big"
 	"reflect"
 	"testing"
@@ -578,8 +580,11 @@ func Test_BulkConnect_EthereumType(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if d.is.DbState != common.DbStateInconsistent {
-		t.Fatal("DB not in DbStateInconsistent")
+	if d.is.DbState == common.DbStateInconsistent {
+		t.Fatal("DB in DbStateInconsistent")
+	}
+	if c := d.is.GetBulkCheckpoint(); c == nil || c.Height != 0 || c.Hash != "" {
+		t.Fatalf("Unexpected bulk checkpoint %+v", c)
 	}
 
 	if len(d.is.BlockTimes) != 0 {
@@ -594,6 +599,10 @@ func Test_BulkConnect_EthereumType(t *testing.T) {
 			t.Fatal(err)
 		}
 	}
+	// block without blockTxs is not stored before the next checkpoint
+	if height, hash, err := d.GetBestBlock(); err != nil || height != 0 || hash != "" {
+		t.Fatalf("GetBestBlock() = %d %s %v, want 0", height, hash, err)
+	}
 
 	// connect 2nd block, simulate InternalDataError
 	block2 := dbtestdata.GetTestEthereumTypeBlock2(d.chainParser)
@@ -601,6 +610,10 @@ func Test_BulkConnect_EthereumType(t *testing.T) {
 		t.Fatal(err)
 	}
 	block2.CoinSpecificData = nil
+	// block with blockTxs is stored in checkpoint
+	if c := d.is.GetBulkCheckpoint(); c == nil || c.Height != block2.Height || c.Hash != block2.Hash {
+		t.Fatalf("Unexpected bulk checkpoint %+v", c)
+	}
 
 	if err := bc.Close(); err != nil {
 		t.Fatal(err)
@@ -609,6 +622,9 @@ func Test_BulkConnect_EthereumType(t *testing.T) {
 	if d.is.DbState != common.DbStateOpen {
 		t.Fatal("DB not in DbStateOpen")
 	}
+	if c := d.is.GetBulkCheckpoint(); c != nil {
+		t.Fatalf("Unexpected bulk checkpoint %+v after Close", c)
+	}
 
 	verifyAfterEthereumTypeBlock2(t, d, true)
 
@@ -1427,3 +1443,172 @@ func Test_packUnpackContractInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestRocksDB_ContractCreator_EthereumType(t *testing.T) {
+	d := setupRocksDB(t, &testEthereumParser{
+		EthereumParser: ethereumTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	contractInfo := &bchain.ContractInfo{
+		Type:           bchain.UnhandledTokenStandard,
+		Standard:       bchain.UnhandledTokenStandard,
+		Contract:       dbtestdata.EthAddr20EIP55,
+		CreatedInBlock: 44444,
+		CreatedBy:      dbtestdata.EthAddr7bEIP55,
+		CreationTx:     "0x" + dbtestdata.EthTxidB1T1,
+	}
+	if err := d.StoreContractInfo(contractInfo); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.GetContractInfo(hexToBytes(dbtestdata.EthAddr20), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, contractInfo) {
+		t.Errorf("GetContractInfo() = %+v, want %+v", got, contractInfo)
+	}
+
+	// the destruction of the contract keeps the creator
+	if err := d.StoreContractInfo(&bchain.ContractInfo{Contract: dbtestdata.EthAddr20EIP55, DestructedInBlock: 44445}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = d.GetContractInfo(hexToBytes(dbtestdata.EthAddr20), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CreatedBy != dbtestdata.EthAddr7bEIP55 || got.CreationTx != "0x"+dbtestdata.EthTxidB1T1 || got.DestructedInBlock != 44445 {
+		t.Errorf("GetContractInfo() = %+v", got)
+	}
+}
+
+func approvalLog(contract, owner, spender string, forAll bool, value int64) *bchain.RpcLog {
+	signature := "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+	if forAll {
+		signature = "0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31"
+	}
+	return &bchain.RpcLog{
+		Address: "0x" + contract,
+		Topics: []string{
+			signature,
+			"0x000000000000000000000000" + owner,
+			"0x000000000000000000000000" + spender,
+		},
+		Data: fmt.Sprintf("0x%064x", value),
+	}
+}
+
+func TestRocksDB_Approvals_EthereumType(t *testing.T) {
+	d := setupRocksDB(t, &testEthereumParser{
+		EthereumParser: ethereumTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	newBlock := func(height uint32, logs ...*bchain.RpcLog) *bchain.Block {
+		return &bchain.Block{
+			BlockHeader: bchain.BlockHeader{Height: height},
+			Txs: []bchain.Tx{{
+				CoinSpecificData: bchain.EthereumSpecificData{
+					Receipt: &bchain.RpcReceipt{Logs: logs},
+				},
+			}},
+		}
+	}
+	connect := func(block *bchain.Block) {
+		wb := grocksdb.NewWriteBatch()
+		defer wb.Destroy()
+		approvals := make(map[string][]byte)
+		undo, err := d.processApprovalsEthereumType(block, approvals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.storeApprovalsEthereumType(wb, approvals)
+		d.storeApprovalsUndoEthereumType(wb, block.Height, undo)
+		if err := d.WriteBatch(wb); err != nil {
+			t.Fatal(err)
+		}
+	}
+	disconnect := func(height uint32) {
+		wb := grocksdb.NewWriteBatch()
+		defer wb.Destroy()
+		if err := d.disconnectApprovalsEthereumType(wb, height); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.WriteBatch(wb); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	block1 := newBlock(100,
+		approvalLog(dbtestdata.EthAddrContract0d, dbtestdata.EthAddr4b, dbtestdata.EthAddr20, false, 1000),
+		approvalLog(dbtestdata.EthAddrContractCd, dbtestdata.EthAddr4b, dbtestdata.EthAddr7b, true, 1),
+	)
+	block2 := newBlock(101,
+		approvalLog(dbtestdata.EthAddrContract0d, dbtestdata.EthAddr4b, dbtestdata.EthAddr20, false, 500),
+		approvalLog(dbtestdata.EthAddrContractCd, dbtestdata.EthAddr4b, dbtestdata.EthAddr7b, true, 0),
+		approvalLog(dbtestdata.EthAddrContract0d, dbtestdata.EthAddr4b, dbtestdata.EthAddr7b, false, 7),
+	)
+	key1 := dbtestdata.EthAddr4b + dbtestdata.EthAddrContract0d + dbtestdata.EthAddr20 + "00"
+	key2 := dbtestdata.EthAddr4b + dbtestdata.EthAddrContractCd + dbtestdata.EthAddr7b + "01"
+	key3 := dbtestdata.EthAddr4b + dbtestdata.EthAddrContract0d + dbtestdata.EthAddr7b + "00"
+	value1 := varuintToHex(100) + bigintToHex(big.NewInt(1000))
+	value2 := varuintToHex(100) + bigintToHex(big.NewInt(1))
+	undo1 := key1 + varuintToHex(0) + key2 + varuintToHex(0)
+	afterBlock1 := func() []keyPair {
+		return []keyPair{
+			{uintToHex(100), undo1, nil},
+			{key1, value1, nil},
+			{key2, value2, nil},
+		}
+	}
+	afterBlock2 := func() []keyPair {
+		return []keyPair{
+			{uintToHex(100), undo1, nil},
+			{uintToHex(101), key1 + varuintToHex(uint(len(value1)/2)) + value1 + key2 + varuintToHex(uint(len(value2)/2)) + value2 + key3 + varuintToHex(0), nil},
+			{key1, varuintToHex(101) + bigintToHex(big.NewInt(500)), nil},
+			{key3, varuintToHex(101) + bigintToHex(big.NewInt(7)), nil},
+		}
+	}
+
+	connect(block1)
+	if err := checkColumn(d, cfApprovals, afterBlock1()); err != nil {
+		t.Fatal(err)
+	}
+	connect(block2)
+	if err := checkColumn(d, cfApprovals, afterBlock2()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.GetTokenApprovals(hexToBytes(dbtestdata.EthAddr4b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AddrTokenApproval{
+		{Contract: hexToBytes(dbtestdata.EthAddrContract0d), Spender: hexToBytes(dbtestdata.EthAddr20), Kind: Erc20Allowance, Value: *big.NewInt(500), Height: 101},
+		{Contract: hexToBytes(dbtestdata.EthAddrContract0d), Spender: hexToBytes(dbtestdata.EthAddr7b), Kind: Erc20Allowance, Value: *big.NewInt(7), Height: 101},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetTokenApprovals() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if !bytes.Equal(got[i].Contract, want[i].Contract) || !bytes.Equal(got[i].Spender, want[i].Spender) ||
+			got[i].Kind != want[i].Kind || got[i].Value.Cmp(&want[i].Value) != 0 || got[i].Height != want[i].Height {
+			t.Errorf("GetTokenApprovals()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// disconnect restores the approvals from the undo data and removes the undo row of the block
+	disconnect(101)
+	if err := checkColumn(d, cfApprovals, afterBlock1()); err != nil {
+		t.Fatal(err)
+	}
+	// reconnect gives the same state as the first connect
+	connect(block2)
+	if err := checkColumn(d, cfApprovals, afterBlock2()); err != nil {
+		t.Fatal(err)
+	}
+	disconnect(101)
+	disconnect(100)
+	if err := checkColumn(d, cfApprovals, []keyPair{}); err != nil {
+		t.Fatal(err)
+	}
+}
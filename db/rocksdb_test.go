@@ -766,8 +766,11 @@ func Test_BulkConnect_BitcoinType(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if d.is.DbState != common.DbStateInconsistent {
-		t.Fatal("DB not in DbStateInconsistent")
+	if d.is.DbState == common.DbStateInconsistent {
+		t.Fatal("DB in DbStateInconsistent")
+	}
+	if c := d.is.GetBulkCheckpoint(); c == nil || c.Height != 0 || c.Hash != "" {
+		t.Fatalf("Unexpected bulk checkpoint %+v", c)
 	}
 
 	if len(d.is.BlockTimes) != 0 {
@@ -782,10 +785,19 @@ func Test_BulkConnect_BitcoinType(t *testing.T) {
 			t.Fatal(err)
 		}
 	}
+	// block without blockTxs is not stored before the next checkpoint
+	if height, hash, err := d.GetBestBlock(); err != nil || height != 0 || hash != "" {
+		t.Fatalf("GetBestBlock() = %d %s %v, want 0", height, hash, err)
+	}
 
-	if err := bc.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser), true); err != nil {
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	if err := bc.ConnectBlock(block2, true); err != nil {
 		t.Fatal(err)
 	}
+	// block with blockTxs is stored in checkpoint
+	if c := d.is.GetBulkCheckpoint(); c == nil || c.Height != block2.Height || c.Hash != block2.Hash {
+		t.Fatalf("Unexpected bulk checkpoint %+v", c)
+	}
 
 	if err := bc.Close(); err != nil {
 		t.Fatal(err)
@@ -794,6 +806,9 @@ func Test_BulkConnect_BitcoinType(t *testing.T) {
 	if d.is.DbState != common.DbStateOpen {
 		t.Fatal("DB not in DbStateOpen")
 	}
+	if c := d.is.GetBulkCheckpoint(); c != nil {
+		t.Fatalf("Unexpected bulk checkpoint %+v after Close", c)
+	}
 
 	verifyAfterBitcoinTypeBlock2(t, d)
 
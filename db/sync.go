@@ -23,6 +23,7 @@ type SyncWorker struct {
 	startHash              string
 	chanOsSignal           chan os.Signal
 	missingBlockRetry      MissingBlockRetryConfig
+	pipeline               SyncPipelineConfig
 	metrics                *common.Metrics
 	is                     *common.InternalState
 }
@@ -42,15 +43,18 @@ type MissingBlockRetryConfig struct {
 // SyncWorkerConfig bundles optional tuning knobs for SyncWorker.
 type SyncWorkerConfig struct {
 	MissingBlockRetry MissingBlockRetryConfig
+	Pipeline          SyncPipelineConfig
 }
 
-func defaultSyncWorkerConfig() SyncWorkerConfig {
+// DefaultSyncWorkerConfig returns the default tuning of SyncWorker.
+func DefaultSyncWorkerConfig() SyncWorkerConfig {
 	return SyncWorkerConfig{
 		MissingBlockRetry: MissingBlockRetryConfig{
 			RecheckThreshold:    10,              // - RecheckThreshold >= 1
 			RetryDelay:          1 * time.Second, // - TipRecheckThreshold >= 1 && TipRecheckThreshold <= RecheckThreshold
 			TipRecheckThreshold: 3,               // - RetryDelay > 0
 		},
+		Pipeline: defaultSyncPipelineConfig(),
 	}
 }
 
@@ -64,7 +68,7 @@ func NewSyncWorkerWithConfig(db *RocksDB, chain bchain.BlockChain, syncWorkers,
 	if minStartHeight < 0 {
 		minStartHeight = 0
 	}
-	effectiveCfg := defaultSyncWorkerConfig()
+	effectiveCfg := DefaultSyncWorkerConfig()
 	if cfg != nil {
 		effectiveCfg = *cfg
 	}
@@ -77,6 +81,7 @@ func NewSyncWorkerWithConfig(db *RocksDB, chain bchain.BlockChain, syncWorkers,
 		startHeight:       uint32(minStartHeight),
 		chanOsSignal:      chanOsSignal,
 		missingBlockRetry: effectiveCfg.MissingBlockRetry,
+		pipeline:          effectiveCfg.Pipeline,
 		metrics:           metrics,
 		is:                is,
 	}, nil
@@ -211,7 +216,13 @@ func (w *SyncWorker) resyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync b
 				glog.Infof("resync: bulk sync of blocks %d-%d, using %d workers", w.startHeight, remoteBestHeight, w.syncWorkers)
 				// Bulk sync can encounter a disappearing block hash during reorgs.
 				// When that happens, it returns errResync to trigger a full restart.
-				err = w.BulkConnectBlocks(w.startHeight, remoteBestHeight)
+				// For ChainEthereumType, the fetching of blocks with logs and traces is the bottleneck,
+				// use pipelined sync which adapts the fetch concurrency to the backend.
+				if w.chain.GetChainParser().GetChainType() == bchain.ChainEthereumType && !w.pipeline.Disabled {
+					err = w.PipelinedConnectBlocks(w.startHeight, remoteBestHeight)
+				} else {
+					err = w.BulkConnectBlocks(w.startHeight, remoteBestHeight)
+				}
 				if err != nil {
 					if stdErrors.Is(err, errResync) {
 						// block hash changed during parallel sync, restart the full resync
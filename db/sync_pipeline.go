@@ -0,0 +1,464 @@
+package db
+
+import (
+	"context"
+	stdErrors "errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+)
+
+// pipelined sync
+// the blocks (including logs and traces, which are fetched by the backend GetBlock) are prefetched
+// by a dynamic number of fetchers and connected strictly in order using BulkConnect
+// the number of concurrent fetchers is controlled by AIMD - it grows slowly while the backend
+// responds and it is halved when the backend returns rate limit or timeout errors
+
+// SyncPipelineConfig controls the pipelined sync of Ethereum type coins.
+type SyncPipelineConfig struct {
+	// Disabled switches the initial sync back to BulkConnectBlocks.
+	Disabled bool
+	// MinConcurrency is the lower bound of concurrent block fetches.
+	MinConcurrency int
+	// MaxConcurrency is the upper bound of concurrent block fetches, if zero it is four times the number of sync workers.
+	MaxConcurrency int
+	// Window is the maximum number of blocks fetched ahead of the last connected block,
+	// it bounds the memory used by blocks waiting to be connected.
+	Window int
+	// ThrottleDelay is the initial pause after the backend throttles a request, it doubles with each consecutive throttle.
+	ThrottleDelay time.Duration
+	// MaxThrottleDelay caps the pause after throttling.
+	MaxThrottleDelay time.Duration
+	// TraceRetries is the number of refetches of a block whose traces were throttled
+	// before it is accepted with internal data error (to be refetched later).
+	TraceRetries int
+}
+
+func defaultSyncPipelineConfig() SyncPipelineConfig {
+	return SyncPipelineConfig{
+		MinConcurrency:   1,
+		Window:           256,
+		ThrottleDelay:    500 * time.Millisecond,
+		MaxThrottleDelay: 30 * time.Second,
+		TraceRetries:     3,
+	}
+}
+
+// throttle reasons, used also as metric labels
+const (
+	throttleNone      = ""
+	throttleRateLimit = "ratelimit"
+	throttleTimeout   = "timeout"
+)
+
+// classifyThrottleError returns the reason if the error signals an overloaded backend
+func classifyThrottleError(err error) string {
+	if err == nil {
+		return throttleNone
+	}
+	if stdErrors.Is(err, context.DeadlineExceeded) {
+		return throttleTimeout
+	}
+	var ne net.Error
+	if stdErrors.As(err, &ne) && ne.Timeout() {
+		return throttleTimeout
+	}
+	return classifyThrottleMessage(err.Error())
+}
+
+func classifyThrottleMessage(m string) string {
+	m = strings.ToLower(m)
+	if strings.Contains(m, "429") || strings.Contains(m, "too many requests") || strings.Contains(m, "rate limit") ||
+		strings.Contains(m, "rate-limit") || strings.Contains(m, "ratelimit") || strings.Contains(m, "limit exceeded") {
+		return throttleRateLimit
+	}
+	if strings.Contains(m, "timeout") || strings.Contains(m, "timed out") || strings.Contains(m, "deadline exceeded") ||
+		strings.Contains(m, "execution aborted") {
+		return throttleTimeout
+	}
+	return throttleNone
+}
+
+// adaptiveLimiter is a semaphore with a limit adjusted by the outcome of the requests
+type adaptiveLimiter struct {
+	mux       sync.Mutex
+	min, max  int
+	limit     int
+	inflight  int
+	successes int
+	changed   chan struct{}
+}
+
+func newAdaptiveLimiter(min, max, initial int) *adaptiveLimiter {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	if initial < min {
+		initial = min
+	} else if initial > max {
+		initial = max
+	}
+	return &adaptiveLimiter{
+		min:     min,
+		max:     max,
+		limit:   initial,
+		changed: make(chan struct{}),
+	}
+}
+
+// notify wakes up waiting acquirers, must be called under lock
+func (l *adaptiveLimiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// acquire waits for a free slot, returns false if stop was closed
+func (l *adaptiveLimiter) acquire(stop <-chan struct{}) bool {
+	for {
+		l.mux.Lock()
+		if l.inflight < l.limit {
+			l.inflight++
+			l.mux.Unlock()
+			return true
+		}
+		ch := l.changed
+		l.mux.Unlock()
+		select {
+		case <-ch:
+		case <-stop:
+			return false
+		}
+	}
+}
+
+// release frees the slot, increases the limit additively after limit successful requests
+// and decreases it multiplicatively on throttling
+func (l *adaptiveLimiter) release(throttled bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.inflight--
+	if throttled {
+		l.successes = 0
+		l.limit /= 2
+		if l.limit < l.min {
+			l.limit = l.min
+		}
+	} else {
+		l.successes++
+		if l.successes >= l.limit && l.limit < l.max {
+			l.limit++
+			l.successes = 0
+		}
+	}
+	l.notify()
+}
+
+func (l *adaptiveLimiter) state() (limit int, inflight int) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return l.limit, l.inflight
+}
+
+// blockReorderer releases the blocks received in arbitrary order strictly by height
+type blockReorderer struct {
+	pending map[uint32]*bchain.Block
+	next    uint32
+}
+
+func newBlockReorderer(lower uint32) *blockReorderer {
+	return &blockReorderer{
+		pending: make(map[uint32]*bchain.Block),
+		next:    lower,
+	}
+}
+
+// push stores the block and returns the blocks which can be connected, in the order of height
+func (r *blockReorderer) push(b *bchain.Block) []*bchain.Block {
+	r.pending[b.Height] = b
+	var ready []*bchain.Block
+	for {
+		nb, ok := r.pending[r.next]
+		if !ok {
+			return ready
+		}
+		delete(r.pending, r.next)
+		ready = append(ready, nb)
+		r.next++
+	}
+}
+
+// queued returns the number of blocks waiting for a block with lower height
+func (r *blockReorderer) queued() int {
+	return len(r.pending)
+}
+
+type syncPipeline struct {
+	w             *SyncWorker
+	cfg           SyncPipelineConfig
+	limiter       *adaptiveLimiter
+	higher        uint32
+	stop          chan struct{}
+	stopOnce      sync.Once
+	err           error
+	mux           sync.Mutex
+	pauseUntil    time.Time
+	throttleDelay time.Duration
+}
+
+// stopWith stops the pipeline, only the first error is kept
+func (p *syncPipeline) stopWith(err error) {
+	p.stopOnce.Do(func() {
+		p.err = err
+		close(p.stop)
+	})
+}
+
+// throttle pauses all fetchers, the pause doubles with each consecutive throttling
+func (p *syncPipeline) throttle(reason string) {
+	p.w.metrics.SyncPipelineThrottled.With(common.Labels{"reason": reason}).Inc()
+	p.mux.Lock()
+	if p.throttleDelay == 0 {
+		p.throttleDelay = p.cfg.ThrottleDelay
+	} else {
+		p.throttleDelay *= 2
+		if p.throttleDelay > p.cfg.MaxThrottleDelay {
+			p.throttleDelay = p.cfg.MaxThrottleDelay
+		}
+	}
+	p.pauseUntil = time.Now().Add(p.throttleDelay)
+	delay := p.throttleDelay
+	p.mux.Unlock()
+	limit, _ := p.limiter.state()
+	glog.Warning("sync: backend throttled (", reason, "), concurrency ", limit, ", pausing for ", delay)
+}
+
+func (p *syncPipeline) resetThrottle() {
+	p.mux.Lock()
+	p.throttleDelay = 0
+	p.mux.Unlock()
+}
+
+// waitPause waits until the throttling pause is over, returns false if the pipeline was stopped
+func (p *syncPipeline) waitPause() bool {
+	p.mux.Lock()
+	d := time.Until(p.pauseUntil)
+	p.mux.Unlock()
+	if d <= 0 {
+		return true
+	}
+	start := time.Now()
+	defer func() {
+		p.w.metrics.SyncPipelineWait.With(common.Labels{"reason": "throttle"}).Add(float64(time.Since(start)) / 1e6)
+	}()
+	select {
+	case <-time.After(d):
+		return true
+	case <-p.stop:
+		return false
+	}
+}
+
+func (p *syncPipeline) updateMetrics() {
+	limit, inflight := p.limiter.state()
+	p.w.metrics.SyncPipelineConcurrency.Set(float64(limit))
+	p.w.metrics.SyncPipelineInflight.Set(float64(inflight))
+}
+
+// fetchBlock gets the block at height, retrying until it succeeds or the pipeline is stopped
+func (p *syncPipeline) fetchBlock(height uint32) *bchain.Block {
+	w := p.w
+	notFoundRetries := 0
+	traceRetries := 0
+	for {
+		if !p.waitPause() || !p.limiter.acquire(p.stop) {
+			return nil
+		}
+		p.updateMetrics()
+		hash, err := w.chain.GetBlockHash(height)
+		var block *bchain.Block
+		if err == nil {
+			block, err = w.chain.GetBlock(hash, height)
+		}
+		reason := classifyThrottleError(err)
+		if err == nil && traceRetries < p.cfg.TraceRetries {
+			// the backend returns the block even if the traces failed, refetch it if the traces were throttled
+			if bsd, ok := block.CoinSpecificData.(*bchain.EthereumBlockSpecificData); ok && bsd != nil && bsd.InternalDataError != "" {
+				reason = classifyThrottleMessage(bsd.InternalDataError)
+				if reason != throttleNone {
+					traceRetries++
+				}
+			}
+		}
+		p.limiter.release(reason != throttleNone)
+		p.updateMetrics()
+		if reason != throttleNone {
+			p.throttle(reason)
+			continue
+		}
+		if err == nil {
+			p.resetThrottle()
+			w.metrics.SyncPipelineBlocks.With(common.Labels{"stage": "fetched"}).Inc()
+			return block
+		}
+		if stdErrors.Is(err, bchain.ErrBlockNotFound) {
+			notFoundRetries++
+			glog.Error("sync: pipeline block ", height, " ", hash, " error ", err, ". Retrying...")
+			threshold := w.missingBlockRetry.RecheckThreshold
+			if height == p.higher {
+				threshold = w.missingBlockRetry.TipRecheckThreshold
+			}
+			if notFoundRetries >= threshold {
+				restart, checkErr := w.shouldRestartSyncOnMissingBlock(height, hash)
+				if checkErr != nil {
+					glog.Error("sync: pipeline missing block check error ", checkErr)
+				} else if restart {
+					glog.Warning("sync: block ", height, " ", hash, " no longer on chain, restarting sync")
+					p.stopWith(errResync)
+					return nil
+				}
+			}
+		} else {
+			notFoundRetries = 0
+			glog.Error("sync: pipeline block ", height, " error ", err, ". Retrying...")
+		}
+		w.metrics.IndexResyncErrors.With(common.Labels{"error": "failure"}).Inc()
+		select {
+		case <-p.stop:
+			return nil
+		case <-time.After(w.missingBlockRetry.RetryDelay):
+		}
+	}
+}
+
+// PipelinedConnectBlocks connects blocks in the range lower-higher in bulk mode,
+// the blocks are prefetched with bounded adaptive concurrency and connected strictly in order
+func (w *SyncWorker) PipelinedConnectBlocks(lower, higher uint32) error {
+	cfg := w.pipeline
+	maxConcurrency := cfg.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 4 * w.syncWorkers
+	}
+	window := cfg.Window
+	if window < maxConcurrency {
+		window = maxConcurrency
+	}
+	p := &syncPipeline{
+		w:       w,
+		cfg:     cfg,
+		limiter: newAdaptiveLimiter(cfg.MinConcurrency, maxConcurrency, w.syncWorkers),
+		higher:  higher,
+		stop:    make(chan struct{}),
+	}
+	bc, err := w.db.InitBulkConnect()
+	if err != nil {
+		return err
+	}
+	glog.Infof("sync: pipelined connect of blocks %d-%d, concurrency %d-%d, window %d", lower, higher, p.limiter.min, p.limiter.max, window)
+	// slots bound the number of blocks fetched but not connected yet
+	slots := make(chan struct{}, window)
+	results := make(chan *bchain.Block, window)
+	writeBlockDone := make(chan struct{})
+	keep := uint32(w.chain.GetChainParser().KeepBlockAddresses())
+	go func() {
+		defer close(writeBlockDone)
+		reorderer := newBlockReorderer(lower)
+		start := time.Now()
+	WriteBlockLoop:
+		for reorderer.next <= higher {
+			select {
+			case b := <-results:
+				for _, nb := range reorderer.push(b) {
+					if !w.dryRun {
+						if err := bc.ConnectBlock(nb, nb.Height+keep > higher); err != nil {
+							glog.Fatal("sync: pipeline ", nb.Height, " ", nb.Hash, " error ", err)
+						}
+					}
+					<-slots
+					w.metrics.SyncPipelineBlocks.With(common.Labels{"stage": "connected"}).Inc()
+					if nb.Height > 0 && nb.Height%1000 == 0 {
+						w.metrics.BlockbookBestHeight.Set(float64(nb.Height))
+						limit, inflight := p.limiter.state()
+						glog.Info("connecting block ", nb.Height, " ", nb.Hash, ", elapsed ", time.Since(start), ", concurrency ", limit, ", inflight ", inflight, ", queued ", reorderer.queued(), " ", w.db.GetAndResetConnectBlockStats())
+						start = time.Now()
+					}
+				}
+				w.metrics.SyncPipelineQueued.Set(float64(reorderer.queued()))
+			case <-p.stop:
+				break WriteBlockLoop
+			}
+		}
+		w.metrics.SyncPipelineQueued.Set(0)
+		if err := bc.Close(); err != nil {
+			glog.Error("sync: bulkconnect.Close error ", err)
+		}
+		glog.Info("sync: pipeline writer exiting...")
+	}()
+	var wg sync.WaitGroup
+	msTime := time.Now().Add(1 * time.Minute)
+DispatchLoop:
+	for h := lower; h <= higher; h++ {
+		select {
+		case <-p.stop:
+			break DispatchLoop
+		case <-w.chanOsSignal:
+			glog.Info("sync: pipelined connect interrupted at height ", h)
+			p.stopWith(ErrOperationInterrupted)
+			break DispatchLoop
+		default:
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			// the writer does not keep up or waits for a slow block, apply backpressure
+			start := time.Now()
+			select {
+			case slots <- struct{}{}:
+			case <-p.stop:
+				break DispatchLoop
+			case <-w.chanOsSignal:
+				glog.Info("sync: pipelined connect interrupted at height ", h)
+				p.stopWith(ErrOperationInterrupted)
+				break DispatchLoop
+			}
+			w.metrics.SyncPipelineWait.With(common.Labels{"reason": "window"}).Add(float64(time.Since(start)) / 1e6)
+		}
+		wg.Add(1)
+		go func(height uint32) {
+			defer wg.Done()
+			if b := p.fetchBlock(height); b != nil {
+				b.Height = height
+				select {
+				case results <- b:
+				case <-p.stop:
+				}
+			}
+		}(h)
+		if msTime.Before(time.Now()) {
+			if glog.V(1) {
+				glog.Info(w.db.GetMemoryStats())
+			}
+			w.metrics.IndexDBSize.Set(float64(w.db.DatabaseSizeOnDisk()))
+			msTime = time.Now().Add(10 * time.Minute)
+		}
+	}
+	select {
+	case <-writeBlockDone:
+	case <-p.stop:
+	case <-w.chanOsSignal:
+		glog.Info("sync: pipelined connect interrupted")
+		p.stopWith(ErrOperationInterrupted)
+	}
+	p.stopWith(nil)
+	<-writeBlockDone
+	wg.Wait()
+	p.updateMetrics()
+	return p.err
+}
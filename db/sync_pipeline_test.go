@@ -0,0 +1,102 @@
+//go:build unittest
+
+package db
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+)
+
+func Test_classifyThrottleError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil", err: nil, want: throttleNone},
+		{name: "deadline", err: errors.Annotatef(context.DeadlineExceeded, "hash %v", "0x1"), want: throttleTimeout},
+		{name: "http 429", err: errors.New("429 Too Many Requests: {\"code\":-32005}"), want: throttleRateLimit},
+		{name: "rpc limit", err: errors.New("daily request count exceeded, request rate limited"), want: throttleRateLimit},
+		{name: "trace timeout", err: errors.New("execution timeout"), want: throttleTimeout},
+		{name: "other", err: errors.New("header not found"), want: throttleNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyThrottleError(tt.err); got != tt.want {
+				t.Errorf("classifyThrottleError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_adaptiveLimiter(t *testing.T) {
+	l := newAdaptiveLimiter(1, 4, 2)
+	stop := make(chan struct{})
+	if !l.acquire(stop) || !l.acquire(stop) {
+		t.Fatal("acquire failed")
+	}
+	if limit, inflight := l.state(); limit != 2 || inflight != 2 {
+		t.Fatalf("state = %d/%d, want 2/2", limit, inflight)
+	}
+	// the limit is reached, acquire must block until stopped
+	close(stop)
+	if l.acquire(stop) {
+		t.Fatal("acquire over limit succeeded")
+	}
+	// additive increase after limit successful requests
+	l.release(false)
+	l.release(false)
+	if limit, inflight := l.state(); limit != 3 || inflight != 0 {
+		t.Fatalf("state = %d/%d, want 3/0", limit, inflight)
+	}
+	// multiplicative decrease on throttling, bounded by min
+	stop = make(chan struct{})
+	l.acquire(stop)
+	l.release(true)
+	if limit, _ := l.state(); limit != 1 {
+		t.Fatalf("limit = %d, want 1", limit)
+	}
+	l.acquire(stop)
+	l.release(true)
+	if limit, _ := l.state(); limit != 1 {
+		t.Fatalf("limit = %d, want 1", limit)
+	}
+	// the limit does not grow over max
+	for i := 0; i < 100; i++ {
+		l.acquire(stop)
+		l.release(false)
+	}
+	if limit, _ := l.state(); limit != 4 {
+		t.Fatalf("limit = %d, want 4", limit)
+	}
+}
+
+func Test_blockReorderer(t *testing.T) {
+	const lower, higher = 1000, 1999
+	heights := make([]uint32, 0, higher-lower+1)
+	for h := uint32(lower); h <= higher; h++ {
+		heights = append(heights, h)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Shuffle(len(heights), func(i, j int) { heights[i], heights[j] = heights[j], heights[i] })
+	r := newBlockReorderer(lower)
+	next := uint32(lower)
+	for i, h := range heights {
+		for _, b := range r.push(&bchain.Block{BlockHeader: bchain.BlockHeader{Height: h}}) {
+			if b.Height != next {
+				t.Fatalf("connected block %d, want %d", b.Height, next)
+			}
+			next++
+		}
+		if got, want := r.queued(), i+1-int(next-lower); got != want {
+			t.Fatalf("queued = %d, want %d", got, want)
+		}
+	}
+	if next != higher+1 || r.next != higher+1 || r.queued() != 0 {
+		t.Fatalf("connected up to %d, next %d, queued %d", next, r.next, r.queued())
+	}
+}
@@ -26,3 +26,18 @@ func (w *SyncWorker) ConnectBlocksParallel(lower, higher uint32) error {
 	}
 	return w.ParallelConnectBlocks(nil, lower, higher, uint32(workers))
 }
+
+// SetBulkConnectCheckpointBlocks overrides the number of blocks between bulk connect checkpoints
+func SetBulkConnectCheckpointBlocks(b *BulkConnect, blocks int) {
+	b.checkpointBlocks = blocks
+}
+
+// SimulateCrash closes the db without storing the caches and the internal state
+func SimulateCrash(d *RocksDB) error {
+	if err := d.closeDB(); err != nil {
+		return err
+	}
+	d.wo.Destroy()
+	d.ro.Destroy()
+	return nil
+}
@@ -0,0 +1,254 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+)
+
+// webhooks are stored in the default column as JSON values
+//   - the registrations under the key Webhooks
+//   - the queued deliveries under the prefix WebhookQueue: + 8 bytes of the time of the next attempt + id,
+//     therefore the iteration over the prefix returns the deliveries in the order of their due time
+//   - the transactions tracked for the confirmation and reorg events under the prefix WebhookTx: + webhook id + : + txid
+//   - the last processed block and the hashes of the recent blocks under the key WebhookBlocks
+const (
+	webhooksKey           = "Webhooks"
+	webhookBlocksKey      = "WebhookBlocks"
+	webhookQueuePrefix    = "WebhookQueue:"
+	webhookTxPrefix       = "WebhookTx:"
+	webhookKeySeparator   = ':'
+	webhookQueueTimestamp = 8
+)
+
+// Webhook event types
+const (
+	WebhookEventMempool      = "mempool"
+	WebhookEventConfirmation = "confirmation"
+	WebhookEventReorg        = "reorg"
+	WebhookEventBlock        = "block"
+)
+
+// Webhook is a registration of an URL, to which the events of the watched addresses and blocks are posted
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Secret is the key of the HMAC-SHA256 signature of the payloads
+	Secret    string   `json:"secret"`
+	Addresses []string `json:"addresses,omitempty"`
+	Xpubs     []string `json:"xpubs,omitempty"`
+	// Events are the requested event types, see WebhookEvent* constants
+	Events []string `json:"events"`
+	// Confirmations is the number of confirmations up to which the confirmation events are sent
+	Confirmations uint32 `json:"confirmations,omitempty"`
+	Disabled      bool   `json:"disabled,omitempty"`
+}
+
+// WebhookBlocks is the last block processed by the webhooks, from which the processing continues after the restart,
+// and the hashes of the recent processed blocks by height, which are used to find the fork point after a reorg
+type WebhookBlocks struct {
+	Height uint32            `json:"height"`
+	Hashes map[uint32]string `json:"hashes"`
+}
+
+// WebhookDelivery is a payload queued for the delivery to a webhook
+type WebhookDelivery struct {
+	ID          string          `json:"id"`
+	WebhookID   string          `json:"webhookId"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	Created     int64           `json:"created"`
+	NextAttempt int64           `json:"nextAttempt"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"lastError,omitempty"`
+}
+
+// WebhookTx is a transaction of a watched address tracked until it reaches the requested number of confirmations
+type WebhookTx struct {
+	WebhookID string `json:"webhookId"`
+	Txid      string `json:"txid"`
+	Address   string `json:"address"`
+	// Height and BlockHash are zero/empty while the tx is not confirmed
+	Height    uint32 `json:"height,omitempty"`
+	BlockHash string `json:"blockHash,omitempty"`
+	// Confirmations is the last number of confirmations sent in the confirmation event
+	Confirmations uint32 `json:"confirmations,omitempty"`
+	FirstSeen     int64  `json:"firstSeen"`
+}
+
+// GetWebhooks returns the stored webhooks
+func (d *RocksDB) GetWebhooks() ([]Webhook, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(webhooksKey))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	data := val.Data()
+	if data == nil {
+		return nil, nil
+	}
+	var webhooks []Webhook
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// StoreWebhooks replaces the stored webhooks
+func (d *RocksDB) StoreWebhooks(webhooks []Webhook) error {
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		return err
+	}
+	return d.db.PutCF(d.wo, d.cfh[cfDefault], []byte(webhooksKey), data)
+}
+
+// GetWebhookBlocks returns the last block processed by the webhooks or nil if no block was processed
+func (d *RocksDB) GetWebhookBlocks() (*WebhookBlocks, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(webhookBlocksKey))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	data := val.Data()
+	if data == nil {
+		return nil, nil
+	}
+	var blocks WebhookBlocks
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, err
+	}
+	return &blocks, nil
+}
+
+// StoreWebhookBlocks stores the last block processed by the webhooks
+func (d *RocksDB) StoreWebhookBlocks(blocks *WebhookBlocks) error {
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		return err
+	}
+	return d.db.PutCF(d.wo, d.cfh[cfDefault], []byte(webhookBlocksKey), data)
+}
+
+func packWebhookQueueKey(nextAttempt int64, id string) []byte {
+	key := make([]byte, 0, len(webhookQueuePrefix)+webhookQueueTimestamp+len(id))
+	key = append(key, webhookQueuePrefix...)
+	key = binary.BigEndian.AppendUint64(key, uint64(nextAttempt))
+	return append(key, id...)
+}
+
+func packWebhookTxKey(webhookID, txid string) []byte {
+	key := make([]byte, 0, len(webhookTxPrefix)+len(webhookID)+1+len(txid))
+	key = append(key, webhookTxPrefix...)
+	key = append(key, webhookID...)
+	key = append(key, webhookKeySeparator)
+	return append(key, txid...)
+}
+
+// StoreWebhookDelivery stores the delivery to the queue under its NextAttempt time
+func (d *RocksDB) StoreWebhookDelivery(delivery *WebhookDelivery) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	return d.db.PutCF(d.wo, d.cfh[cfDefault], packWebhookQueueKey(delivery.NextAttempt, delivery.ID), data)
+}
+
+// DeleteWebhookDelivery removes the delivery from the queue
+func (d *RocksDB) DeleteWebhookDelivery(delivery *WebhookDelivery) error {
+	return d.db.DeleteCF(d.wo, d.cfh[cfDefault], packWebhookQueueKey(delivery.NextAttempt, delivery.ID))
+}
+
+// RescheduleWebhookDelivery moves the delivery in the queue to the nextAttempt time
+func (d *RocksDB) RescheduleWebhookDelivery(delivery *WebhookDelivery, nextAttempt int64) error {
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	wb.DeleteCF(d.cfh[cfDefault], packWebhookQueueKey(delivery.NextAttempt, delivery.ID))
+	delivery.NextAttempt = nextAttempt
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	wb.PutCF(d.cfh[cfDefault], packWebhookQueueKey(delivery.NextAttempt, delivery.ID), data)
+	return d.db.Write(d.wo, wb)
+}
+
+// GetWebhookDeliveries returns at most limit deliveries with the NextAttempt time not after until,
+// in the order of their NextAttempt time
+func (d *RocksDB) GetWebhookDeliveries(until int64, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	stopKey := packWebhookQueueKey(until+1, "")
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfDefault])
+	defer it.Close()
+	for it.Seek([]byte(webhookQueuePrefix)); it.Valid() && len(deliveries) < limit; it.Next() {
+		key := it.Key().Data()
+		if bytes.Compare(key, stopKey) >= 0 {
+			break
+		}
+		var delivery WebhookDelivery
+		if err := json.Unmarshal(it.Value().Data(), &delivery); err != nil {
+			return nil, errors.Annotatef(err, "webhook delivery %q", key)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// StoreWebhookTx stores the tracked transaction
+func (d *RocksDB) StoreWebhookTx(tx *WebhookTx) error {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	return d.db.PutCF(d.wo, d.cfh[cfDefault], packWebhookTxKey(tx.WebhookID, tx.Txid), data)
+}
+
+// GetWebhookTx returns the tracked transaction or nil if it is not tracked
+func (d *RocksDB) GetWebhookTx(webhookID, txid string) (*WebhookTx, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], packWebhookTxKey(webhookID, txid))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	data := val.Data()
+	if data == nil {
+		return nil, nil
+	}
+	var tx WebhookTx
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// DeleteWebhookTx stops the tracking of the transaction
+func (d *RocksDB) DeleteWebhookTx(webhookID, txid string) error {
+	return d.db.DeleteCF(d.wo, d.cfh[cfDefault], packWebhookTxKey(webhookID, txid))
+}
+
+// GetWebhookTxs returns the transactions tracked for the webhook, for all webhooks if webhookID is empty
+func (d *RocksDB) GetWebhookTxs(webhookID string) ([]WebhookTx, error) {
+	prefix := []byte(webhookTxPrefix)
+	if webhookID != "" {
+		prefix = append(prefix, webhookID...)
+		prefix = append(prefix, webhookKeySeparator)
+	}
+	var txs []WebhookTx
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfDefault])
+	defer it.Close()
+	for it.Seek(prefix); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		var tx WebhookTx
+		if err := json.Unmarshal(it.Value().Data(), &tx); err != nil {
+			return nil, errors.Annotatef(err, "webhook tx %q", key)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
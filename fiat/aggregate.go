@@ -0,0 +1,254 @@
+package fiat
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+// DefaultMaxDeviation is the default maximum relative deviation of a rate from the median of the rates of all providers
+const DefaultMaxDeviation = 0.1
+
+// AggregateDownloader combines the rates of several providers, it implements RatesDownloaderInterface
+// the current and high granularity rates are aggregated as median of the rates of the providers with rejected outliers
+// the historical rates of the providers are merged per timestamp before they are stored to the db,
+// a rate provided by more providers is aggregated in the same way as the current rates, the rates stored in the db are not overwritten
+type AggregateDownloader struct {
+	db           *db.RocksDB
+	names        []string
+	downloaders  []RatesDownloaderInterface
+	maxDeviation float64
+}
+
+// NewAggregateDownloader creates a downloader aggregating the rates of the downloaders
+func NewAggregateDownloader(d *db.RocksDB, names []string, downloaders []RatesDownloaderInterface, maxDeviation float64) RatesDownloaderInterface {
+	if maxDeviation <= 0 {
+		maxDeviation = DefaultMaxDeviation
+	}
+	glog.Infof("Aggregating fiat rates of providers %v, max deviation %v", names, maxDeviation)
+	return &AggregateDownloader{
+		db:           d,
+		names:        names,
+		downloaders:  downloaders,
+		maxDeviation: maxDeviation,
+	}
+}
+
+// medianWithoutOutliers returns the median of the values after removal of the values deviating from the median of all values
+// by more than maxDeviation (relative), false is returned if no value remains
+func medianWithoutOutliers(values []float32, maxDeviation float64) (float32, bool) {
+	median := func(v []float64) float64 {
+		sort.Float64s(v)
+		l := len(v)
+		if l%2 == 1 {
+			return v[l/2]
+		}
+		return (v[l/2-1] + v[l/2]) / 2
+	}
+	all := make([]float64, 0, len(values))
+	for _, v := range values {
+		if v > 0 && !math.IsInf(float64(v), 0) && !math.IsNaN(float64(v)) {
+			all = append(all, float64(v))
+		}
+	}
+	if len(all) == 0 {
+		return 0, false
+	}
+	m := median(all)
+	kept := make([]float64, 0, len(all))
+	for _, v := range all {
+		if math.Abs(v-m) <= m*maxDeviation {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == 0 {
+		return 0, false
+	}
+	return float32(median(kept)), true
+}
+
+// aggregateRates aggregates the rates of the same currency (or token) in several maps
+func aggregateRates(rates []map[string]float32, maxDeviation float64, logPrefix string) map[string]float32 {
+	values := make(map[string][]float32)
+	for _, r := range rates {
+		for currency, v := range r {
+			values[currency] = append(values[currency], v)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	aggregated := make(map[string]float32, len(values))
+	for currency, v := range values {
+		if m, ok := medianWithoutOutliers(v, maxDeviation); ok {
+			aggregated[currency] = m
+		} else {
+			glog.Warningf("%s: rates %v of %s rejected, the providers do not agree", logPrefix, v, currency)
+		}
+	}
+	return aggregated
+}
+
+// aggregateTickers aggregates the tickers of the providers to one ticker with the given timestamp
+func aggregateTickers(tickers []*common.CurrencyRatesTicker, timestamp time.Time, maxDeviation float64) *common.CurrencyRatesTicker {
+	rates := make([]map[string]float32, 0, len(tickers))
+	tokenRates := make([]map[string]float32, 0, len(tickers))
+	for _, t := range tickers {
+		if t != nil {
+			rates = append(rates, t.Rates)
+			if len(t.TokenRates) > 0 {
+				tokenRates = append(tokenRates, t.TokenRates)
+			}
+		}
+	}
+	if len(rates) == 0 {
+		return nil
+	}
+	return &common.CurrencyRatesTicker{
+		Timestamp:  timestamp,
+		Rates:      aggregateRates(rates, maxDeviation, "AggregateDownloader"),
+		TokenRates: aggregateRates(tokenRates, maxDeviation, "AggregateDownloader token"),
+	}
+}
+
+// CurrentTickers returns the current rates aggregated from all providers
+func (a *AggregateDownloader) CurrentTickers() (*common.CurrencyRatesTicker, error) {
+	tickers := make([]*common.CurrencyRatesTicker, 0, len(a.downloaders))
+	var lastErr error
+	for i, d := range a.downloaders {
+		t, err := d.CurrentTickers()
+		if err != nil {
+			glog.Errorf("AggregateDownloader: %s CurrentTickers error %v", a.names[i], err)
+			lastErr = err
+			continue
+		}
+		tickers = append(tickers, t)
+	}
+	ticker := aggregateTickers(tickers, time.Now().UTC(), a.maxDeviation)
+	if ticker == nil {
+		return nil, lastErr
+	}
+	return ticker, nil
+}
+
+// aggregateHighGranularityTickers aggregates the tickers of the providers with the same timestamp
+func (a *AggregateDownloader) aggregateHighGranularityTickers(get func(d RatesDownloaderInterface) (*[]common.CurrencyRatesTicker, error), granularity int64) (*[]common.CurrencyRatesTicker, error) {
+	byTime := make(map[int64][]*common.CurrencyRatesTicker)
+	var lastErr error
+	for i, d := range a.downloaders {
+		t, err := get(d)
+		if err != nil {
+			glog.Errorf("AggregateDownloader: %s high granularity tickers error %v", a.names[i], err)
+			lastErr = err
+			continue
+		}
+		if t == nil {
+			continue
+		}
+		for j := range *t {
+			ticker := &(*t)[j]
+			ts := roundTimeUnix(ticker.Timestamp, granularity)
+			byTime[ts] = append(byTime[ts], ticker)
+		}
+	}
+	if len(byTime) == 0 {
+		return nil, lastErr
+	}
+	timestamps := make([]int64, 0, len(byTime))
+	for ts := range byTime {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	tickers := make([]common.CurrencyRatesTicker, 0, len(timestamps))
+	for _, ts := range timestamps {
+		if t := aggregateTickers(byTime[ts], time.Unix(ts, 0).UTC(), a.maxDeviation); t != nil {
+			tickers = append(tickers, *t)
+		}
+	}
+	return &tickers, nil
+}
+
+// HourlyTickers returns the hourly rates aggregated from all providers
+func (a *AggregateDownloader) HourlyTickers() (*[]common.CurrencyRatesTicker, error) {
+	return a.aggregateHighGranularityTickers(func(d RatesDownloaderInterface) (*[]common.CurrencyRatesTicker, error) {
+		return d.HourlyTickers()
+	}, secondsInHour)
+}
+
+// FiveMinutesTickers returns the five minutes rates aggregated from all providers
+func (a *AggregateDownloader) FiveMinutesTickers() (*[]common.CurrencyRatesTicker, error) {
+	return a.aggregateHighGranularityTickers(func(d RatesDownloaderInterface) (*[]common.CurrencyRatesTicker, error) {
+		return d.FiveMinutesTickers()
+	}, secondsInFiveMinutes)
+}
+
+// mergeHistoricalTickers groups the historical tickers of the providers by timestamp and aggregates each group to one ticker
+func (a *AggregateDownloader) mergeHistoricalTickers(tickers [][]common.CurrencyRatesTicker) []common.CurrencyRatesTicker {
+	byTime := make(map[int64][]*common.CurrencyRatesTicker)
+	for i := range tickers {
+		for j := range tickers[i] {
+			t := &tickers[i][j]
+			ts := t.Timestamp.Unix()
+			byTime[ts] = append(byTime[ts], t)
+		}
+	}
+	timestamps := make([]int64, 0, len(byTime))
+	for ts := range byTime {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	merged := make([]common.CurrencyRatesTicker, 0, len(timestamps))
+	for _, ts := range timestamps {
+		if t := aggregateTickers(byTime[ts], time.Unix(ts, 0).UTC(), a.maxDeviation); t != nil {
+			merged = append(merged, *t)
+		}
+	}
+	return merged
+}
+
+// UpdateHistoricalTickers downloads historical tickers by all providers, merges them per timestamp and stores them
+// the providers, which cannot return the tickers, store them to the db themselves in the configured order
+func (a *AggregateDownloader) UpdateHistoricalTickers() error {
+	var lastErr error
+	tickers := make([][]common.CurrencyRatesTicker, 0, len(a.downloaders))
+	for i, d := range a.downloaders {
+		var err error
+		if g, ok := d.(historicalTickersGetter); ok {
+			var t []common.CurrencyRatesTicker
+			if t, err = g.historicalTickers(); err == nil && len(t) > 0 {
+				tickers = append(tickers, t)
+			}
+		} else {
+			err = d.UpdateHistoricalTickers()
+		}
+		if err != nil {
+			glog.Errorf("AggregateDownloader: %s UpdateHistoricalTickers error %v", a.names[i], err)
+			lastErr = err
+		}
+	}
+	if len(tickers) > 0 {
+		merged := a.mergeHistoricalTickers(tickers)
+		updated, err := StoreHistoricalTickers(a.db, merged, false)
+		if err != nil {
+			return err
+		}
+		glog.Infof("AggregateDownloader: merged %d historical tickers, updated %d", len(merged), updated)
+	}
+	return lastErr
+}
+
+// UpdateHistoricalTokenTickers updates historical token tickers by all providers in the configured order
+func (a *AggregateDownloader) UpdateHistoricalTokenTickers() error {
+	var lastErr error
+	for i, d := range a.downloaders {
+		if err := d.UpdateHistoricalTokenTickers(); err != nil {
+			glog.Errorf("AggregateDownloader: %s UpdateHistoricalTokenTickers error %v", a.names[i], err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
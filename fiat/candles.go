@@ -0,0 +1,259 @@
+package fiat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trezor/blockbook/common"
+)
+
+// Intervals of the candles
+const (
+	CandleInterval5m = "5m"
+	CandleInterval1h = "1h"
+	CandleInterval1d = "1d"
+)
+
+// MaxCandles is the maximum number of candles returned by one call of GetCandles
+const MaxCandles = 1000
+
+const maxCandlesCacheEntries = 10000
+
+// ErrTooManyCandles is returned if the requested time range contains more than MaxCandles intervals
+var ErrTooManyCandles = fmt.Errorf("too many candles, maximum is %d", MaxCandles)
+
+// Candle contains open, high, low and close rate of one interval starting at Timestamp
+type Candle struct {
+	Timestamp int64
+	Open      float32
+	High      float32
+	Low       float32
+	Close     float32
+}
+
+type rateSample struct {
+	ts   int64
+	rate float32
+}
+
+type candlesCacheEntry struct {
+	candles []Candle
+	expires time.Time
+}
+
+// candlesCache caches the computed candles, the entries of recent time ranges expire sooner than the historical ones
+type candlesCache struct {
+	mux     sync.Mutex
+	entries map[string]*candlesCacheEntry
+}
+
+func (c *candlesCache) get(key string) ([]Candle, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	e, found := c.entries[key]
+	if !found || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.candles, true
+}
+
+func (c *candlesCache) set(key string, candles []Candle, ttl time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	now := time.Now()
+	if c.entries == nil {
+		c.entries = make(map[string]*candlesCacheEntry)
+	} else if len(c.entries) >= maxCandlesCacheEntries {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCandlesCacheEntries {
+			c.entries = make(map[string]*candlesCacheEntry)
+		}
+	}
+	c.entries[key] = &candlesCacheEntry{candles: candles, expires: now.Add(ttl)}
+}
+
+// CandleIntervalSeconds returns the length of the candle interval in seconds
+func CandleIntervalSeconds(interval string) (int64, error) {
+	switch interval {
+	case CandleInterval5m:
+		return secondsInFiveMinutes, nil
+	case CandleInterval1h:
+		return secondsInHour, nil
+	case CandleInterval1d:
+		return secondsInDay, nil
+	}
+	return 0, fmt.Errorf("invalid interval %q, expected %s, %s or %s", interval, CandleInterval5m, CandleInterval1h, CandleInterval1d)
+}
+
+// tickerRate returns the rate of the base currency or of the token in vsCurrency, 0 if not available
+func tickerRate(t *common.CurrencyRatesTicker, vsCurrency string, token string) float32 {
+	if t == nil {
+		return 0
+	}
+	if token != "" {
+		return t.TokenRateInCurrency(token, vsCurrency)
+	}
+	return t.Rates[vsCurrency]
+}
+
+// appendSamples appends the rates of the tickers from the map in the range [from, to) sorted by time
+// the map entries filling the gaps in the tickers (pointing to a ticker of another period) are skipped
+func appendSamples(samples []rateSample, tickers map[int64]*common.CurrencyRatesTicker, granularity int64, from, to int64, vsCurrency string, token string) []rateSample {
+	start := len(samples)
+	for ts, t := range tickers {
+		if ts >= from && ts < to && t != nil && roundTimeUnix(t.Timestamp, granularity) == ts {
+			if rate := tickerRate(t, vsCurrency, token); rate > 0 {
+				samples = append(samples, rateSample{ts: ts, rate: rate})
+			}
+		}
+	}
+	s := samples[start:]
+	sort.Slice(s, func(i, j int) bool { return s[i].ts < s[j].ts })
+	return samples
+}
+
+// rateSamples returns the rate samples in the range [from, to) and the last sample before from (if any), sorted by time
+// the samples of the highest available granularity are used - five minutes, hourly and for the rest daily tickers
+func (fr *FiatRates) rateSamples(from, to int64, vsCurrency string, token string) ([]rateSample, error) {
+	// look back for the sample preceding the range, the daily tickers can be missing for some days
+	lookBack := from - 7*secondsInDay
+	fr.mux.RLock()
+	dailyTo, hourlyFrom, fiveMinutesFrom := to, to, to
+	if len(fr.hourlyTickers) > 0 {
+		dailyTo = fr.hourlyTickersFrom
+		hourlyFrom = fr.hourlyTickersFrom
+	}
+	if len(fr.fiveMinutesTickers) > 0 {
+		fiveMinutesFrom = fr.fiveMinutesTickersFrom
+		if len(fr.hourlyTickers) == 0 {
+			dailyTo = fiveMinutesFrom
+		}
+	}
+	if dailyTo > to {
+		dailyTo = to
+	}
+	if fiveMinutesFrom < lookBack {
+		fiveMinutesFrom = lookBack
+	}
+	if hourlyFrom < lookBack {
+		hourlyFrom = lookBack
+	}
+	samples := make([]rateSample, 0)
+	// the token rates are not kept in the daily tickers in memory, they must be read from the db
+	if token == "" {
+		samples = appendSamples(samples, fr.dailyTickers, secondsInDay, lookBack, dailyTo, vsCurrency, token)
+	}
+	samples = appendSamples(samples, fr.hourlyTickers, secondsInHour, hourlyFrom, fiveMinutesFrom, vsCurrency, token)
+	samples = appendSamples(samples, fr.fiveMinutesTickers, secondsInFiveMinutes, fiveMinutesFrom, to, vsCurrency, token)
+	fr.mux.RUnlock()
+	if token != "" && lookBack < dailyTo {
+		var daily []rateSample
+		f := time.Unix(lookBack, 0)
+		t := time.Unix(dailyTo, 0)
+		err := fr.db.FiatRatesGetTickersInRange(&f, &t, func(ticker *common.CurrencyRatesTicker) error {
+			if rate := tickerRate(ticker, vsCurrency, token); rate > 0 {
+				daily = append(daily, rateSample{ts: ticker.Timestamp.Unix(), rate: rate})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		samples = append(daily, samples...)
+	}
+	// keep only the last sample before the range
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].ts >= from })
+	if i > 1 {
+		samples = samples[i-1:]
+	}
+	return samples, nil
+}
+
+// computeCandles computes the candles of the given interval in the range [from, to) from the samples sorted by time
+// the open rate of a candle is the close rate of the previous sample, the intervals without samples are skipped
+func computeCandles(samples []rateSample, from, to, interval int64) []Candle {
+	candles := make([]Candle, 0)
+	var prev *rateSample
+	i := 0
+	for ; i < len(samples) && samples[i].ts < from; i++ {
+		prev = &samples[i]
+	}
+	for start := from; start < to; start += interval {
+		end := start + interval
+		if i >= len(samples) || samples[i].ts >= end {
+			continue
+		}
+		var c Candle
+		c.Timestamp = start
+		if prev != nil {
+			c.Open = prev.rate
+		} else {
+			c.Open = samples[i].rate
+		}
+		c.High, c.Low = c.Open, c.Open
+		for ; i < len(samples) && samples[i].ts < end; i++ {
+			r := samples[i].rate
+			if r > c.High {
+				c.High = r
+			}
+			if r < c.Low {
+				c.Low = r
+			}
+			c.Close = r
+			prev = &samples[i]
+		}
+		candles = append(candles, c)
+	}
+	return candles
+}
+
+// GetCandles returns open, high, low and close rates of the base currency or of the token in vsCurrency
+// for the intervals in the time range [from, to), computed from the stored tickers
+func (fr *FiatRates) GetCandles(vsCurrency string, token string, interval string, from, to int64) ([]Candle, error) {
+	if !fr.Enabled {
+		return nil, errors.New("fiat rates are not enabled")
+	}
+	seconds, err := CandleIntervalSeconds(interval)
+	if err != nil {
+		return nil, err
+	}
+	vsCurrency = strings.ToLower(vsCurrency)
+	token = strings.ToLower(token)
+	if vsCurrency == "" {
+		return nil, errors.New("missing currency")
+	}
+	from -= from % seconds
+	if to%seconds != 0 {
+		to += seconds - to%seconds
+	}
+	if to <= from {
+		return nil, errors.New("invalid time range")
+	}
+	if (to-from)/seconds > MaxCandles {
+		return nil, ErrTooManyCandles
+	}
+	key := fmt.Sprintf("%s:%s:%d:%d:%d", vsCurrency, token, seconds, from, to)
+	if candles, found := fr.candles.get(key); found {
+		return candles, nil
+	}
+	samples, err := fr.rateSamples(from, to, vsCurrency, token)
+	if err != nil {
+		return nil, err
+	}
+	candles := computeCandles(samples, from, to, seconds)
+	// the recent candles change with the new tickers, the historical ones only if the rates are imported or updated
+	ttl := time.Hour
+	if to > time.Now().Unix()-secondsInDay {
+		ttl = time.Duration(fr.periodSeconds) * time.Second
+	}
+	fr.candles.set(key, candles, ttl)
+	return candles, nil
+}
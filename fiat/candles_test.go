@@ -0,0 +1,95 @@
+//go:build unittest
+
+package fiat
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/trezor/blockbook/common"
+)
+
+func Test_computeCandles(t *testing.T) {
+	samples := []rateSample{
+		{ts: 50, rate: 5},
+		{ts: 100, rate: 10},
+		{ts: 110, rate: 12},
+		{ts: 150, rate: 8},
+		{ts: 320, rate: 9},
+	}
+	got := computeCandles(samples, 100, 400, 100)
+	want := []Candle{
+		{Timestamp: 100, Open: 5, High: 12, Low: 5, Close: 8},
+		{Timestamp: 300, Open: 8, High: 9, Low: 8, Close: 9},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeCandles() = %+v, want %+v", got, want)
+	}
+	// without the preceding sample the open rate is the first rate in the interval
+	got = computeCandles(samples[1:], 100, 200, 100)
+	want = []Candle{{Timestamp: 100, Open: 10, High: 12, Low: 8, Close: 8}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeCandles() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFiatRates_GetCandles(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	ticker := func(ts int64, usd float32) *common.CurrencyRatesTicker {
+		return &common.CurrencyRatesTicker{Timestamp: time.Unix(ts, 0).UTC(), Rates: map[string]float32{"usd": usd}}
+	}
+	d3 := ticker(day+3*secondsInDay, 130)
+	fr := &FiatRates{
+		Enabled: true,
+		dailyTickers: map[int64]*common.CurrencyRatesTicker{
+			day:                  ticker(day, 100),
+			day + secondsInDay:   ticker(day+secondsInDay, 110),
+			day + 2*secondsInDay: d3, // gap filled by the following ticker
+			day + 3*secondsInDay: d3,
+		},
+		dailyTickersFrom: day,
+		dailyTickersTo:   day + 3*secondsInDay,
+		hourlyTickers: map[int64]*common.CurrencyRatesTicker{
+			day + 3*secondsInDay + secondsInHour:   ticker(day+3*secondsInDay+secondsInHour, 131),
+			day + 3*secondsInDay + 2*secondsInHour: ticker(day+3*secondsInDay+2*secondsInHour, 129),
+		},
+		hourlyTickersFrom: day + 3*secondsInDay + secondsInHour,
+		hourlyTickersTo:   day + 3*secondsInDay + 2*secondsInHour,
+		fiveMinutesTickers: map[int64]*common.CurrencyRatesTicker{
+			day + 3*secondsInDay + 2*secondsInHour + 5*60:  ticker(day+3*secondsInDay+2*secondsInHour+5*60, 135),
+			day + 3*secondsInDay + 2*secondsInHour + 10*60: ticker(day+3*secondsInDay+2*secondsInHour+10*60, 125),
+		},
+		fiveMinutesTickersFrom: day + 3*secondsInDay + 2*secondsInHour + 5*60,
+		fiveMinutesTickersTo:   day + 3*secondsInDay + 2*secondsInHour + 10*60,
+	}
+	got, err := fr.GetCandles("USD", "", CandleInterval1d, day+secondsInDay+1, day+4*secondsInDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Candle{
+		{Timestamp: day + secondsInDay, Open: 100, High: 110, Low: 100, Close: 110},
+		{Timestamp: day + 3*secondsInDay, Open: 110, High: 135, Low: 110, Close: 125},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCandles(1d) = %+v, want %+v", got, want)
+	}
+	got, err = fr.GetCandles("usd", "", CandleInterval1h, day+3*secondsInDay, day+3*secondsInDay+3*secondsInHour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []Candle{
+		{Timestamp: day + 3*secondsInDay, Open: 110, High: 130, Low: 110, Close: 130},
+		{Timestamp: day + 3*secondsInDay + secondsInHour, Open: 130, High: 131, Low: 130, Close: 131},
+		{Timestamp: day + 3*secondsInDay + 2*secondsInHour, Open: 131, High: 135, Low: 125, Close: 125},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCandles(1h) = %+v, want %+v", got, want)
+	}
+	if _, err = fr.GetCandles("usd", "", CandleInterval5m, day, day+(MaxCandles+1)*secondsInFiveMinutes); err != ErrTooManyCandles {
+		t.Errorf("GetCandles() error = %v, want %v", err, ErrTooManyCandles)
+	}
+	if _, err = fr.GetCandles("usd", "", "1w", day, day+secondsInDay); err == nil {
+		t.Error("GetCandles(1w): expected error")
+	}
+}
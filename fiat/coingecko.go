@@ -382,7 +382,9 @@ func (cg *Coingecko) FiveMinutesTickers() (*[]common.CurrencyRatesTicker, error)
 	return cg.getHighGranularityTickers("1")
 }
 
-func (cg *Coingecko) getHistoricalTicker(tickersToUpdate map[uint]*common.CurrencyRatesTicker, coinId string, vsCurrency string, token string) (bool, error) {
+// getHistoricalTicker downloads the daily rates of the coin to tickersToUpdate
+// if fromDb is true, the new tickers are initialized by the tickers stored in the db so that the stored rates are kept
+func (cg *Coingecko) getHistoricalTicker(tickersToUpdate map[uint]*common.CurrencyRatesTicker, coinId string, vsCurrency string, token string, fromDb bool) (bool, error) {
 	lastTicker, err := cg.db.FiatRatesFindLastTicker(vsCurrency, token)
 	if err != nil {
 		return false, err
@@ -416,9 +418,11 @@ func (cg *Coingecko) getHistoricalTicker(tickersToUpdate map[uint]*common.Curren
 			var ticker *common.CurrencyRatesTicker
 			if ticker, found = tickersToUpdate[timestamp]; !found {
 				u := time.Unix(int64(timestamp), 0).UTC()
-				ticker, err = cg.db.FiatRatesGetTicker(&u)
-				if err != nil {
-					return false, err
+				if fromDb {
+					ticker, err = cg.db.FiatRatesGetTicker(&u)
+					if err != nil {
+						return false, err
+					}
 				}
 				if ticker == nil {
 					if token != "" { // if the base currency is not found in DB, do not create ticker for the token
@@ -473,14 +477,14 @@ func (cg *Coingecko) throttleHistoricalDownload() {
 	time.Sleep(cg.throttlingDelay * time.Duration(delay))
 }
 
-// UpdateHistoricalTickers gets historical tickers for the main crypto currency
-func (cg *Coingecko) UpdateHistoricalTickers() error {
+// downloadHistoricalTickers downloads the historical tickers of the main crypto currency in all vs currencies
+func (cg *Coingecko) downloadHistoricalTickers(fromDb bool) (map[uint]*common.CurrencyRatesTicker, error) {
 	tickersToUpdate := make(map[uint]*common.CurrencyRatesTicker)
 
 	// reload vs_currencies
 	vs, err := cg.simpleSupportedVSCurrencies()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	vsCurrencies = vs
 
@@ -488,7 +492,7 @@ func (cg *Coingecko) UpdateHistoricalTickers() error {
 		// get historical rates for each currency
 		var err error
 		var req bool
-		if req, err = cg.getHistoricalTicker(tickersToUpdate, cg.coin, currency, ""); err != nil {
+		if req, err = cg.getHistoricalTicker(tickersToUpdate, cg.coin, currency, "", fromDb); err != nil {
 			// report error and continue, Coingecko may return error like "Could not find coin with the given id"
 			// the rates will be updated next run
 			glog.Errorf("getHistoricalTicker %s-%s %v", cg.coin, currency, err)
@@ -497,10 +501,31 @@ func (cg *Coingecko) UpdateHistoricalTickers() error {
 			cg.throttleHistoricalDownload()
 		}
 	}
+	return tickersToUpdate, nil
+}
 
+// UpdateHistoricalTickers gets historical tickers for the main crypto currency
+func (cg *Coingecko) UpdateHistoricalTickers() error {
+	tickersToUpdate, err := cg.downloadHistoricalTickers(true)
+	if err != nil {
+		return err
+	}
 	return cg.storeTickers(tickersToUpdate)
 }
 
+// historicalTickers returns the downloaded historical tickers of the main crypto currency without storing them
+func (cg *Coingecko) historicalTickers() ([]common.CurrencyRatesTicker, error) {
+	tickersToUpdate, err := cg.downloadHistoricalTickers(false)
+	if err != nil {
+		return nil, err
+	}
+	tickers := make([]common.CurrencyRatesTicker, 0, len(tickersToUpdate))
+	for _, t := range tickersToUpdate {
+		tickers = append(tickers, *t)
+	}
+	return tickers, nil
+}
+
 // UpdateHistoricalTokenTickers gets historical tickers for the tokens
 func (cg *Coingecko) UpdateHistoricalTokenTickers() error {
 	if cg.updatingTokens {
@@ -521,7 +546,7 @@ func (cg *Coingecko) UpdateHistoricalTokenTickers() error {
 		for tokenId, token := range platformIdsToTokens {
 			var err error
 			var req bool
-			if req, err = cg.getHistoricalTicker(tickersToUpdate, tokenId, cg.platformVsCurrency, token); err != nil {
+			if req, err = cg.getHistoricalTicker(tickersToUpdate, tokenId, cg.platformVsCurrency, token, true); err != nil {
 				// report error and continue, Coingecko may return error like "Could not find coin with the given id"
 				// the rates will be updated next run
 				glog.Errorf("getHistoricalTicker %s-%s %v", tokenId, cg.platformVsCurrency, err)
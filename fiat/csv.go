@@ -0,0 +1,259 @@
+package fiat
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+// csvTokenColumnPrefix is the prefix of the CSV columns with token rates, the rest of the column name is the token contract
+const csvTokenColumnPrefix = "token:"
+
+// CsvDownloader imports historical daily rates from a static CSV file, it implements RatesDownloaderInterface
+// the imported rates do not overwrite rates already stored in the db, the file is imported again only if it is modified
+type CsvDownloader struct {
+	file     string
+	db       *db.RocksDB
+	imported time.Time
+}
+
+// NewCsvDownloader creates a CsvDownloader, the parameter file is the path to the CSV file
+func NewCsvDownloader(ctx *RatesDownloaderContext, params json.RawMessage) (RatesDownloaderInterface, error) {
+	var p struct {
+		File string `json:"file"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	if p.File == "" {
+		return nil, errors.New("missing file")
+	}
+	return &CsvDownloader{
+		file: p.File,
+		db:   ctx.DB,
+	}, nil
+}
+
+func parseCsvTimestamp(s string) (time.Time, error) {
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return t.UTC(), nil
+}
+
+// ReadTickersCSV reads tickers from CSV data
+// the first row is a header, the first column contains the time of the ticker (unix timestamp, date YYYY-MM-DD or RFC3339 time),
+// the other columns contain the rates of the vs currencies named in the header, columns named token:<contract> contain token rates
+// empty values are skipped
+func ReadTickersCSV(r io.Reader) ([]common.CurrencyRatesTicker, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(header) < 2 {
+		return nil, errors.New("CSV header must contain time and at least one currency")
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	var tickers []common.CurrencyRatesTicker
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseCsvTimestamp(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		ticker := common.CurrencyRatesTicker{
+			Timestamp: t,
+			Rates:     make(map[string]float32),
+		}
+		for i := 1; i < len(record) && i < len(header); i++ {
+			v := strings.TrimSpace(record[i])
+			if v == "" {
+				continue
+			}
+			rate, err := strconv.ParseFloat(v, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d, column %s: %v", line, header[i], err)
+			}
+			if strings.HasPrefix(header[i], csvTokenColumnPrefix) {
+				if ticker.TokenRates == nil {
+					ticker.TokenRates = make(map[string]float32)
+				}
+				ticker.TokenRates[header[i][len(csvTokenColumnPrefix):]] = float32(rate)
+			} else {
+				ticker.Rates[header[i]] = float32(rate)
+			}
+		}
+		tickers = append(tickers, ticker)
+	}
+	return tickers, nil
+}
+
+// mergeRates sets the rates from the map from to the map to, which is created if necessary, and returns true if a rate was changed
+// if overwrite is false, only the missing rates are set
+func mergeRates(to map[string]float32, from map[string]float32, overwrite bool) (map[string]float32, bool) {
+	changed := false
+	for k, v := range from {
+		if to == nil {
+			to = make(map[string]float32)
+		}
+		if stored, found := to[k]; !found || overwrite && stored != v {
+			to[k] = v
+			changed = true
+		}
+	}
+	return to, changed
+}
+
+// StoreHistoricalTickers merges the tickers to the tickers stored in the db and returns the number of updated tickers
+// if overwrite is false, only the rates missing in the db are set
+// tickers without the rates of the base currency are not created, as there are no token rates without base currency rates
+func StoreHistoricalTickers(d *db.RocksDB, tickers []common.CurrencyRatesTicker, overwrite bool) (int, error) {
+	// merge the tickers with the same timestamp first, so that they are written only once
+	updated := make(map[int64]*common.CurrencyRatesTicker)
+	var order []int64
+	skipped := 0
+	for i := range tickers {
+		t := &tickers[i]
+		unix := t.Timestamp.Unix()
+		stored, found := updated[unix]
+		if !found {
+			var err error
+			if stored, err = d.FiatRatesGetTicker(&t.Timestamp); err != nil {
+				return 0, err
+			}
+			if stored == nil {
+				stored = &common.CurrencyRatesTicker{Timestamp: t.Timestamp.UTC()}
+			}
+		}
+		var changedRates, changedTokens bool
+		stored.Rates, changedRates = mergeRates(stored.Rates, t.Rates, overwrite)
+		stored.TokenRates, changedTokens = mergeRates(stored.TokenRates, t.TokenRates, overwrite)
+		if !found && (changedRates || changedTokens) {
+			if len(stored.Rates) == 0 {
+				skipped++
+				continue
+			}
+			updated[unix] = stored
+			order = append(order, unix)
+		}
+	}
+	if skipped > 0 {
+		glog.Warningf("StoreHistoricalTickers: skipped %d tickers without base currency rates", skipped)
+	}
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for _, unix := range order {
+		if err := d.FiatRatesStoreTicker(wb, updated[unix]); err != nil {
+			return 0, err
+		}
+		if wb.Count() >= 1000 {
+			if err := d.WriteBatch(wb); err != nil {
+				return 0, err
+			}
+			wb.Clear()
+		}
+	}
+	if err := d.WriteBatch(wb); err != nil {
+		return 0, err
+	}
+	return len(order), nil
+}
+
+// CurrentTickers is not supported by CsvDownloader
+func (cd *CsvDownloader) CurrentTickers() (*common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// HourlyTickers is not supported by CsvDownloader
+func (cd *CsvDownloader) HourlyTickers() (*[]common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// FiveMinutesTickers is not supported by CsvDownloader
+func (cd *CsvDownloader) FiveMinutesTickers() (*[]common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// readTickers reads the daily rates from the CSV file, zero time is returned if the file was not modified since the last import
+func (cd *CsvDownloader) readTickers() ([]common.CurrencyRatesTicker, time.Time, error) {
+	fi, err := os.Stat(cd.file)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if !fi.ModTime().After(cd.imported) {
+		return nil, time.Time{}, nil
+	}
+	f, err := os.Open(cd.file)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+	tickers, err := ReadTickersCSV(f)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %v", cd.file, err)
+	}
+	// the historical rates are daily, aligned to the UTC midnight
+	for i := range tickers {
+		tickers[i].Timestamp = tickers[i].Timestamp.Truncate(secondsInDay * time.Second)
+	}
+	return tickers, fi.ModTime(), nil
+}
+
+// UpdateHistoricalTickers imports the daily rates from the CSV file if it was modified since the last import
+func (cd *CsvDownloader) UpdateHistoricalTickers() error {
+	tickers, modTime, err := cd.readTickers()
+	if err != nil || modTime.IsZero() {
+		return err
+	}
+	updated, err := StoreHistoricalTickers(cd.db, tickers, false)
+	if err != nil {
+		return err
+	}
+	cd.imported = modTime
+	glog.Infof("CsvDownloader: imported %s, %d rows, updated %d tickers", cd.file, len(tickers), updated)
+	return nil
+}
+
+// historicalTickers returns the daily rates from the CSV file if it was modified since the last import
+func (cd *CsvDownloader) historicalTickers() ([]common.CurrencyRatesTicker, error) {
+	tickers, modTime, err := cd.readTickers()
+	if err != nil || modTime.IsZero() {
+		return nil, err
+	}
+	cd.imported = modTime
+	return tickers, nil
+}
+
+// UpdateHistoricalTokenTickers does nothing, the token rates are imported together with the other rates
+func (cd *CsvDownloader) UpdateHistoricalTokenTickers() error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package fiat
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/common"
+)
+
+// DerivedDownloader derives the rates of the coin from the rates of another asset downloaded by a source provider,
+// multiplied by a constant factor (for example a wrapped or pegged asset priced 1:1 against BTC), it implements RatesDownloaderInterface
+// the historical rates are stored to the db by the source provider, therefore they are supported only for the factor 1
+type DerivedDownloader struct {
+	source RatesDownloaderInterface
+	factor float32
+}
+
+// NewDerivedDownloader creates a DerivedDownloader
+// the parameters are the name of the source provider, its parameters and the factor, by which the source rates are multiplied (default 1)
+func NewDerivedDownloader(ctx *RatesDownloaderContext, params json.RawMessage) (RatesDownloaderInterface, error) {
+	var p struct {
+		Source       string          `json:"source"`
+		SourceParams json.RawMessage `json:"sourceParams"`
+		Factor       float32         `json:"factor"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Source == "" || len(p.SourceParams) == 0 {
+		return nil, errors.New("missing source or sourceParams")
+	}
+	if providerType(p.Source) == "derived" {
+		return nil, errors.New("source cannot be derived")
+	}
+	if p.Factor < 0 {
+		return nil, errors.New("invalid factor")
+	}
+	if p.Factor == 0 {
+		p.Factor = 1
+	}
+	source, err := newRatesDownloader(ctx, p.Source, nil, p.SourceParams)
+	if err != nil {
+		return nil, err
+	}
+	if p.Factor != 1 {
+		glog.Warningf("Derived fiat rates with factor %v, historical rates are not downloaded", p.Factor)
+	}
+	return &DerivedDownloader{
+		source: source,
+		factor: p.Factor,
+	}, nil
+}
+
+func (dd *DerivedDownloader) deriveTicker(t *common.CurrencyRatesTicker) {
+	if t == nil || dd.factor == 1 {
+		return
+	}
+	for currency, rate := range t.Rates {
+		t.Rates[currency] = rate * dd.factor
+	}
+	// the token rates are relative to the base currency, they must be divided by the factor
+	for token, rate := range t.TokenRates {
+		t.TokenRates[token] = rate / dd.factor
+	}
+}
+
+func (dd *DerivedDownloader) deriveTickers(tickers *[]common.CurrencyRatesTicker) {
+	if tickers == nil {
+		return
+	}
+	for i := range *tickers {
+		dd.deriveTicker(&(*tickers)[i])
+	}
+}
+
+// CurrentTickers returns the current rates of the source multiplied by the factor
+func (dd *DerivedDownloader) CurrentTickers() (*common.CurrencyRatesTicker, error) {
+	t, err := dd.source.CurrentTickers()
+	if err != nil {
+		return nil, err
+	}
+	dd.deriveTicker(t)
+	return t, nil
+}
+
+// HourlyTickers returns the hourly rates of the source multiplied by the factor
+func (dd *DerivedDownloader) HourlyTickers() (*[]common.CurrencyRatesTicker, error) {
+	t, err := dd.source.HourlyTickers()
+	if err != nil {
+		return nil, err
+	}
+	dd.deriveTickers(t)
+	return t, nil
+}
+
+// FiveMinutesTickers returns the five minutes rates of the source multiplied by the factor
+func (dd *DerivedDownloader) FiveMinutesTickers() (*[]common.CurrencyRatesTicker, error) {
+	t, err := dd.source.FiveMinutesTickers()
+	if err != nil {
+		return nil, err
+	}
+	dd.deriveTickers(t)
+	return t, nil
+}
+
+// UpdateHistoricalTickers updates the historical tickers by the source provider, only if the factor is 1
+func (dd *DerivedDownloader) UpdateHistoricalTickers() error {
+	if dd.factor != 1 {
+		return nil
+	}
+	return dd.source.UpdateHistoricalTickers()
+}
+
+// historicalTickers returns the historical tickers of the source provider, only if the factor is 1
+// a source, which cannot return the tickers, stores them to the db itself
+func (dd *DerivedDownloader) historicalTickers() ([]common.CurrencyRatesTicker, error) {
+	if dd.factor != 1 {
+		return nil, nil
+	}
+	if g, ok := dd.source.(historicalTickersGetter); ok {
+		return g.historicalTickers()
+	}
+	return nil, dd.source.UpdateHistoricalTickers()
+}
+
+// UpdateHistoricalTokenTickers updates the historical token tickers by the source provider, only if the factor is 1
+func (dd *DerivedDownloader) UpdateHistoricalTokenTickers() error {
+	if dd.factor != 1 {
+		return nil
+	}
+	return dd.source.UpdateHistoricalTokenTickers()
+}
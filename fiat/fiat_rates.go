@@ -3,7 +3,6 @@ package fiat
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"math/rand"
 	"strings"
 	"sync"
@@ -36,6 +35,12 @@ type RatesDownloaderInterface interface {
 	UpdateHistoricalTokenTickers() error
 }
 
+// historicalTickersGetter is implemented by the downloaders, which can return the downloaded historical tickers
+// instead of storing them to the db, the AggregateDownloader merges them per timestamp before they are stored
+type historicalTickersGetter interface {
+	historicalTickers() ([]common.CurrencyRatesTicker, error)
+}
+
 // FiatRates is used to fetch and refresh fiat rates
 type FiatRates struct {
 	Enabled                bool
@@ -58,6 +63,7 @@ type FiatRates struct {
 	dailyTickers           map[int64]*common.CurrencyRatesTicker
 	dailyTickersFrom       int64
 	dailyTickersTo         int64
+	candles                candlesCache
 }
 
 // NewFiatRates initializes the FiatRates handler
@@ -75,8 +81,6 @@ func NewFiatRates(db *db.RocksDB, config *common.Config, metrics *common.Metrics
 	}
 
 	type fiatRatesParams struct {
-		URL                string `json:"url"`
-		Coin               string `json:"coin"`
 		PlatformIdentifier string `json:"platformIdentifier"`
 		PlatformVsCurrency string `json:"platformVsCurrency"`
 		PeriodSeconds      int64  `json:"periodSeconds"`
@@ -97,50 +101,58 @@ func NewFiatRates(db *db.RocksDB, config *common.Config, metrics *common.Metrics
 	fr.db = db
 	fr.callbackOnNewTicker = callback
 	fr.downloadTokens = rdParams.PlatformIdentifier != "" && rdParams.PlatformVsCurrency != ""
+	is := fr.db.GetInternalState()
+	var network, coinShortcut string
+	if is != nil {
+		network = is.GetNetwork()
+		coinShortcut = is.CoinShortcut
+	}
 	if fr.downloadTokens {
-		common.TickerRecalculateTokenRate = strings.ToLower(db.GetInternalState().CoinShortcut) != rdParams.PlatformVsCurrency
+		common.TickerRecalculateTokenRate = strings.ToLower(coinShortcut) != rdParams.PlatformVsCurrency
 		common.TickerTokenVsCurrency = rdParams.PlatformVsCurrency
 	}
-	is := fr.db.GetInternalState()
-	if fr.provider == "coingecko" {
-		throttle := true
-		if callback == nil {
-			// a small hack - in tests the callback is not used, therefore there is no delay slowing down the test
-			throttle = false
-		}
-		fr.downloader = NewCoinGeckoDownloader(db, db.GetInternalState().GetNetwork(), rdParams.URL, rdParams.Coin, rdParams.PlatformIdentifier, rdParams.PlatformVsCurrency, fr.allowedVsCurrencies, fr.timeFormat, metrics, throttle)
-		if is != nil {
-			is.HasFiatRates = true
-			is.HasTokenFiatRates = fr.downloadTokens
-			fr.Enabled = true
-
-			if err := fr.loadDailyTickers(); err != nil {
-				return nil, err
-			}
+	ctx := &RatesDownloaderContext{
+		DB:                  db,
+		Network:             network,
+		AllowedVsCurrencies: fr.allowedVsCurrencies,
+		TimeFormat:          fr.timeFormat,
+		Metrics:             metrics,
+		// a small hack - in tests the callback is not used, therefore there is no delay slowing down the test
+		Throttle: callback != nil,
+	}
+	fr.downloader, err = NewRatesDownloader(ctx, fr.provider, config.FiatRatesParams)
+	if err != nil {
+		return nil, err
+	}
+	if is != nil {
+		is.HasFiatRates = true
+		is.HasTokenFiatRates = fr.downloadTokens
+		fr.Enabled = true
 
-			currentTickers, err := db.FiatRatesGetSpecialTickers(currentTickersKey)
-			if err != nil {
-				glog.Error("FiatRatesDownloader: get CurrentTickers from DB error ", err)
-			}
-			if currentTickers != nil && len(*currentTickers) > 0 {
-				fr.currentTicker = &(*currentTickers)[0]
-			}
+		if err := fr.loadDailyTickers(); err != nil {
+			return nil, err
+		}
 
-			hourlyTickers, err := db.FiatRatesGetSpecialTickers(hourlyTickersKey)
-			if err != nil {
-				glog.Error("FiatRatesDownloader: get HourlyTickers from DB error ", err)
-			}
-			fr.hourlyTickers, fr.hourlyTickersFrom, fr.hourlyTickersTo = fr.tickersToMap(hourlyTickers, secondsInHour)
+		currentTickers, err := db.FiatRatesGetSpecialTickers(currentTickersKey)
+		if err != nil {
+			glog.Error("FiatRatesDownloader: get CurrentTickers from DB error ", err)
+		}
+		if currentTickers != nil && len(*currentTickers) > 0 {
+			fr.currentTicker = &(*currentTickers)[0]
+		}
 
-			fiveMinutesTickers, err := db.FiatRatesGetSpecialTickers(fiveMinutesTickersKey)
-			if err != nil {
-				glog.Error("FiatRatesDownloader: get FiveMinutesTickers from DB error ", err)
-			}
-			fr.fiveMinutesTickers, fr.fiveMinutesTickersFrom, fr.fiveMinutesTickersTo = fr.tickersToMap(fiveMinutesTickers, secondsInFiveMinutes)
+		hourlyTickers, err := db.FiatRatesGetSpecialTickers(hourlyTickersKey)
+		if err != nil {
+			glog.Error("FiatRatesDownloader: get HourlyTickers from DB error ", err)
+		}
+		fr.hourlyTickers, fr.hourlyTickersFrom, fr.hourlyTickersTo = fr.tickersToMap(hourlyTickers, secondsInHour)
 
+		fiveMinutesTickers, err := db.FiatRatesGetSpecialTickers(fiveMinutesTickersKey)
+		if err != nil {
+			glog.Error("FiatRatesDownloader: get FiveMinutesTickers from DB error ", err)
 		}
-	} else {
-		return nil, fmt.Errorf("unknown provider %q", fr.provider)
+		fr.fiveMinutesTickers, fr.fiveMinutesTickersFrom, fr.fiveMinutesTickersTo = fr.tickersToMap(fiveMinutesTickers, secondsInFiveMinutes)
+
 	}
 	fr.logTickersInfo()
 	return fr, nil
@@ -409,9 +421,9 @@ func (fr *FiatRates) RunDownloader() error {
 
 		// load current tickers
 		currentTicker, err := fr.downloader.CurrentTickers()
-		if err != nil || currentTicker == nil {
+		if err != nil {
 			glog.Error("FiatRatesDownloader: CurrentTickers error ", err)
-		} else {
+		} else if currentTicker != nil {
 			fr.setCurrentTicker(currentTicker)
 			glog.Info("FiatRatesDownloader: CurrentTickers updated")
 			if fr.callbackOnNewTicker != nil {
@@ -422,9 +434,9 @@ func (fr *FiatRates) RunDownloader() error {
 		// load hourly tickers, it is necessary to wait about 1 hour to prepare the tickers
 		if time.Now().UTC().Unix() >= fr.hourlyTickersTo+secondsInHour+secondsInHour {
 			hourlyTickers, err := fr.downloader.HourlyTickers()
-			if err != nil || hourlyTickers == nil {
+			if err != nil {
 				glog.Error("FiatRatesDownloader: HourlyTickers error ", err)
-			} else {
+			} else if hourlyTickers != nil {
 				fr.setHourlyTickers(hourlyTickers)
 				glog.Info("FiatRatesDownloader: HourlyTickers updated")
 			}
@@ -433,9 +445,9 @@ func (fr *FiatRates) RunDownloader() error {
 		// load five minute tickers, it is necessary to wait about 10 minutes to prepare the tickers
 		if time.Now().UTC().Unix() >= fr.fiveMinutesTickersTo+3*secondsInFiveMinutes {
 			fiveMinutesTickers, err := fr.downloader.FiveMinutesTickers()
-			if err != nil || fiveMinutesTickers == nil {
+			if err != nil {
 				glog.Error("FiatRatesDownloader: FiveMinutesTickers error ", err)
-			} else {
+			} else if fiveMinutesTickers != nil {
 				fr.setFiveMinutesTickers(fiveMinutesTickers)
 				glog.Info("FiatRatesDownloader: FiveMinutesTickers updated")
 			}
@@ -0,0 +1,285 @@
+package fiat
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+// Granularities of the imported and exported fiat rates
+const (
+	GranularityDaily       = "daily"
+	GranularityHourly      = "hourly"
+	GranularityFiveMinutes = "5min"
+)
+
+// granularityParams returns the length of the period of the granularity in seconds
+// and the key of the special tickers in the db (empty for the daily tickers stored in the fiatRates column)
+func granularityParams(granularity string) (int64, string, error) {
+	switch granularity {
+	case GranularityDaily, "":
+		return secondsInDay, "", nil
+	case GranularityHourly:
+		return secondsInHour, hourlyTickersKey, nil
+	case GranularityFiveMinutes:
+		return secondsInFiveMinutes, fiveMinutesTickersKey, nil
+	}
+	return 0, "", fmt.Errorf("unknown granularity %q, expected %s, %s or %s", granularity, GranularityDaily, GranularityHourly, GranularityFiveMinutes)
+}
+
+// isJSONFile returns true if the file has the .json extension, other files are in the CSV format
+func isJSONFile(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".json")
+}
+
+// ReadTickersJSON reads tickers from a JSON array of CurrencyRatesTicker
+func ReadTickersJSON(r io.Reader) ([]common.CurrencyRatesTicker, error) {
+	var tickers []common.CurrencyRatesTicker
+	if err := json.NewDecoder(r).Decode(&tickers); err != nil {
+		return nil, err
+	}
+	return tickers, nil
+}
+
+// WriteTickersJSON writes the tickers as a JSON array of CurrencyRatesTicker
+func WriteTickersJSON(w io.Writer, tickers []common.CurrencyRatesTicker) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", " ")
+	return e.Encode(tickers)
+}
+
+// WriteTickersCSV writes the tickers in the format read by ReadTickersCSV, the time is in RFC3339 format
+func WriteTickersCSV(w io.Writer, tickers []common.CurrencyRatesTicker) error {
+	currencies := make(map[string]struct{})
+	tokens := make(map[string]struct{})
+	for i := range tickers {
+		for c := range tickers[i].Rates {
+			currencies[c] = struct{}{}
+		}
+		for t := range tickers[i].TokenRates {
+			tokens[t] = struct{}{}
+		}
+	}
+	sortedKeys := func(m map[string]struct{}) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	columns := sortedKeys(currencies)
+	tokenColumns := sortedKeys(tokens)
+	header := make([]string, 0, 1+len(columns)+len(tokenColumns))
+	header = append(header, "time")
+	header = append(header, columns...)
+	for _, t := range tokenColumns {
+		header = append(header, csvTokenColumnPrefix+t)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	formatRate := func(m map[string]float32, k string) string {
+		if v, found := m[k]; found {
+			return strconv.FormatFloat(float64(v), 'g', -1, 32)
+		}
+		return ""
+	}
+	record := make([]string, len(header))
+	for i := range tickers {
+		t := &tickers[i]
+		record[0] = t.Timestamp.UTC().Format(time.RFC3339)
+		for j, c := range columns {
+			record[1+j] = formatRate(t.Rates, c)
+		}
+		for j, c := range tokenColumns {
+			record[1+len(columns)+j] = formatRate(t.TokenRates, c)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ValidateTickers checks that the timestamps of the tickers are aligned to the granularity and are not in the future
+// and that the rates are positive numbers, the currencies and tokens are converted to lower case
+func ValidateTickers(tickers []common.CurrencyRatesTicker, granularity string) error {
+	period, _, err := granularityParams(granularity)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	validRates := func(rates map[string]float32, what string, ts time.Time) (map[string]float32, error) {
+		if rates == nil {
+			return nil, nil
+		}
+		lower := make(map[string]float32, len(rates))
+		for k, v := range rates {
+			if k == "" || v <= 0 || math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+				return nil, fmt.Errorf("ticker %v: invalid %s rate %q: %v", ts, what, k, v)
+			}
+			lower[strings.ToLower(k)] = v
+		}
+		return lower, nil
+	}
+	for i := range tickers {
+		t := &tickers[i]
+		unix := t.Timestamp.Unix()
+		if unix <= 0 || unix%period != 0 {
+			return fmt.Errorf("ticker %v: time is not aligned to %s granularity", t.Timestamp, granularity)
+		}
+		if unix > now {
+			return fmt.Errorf("ticker %v: time is in the future", t.Timestamp)
+		}
+		t.Timestamp = t.Timestamp.UTC()
+		if t.Rates, err = validRates(t.Rates, "currency", t.Timestamp); err != nil {
+			return err
+		}
+		if t.TokenRates, err = validRates(t.TokenRates, "token", t.Timestamp); err != nil {
+			return err
+		}
+		if len(t.Rates) == 0 {
+			return fmt.Errorf("ticker %v: missing base currency rates", t.Timestamp)
+		}
+	}
+	return nil
+}
+
+// mergeSpecialTickers merges the tickers to the hourly or five minutes tickers stored in the db and returns the number of updated tickers
+func mergeSpecialTickers(d *db.RocksDB, key string, tickers []common.CurrencyRatesTicker, overwrite bool) (int, error) {
+	stored, err := d.FiatRatesGetSpecialTickers(key)
+	if err != nil {
+		return 0, err
+	}
+	byTime := make(map[int64]*common.CurrencyRatesTicker)
+	if stored != nil {
+		for i := range *stored {
+			t := &(*stored)[i]
+			byTime[t.Timestamp.Unix()] = t
+		}
+	}
+	updated := make(map[int64]struct{})
+	for i := range tickers {
+		t := &tickers[i]
+		unix := t.Timestamp.Unix()
+		s, found := byTime[unix]
+		if !found {
+			s = &common.CurrencyRatesTicker{Timestamp: t.Timestamp}
+			byTime[unix] = s
+		}
+		var changedRates, changedTokens bool
+		s.Rates, changedRates = mergeRates(s.Rates, t.Rates, overwrite)
+		s.TokenRates, changedTokens = mergeRates(s.TokenRates, t.TokenRates, overwrite)
+		if changedRates || changedTokens {
+			updated[unix] = struct{}{}
+		}
+	}
+	if len(updated) == 0 {
+		return 0, nil
+	}
+	merged := make([]common.CurrencyRatesTicker, 0, len(byTime))
+	for _, t := range byTime {
+		merged = append(merged, *t)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Timestamp.Before(merged[j].Timestamp) })
+	if err := d.FiatRatesStoreSpecialTickers(key, &merged); err != nil {
+		return 0, err
+	}
+	return len(updated), nil
+}
+
+// ImportTickers imports the tickers of the given granularity from a CSV or JSON file (by the .json extension) to the db
+// the daily tickers are merged to the fiatRates column, the hourly and five minutes tickers to the lists used by FiatRates
+// if overwrite is false, the rates already stored in the db are kept
+func ImportTickers(d *db.RocksDB, file string, granularity string, overwrite bool) (int, error) {
+	_, key, err := granularityParams(granularity)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	var tickers []common.CurrencyRatesTicker
+	if isJSONFile(file) {
+		tickers, err = ReadTickersJSON(f)
+	} else {
+		tickers, err = ReadTickersCSV(f)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", file, err)
+	}
+	if err = ValidateTickers(tickers, granularity); err != nil {
+		return 0, fmt.Errorf("%s: %v", file, err)
+	}
+	var updated int
+	if key == "" {
+		updated, err = StoreHistoricalTickers(d, tickers, overwrite)
+	} else {
+		updated, err = mergeSpecialTickers(d, key, tickers, overwrite)
+	}
+	if err != nil {
+		return 0, err
+	}
+	glog.Infof("ImportTickers: %s, %d %s tickers read, %d tickers updated", file, len(tickers), granularity, updated)
+	return updated, nil
+}
+
+// ExportTickers exports the tickers of the given granularity from the db to a CSV or JSON file (by the .json extension)
+// file "-" means the standard output in the CSV format
+func ExportTickers(d *db.RocksDB, file string, granularity string) (int, error) {
+	_, key, err := granularityParams(granularity)
+	if err != nil {
+		return 0, err
+	}
+	var tickers []common.CurrencyRatesTicker
+	if key == "" {
+		err = d.FiatRatesGetAllTickers(func(t *common.CurrencyRatesTicker) error {
+			tickers = append(tickers, *t)
+			return nil
+		})
+	} else {
+		var t *[]common.CurrencyRatesTicker
+		if t, err = d.FiatRatesGetSpecialTickers(key); t != nil {
+			tickers = *t
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	var w io.Writer = os.Stdout
+	if file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		w = f
+	}
+	if isJSONFile(file) {
+		err = WriteTickersJSON(w, tickers)
+	} else {
+		err = WriteTickersCSV(w, tickers)
+	}
+	if err != nil {
+		return 0, err
+	}
+	glog.Infof("ExportTickers: %s, %d %s tickers written", file, len(tickers), granularity)
+	return len(tickers), nil
+}
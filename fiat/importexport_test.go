@@ -0,0 +1,159 @@
+//go:build unittest
+
+package fiat
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/trezor/blockbook/common"
+)
+
+func TestValidateTickers(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		ticker      common.CurrencyRatesTicker
+		granularity string
+		wantErr     bool
+	}{
+		{name: "daily", ticker: common.CurrencyRatesTicker{Timestamp: day, Rates: map[string]float32{"USD": 1}}, granularity: GranularityDaily},
+		{name: "daily not aligned", ticker: common.CurrencyRatesTicker{Timestamp: day.Add(time.Hour), Rates: map[string]float32{"usd": 1}}, granularity: GranularityDaily, wantErr: true},
+		{name: "hourly", ticker: common.CurrencyRatesTicker{Timestamp: day.Add(time.Hour), Rates: map[string]float32{"usd": 1}}, granularity: GranularityHourly},
+		{name: "5min", ticker: common.CurrencyRatesTicker{Timestamp: day.Add(5 * time.Minute), Rates: map[string]float32{"usd": 1}}, granularity: GranularityFiveMinutes},
+		{name: "5min not aligned", ticker: common.CurrencyRatesTicker{Timestamp: day.Add(time.Minute), Rates: map[string]float32{"usd": 1}}, granularity: GranularityFiveMinutes, wantErr: true},
+		{name: "future", ticker: common.CurrencyRatesTicker{Timestamp: time.Now().Add(48 * time.Hour).Truncate(24 * time.Hour), Rates: map[string]float32{"usd": 1}}, granularity: GranularityDaily, wantErr: true},
+		{name: "negative rate", ticker: common.CurrencyRatesTicker{Timestamp: day, Rates: map[string]float32{"usd": -1}}, granularity: GranularityDaily, wantErr: true},
+		{name: "no base rates", ticker: common.CurrencyRatesTicker{Timestamp: day, TokenRates: map[string]float32{"0xa": 1}}, granularity: GranularityDaily, wantErr: true},
+		{name: "unknown granularity", ticker: common.CurrencyRatesTicker{Timestamp: day, Rates: map[string]float32{"usd": 1}}, granularity: "weekly", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickers := []common.CurrencyRatesTicker{tt.ticker}
+			err := ValidateTickers(tickers, tt.granularity)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTickers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteReadTickersCSV(t *testing.T) {
+	tickers := []common.CurrencyRatesTicker{
+		{Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Rates: map[string]float32{"usd": 42000.5, "eur": 38000}, TokenRates: map[string]float32{"0xa": 0.001}},
+		{Timestamp: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Rates: map[string]float32{"usd": 43000}},
+	}
+	var buf bytes.Buffer
+	if err := WriteTickersCSV(&buf, tickers); err != nil {
+		t.Fatal(err)
+	}
+	want := "time,eur,usd,token:0xa\n2024-01-01T00:00:00Z,38000,42000.5,0.001\n2024-01-02T00:00:00Z,,43000,\n"
+	if buf.String() != want {
+		t.Errorf("WriteTickersCSV() = %q, want %q", buf.String(), want)
+	}
+	got, err := ReadTickersCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, tickers) {
+		t.Errorf("ReadTickersCSV() = %+v, want %+v", got, tickers)
+	}
+}
+
+func TestImportExportTickers(t *testing.T) {
+	d, _, tmp := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	}, &common.Config{})
+	defer closeAndDestroyRocksDB(t, d, tmp)
+
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := StoreHistoricalTickers(d, []common.CurrencyRatesTicker{{Timestamp: day1, Rates: map[string]float32{"usd": 1}}}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	csvFile := filepath.Join(tmp, "daily.csv")
+	if err := os.WriteFile(csvFile, []byte("time,USD,EUR\n2024-01-01,42000,38000\n2024-01-02,43000,39000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// without overwrite, the stored usd rate of day1 is kept
+	updated, err := ImportTickers(d, csvFile, GranularityDaily, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 {
+		t.Errorf("ImportTickers() = %v, want 2", updated)
+	}
+	jsonFile := filepath.Join(tmp, "daily.json")
+	if _, err := ExportTickers(d, jsonFile, GranularityDaily); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := ReadTickersJSON(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.CurrencyRatesTicker{
+		{Timestamp: day1, Rates: map[string]float32{"usd": 1, "eur": 38000}},
+		{Timestamp: day2, Rates: map[string]float32{"usd": 43000, "eur": 39000}},
+	}
+	if !reflect.DeepEqual(exported, want) {
+		t.Errorf("exported daily tickers = %+v, want %+v", exported, want)
+	}
+	// with overwrite, the usd rate of day1 is replaced, day2 is unchanged
+	updated, err = ImportTickers(d, csvFile, GranularityDaily, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 1 {
+		t.Errorf("ImportTickers() with overwrite = %v, want 1", updated)
+	}
+	ticker, err := d.FiatRatesGetTicker(&day1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticker == nil || ticker.Rates["usd"] != 42000 {
+		t.Errorf("day1 ticker = %+v", ticker)
+	}
+
+	// hourly tickers are merged to the list of hourly tickers
+	hour1 := day2.Add(time.Hour)
+	hour2 := day2.Add(2 * time.Hour)
+	if err := d.FiatRatesStoreSpecialTickers(hourlyTickersKey, &[]common.CurrencyRatesTicker{{Timestamp: hour2, Rates: map[string]float32{"usd": 2}}}); err != nil {
+		t.Fatal(err)
+	}
+	hourlyFile := filepath.Join(tmp, "hourly.json")
+	if err := os.WriteFile(hourlyFile, []byte(`[{"timestamp":"2024-01-02T01:00:00Z","rates":{"usd":1}},{"timestamp":"2024-01-02T02:00:00Z","rates":{"usd":3,"eur":4}}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if updated, err = ImportTickers(d, hourlyFile, GranularityHourly, false); err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 {
+		t.Errorf("ImportTickers() hourly = %v, want 2", updated)
+	}
+	hourly, err := d.FiatRatesGetSpecialTickers(hourlyTickersKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHourly := []common.CurrencyRatesTicker{
+		{Timestamp: hour1, Rates: map[string]float32{"usd": 1}},
+		{Timestamp: hour2, Rates: map[string]float32{"usd": 2, "eur": 4}},
+	}
+	if hourly == nil || !reflect.DeepEqual(*hourly, wantHourly) {
+		t.Errorf("hourly tickers = %+v, want %+v", hourly, wantHourly)
+	}
+
+	// hourly tickers are not aligned to the daily granularity
+	if _, err := ImportTickers(d, hourlyFile, GranularityDaily, false); err == nil {
+		t.Error("ImportTickers() of hourly tickers as daily: expected error")
+	}
+}
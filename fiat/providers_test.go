@@ -0,0 +1,295 @@
+//go:build unittest
+
+package fiat
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trezor/blockbook/common"
+)
+
+func Test_medianWithoutOutliers(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float32
+		want   float32
+		wantOk bool
+	}{
+		{name: "empty", values: nil, want: 0, wantOk: false},
+		{name: "invalid", values: []float32{0, -1}, want: 0, wantOk: false},
+		{name: "one", values: []float32{100}, want: 100, wantOk: true},
+		{name: "two", values: []float32{100, 102}, want: 101, wantOk: true},
+		{name: "outlier", values: []float32{100, 101, 102, 200}, want: 101, wantOk: true},
+		{name: "outlier low", values: []float32{1, 100, 104}, want: 102, wantOk: true},
+		{name: "no agreement", values: []float32{1, 100}, want: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := medianWithoutOutliers(tt.values, 0.1)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("medianWithoutOutliers() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_jsonPathValue(t *testing.T) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(`{"result":{"XXBTZUSD":{"c":["65000.1","0.1"]}},"price":1.5,"s":"x"}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path    string
+		want    float64
+		wantErr bool
+	}{
+		{path: "result.XXBTZUSD.c.0", want: 65000.1},
+		{path: "price", want: 1.5},
+		{path: "result.XXBTZUSD.c.2", wantErr: true},
+		{path: "result.missing", wantErr: true},
+		{path: "price.x", wantErr: true},
+		{path: "s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := jsonPathValue(data, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jsonPathValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("jsonPathValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testDownloader returns fixed tickers
+type testDownloader struct {
+	current *common.CurrencyRatesTicker
+	hourly  *[]common.CurrencyRatesTicker
+}
+
+func (d *testDownloader) CurrentTickers() (*common.CurrencyRatesTicker, error) {
+	return d.current, nil
+}
+
+func (d *testDownloader) HourlyTickers() (*[]common.CurrencyRatesTicker, error) {
+	return d.hourly, nil
+}
+
+func (d *testDownloader) FiveMinutesTickers() (*[]common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+func (d *testDownloader) UpdateHistoricalTickers() error {
+	return nil
+}
+
+func (d *testDownloader) UpdateHistoricalTokenTickers() error {
+	return nil
+}
+
+func TestAggregateDownloader(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	downloaders := []RatesDownloaderInterface{
+		&testDownloader{
+			current: &common.CurrencyRatesTicker{Rates: map[string]float32{"usd": 100, "eur": 90}, TokenRates: map[string]float32{"0xa": 0.5}},
+			hourly:  &[]common.CurrencyRatesTicker{{Timestamp: ts, Rates: map[string]float32{"usd": 100}}},
+		},
+		&testDownloader{
+			current: &common.CurrencyRatesTicker{Rates: map[string]float32{"usd": 102, "eur": 200}},
+			hourly:  &[]common.CurrencyRatesTicker{{Timestamp: ts.Add(time.Minute), Rates: map[string]float32{"usd": 104}}},
+		},
+		&testDownloader{
+			current: &common.CurrencyRatesTicker{Rates: map[string]float32{"usd": 104, "eur": 92}},
+		},
+	}
+	a := NewAggregateDownloader(nil, []string{"a", "b", "c"}, downloaders, 0)
+	current, err := a.CurrentTickers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]float32{"usd": 102, "eur": 91}; !reflect.DeepEqual(current.Rates, want) {
+		t.Errorf("CurrentTickers().Rates = %v, want %v", current.Rates, want)
+	}
+	if want := map[string]float32{"0xa": 0.5}; !reflect.DeepEqual(current.TokenRates, want) {
+		t.Errorf("CurrentTickers().TokenRates = %v, want %v", current.TokenRates, want)
+	}
+	hourly, err := a.HourlyTickers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.CurrencyRatesTicker{{Timestamp: ts, Rates: map[string]float32{"usd": 102}}}
+	if !reflect.DeepEqual(*hourly, want) {
+		t.Errorf("HourlyTickers() = %+v, want %+v", *hourly, want)
+	}
+}
+
+func TestAggregateDownloader_mergeHistoricalTickers(t *testing.T) {
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	a := NewAggregateDownloader(nil, []string{"a", "b"}, nil, 0).(*AggregateDownloader)
+	got := a.mergeHistoricalTickers([][]common.CurrencyRatesTicker{
+		{
+			{Timestamp: day2, Rates: map[string]float32{"usd": 110}},
+			{Timestamp: day1, Rates: map[string]float32{"usd": 100, "eur": 90}},
+		},
+		{
+			{Timestamp: day1, Rates: map[string]float32{"usd": 102, "czk": 2000}, TokenRates: map[string]float32{"0xa": 0.5}},
+		},
+	})
+	want := []common.CurrencyRatesTicker{
+		{Timestamp: day1, Rates: map[string]float32{"usd": 101, "eur": 90, "czk": 2000}, TokenRates: map[string]float32{"0xa": 0.5}},
+		{Timestamp: day2, Rates: map[string]float32{"usd": 110}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeHistoricalTickers() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRestDownloader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/ticker/USD":
+			w.Write([]byte(`{"data":{"last":"65000.5"}}`))
+		case "/ticker/EUR":
+			w.Write([]byte(`{"data":{"last":60000}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	ctx := &RatesDownloaderContext{}
+	d, err := NewRatesDownloader(ctx, "rest", `{"url":"`+server.URL+`/ticker/{CURRENCY}","rates":{"USD":"data.last","eur":"data.last","czk":"data.last"},"headers":{"X-Api-Key":"key"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticker, err := d.CurrentTickers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]float32{"usd": 65000.5, "eur": 60000}; !reflect.DeepEqual(ticker.Rates, want) {
+		t.Errorf("CurrentTickers().Rates = %v, want %v", ticker.Rates, want)
+	}
+	if _, err := NewRatesDownloader(ctx, "rest", `{"url":"`+server.URL+`"}`); err == nil {
+		t.Error("NewRatesDownloader without rates: expected error")
+	}
+}
+
+func TestNewRatesDownloader(t *testing.T) {
+	ctx := &RatesDownloaderContext{}
+	params := `{"maxDeviation":0.05,"rest:a":{"url":"http://a","rates":{"usd":"p"}},"derived":{"source":"rest:b","sourceParams":{"url":"http://b","rates":{"usd":"p"}},"factor":2}}`
+	d, err := NewRatesDownloader(ctx, "rest:a, derived", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, ok := d.(*AggregateDownloader)
+	if !ok {
+		t.Fatalf("NewRatesDownloader() = %T, want *AggregateDownloader", d)
+	}
+	if !reflect.DeepEqual(a.names, []string{"rest:a", "derived"}) || a.maxDeviation != 0.05 {
+		t.Errorf("NewRatesDownloader() names %v, maxDeviation %v", a.names, a.maxDeviation)
+	}
+	if dd, ok := a.downloaders[1].(*DerivedDownloader); !ok || dd.factor != 2 {
+		t.Errorf("NewRatesDownloader() derived = %+v", a.downloaders[1])
+	}
+	if _, err := NewRatesDownloader(ctx, "unknown", params); err == nil {
+		t.Error("NewRatesDownloader(unknown): expected error")
+	}
+	if _, err := NewRatesDownloader(ctx, "derived", `{"source":"derived","sourceParams":{}}`); err == nil {
+		t.Error("NewRatesDownloader(derived of derived): expected error")
+	}
+}
+
+func TestDerivedDownloader(t *testing.T) {
+	dd := &DerivedDownloader{
+		source: &testDownloader{
+			current: &common.CurrencyRatesTicker{Rates: map[string]float32{"usd": 100}, TokenRates: map[string]float32{"0xa": 0.5}},
+		},
+		factor: 2,
+	}
+	ticker, err := dd.CurrentTickers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &common.CurrencyRatesTicker{Rates: map[string]float32{"usd": 200}, TokenRates: map[string]float32{"0xa": 0.25}}
+	if !reflect.DeepEqual(ticker, want) {
+		t.Errorf("CurrentTickers() = %+v, want %+v", ticker, want)
+	}
+}
+
+func TestCsvDownloader(t *testing.T) {
+	d, _, tmp := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	}, &common.Config{})
+	defer closeAndDestroyRocksDB(t, d, tmp)
+
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	// rate stored before the import is not overwritten
+	if _, err := StoreHistoricalTickers(d, []common.CurrencyRatesTicker{{Timestamp: day1, Rates: map[string]float32{"usd": 1}}}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(tmp, "rates.csv")
+	data := strings.Join([]string{
+		"time,USD,EUR,token:0xa",
+		"# comment",
+		"2024-01-01,42000,38000,",
+		"1704153600,43000,,0.001",
+		"2024-01-03T00:00:00Z,,,0.002",
+	}, "\n")
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := &RatesDownloaderContext{DB: d}
+	cd, err := NewRatesDownloader(ctx, "csv", `{"file":"`+file+`"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cd.UpdateHistoricalTickers(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := d.FiatRatesGetTicker(&day1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &common.CurrencyRatesTicker{Timestamp: day1, Rates: map[string]float32{"usd": 1, "eur": 38000}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("day1 ticker = %+v, want %+v", got, want)
+	}
+	got, err = d.FiatRatesGetTicker(&day2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &common.CurrencyRatesTicker{Timestamp: day2, Rates: map[string]float32{"usd": 43000}, TokenRates: map[string]float32{"0xa": 0.001}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("day2 ticker = %+v, want %+v", got, want)
+	}
+	// ticker without base currency rates is not created
+	day3 := day2.Add(24 * time.Hour)
+	got, err = d.FiatRatesGetTicker(&day3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("day3 ticker = %+v, want nil", got)
+	}
+
+	if _, err := ReadTickersCSV(strings.NewReader("time,usd\nx,1")); err == nil {
+		t.Error("ReadTickersCSV invalid time: expected error")
+	}
+}
@@ -0,0 +1,110 @@
+package fiat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+// RatesDownloaderContext contains the data shared by all fiat rates downloaders
+type RatesDownloaderContext struct {
+	DB                  *db.RocksDB
+	Network             string
+	AllowedVsCurrencies string
+	TimeFormat          string
+	Metrics             *common.Metrics
+	Throttle            bool
+}
+
+// RatesDownloaderFactory creates a fiat rates downloader from its parameters in JSON format
+type RatesDownloaderFactory func(ctx *RatesDownloaderContext, params json.RawMessage) (RatesDownloaderInterface, error)
+
+// RatesDownloaderFactories is the registry of the fiat rates providers, the key is the provider name used in the fiat_rates option
+var RatesDownloaderFactories = make(map[string]RatesDownloaderFactory)
+
+func init() {
+	RatesDownloaderFactories["coingecko"] = newCoinGeckoFromParams
+	RatesDownloaderFactories["rest"] = NewRestDownloader
+	RatesDownloaderFactories["csv"] = NewCsvDownloader
+	RatesDownloaderFactories["derived"] = NewDerivedDownloader
+	RatesDownloaderFactories["static"] = NewStaticDownloader
+}
+
+func newCoinGeckoFromParams(ctx *RatesDownloaderContext, params json.RawMessage) (RatesDownloaderInterface, error) {
+	var p struct {
+		URL                string `json:"url"`
+		Coin               string `json:"coin"`
+		PlatformIdentifier string `json:"platformIdentifier"`
+		PlatformVsCurrency string `json:"platformVsCurrency"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	return NewCoinGeckoDownloader(ctx.DB, ctx.Network, p.URL, p.Coin, p.PlatformIdentifier, p.PlatformVsCurrency, ctx.AllowedVsCurrencies, ctx.TimeFormat, ctx.Metrics, ctx.Throttle), nil
+}
+
+// providerType returns the type of the provider, the provider can be configured as type:name to allow several instances of the same type
+func providerType(provider string) string {
+	if i := strings.IndexByte(provider, ':'); i >= 0 {
+		return provider[:i]
+	}
+	return provider
+}
+
+// newRatesDownloader creates the downloader of a single provider
+// the parameters of the provider are taken from the object with the provider name in params, if not present, params are used
+func newRatesDownloader(ctx *RatesDownloaderContext, provider string, params map[string]json.RawMessage, rawParams json.RawMessage) (RatesDownloaderInterface, error) {
+	factory, found := RatesDownloaderFactories[providerType(provider)]
+	if !found {
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+	p, found := params[provider]
+	if !found {
+		p = rawParams
+	}
+	d, err := factory(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("provider %q: %v", provider, err)
+	}
+	return d, nil
+}
+
+// NewRatesDownloader creates the downloader for the comma separated list of providers from the fiat_rates option
+// if more providers are configured, their rates are aggregated by median with rejection of the outliers
+func NewRatesDownloader(ctx *RatesDownloaderContext, providers string, fiatRatesParams string) (RatesDownloaderInterface, error) {
+	rawParams := json.RawMessage(fiatRatesParams)
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(rawParams, &params); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, p := range strings.Split(providers, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			names = append(names, p)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("missing provider")
+	}
+	if len(names) == 1 {
+		return newRatesDownloader(ctx, names[0], params, rawParams)
+	}
+	downloaders := make([]RatesDownloaderInterface, len(names))
+	for i, name := range names {
+		d, err := newRatesDownloader(ctx, name, params, rawParams)
+		if err != nil {
+			return nil, err
+		}
+		downloaders[i] = d
+	}
+	var p struct {
+		MaxDeviation float64 `json:"maxDeviation"`
+	}
+	if err := json.Unmarshal(rawParams, &p); err != nil {
+		return nil, err
+	}
+	return NewAggregateDownloader(ctx.DB, names, downloaders, p.MaxDeviation), nil
+}
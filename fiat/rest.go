@@ -0,0 +1,169 @@
+package fiat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/common"
+)
+
+// RestDownloader is a generic downloader of the current rates from a REST/JSON API of an exchange, it implements RatesDownloaderInterface
+// the rates are extracted from the JSON response using paths configured for each vs currency
+// the downloader provides only the current rates
+type RestDownloader struct {
+	url        string
+	rates      map[string]string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+type restDownloaderParams struct {
+	URL     string            `json:"url"`
+	Rates   map[string]string `json:"rates"`
+	Headers map[string]string `json:"headers"`
+}
+
+// NewRestDownloader creates a RestDownloader from the parameters
+// url is the address of the API, it can contain placeholders {currency} and {CURRENCY}, in that case one request per currency is made
+// rates maps vs currency to the path of the rate in the JSON response, the path is a dot separated list of object keys and array indexes
+// headers are optional http headers sent with the requests
+func NewRestDownloader(ctx *RatesDownloaderContext, params json.RawMessage) (RatesDownloaderInterface, error) {
+	var p restDownloaderParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	if p.URL == "" || len(p.Rates) == 0 {
+		return nil, errors.New("missing url or rates")
+	}
+	rates := make(map[string]string, len(p.Rates))
+	for currency, path := range p.Rates {
+		rates[strings.ToLower(currency)] = path
+	}
+	glog.Info("REST fiat rates downloader url ", p.URL)
+	return &RestDownloader{
+		url:     p.URL,
+		rates:   rates,
+		headers: p.Headers,
+		httpClient: &http.Client{
+			Timeout: DefaultHTTPTimeout,
+		},
+	}, nil
+}
+
+func (rd *RestDownloader) get(url string) (interface{}, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range rd.headers {
+		req.Header.Set(k, v)
+	}
+	body, err := doReq(req, rd.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// jsonPathValue returns the number found in the unmarshalled JSON data at the dot separated path
+// the value can be a JSON number or a string containing a number
+func jsonPathValue(data interface{}, path string) (float64, error) {
+	v := data
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			switch d := v.(type) {
+			case map[string]interface{}:
+				var found bool
+				if v, found = d[key]; !found {
+					return 0, fmt.Errorf("path %q: key %q not found", path, key)
+				}
+			case []interface{}:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(d) {
+					return 0, fmt.Errorf("path %q: invalid index %q", path, key)
+				}
+				v = d[i]
+			default:
+				return 0, fmt.Errorf("path %q: cannot select %q", path, key)
+			}
+		}
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, fmt.Errorf("path %q: %v", path, err)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("path %q: value is not a number", path)
+}
+
+// CurrentTickers returns the current rates
+func (rd *RestDownloader) CurrentTickers() (*common.CurrencyRatesTicker, error) {
+	ticker := common.CurrencyRatesTicker{
+		Rates: make(map[string]float32, len(rd.rates)),
+	}
+	perCurrency := strings.Contains(rd.url, "{currency}") || strings.Contains(rd.url, "{CURRENCY}")
+	var data interface{}
+	var err error
+	if !perCurrency {
+		if data, err = rd.get(rd.url); err != nil {
+			return nil, err
+		}
+	}
+	for currency, path := range rd.rates {
+		if perCurrency {
+			url := strings.ReplaceAll(rd.url, "{currency}", currency)
+			url = strings.ReplaceAll(url, "{CURRENCY}", strings.ToUpper(currency))
+			if data, err = rd.get(url); err != nil {
+				glog.Errorf("RestDownloader: %s error %v", currency, err)
+				continue
+			}
+		}
+		rate, err := jsonPathValue(data, path)
+		if err != nil {
+			glog.Errorf("RestDownloader: %s error %v", currency, err)
+			continue
+		}
+		ticker.Rates[currency] = float32(rate)
+	}
+	if len(ticker.Rates) == 0 {
+		return nil, errors.New("no rates found")
+	}
+	ticker.Timestamp = time.Now().UTC()
+	return &ticker, nil
+}
+
+// HourlyTickers is not supported by RestDownloader
+func (rd *RestDownloader) HourlyTickers() (*[]common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// FiveMinutesTickers is not supported by RestDownloader
+func (rd *RestDownloader) FiveMinutesTickers() (*[]common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// UpdateHistoricalTickers is not supported by RestDownloader
+func (rd *RestDownloader) UpdateHistoricalTickers() error {
+	return nil
+}
+
+// UpdateHistoricalTokenTickers is not supported by RestDownloader
+func (rd *RestDownloader) UpdateHistoricalTokenTickers() error {
+	return nil
+}
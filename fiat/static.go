@@ -0,0 +1,41 @@
+package fiat
+
+import (
+	"encoding/json"
+
+	"github.com/trezor/blockbook/common"
+)
+
+// StaticDownloader does not download any rates, it implements RatesDownloaderInterface
+// it is used in deployments without access to the rates providers, the rates are imported to the db by -importfiatrates
+type StaticDownloader struct{}
+
+// NewStaticDownloader creates a StaticDownloader, it has no parameters
+func NewStaticDownloader(ctx *RatesDownloaderContext, params json.RawMessage) (RatesDownloaderInterface, error) {
+	return &StaticDownloader{}, nil
+}
+
+// CurrentTickers returns no ticker
+func (sd *StaticDownloader) CurrentTickers() (*common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// HourlyTickers returns no tickers
+func (sd *StaticDownloader) HourlyTickers() (*[]common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// FiveMinutesTickers returns no tickers
+func (sd *StaticDownloader) FiveMinutesTickers() (*[]common.CurrencyRatesTicker, error) {
+	return nil, nil
+}
+
+// UpdateHistoricalTickers does nothing
+func (sd *StaticDownloader) UpdateHistoricalTickers() error {
+	return nil
+}
+
+// UpdateHistoricalTokenTickers does nothing
+func (sd *StaticDownloader) UpdateHistoricalTokenTickers() error {
+	return nil
+}
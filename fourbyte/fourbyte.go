@@ -1,6 +1,7 @@
 package fourbyte
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -15,11 +16,16 @@ import (
 	"github.com/trezor/blockbook/db"
 )
 
-// Coingecko is a structure that implements RatesDownloaderInterface
+// FourByteSignaturesDownloader downloads the function or event signatures from the 4byte.directory API
 type FourByteSignaturesDownloader struct {
 	url                string
 	httpTimeoutSeconds time.Duration
 	db                 *db.RocksDB
+	name               string
+	// isStored checks if the signature is already stored in db
+	isStored func(r *signatureData) (bool, error)
+	// store stores the signature to the write batch
+	store func(wb *grocksdb.WriteBatch, r *signatureData) error
 }
 
 // NewFourByteSignaturesDownloader initializes the downloader for FourByteSignatures API.
@@ -28,6 +34,55 @@ func NewFourByteSignaturesDownloader(db *db.RocksDB, url string) (*FourByteSigna
 		url:                url,
 		httpTimeoutSeconds: 15 * time.Second,
 		db:                 db,
+		name:               "FourByteSignaturesDownloader",
+		isStored: func(r *signatureData) (bool, error) {
+			fourBytes, err := strconv.ParseUint(r.HexSignature, 0, 0)
+			if err != nil {
+				return false, err
+			}
+			sig, err := db.GetFourByteSignature(uint32(fourBytes), uint32(r.Id))
+			return sig != nil, err
+		},
+		store: func(wb *grocksdb.WriteBatch, r *signatureData) error {
+			fourBytes, err := strconv.ParseUint(r.HexSignature, 0, 0)
+			if err != nil {
+				return err
+			}
+			fbs := parseSignatureFromText(r.TextSignature)
+			if fbs == nil {
+				return errors.New("invalid signature " + r.TextSignature)
+			}
+			return db.StoreFourByteSignature(wb, uint32(fourBytes), uint32(r.Id), fbs)
+		},
+	}, nil
+}
+
+// NewEventSignaturesDownloader initializes the downloader of the event signatures from the 4byte.directory API.
+func NewEventSignaturesDownloader(db *db.RocksDB, url string) (*FourByteSignaturesDownloader, error) {
+	return &FourByteSignaturesDownloader{
+		url:                url,
+		httpTimeoutSeconds: 15 * time.Second,
+		db:                 db,
+		name:               "EventSignaturesDownloader",
+		isStored: func(r *signatureData) (bool, error) {
+			topic, err := parseTopic(r.HexSignature)
+			if err != nil {
+				return false, err
+			}
+			sig, err := db.GetEventSignature(topic, uint32(r.Id))
+			return sig != nil, err
+		},
+		store: func(wb *grocksdb.WriteBatch, r *signatureData) error {
+			topic, err := parseTopic(r.HexSignature)
+			if err != nil {
+				return err
+			}
+			es := parseEventSignatureFromText(r.TextSignature)
+			if es == nil {
+				return errors.New("invalid signature " + r.TextSignature)
+			}
+			return db.StoreEventSignature(wb, topic, uint32(r.Id), es)
+		},
 	}, nil
 }
 
@@ -128,12 +183,42 @@ func parseSignatureFromText(t string) *bchain.FourByteSignature {
 	return &signature
 }
 
+// parseTopic parses the hex signature of an event to the 32 bytes topic
+func parseTopic(hexSignature string) ([]byte, error) {
+	topic, err := hex.DecodeString(strings.TrimPrefix(hexSignature, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(topic) != 32 {
+		return nil, errors.New("invalid topic length")
+	}
+	return topic, nil
+}
+
+// parseEventSignatureFromText parses the event signature, the text signature does not contain
+// the information which parameters are indexed
+func parseEventSignatureFromText(t string) *bchain.EventSignature {
+	fbs := parseSignatureFromText(t)
+	if fbs == nil {
+		return nil
+	}
+	es := bchain.EventSignature{
+		Name:           fbs.Name,
+		Inputs:         make([]bchain.EventSignatureParam, len(fbs.Parameters)),
+		IndexedUnknown: true,
+	}
+	for i := range fbs.Parameters {
+		es.Inputs[i].Type = fbs.Parameters[i]
+	}
+	return &es
+}
+
 func (fd *FourByteSignaturesDownloader) downloadSignatures() {
 	period := time.Millisecond * 100
 	timer := time.NewTimer(period)
 	url := fd.url
 	results := make([]signatureData, 0)
-	glog.Info("FourByteSignaturesDownloader starting download")
+	glog.Info(fd.name, " starting download")
 	for {
 		page, err := fd.getPageWithRetry(url)
 		if err != nil {
@@ -144,20 +229,15 @@ func (fd *FourByteSignaturesDownloader) downloadSignatures() {
 			glog.Errorf("Empty page from 4byte signatures from %s: %v", url, err)
 			return
 		}
-		glog.Infof("FourByteSignaturesDownloader downloaded %s with %d results", url, len(page.Results))
+		glog.Infof("%s downloaded %s with %d results", fd.name, url, len(page.Results))
 		if len(page.Results) > 0 {
-			fourBytes, err := strconv.ParseUint(page.Results[0].HexSignature, 0, 0)
-			if err != nil {
-				glog.Errorf("Invalid 4byte signature %+v on page %s: %v", page.Results[0], url, err)
-				return
-			}
-			sig, err := fd.db.GetFourByteSignature(uint32(fourBytes), uint32(page.Results[0].Id))
+			stored, err := fd.isStored(&page.Results[0])
 			if err != nil {
-				glog.Errorf("db.GetFourByteSignature error %+v on page %s: %v", page.Results[0], url, err)
+				glog.Errorf("%s error %+v on page %s: %v", fd.name, page.Results[0], url, err)
 				return
 			}
 			// signature is already stored in db, break
-			if sig != nil {
+			if stored {
 				break
 			}
 			results = append(results, page.Results...)
@@ -172,29 +252,21 @@ func (fd *FourByteSignaturesDownloader) downloadSignatures() {
 		timer.Reset(period)
 	}
 	if len(results) > 0 {
-		glog.Infof("FourByteSignaturesDownloader storing %d new signatures", len(results))
+		glog.Infof("%s storing %d new signatures", fd.name, len(results))
 		wb := grocksdb.NewWriteBatch()
 		defer wb.Destroy()
 
 		for i := range results {
 			r := &results[i]
-			fourBytes, err := strconv.ParseUint(r.HexSignature, 0, 0)
-			if err != nil {
-				glog.Errorf("Invalid 4byte signature %+v: %v", r, err)
-				return
-			}
-			fbs := parseSignatureFromText(r.TextSignature)
-			if fbs != nil {
-				fd.db.StoreFourByteSignature(wb, uint32(fourBytes), uint32(r.Id), fbs)
-			} else {
-				glog.Errorf("FourByteSignaturesDownloader invalid signature %s", r.TextSignature)
+			if err := fd.store(wb, r); err != nil {
+				glog.Errorf("%s invalid signature %+v: %v", fd.name, r, err)
 			}
 		}
 
 		if err := fd.db.WriteBatch(wb); err != nil {
-			glog.Errorf("FourByteSignaturesDownloader failed to store signatures, %v", err)
+			glog.Errorf("%s failed to store signatures, %v", fd.name, err)
 		}
 
 	}
-	glog.Infof("FourByteSignaturesDownloader finished")
+	glog.Infof("%s finished", fd.name)
 }
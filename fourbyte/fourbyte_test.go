@@ -53,3 +53,28 @@ func Test_parseSignatureFromText(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseEventSignatureFromText(t *testing.T) {
+	want := bchain.EventSignature{
+		Name: "Approval",
+		Inputs: []bchain.EventSignatureParam{
+			{Type: "address"},
+			{Type: "address"},
+			{Type: "uint256"},
+		},
+		IndexedUnknown: true,
+	}
+	if got := parseEventSignatureFromText("Approval(address,address,uint256)"); !reflect.DeepEqual(*got, want) {
+		t.Errorf("parseEventSignatureFromText() = %v, want %v", *got, want)
+	}
+	if got := parseEventSignatureFromText("Approval"); got != nil {
+		t.Errorf("parseEventSignatureFromText() = %v, want nil", got)
+	}
+	topic, err := parseTopic("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+	if err != nil || len(topic) != 32 || topic[0] != 0x8c || topic[31] != 0x25 {
+		t.Errorf("parseTopic() = %x, %v", topic, err)
+	}
+	if _, err := parseTopic("0x8c5be1e5"); err == nil {
+		t.Error("parseTopic() expected error for short topic")
+	}
+}
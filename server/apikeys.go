@@ -0,0 +1,290 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+// apiKeyHeader is the http header with the API key, the key can be passed also in the apikey query parameter
+const apiKeyHeader = "X-Api-Key"
+
+// interfaces of the public server counted in the API key metrics
+const (
+	apiKeyInterfaceHTTP      = "http"
+	apiKeyInterfaceWebsocket = "websocket"
+	apiKeyInterfaceSocketIO  = "socketio"
+	apiKeyInterfaceJSONRPC   = "jsonrpc"
+	apiKeyInterfaceGRPC      = "grpc"
+)
+
+// apiKeyError is returned when a request is rejected by APIKeys
+type apiKeyError struct {
+	Text       string
+	HTTPStatus int
+	reason     string
+}
+
+func (e *apiKeyError) Error() string {
+	return e.Text
+}
+
+var (
+	errAPIKeyMissing       = &apiKeyError{"Missing API key", http.StatusUnauthorized, "missing"}
+	errAPIKeyUnknown       = &apiKeyError{"Invalid API key", http.StatusUnauthorized, "unknown"}
+	errAPIKeyDisabled      = &apiKeyError{"API key is disabled", http.StatusForbidden, "disabled"}
+	errAPIKeyMethod        = &apiKeyError{"Method not allowed for the API key", http.StatusForbidden, "method"}
+	errAPIKeyOrigin        = &apiKeyError{"Origin not allowed for the API key", http.StatusForbidden, "origin"}
+	errAPIKeyQuotaExceeded = &apiKeyError{"Daily quota of the API key exceeded", http.StatusTooManyRequests, "quota"}
+)
+
+// APIKeyUsage contains the definition of the API key and its usage since the start of Blockbook
+type APIKeyUsage struct {
+	db.APIKey
+	RequestsToday int64 `json:"requestsToday"`
+	Requests      int64 `json:"requests"`
+	Rejected      int64 `json:"rejected"`
+}
+
+type apiKeyState struct {
+	key     db.APIKey
+	methods map[string]struct{}
+	origins map[string]struct{}
+	day     int64
+	usage   APIKeyUsage
+}
+
+// APIKeys authorizes and meters the requests to the public interfaces by the API keys stored in the db
+// the requests without a key are allowed unless the key is required
+type APIKeys struct {
+	db       *db.RocksDB
+	metrics  *common.Metrics
+	required bool
+	mux      sync.Mutex
+	keys     map[string]*apiKeyState
+	// storeMux serializes the read-modify-write of the keys stored in the db by Update and Delete
+	storeMux sync.Mutex
+}
+
+// NewAPIKeys loads the API keys from the db and returns a handle to them
+func NewAPIKeys(d *db.RocksDB, metrics *common.Metrics, required bool) (*APIKeys, error) {
+	k := &APIKeys{
+		db:       d,
+		metrics:  metrics,
+		required: required,
+		keys:     make(map[string]*apiKeyState),
+	}
+	keys, err := d.GetAPIKeys()
+	if err != nil {
+		return nil, err
+	}
+	k.setKeys(keys)
+	glog.Info("API keys: ", len(keys), " keys loaded, key required ", required)
+	return k, nil
+}
+
+// setKeys replaces the keys in memory, keeping the usage of the existing keys
+func (k *APIKeys) setKeys(keys []db.APIKey) {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	states := make(map[string]*apiKeyState, len(keys))
+	for i := range keys {
+		key := keys[i]
+		s, found := k.keys[key.Key]
+		if !found {
+			s = &apiKeyState{}
+		}
+		s.key = key
+		s.usage.APIKey = key
+		s.methods = toSet(key.AllowedMethods, false)
+		s.origins = toSet(key.AllowedOrigins, true)
+		states[key.Key] = s
+	}
+	k.keys = states
+}
+
+func toSet(values []string, lower bool) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+	m := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		if lower {
+			v = strings.ToLower(v)
+		}
+		m[v] = struct{}{}
+	}
+	return m
+}
+
+// List returns the API keys with their usage sorted by name
+func (k *APIKeys) List() []APIKeyUsage {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	today := time.Now().Unix() / 86400
+	list := make([]APIKeyUsage, 0, len(k.keys))
+	for _, s := range k.keys {
+		u := s.usage
+		if s.day != today {
+			u.RequestsToday = 0
+		}
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name == list[j].Name {
+			return list[i].Key < list[j].Key
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Update adds new or replaces existing API keys and stores them to the db
+func (k *APIKeys) Update(keys []db.APIKey) error {
+	k.storeMux.Lock()
+	defer k.storeMux.Unlock()
+	stored, err := k.db.GetAPIKeys()
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]int, len(stored))
+	for i := range stored {
+		byKey[stored[i].Key] = i
+	}
+	for i := range keys {
+		if j, found := byKey[keys[i].Key]; found {
+			stored[j] = keys[i]
+		} else {
+			byKey[keys[i].Key] = len(stored)
+			stored = append(stored, keys[i])
+		}
+	}
+	if err := k.db.StoreAPIKeys(stored); err != nil {
+		return err
+	}
+	k.setKeys(stored)
+	return nil
+}
+
+// Delete removes the API key and returns false if it does not exist
+func (k *APIKeys) Delete(key string) (bool, error) {
+	k.storeMux.Lock()
+	defer k.storeMux.Unlock()
+	stored, err := k.db.GetAPIKeys()
+	if err != nil {
+		return false, err
+	}
+	for i := range stored {
+		if stored[i].Key == key {
+			stored = append(stored[:i], stored[i+1:]...)
+			if err := k.db.StoreAPIKeys(stored); err != nil {
+				return false, err
+			}
+			k.setKeys(stored)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// metricsName returns the name of the key used as a label in the metrics, not revealing the key
+func (s *apiKeyState) metricsName() string {
+	if s.key.Name != "" {
+		return s.key.Name
+	}
+	if len(s.key.Key) > 6 {
+		return s.key.Key[:6] + "..."
+	}
+	return s.key.Key
+}
+
+// authorize checks that the request with the key to the method from the origin is allowed and counts it,
+// returns the rate limit of the key enforced by the RateLimiter
+func (k *APIKeys) authorize(key, method, origin, iface string) (float64, error) {
+	if k == nil {
+		return 0, nil
+	}
+	if key == "" {
+		if k.required {
+			return 0, errAPIKeyMissing
+		}
+		return 0, nil
+	}
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	s, found := k.keys[key]
+	if !found {
+		return 0, errAPIKeyUnknown
+	}
+	err := s.check(method, origin, time.Now())
+	status := "success"
+	if err != nil {
+		status = err.reason
+		s.usage.Rejected++
+	}
+	if k.metrics != nil {
+		k.metrics.APIKeyRequests.With(common.Labels{"key": s.metricsName(), "interface": iface, "status": status}).Inc()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return s.key.RateLimit, nil
+}
+
+func (s *apiKeyState) check(method, origin string, now time.Time) *apiKeyError {
+	if s.key.Disabled {
+		return errAPIKeyDisabled
+	}
+	if s.methods != nil {
+		if _, found := s.methods[method]; !found {
+			return errAPIKeyMethod
+		}
+	}
+	if s.origins != nil {
+		if _, found := s.origins[strings.ToLower(origin)]; !found {
+			return errAPIKeyOrigin
+		}
+	}
+	today := now.Unix() / 86400
+	if s.day != today {
+		s.day = today
+		s.usage.RequestsToday = 0
+	}
+	if s.key.DailyQuota > 0 && s.usage.RequestsToday >= s.key.DailyQuota {
+		return errAPIKeyQuotaExceeded
+	}
+	s.usage.RequestsToday++
+	s.usage.Requests++
+	return nil
+}
+
+// apiKeyFromRequest returns the API key from the http header or from the apikey query parameter
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	return r.URL.Query().Get("apikey")
+}
+
+// apiMethodFromPath returns the name of the REST endpoint used in the allowed methods of the API keys,
+// for example "address" for /api/v2/address/<address>
+func apiMethodFromPath(path string) string {
+	i := strings.Index(path, "api/")
+	if i < 0 {
+		return ""
+	}
+	path = path[i+4:]
+	if strings.HasPrefix(path, "v1/") || strings.HasPrefix(path, "v2/") {
+		path = path[3:]
+	}
+	if i = strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
@@ -0,0 +1,156 @@
+//go:build unittest
+
+package server
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/trezor/blockbook/bchain/coins/eth"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+func Test_apiMethodFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v2/address/0x1234", "address"},
+		{"/api/address/abc", "address"},
+		{"/api/v1/tx/abc", "tx"},
+		{"/api/v2/stats/chain", "stats"},
+		{"/api/v2/candles", "candles"},
+		{"/explorer/api/v2/tickers-list/", "tickers-list"},
+		{"/api/", ""},
+		{"/tx/abc", ""},
+	}
+	for _, tt := range tests {
+		if got := apiMethodFromPath(tt.path); got != tt.want {
+			t.Errorf("apiMethodFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAPIKeys_authorize(t *testing.T) {
+	k := &APIKeys{}
+	k.setKeys([]db.APIKey{
+		{Key: "limited", Name: "limited", RateLimit: 2, DailyQuota: 3},
+		{Key: "restricted", AllowedMethods: []string{"getInfo", "address"}, AllowedOrigins: []string{"https://Example.com"}},
+		{Key: "disabled", Disabled: true},
+	})
+	tests := []struct {
+		name   string
+		key    string
+		method string
+		origin string
+		want   error
+	}{
+		{"anonymous", "", "getInfo", "", nil},
+		{"unknown", "unknown", "getInfo", "", errAPIKeyUnknown},
+		{"disabled", "disabled", "getInfo", "", errAPIKeyDisabled},
+		{"allowed", "restricted", "address", "https://example.com", nil},
+		{"method not allowed", "restricted", "getAccountInfo", "https://example.com", errAPIKeyMethod},
+		{"origin not allowed", "restricted", "getInfo", "https://other.com", errAPIKeyOrigin},
+		{"no origin", "restricted", "getInfo", "", errAPIKeyOrigin},
+		{"limited 1", "limited", "getInfo", "", nil},
+		{"limited 2", "limited", "getInfo", "", nil},
+		{"limited 3", "limited", "getInfo", "", nil},
+		{"quota", "limited", "getInfo", "", errAPIKeyQuotaExceeded},
+	}
+	for _, tt := range tests {
+		if _, err := k.authorize(tt.key, tt.method, tt.origin, apiKeyInterfaceHTTP); err != tt.want {
+			t.Errorf("%s: authorize() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	// the rate limit of the key is returned for the rate limiter
+	if rate, err := k.authorize("restricted", "getInfo", "https://example.com", apiKeyInterfaceHTTP); err != nil || rate != 0 {
+		t.Errorf("authorize() = %v, %v, want 0, nil", rate, err)
+	}
+	usage := k.List()
+	if len(usage) != 3 || usage[2].Name != "limited" || usage[2].Requests != 3 || usage[2].Rejected != 1 {
+		t.Errorf("List() = %+v", usage)
+	}
+	// the key is required
+	k.required = true
+	if _, err := k.authorize("", "getInfo", "", apiKeyInterfaceHTTP); err != errAPIKeyMissing {
+		t.Errorf("authorize() = %v, want %v", err, errAPIKeyMissing)
+	}
+}
+
+func Test_checkSimulationAllowed(t *testing.T) {
+	tests := []struct {
+		mode    string
+		apiKey  string
+		wantErr bool
+	}{
+		{"", "", true},
+		{"", "key", true},
+		{"disabled", "key", true},
+		{simulateTransactionAPIKey, "", true},
+		{simulateTransactionAPIKey, "key", false},
+		{simulateTransactionPublic, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+"/"+tt.apiKey, func(t *testing.T) {
+			err := checkSimulationAllowed(&common.InternalState{SimulateTransaction: tt.mode}, tt.apiKey)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkSimulationAllowed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIKeys_UpdateDeleteConcurrent(t *testing.T) {
+	tmp, err := os.MkdirTemp("", "testdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	d, err := db.NewRocksDB(tmp, 100000, -1, eth.NewEthereumParser(1, false), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	k, err := NewAPIKeys(d, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Update([]db.APIKey{{Key: "deleted"}}); err != nil {
+		t.Fatal(err)
+	}
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := k.Update([]db.APIKey{{Key: "key" + strconv.Itoa(i)}}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if found, err := k.Delete("deleted"); err != nil || !found {
+			t.Error("Delete() = ", found, err)
+		}
+	}()
+	wg.Wait()
+	// none of the concurrent writes is lost
+	stored, err := d.GetAPIKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != n || len(k.List()) != n {
+		t.Errorf("stored %d keys, in memory %d keys, want %d", len(stored), len(k.List()), n)
+	}
+	for _, key := range stored {
+		if key.Key == "deleted" {
+			t.Error("deleted key is stored")
+		}
+	}
+}
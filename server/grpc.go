@@ -0,0 +1,574 @@
+package server
+
+import (
+	"context"
+	"net"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+	"github.com/trezor/blockbook/fiat"
+	"github.com/trezor/blockbook/server/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcAPIKeyMetadata is the metadata key with the API key, gRPC metadata keys are lowercase
+const grpcAPIKeyMetadata = "x-api-key"
+
+// GRPCServer is the gRPC interface to the core queries of the Worker and to the subscriptions
+type GRPCServer struct {
+	pb.UnimplementedBlockbookServer
+	binding                    string
+	certFiles                  string
+	server                     *grpc.Server
+	chainParser                bchain.BlockChainParser
+	api                        *api.Worker
+	metrics                    *common.Metrics
+	is                         *common.InternalState
+	guard                      requestGuard
+	quit                       chan struct{}
+	newBlockSubscriptions      map[*grpcSubscriber[pb.NewBlock]]struct{}
+	newBlockSubscriptionsLock  sync.Mutex
+	addressSubscriptions       map[string]map[*grpcSubscriber[pb.AddressTransaction]]string
+	addressSubscriptionsLock   sync.Mutex
+	fiatRatesSubscriptions     map[*grpcSubscriber[pb.FiatTicker]]*pb.SubscribeFiatRatesRequest
+	fiatRatesSubscriptionsLock sync.Mutex
+}
+
+// NewGRPCServer creates new gRPC server, certFiles enable TLS in the same way as in the public server
+func NewGRPCServer(binding string, certFiles string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState, fiatRates *fiat.FiatRates) (*GRPCServer, error) {
+	api, err := api.NewWorker(db, chain, mempool, txCache, metrics, is, fiatRates)
+	if err != nil {
+		return nil, err
+	}
+	s := &GRPCServer{
+		binding:                binding,
+		certFiles:              certFiles,
+		chainParser:            chain.GetChainParser(),
+		api:                    api,
+		metrics:                metrics,
+		is:                     is,
+		guard:                  requestGuard{rateLimiter: NewRateLimiter(is.RateLimits, metrics)},
+		quit:                   make(chan struct{}),
+		newBlockSubscriptions:  make(map[*grpcSubscriber[pb.NewBlock]]struct{}),
+		addressSubscriptions:   make(map[string]map[*grpcSubscriber[pb.AddressTransaction]]string),
+		fiatRatesSubscriptions: make(map[*grpcSubscriber[pb.FiatTicker]]*pb.SubscribeFiatRatesRequest),
+	}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	}
+	if certFiles != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFiles+".crt", certFiles+".key")
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	s.server = grpc.NewServer(opts...)
+	pb.RegisterBlockbookServer(s.server, s)
+	return s, nil
+}
+
+// SetAPIKeys sets the API keys used to authorize the requests
+func (s *GRPCServer) SetAPIKeys(k *APIKeys) {
+	s.guard.apiKeys = k
+}
+
+// SetRateLimiter replaces the own rate limiter of the server by the limiter shared with the public server,
+// so that the client is limited by the total cost of its requests to all interfaces
+func (s *GRPCServer) SetRateLimiter(l *RateLimiter) {
+	s.guard.rateLimiter = l
+}
+
+// Run starts the server
+func (s *GRPCServer) Run() error {
+	lis, err := net.Listen("tcp", s.binding)
+	if err != nil {
+		return err
+	}
+	glog.Info("grpc server starting to listen on ", s.binding)
+	return s.server.Serve(lis)
+}
+
+// Shutdown closes the subscriptions and stops the server, waiting for the running requests until ctx is done
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	glog.Infof("grpc server: shutdown")
+	close(s.quit)
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+type grpcClientKey struct{}
+
+// grpcClient identifies the client of the request for charging its cost
+type grpcClient struct {
+	id      string
+	keyRate float64
+}
+
+// grpcServerStream overrides the context of the stream
+type grpcServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcMethodName returns the name of the method from the full name /blockbook.Blockbook/<method>
+func grpcMethodName(fullMethod string) string {
+	return fullMethod[strings.LastIndexByte(fullMethod, '/')+1:]
+}
+
+// admit checks the API key and the rate limit of the request and stores the client to the context
+func (s *GRPCServer) admit(ctx context.Context, method string) (context.Context, error) {
+	var key, origin, ip string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(grpcAPIKeyMetadata); len(v) > 0 {
+			key = v[0]
+		}
+		if v := md.Get("origin"); len(v) > 0 {
+			origin = v[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	client, keyRate, err := s.guard.admit(key, ip, method, origin, apiKeyInterfaceGRPC)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, grpcClientKey{}, &grpcClient{id: client, keyRate: keyRate}), nil
+}
+
+// charge consumes the additional cost of the request computed from the result of the Worker
+func (s *GRPCServer) charge(ctx context.Context, method string, result interface{}) {
+	if c, ok := ctx.Value(grpcClientKey{}).(*grpcClient); ok {
+		s.guard.charge(c.id, c.keyRate, method, result)
+	}
+}
+
+// grpcError converts the error to the gRPC status error
+func grpcError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch e := err.(type) {
+	case *rateLimitError:
+		return status.Error(codes.ResourceExhausted, e.Error())
+	case *apiKeyError:
+		switch e.HTTPStatus {
+		case 401:
+			return status.Error(codes.Unauthenticated, e.Error())
+		case 429:
+			return status.Error(codes.ResourceExhausted, e.Error())
+		}
+		return status.Error(codes.PermissionDenied, e.Error())
+	case *api.APIError:
+		if e.Public {
+			return status.Error(codes.InvalidArgument, e.Error())
+		}
+	}
+	glog.Error("grpc ", method, ": ", errors.ErrorStack(err))
+	return status.Error(codes.Internal, err.Error())
+}
+
+// retryAfterTrailer returns the metadata with the time to wait if the error is a rate limit error
+func retryAfterTrailer(err error) metadata.MD {
+	if e, ok := err.(*rateLimitError); ok {
+		return metadata.Pairs("retry-after", strconv.Itoa(e.retryAfter))
+	}
+	return nil
+}
+
+func (s *GRPCServer) countRequest(method string, err error) {
+	st := "success"
+	if err != nil {
+		st = "failure"
+	}
+	s.metrics.GRPCRequests.With(common.Labels{"method": method, "status": st}).Inc()
+}
+
+func (s *GRPCServer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	method := grpcMethodName(info.FullMethod)
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("grpc ", method, " recovered from panic: ", r)
+			debug.PrintStack()
+			resp, err = nil, status.Error(codes.Internal, "Internal error")
+		}
+		s.countRequest(method, err)
+	}()
+	if ctx, err = s.admit(ctx, method); err != nil {
+		if md := retryAfterTrailer(err); md != nil {
+			grpc.SetTrailer(ctx, md)
+		}
+		return nil, grpcError(method, err)
+	}
+	resp, err = handler(ctx, req)
+	return resp, grpcError(method, err)
+}
+
+func (s *GRPCServer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	method := grpcMethodName(info.FullMethod)
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("grpc ", method, " recovered from panic: ", r)
+			debug.PrintStack()
+			err = status.Error(codes.Internal, "Internal error")
+		}
+		s.countRequest(method, err)
+	}()
+	ctx, err := s.admit(ss.Context(), method)
+	if err != nil {
+		if md := retryAfterTrailer(err); md != nil {
+			ss.SetTrailer(md)
+		}
+		return grpcError(method, err)
+	}
+	return grpcError(method, handler(srv, &grpcServerStream{ServerStream: ss, ctx: ctx}))
+}
+
+// GetTransaction returns the transaction
+func (s *GRPCServer) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.Transaction, error) {
+	tx, err := s.api.GetTransaction(req.Txid, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return grpcTx(tx), nil
+}
+
+func grpcAddressFilter(fromHeight, toHeight uint32, contract string, tokens pb.TokensToReturn) *api.AddressFilter {
+	filter := api.AddressFilter{
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+		Contract:   contract,
+		Vout:       api.AddressFilterVoutOff,
+	}
+	switch tokens {
+	case pb.TokensToReturn_TOKENS_TO_RETURN_USED:
+		filter.TokensToReturn = api.TokensToReturnUsed
+	case pb.TokensToReturn_TOKENS_TO_RETURN_NONZERO_BALANCE:
+		filter.TokensToReturn = api.TokensToReturnNonzeroBalance
+	default:
+		filter.TokensToReturn = api.TokensToReturnDerived
+	}
+	return &filter
+}
+
+func grpcAccountDetails(details pb.AccountDetails) api.AccountDetails {
+	switch details {
+	case pb.AccountDetails_ACCOUNT_DETAILS_TOKENS:
+		return api.AccountDetailsTokens
+	case pb.AccountDetails_ACCOUNT_DETAILS_TOKEN_BALANCES:
+		return api.AccountDetailsTokenBalances
+	case pb.AccountDetails_ACCOUNT_DETAILS_TXIDS:
+		return api.AccountDetailsTxidHistory
+	case pb.AccountDetails_ACCOUNT_DETAILS_TXS_LIGHT:
+		return api.AccountDetailsTxHistoryLight
+	case pb.AccountDetails_ACCOUNT_DETAILS_TXS:
+		return api.AccountDetailsTxHistory
+	}
+	return api.AccountDetailsBasic
+}
+
+func grpcPageSize(pageSize uint32) int {
+	if pageSize == 0 {
+		return txsOnPage
+	}
+	return int(pageSize)
+}
+
+// GetAddress returns the balances and transactions of the address
+func (s *GRPCServer) GetAddress(ctx context.Context, req *pb.GetAddressRequest) (*pb.Address, error) {
+	filter := grpcAddressFilter(req.FromHeight, req.ToHeight, req.Contract, req.Tokens)
+	a, err := s.api.GetAddress(req.Address, int(req.Page), grpcPageSize(req.PageSize), grpcAccountDetails(req.Details), filter, strings.ToLower(req.SecondaryCurrency))
+	if err != nil {
+		return nil, err
+	}
+	s.charge(ctx, "GetAddress", a)
+	return grpcAddress(a), nil
+}
+
+// GetXpub returns the balances and transactions of the xpub
+func (s *GRPCServer) GetXpub(ctx context.Context, req *pb.GetXpubRequest) (*pb.Address, error) {
+	filter := grpcAddressFilter(req.FromHeight, req.ToHeight, req.Contract, req.Tokens)
+	a, err := s.api.GetXpubAddress(req.Xpub, int(req.Page), grpcPageSize(req.PageSize), grpcAccountDetails(req.Details), filter, int(req.Gap), strings.ToLower(req.SecondaryCurrency))
+	if err != nil {
+		return nil, err
+	}
+	s.charge(ctx, "GetXpub", a)
+	return grpcAddress(a), nil
+}
+
+// GetUtxos returns the unspent outputs of the xpub or of the address
+func (s *GRPCServer) GetUtxos(ctx context.Context, req *pb.GetUtxosRequest) (*pb.Utxos, error) {
+	utxos, err := s.api.GetXpubUtxo(req.Descriptor_, req.OnlyConfirmed, int(req.Gap))
+	if err != nil {
+		if utxos, err = s.api.GetAddressUtxo(req.Descriptor_, req.OnlyConfirmed); err != nil {
+			return nil, err
+		}
+	}
+	s.charge(ctx, "GetUtxos", utxos)
+	return grpcUtxos(utxos), nil
+}
+
+// GetBlock returns the block with its transactions
+func (s *GRPCServer) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb.Block, error) {
+	pageSize := int(req.PageSize)
+	if pageSize == 0 {
+		pageSize = txsInAPI
+	}
+	b, err := s.api.GetBlock(req.Id, int(req.Page), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	s.charge(ctx, "GetBlock", b)
+	return grpcBlock(b), nil
+}
+
+// EstimateFee returns the fee per unit for the requested numbers of blocks
+func (s *GRPCServer) EstimateFee(ctx context.Context, req *pb.EstimateFeeRequest) (*pb.EstimateFeeResponse, error) {
+	res := &pb.EstimateFeeResponse{FeePerUnit: make([]string, len(req.Blocks))}
+	for i, b := range req.Blocks {
+		fee, err := s.api.EstimateFee(int(b), req.Conservative)
+		if err != nil {
+			return nil, err
+		}
+		res.FeePerUnit[i] = fee.String()
+	}
+	return res, nil
+}
+
+// GetCurrentFiatRates returns the current fiat rates of the coin or of the token
+func (s *GRPCServer) GetCurrentFiatRates(ctx context.Context, req *pb.GetCurrentFiatRatesRequest) (*pb.FiatTicker, error) {
+	ticker, err := s.api.GetCurrentFiatRates(req.Currencies, strings.ToLower(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	return grpcFiatTicker(ticker), nil
+}
+
+// GetFiatRatesForTimestamps returns the fiat rates of the coin or of the token closest to the timestamps
+func (s *GRPCServer) GetFiatRatesForTimestamps(ctx context.Context, req *pb.GetFiatRatesForTimestampsRequest) (*pb.FiatTickers, error) {
+	tickers, err := s.api.GetFiatRatesForTimestamps(req.Timestamps, req.Currencies, strings.ToLower(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.FiatTickers{Tickers: make([]*pb.FiatTicker, len(tickers.Tickers))}
+	for i := range tickers.Tickers {
+		res.Tickers[i] = grpcFiatTicker(&tickers.Tickers[i])
+	}
+	return res, nil
+}
+
+// grpcSubscriber buffers the messages of a subscription stream, a subscriber not keeping up with the messages is disconnected
+type grpcSubscriber[T any] struct {
+	out      chan *T
+	overflow chan struct{}
+	once     sync.Once
+}
+
+func newGRPCSubscriber[T any]() *grpcSubscriber[T] {
+	return &grpcSubscriber[T]{
+		out:      make(chan *T, outChannelSize),
+		overflow: make(chan struct{}),
+	}
+}
+
+func (c *grpcSubscriber[T]) send(m *T) {
+	select {
+	case c.out <- m:
+	default:
+		c.once.Do(func() { close(c.overflow) })
+	}
+}
+
+// serveGRPCSubscription sends the messages of the subscriber to the stream until the client or the server closes it
+func serveGRPCSubscription[T any](s *GRPCServer, stream grpc.ServerStreamingServer[T], c *grpcSubscriber[T], method string) error {
+	s.metrics.GRPCSubscriptions.With(common.Labels{"method": method}).Inc()
+	defer s.metrics.GRPCSubscriptions.With(common.Labels{"method": method}).Dec()
+	for {
+		select {
+		case m := <-c.out:
+			if err := stream.Send(m); err != nil {
+				return err
+			}
+		case <-c.overflow:
+			return status.Error(codes.ResourceExhausted, "Subscription closed, the client is not reading the messages fast enough")
+		case <-s.quit:
+			return status.Error(codes.Unavailable, "Server is shutting down")
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// SubscribeNewBlock streams the new blocks
+func (s *GRPCServer) SubscribeNewBlock(req *pb.SubscribeNewBlockRequest, stream grpc.ServerStreamingServer[pb.NewBlock]) error {
+	c := newGRPCSubscriber[pb.NewBlock]()
+	s.newBlockSubscriptionsLock.Lock()
+	s.newBlockSubscriptions[c] = struct{}{}
+	s.newBlockSubscriptionsLock.Unlock()
+	defer func() {
+		s.newBlockSubscriptionsLock.Lock()
+		delete(s.newBlockSubscriptions, c)
+		s.newBlockSubscriptionsLock.Unlock()
+	}()
+	return serveGRPCSubscription(s, stream, c, "SubscribeNewBlock")
+}
+
+// SubscribeAddresses streams the new mempool transactions of the addresses
+func (s *GRPCServer) SubscribeAddresses(req *pb.SubscribeAddressesRequest, stream grpc.ServerStreamingServer[pb.AddressTransaction]) error {
+	if len(req.Addresses) == 0 {
+		return status.Error(codes.InvalidArgument, "Missing addresses")
+	}
+	addrDescs := make(map[string]string, len(req.Addresses))
+	for _, a := range req.Addresses {
+		addrDesc, err := s.chainParser.GetAddrDescFromAddress(a)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "Invalid address "+a)
+		}
+		addrDescs[string(addrDesc)] = a
+	}
+	c := newGRPCSubscriber[pb.AddressTransaction]()
+	s.addressSubscriptionsLock.Lock()
+	for sad, a := range addrDescs {
+		as, ok := s.addressSubscriptions[sad]
+		if !ok {
+			as = make(map[*grpcSubscriber[pb.AddressTransaction]]string)
+			s.addressSubscriptions[sad] = as
+		}
+		as[c] = a
+	}
+	s.addressSubscriptionsLock.Unlock()
+	defer func() {
+		s.addressSubscriptionsLock.Lock()
+		for sad := range addrDescs {
+			if as, ok := s.addressSubscriptions[sad]; ok {
+				delete(as, c)
+				if len(as) == 0 {
+					delete(s.addressSubscriptions, sad)
+				}
+			}
+		}
+		s.addressSubscriptionsLock.Unlock()
+	}()
+	return serveGRPCSubscription(s, stream, c, "SubscribeAddresses")
+}
+
+// SubscribeFiatRates streams the new fiat rates tickers of the currency, or of all currencies
+func (s *GRPCServer) SubscribeFiatRates(req *pb.SubscribeFiatRatesRequest, stream grpc.ServerStreamingServer[pb.FiatTicker]) error {
+	r := &pb.SubscribeFiatRatesRequest{Currency: strings.ToLower(req.Currency)}
+	for _, t := range req.Tokens {
+		r.Tokens = append(r.Tokens, strings.ToLower(t))
+	}
+	c := newGRPCSubscriber[pb.FiatTicker]()
+	s.fiatRatesSubscriptionsLock.Lock()
+	s.fiatRatesSubscriptions[c] = r
+	s.fiatRatesSubscriptionsLock.Unlock()
+	defer func() {
+		s.fiatRatesSubscriptionsLock.Lock()
+		delete(s.fiatRatesSubscriptions, c)
+		s.fiatRatesSubscriptionsLock.Unlock()
+	}()
+	return serveGRPCSubscription(s, stream, c, "SubscribeFiatRates")
+}
+
+// OnNewBlock is a callback that sends the new block to the subscribers
+func (s *GRPCServer) OnNewBlock(hash string, height uint32) {
+	s.newBlockSubscriptionsLock.Lock()
+	defer s.newBlockSubscriptionsLock.Unlock()
+	m := &pb.NewBlock{Height: height, Hash: hash}
+	for c := range s.newBlockSubscriptions {
+		c.send(m)
+	}
+}
+
+// OnNewTx is a callback that sends the new mempool tx to the subscribers of its addresses
+func (s *GRPCServer) OnNewTx(tx *bchain.MempoolTx) {
+	s.addressSubscriptionsLock.Lock()
+	subscribed := make(map[string]struct{})
+	forEachMempoolTxAddrDesc(s.chainParser, tx, func(sad string) {
+		if _, ok := s.addressSubscriptions[sad]; ok {
+			subscribed[sad] = struct{}{}
+		}
+	})
+	s.addressSubscriptionsLock.Unlock()
+	if len(subscribed) > 0 {
+		go s.onNewTxAsync(tx, subscribed)
+	}
+}
+
+func (s *GRPCServer) onNewTxAsync(tx *bchain.MempoolTx, subscribed map[string]struct{}) {
+	atx, err := s.api.GetTransactionFromMempoolTx(tx)
+	if err != nil {
+		glog.Error("GetTransactionFromMempoolTx error ", err, " for ", tx.Txid)
+		return
+	}
+	ptx := grpcTx(atx)
+	s.addressSubscriptionsLock.Lock()
+	defer s.addressSubscriptionsLock.Unlock()
+	for sad := range subscribed {
+		for c, a := range s.addressSubscriptions[sad] {
+			c.send(&pb.AddressTransaction{Address: a, Tx: ptx})
+		}
+	}
+}
+
+// OnNewFiatRatesTicker is a callback that sends the new rates to the subscribers of the currencies
+func (s *GRPCServer) OnNewFiatRatesTicker(ticker *common.CurrencyRatesTicker) {
+	s.fiatRatesSubscriptionsLock.Lock()
+	defer s.fiatRatesSubscriptionsLock.Unlock()
+	for c, r := range s.fiatRatesSubscriptions {
+		m := &pb.FiatTicker{Timestamp: ticker.Timestamp.Unix()}
+		if r.Currency == "" {
+			m.Rates = ticker.Rates
+		} else {
+			rate, found := ticker.Rates[r.Currency]
+			if !found {
+				continue
+			}
+			m.Rates = map[string]float32{r.Currency: rate}
+			for _, token := range r.Tokens {
+				if rate := ticker.TokenRateInCurrency(token, r.Currency); rate > 0 {
+					if m.TokenRates == nil {
+						m.TokenRates = make(map[string]float32)
+					}
+					m.TokenRates[token] = rate
+				}
+			}
+		}
+		c.send(m)
+	}
+}
@@ -0,0 +1,81 @@
+//go:build unittest
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/server/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_grpcError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"rate limit", &rateLimitError{retryAfter: 2}, codes.ResourceExhausted},
+		{"missing key", errAPIKeyMissing, codes.Unauthenticated},
+		{"method not allowed", errAPIKeyMethod, codes.PermissionDenied},
+		{"quota", errAPIKeyQuotaExceeded, codes.ResourceExhausted},
+		{"public api error", api.NewAPIError("Invalid address", true), codes.InvalidArgument},
+		{"status", status.Error(codes.NotFound, "not found"), codes.NotFound},
+	}
+	for _, tt := range tests {
+		if got := status.Code(grpcError("test", tt.err)); got != tt.want {
+			t.Errorf("%s: grpcError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := grpcMethodName("/blockbook.Blockbook/GetAddress"); got != "GetAddress" {
+		t.Errorf("grpcMethodName() = %v, want GetAddress", got)
+	}
+}
+
+func TestGRPCServer_subscriptions(t *testing.T) {
+	s := &GRPCServer{
+		newBlockSubscriptions:  make(map[*grpcSubscriber[pb.NewBlock]]struct{}),
+		fiatRatesSubscriptions: make(map[*grpcSubscriber[pb.FiatTicker]]*pb.SubscribeFiatRatesRequest),
+	}
+	b := newGRPCSubscriber[pb.NewBlock]()
+	s.newBlockSubscriptions[b] = struct{}{}
+	s.OnNewBlock("abcd", 100)
+	if m := <-b.out; m.Height != 100 || m.Hash != "abcd" {
+		t.Errorf("OnNewBlock() sent %v", m)
+	}
+	// the subscriber not reading the messages overflows
+	for i := 0; i <= outChannelSize; i++ {
+		s.OnNewBlock("abcd", 100)
+	}
+	select {
+	case <-b.overflow:
+	default:
+		t.Error("subscriber did not overflow")
+	}
+
+	usd := newGRPCSubscriber[pb.FiatTicker]()
+	s.fiatRatesSubscriptions[usd] = &pb.SubscribeFiatRatesRequest{Currency: "usd", Tokens: []string{"0xa"}}
+	all := newGRPCSubscriber[pb.FiatTicker]()
+	s.fiatRatesSubscriptions[all] = &pb.SubscribeFiatRatesRequest{}
+	czk := newGRPCSubscriber[pb.FiatTicker]()
+	s.fiatRatesSubscriptions[czk] = &pb.SubscribeFiatRatesRequest{Currency: "czk"}
+	s.OnNewFiatRatesTicker(&common.CurrencyRatesTicker{
+		Timestamp:  time.Unix(1700000000, 0),
+		Rates:      map[string]float32{"usd": 2, "eur": 1.5},
+		TokenRates: map[string]float32{"0xa": 0.5},
+	})
+	if m := <-usd.out; m.Timestamp != 1700000000 || len(m.Rates) != 1 || m.Rates["usd"] != 2 || m.TokenRates["0xa"] != 1 {
+		t.Errorf("OnNewFiatRatesTicker() usd sent %v", m)
+	}
+	if m := <-all.out; len(m.Rates) != 2 || m.TokenRates != nil {
+		t.Errorf("OnNewFiatRatesTicker() all sent %v", m)
+	}
+	if len(czk.out) != 0 {
+		t.Error("OnNewFiatRatesTicker() sent ticker without the currency")
+	}
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"math/big"
+
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/server/pb"
+)
+
+// conversion of the api types to the protobuf messages of the gRPC interface
+
+func grpcBigInt(b *big.Int) string {
+	if b == nil {
+		return ""
+	}
+	return b.String()
+}
+
+func grpcMultiTokenValues(values []api.MultiTokenValue) []*pb.MultiTokenValue {
+	if len(values) == 0 {
+		return nil
+	}
+	res := make([]*pb.MultiTokenValue, len(values))
+	for i := range values {
+		res[i] = &pb.MultiTokenValue{Id: values[i].Id.String(), Value: values[i].Value.String()}
+	}
+	return res
+}
+
+func grpcEthereumSpecific(e *api.EthereumSpecific) *pb.EthereumSpecific {
+	if e == nil {
+		return nil
+	}
+	res := &pb.EthereumSpecific{
+		Type:                 int32(e.Type),
+		CreatedContract:      e.CreatedContract,
+		Status:               int32(e.Status),
+		Error:                e.Error,
+		Nonce:                e.Nonce,
+		GasLimit:             grpcBigInt(e.GasLimit),
+		GasUsed:              grpcBigInt(e.GasUsed),
+		GasPrice:             e.GasPrice.String(),
+		MaxPriorityFeePerGas: e.MaxPriorityFeePerGas.String(),
+		MaxFeePerGas:         e.MaxFeePerGas.String(),
+		BaseFeePerGas:        e.BaseFeePerGas.String(),
+		Data:                 e.Data,
+	}
+	for i := range e.InternalTransfers {
+		t := &e.InternalTransfers[i]
+		res.InternalTransfers = append(res.InternalTransfers, &pb.EthereumInternalTransfer{
+			Type:  int32(t.Type),
+			From:  t.From,
+			To:    t.To,
+			Value: t.Value.String(),
+		})
+	}
+	return res
+}
+
+func grpcTx(tx *api.Tx) *pb.Transaction {
+	if tx == nil {
+		return nil
+	}
+	res := &pb.Transaction{
+		Txid:             tx.Txid,
+		Version:          tx.Version,
+		LockTime:         tx.Locktime,
+		Vin:              make([]*pb.Vin, len(tx.Vin)),
+		Vout:             make([]*pb.Vout, len(tx.Vout)),
+		BlockHash:        tx.Blockhash,
+		BlockHeight:      int64(tx.Blockheight),
+		Confirmations:    tx.Confirmations,
+		BlockTime:        tx.Blocktime,
+		Size:             int64(tx.Size),
+		Vsize:            int64(tx.VSize),
+		Value:            tx.ValueOutSat.String(),
+		ValueIn:          tx.ValueInSat.String(),
+		Fees:             tx.FeesSat.String(),
+		Hex:              tx.Hex,
+		Rbf:              tx.Rbf,
+		EthereumSpecific: grpcEthereumSpecific(tx.EthereumSpecific),
+		CoinSpecificData: tx.CoinSpecificData,
+	}
+	for i := range tx.Vin {
+		vin := &tx.Vin[i]
+		res.Vin[i] = &pb.Vin{
+			N:         int32(vin.N),
+			Txid:      vin.Txid,
+			Vout:      vin.Vout,
+			Sequence:  vin.Sequence,
+			Addresses: vin.Addresses,
+			IsAddress: vin.IsAddress,
+			IsOwn:     vin.IsOwn,
+			Value:     vin.ValueSat.String(),
+			Hex:       vin.Hex,
+			Asm:       vin.Asm,
+			Coinbase:  vin.Coinbase,
+		}
+	}
+	for i := range tx.Vout {
+		vout := &tx.Vout[i]
+		res.Vout[i] = &pb.Vout{
+			N:           int32(vout.N),
+			Value:       vout.ValueSat.String(),
+			Spent:       vout.Spent,
+			SpentTxid:   vout.SpentTxID,
+			SpentIndex:  int32(vout.SpentIndex),
+			SpentHeight: int32(vout.SpentHeight),
+			Hex:         vout.Hex,
+			Asm:         vout.Asm,
+			Addresses:   vout.Addresses,
+			IsAddress:   vout.IsAddress,
+			IsOwn:       vout.IsOwn,
+			Type:        vout.Type,
+		}
+	}
+	for i := range tx.TokenTransfers {
+		t := &tx.TokenTransfers[i]
+		res.TokenTransfers = append(res.TokenTransfers, &pb.TokenTransfer{
+			Standard:         string(t.Standard),
+			From:             t.From,
+			To:               t.To,
+			Contract:         t.Contract,
+			Name:             t.Name,
+			Symbol:           t.Symbol,
+			Decimals:         int32(t.Decimals),
+			Value:            t.Value.String(),
+			MultiTokenValues: grpcMultiTokenValues(t.MultiTokenValues),
+		})
+	}
+	return res
+}
+
+func grpcTxs(txs []*api.Tx) []*pb.Transaction {
+	if len(txs) == 0 {
+		return nil
+	}
+	res := make([]*pb.Transaction, len(txs))
+	for i := range txs {
+		res[i] = grpcTx(txs[i])
+	}
+	return res
+}
+
+func grpcAddress(a *api.Address) *pb.Address {
+	res := &pb.Address{
+		Page:                 int32(a.Page),
+		TotalPages:           int32(a.TotalPages),
+		ItemsOnPage:          int32(a.ItemsOnPage),
+		Address:              a.AddrStr,
+		Balance:              a.BalanceSat.String(),
+		TotalReceived:        a.TotalReceivedSat.String(),
+		TotalSent:            a.TotalSentSat.String(),
+		UnconfirmedBalance:   a.UnconfirmedBalanceSat.String(),
+		UnconfirmedTxs:       int32(a.UnconfirmedTxs),
+		Txs:                  int32(a.Txs),
+		Transactions:         grpcTxs(a.Transactions),
+		Txids:                a.Txids,
+		Nonce:                a.Nonce,
+		UsedTokens:           int32(a.UsedTokens),
+		SecondaryCurrency:    a.SecondaryCurrency,
+		SecondaryValue:       a.SecondaryValue,
+		TokensBaseValue:      a.TokensBaseValue,
+		TokensSecondaryValue: a.TokensSecondaryValue,
+		TotalBaseValue:       a.TotalBaseValue,
+		TotalSecondaryValue:  a.TotalSecondaryValue,
+	}
+	for i := range a.Tokens {
+		t := &a.Tokens[i]
+		pt := &pb.Token{
+			Standard:         string(t.Standard),
+			Name:             t.Name,
+			Path:             t.Path,
+			Contract:         t.Contract,
+			Transfers:        int32(t.Transfers),
+			Symbol:           t.Symbol,
+			Decimals:         int32(t.Decimals),
+			Balance:          t.BalanceSat.String(),
+			BaseValue:        t.BaseValue,
+			SecondaryValue:   t.SecondaryValue,
+			MultiTokenValues: grpcMultiTokenValues(t.MultiTokenValues),
+			TotalReceived:    t.TotalReceivedSat.String(),
+			TotalSent:        t.TotalSentSat.String(),
+		}
+		for j := range t.Ids {
+			pt.Ids = append(pt.Ids, t.Ids[j].String())
+		}
+		res.Tokens = append(res.Tokens, pt)
+	}
+	return res
+}
+
+func grpcUtxos(utxos api.Utxos) *pb.Utxos {
+	res := &pb.Utxos{Utxos: make([]*pb.Utxo, len(utxos))}
+	for i := range utxos {
+		u := &utxos[i]
+		res.Utxos[i] = &pb.Utxo{
+			Txid:          u.Txid,
+			Vout:          u.Vout,
+			Value:         u.AmountSat.String(),
+			Height:        int64(u.Height),
+			Confirmations: int64(u.Confirmations),
+			Address:       u.Address,
+			Path:          u.Path,
+			LockTime:      u.Locktime,
+			Coinbase:      u.Coinbase,
+		}
+	}
+	return res
+}
+
+func grpcBlock(b *api.Block) *pb.Block {
+	return &pb.Block{
+		Page:              int32(b.Page),
+		TotalPages:        int32(b.TotalPages),
+		ItemsOnPage:       int32(b.ItemsOnPage),
+		Hash:              b.Hash,
+		PreviousBlockHash: b.Prev,
+		NextBlockHash:     b.Next,
+		Height:            b.Height,
+		Confirmations:     int64(b.Confirmations),
+		Size:              int64(b.Size),
+		Time:              b.Time,
+		Version:           string(b.Version),
+		MerkleRoot:        b.MerkleRoot,
+		Nonce:             b.Nonce,
+		Bits:              b.Bits,
+		Difficulty:        b.Difficulty,
+		TxCount:           int32(b.TxCount),
+		Txs:               grpcTxs(b.Transactions),
+	}
+}
+
+func grpcFiatTicker(t *api.FiatTicker) *pb.FiatTicker {
+	return &pb.FiatTicker{
+		Timestamp: t.Timestamp,
+		Rates:     t.Rates,
+		Error:     t.Error,
+	}
+}
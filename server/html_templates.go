@@ -53,6 +53,7 @@ type htmlTemplates[TD any] struct {
 	newTemplateDataWithError func(error *api.APIError, r *http.Request) *TD
 	parseTemplates           func() []*template.Template
 	postHtmlTemplateHandler  func(data *TD, w http.ResponseWriter, r *http.Request)
+	guard                    requestGuard
 }
 
 func (s *htmlTemplates[TD]) jsonHandler(handler func(r *http.Request, apiVersion int) (interface{}, error), apiVersion int) func(w http.ResponseWriter, r *http.Request) {
@@ -63,7 +64,7 @@ func (s *htmlTemplates[TD]) jsonHandler(handler func(r *http.Request, apiVersion
 	handlerName := getFunctionName(handler)
 	return func(w http.ResponseWriter, r *http.Request) {
 		var data interface{}
-		var err error
+		var err, guardErr error
 		defer func() {
 			if e := recover(); e != nil {
 				glog.Error(handlerName, " recovered from panic: ", e)
@@ -76,6 +77,7 @@ func (s *htmlTemplates[TD]) jsonHandler(handler func(r *http.Request, apiVersion
 			}
 			w.Header().Set("Content-Type", "application/json; charset=utf-8")
 			w.Header().Set("Content-Security-Policy", getContentSecurityPolicy())
+			retryAfterHeader(w, guardErr)
 			if e, isError := data.(jsonError); isError {
 				w.WriteHeader(e.HTTPStatus)
 			}
@@ -90,7 +92,20 @@ func (s *htmlTemplates[TD]) jsonHandler(handler func(r *http.Request, apiVersion
 		if s.metrics != nil {
 			s.metrics.ExplorerPendingRequests.With((common.Labels{"method": handlerName})).Inc()
 		}
+		method := apiMethodFromPath(r.URL.Path)
+		client, keyRate, guardErr := s.guard.admit(apiKeyFromRequest(r), getIP(r), method, r.Header.Get("Origin"), apiKeyInterfaceHTTP)
+		if guardErr != nil {
+			if e, ok := guardErr.(*apiKeyError); ok {
+				data = jsonError{e.Text, e.HTTPStatus}
+			} else {
+				data = jsonError{guardErr.Error(), http.StatusTooManyRequests}
+			}
+			return
+		}
 		data, err = handler(r, apiVersion)
+		if err == nil {
+			s.guard.charge(client, keyRate, method, data)
+		}
 		if err != nil || data == nil {
 			if apiErr, ok := err.(*api.APIError); ok {
 				if apiErr.Public {
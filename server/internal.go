@@ -11,12 +11,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/juju/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/trezor/blockbook/api"
 	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/eth"
 	"github.com/trezor/blockbook/common"
 	"github.com/trezor/blockbook/db"
 	"github.com/trezor/blockbook/fiat"
@@ -34,6 +36,9 @@ type InternalServer struct {
 	mempool     bchain.Mempool
 	is          *common.InternalState
 	api         *api.Worker
+	// apiKeysAdmin are the API keys of the public server managed by the internal server
+	apiKeysAdmin *APIKeys
+	webhooks     *Webhooks
 }
 
 // NewInternalServer creates new internal http interface to blockbook and returns its handle
@@ -74,14 +79,27 @@ func NewInternalServer(binding, certFiles string, db *db.RocksDB, chain bchain.B
 	serveMux.HandleFunc(path, s.index)
 	serveMux.HandleFunc(path+"admin", s.htmlTemplateHandler(s.adminIndex))
 	serveMux.HandleFunc(path+"admin/ws-limit-exceeding-ips", s.htmlTemplateHandler(s.wsLimitExceedingIPs))
+	serveMux.HandleFunc(path+"admin/api-keys", s.jsonHandler(s.apiAPIKeys, 0))
+	serveMux.HandleFunc(path+"admin/webhooks", s.jsonHandler(s.apiWebhooks, 0))
 	if s.chainParser.GetChainType() == bchain.ChainEthereumType {
 		serveMux.HandleFunc(path+"admin/internal-data-errors", s.htmlTemplateHandler(s.internalDataErrors))
 		serveMux.HandleFunc(path+"admin/contract-info", s.htmlTemplateHandler(s.contractInfoPage))
 		serveMux.HandleFunc(path+"admin/contract-info/", s.jsonHandler(s.apiContractInfo, 0))
+		serveMux.HandleFunc(path+"admin/contract-abi/", s.jsonHandler(s.apiContractABI, 0))
 	}
 	return s, nil
 }
 
+// SetAPIKeys sets the API keys managed by the internal server
+func (s *InternalServer) SetAPIKeys(k *APIKeys) {
+	s.apiKeysAdmin = k
+}
+
+// SetWebhooks sets the webhooks managed by the internal server
+func (s *InternalServer) SetWebhooks(w *Webhooks) {
+	s.webhooks = w
+}
+
 // Run starts the server
 func (s *InternalServer) Run() error {
 	if s.certFiles == "" {
@@ -272,3 +290,134 @@ func (s *InternalServer) updateContracts(r *http.Request) (interface{}, error) {
 	}
 	return "{\"success\":\"Updated " + strconv.Itoa(len(contractInfos)) + " contracts\"}", nil
 }
+
+// apiContractABI returns the ABI of the contract given in the path (GET), stores the contract ABIs passed as a JSON array in the body (POST)
+// or deletes the ABI of the contract given in the path (DELETE)
+func (s *InternalServer) apiContractABI(r *http.Request, apiVersion int) (interface{}, error) {
+	if r.Method == http.MethodPost {
+		return s.updateContractABIs(r)
+	}
+	var contractAddress string
+	i := strings.LastIndexByte(r.URL.Path, '/')
+	if i > 0 {
+		contractAddress = r.URL.Path[i+1:]
+	}
+	if len(contractAddress) == 0 {
+		return nil, api.NewAPIError("Missing contract address", true)
+	}
+	if r.Method == http.MethodDelete {
+		if err := s.db.DeleteContractABI(contractAddress); err != nil {
+			return nil, api.NewAPIError("Error deleting ABI of contract "+contractAddress+" "+err.Error(), true)
+		}
+		return "{\"success\":\"Deleted ABI of contract " + contractAddress + "\"}", nil
+	}
+	contractABI, err := s.db.GetContractABIForAddress(contractAddress)
+	if err != nil {
+		return nil, api.NewAPIError(err.Error(), true)
+	}
+	if contractABI == nil {
+		return nil, api.NewAPIError("Contract ABI not found", true)
+	}
+	return contractABI, nil
+}
+
+func (s *InternalServer) updateContractABIs(r *http.Request) (interface{}, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, api.NewAPIError("Cannot get request body", true)
+	}
+	var contractABIs []bchain.ContractABI
+	err = json.Unmarshal(data, &contractABIs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "Cannot unmarshal body to array of ContractABI objects")
+	}
+	// validate all ABIs before storing any of them
+	for i := range contractABIs {
+		c := &contractABIs[i]
+		if err := eth.ParseContractABI(c); err != nil {
+			return nil, api.NewAPIError("Invalid ABI of contract "+c.Contract+" "+err.Error(), true)
+		}
+	}
+	now := time.Now().Unix()
+	for i := range contractABIs {
+		c := &contractABIs[i]
+		c.Updated = now
+		err := s.db.StoreContractABI(c)
+		if err != nil {
+			return nil, api.NewAPIError("Error updating ABI of contract "+c.Contract+" "+err.Error(), true)
+		}
+	}
+	return "{\"success\":\"Updated " + strconv.Itoa(len(contractABIs)) + " contract ABIs\"}", nil
+}
+
+// apiAPIKeys returns the API keys with their usage (GET), adds or updates the keys passed as a JSON array in the body (POST)
+// or deletes the key given by the key query parameter (DELETE)
+func (s *InternalServer) apiAPIKeys(r *http.Request, apiVersion int) (interface{}, error) {
+	if s.apiKeysAdmin == nil {
+		return nil, api.NewAPIError("API keys are not enabled", true)
+	}
+	switch r.Method {
+	case http.MethodPost:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, api.NewAPIError("Cannot get request body", true)
+		}
+		var keys []db.APIKey
+		if err = json.Unmarshal(data, &keys); err != nil {
+			return nil, api.NewAPIError("Cannot unmarshal body to array of APIKey objects: "+err.Error(), true)
+		}
+		for i := range keys {
+			if len(keys[i].Key) < 16 {
+				return nil, api.NewAPIError("API key must have at least 16 characters", true)
+			}
+			if keys[i].RateLimit < 0 || keys[i].DailyQuota < 0 {
+				return nil, api.NewAPIError("Invalid limits of API key "+keys[i].Name, true)
+			}
+		}
+		if err = s.apiKeysAdmin.Update(keys); err != nil {
+			return nil, err
+		}
+		return "{\"success\":\"Updated " + strconv.Itoa(len(keys)) + " API keys\"}", nil
+	case http.MethodDelete:
+		key := r.URL.Query().Get("key")
+		found, err := s.apiKeysAdmin.Delete(key)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, api.NewAPIError("API key not found", true)
+		}
+		return "{\"success\":\"Deleted API key\"}", nil
+	}
+	return s.apiKeysAdmin.List(), nil
+}
+
+// apiWebhooks returns the webhooks with masked secrets (GET), adds or updates the webhooks passed as a JSON array in the body (POST)
+// or deletes the webhook given by the id query parameter (DELETE)
+func (s *InternalServer) apiWebhooks(r *http.Request, apiVersion int) (interface{}, error) {
+	if s.webhooks == nil {
+		return nil, api.NewAPIError("Webhooks are not enabled", true)
+	}
+	switch r.Method {
+	case http.MethodPost:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, api.NewAPIError("Cannot get request body", true)
+		}
+		var webhooks []db.Webhook
+		if err = json.Unmarshal(data, &webhooks); err != nil {
+			return nil, api.NewAPIError("Cannot unmarshal body to array of Webhook objects: "+err.Error(), true)
+		}
+		return s.webhooks.Update(webhooks)
+	case http.MethodDelete:
+		found, err := s.webhooks.Delete(r.URL.Query().Get("id"))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, api.NewAPIError("Webhook not found", true)
+		}
+		return "{\"success\":\"Deleted webhook\"}", nil
+	}
+	return s.webhooks.List(), nil
+}
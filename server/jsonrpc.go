@@ -0,0 +1,252 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/common"
+)
+
+const (
+	jsonRPCVersion         = "2.0"
+	maxJSONRPCRequestSize  = 8 << 20
+	maxJSONRPCBatchSize    = 100
+	jsonRPCRetryAfterField = "retryAfter"
+	// the getAccountInfo descriptors of a JSON-RPC client are counted in this window
+	jsonRPCAccountInfoWindow = time.Hour
+)
+
+// JSON-RPC 2.0 error codes, the codes from -32000 to -32099 are reserved for the server errors
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCInternalError  = -32603
+	jsonRPCMethodError    = -32000
+	jsonRPCAPIKeyError    = -32001
+	jsonRPCRateLimitError = -32002
+)
+
+// jsonRPCMethodAllowed returns false for the subscription methods, which require the websocket connection
+func jsonRPCMethodAllowed(method string) bool {
+	return !strings.HasPrefix(method, "subscribe") && !strings.HasPrefix(method, "unsubscribe")
+}
+
+func newJSONRPCError(id json.RawMessage, code int, message string) *JSONRPCRes {
+	return &JSONRPCRes{
+		JSONRPC: jsonRPCVersion,
+		ID:      id,
+		Error:   &JSONRPCError{Code: code, Message: message},
+	}
+}
+
+// validJSONRPCID checks that the id is a string, a number or null
+func validJSONRPCID(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return true
+	}
+	switch c := id[0]; {
+	case c == '"':
+		var s string
+		return json.Unmarshal(id, &s) == nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		var n json.Number
+		return json.Unmarshal(id, &n) == nil
+	}
+	return string(id) == "null"
+}
+
+// ServeJSONRPC handles the stateless JSON-RPC 2.0 requests and batches over http,
+// the requests are dispatched to the websocket request handlers, except for the subscriptions
+func (s *WebsocketServer) ServeJSONRPC(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", getContentSecurityPolicy())
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONRPC(w, http.StatusMethodNotAllowed, newJSONRPCError(nil, jsonRPCInvalidRequest, "Only POST method is supported"))
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJSONRPCRequestSize))
+	if err != nil {
+		writeJSONRPC(w, http.StatusRequestEntityTooLarge, newJSONRPCError(nil, jsonRPCInvalidRequest, "Request too large"))
+		return
+	}
+	// the channel is not connected, it only carries the identity of the client to the request handlers
+	c := &websocketChannel{
+		id:            atomic.AddUint64(&connectionCounter, 1),
+		ip:            getIP(r),
+		requestHeader: r.Header,
+		apiKey:        apiKeyFromRequest(r),
+	}
+	if s.is.WsGetAccountInfoLimit > 0 {
+		c.accountInfoDescriptors = s.jsonRPCAccountInfoDescriptors(clientID(c.apiKey, c.ip), time.Now())
+	}
+	var rv interface{}
+	var responses []*JSONRPCRes
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			writeJSONRPC(w, http.StatusOK, newJSONRPCError(nil, jsonRPCParseError, "Parse error"))
+			return
+		}
+		if len(batch) == 0 {
+			writeJSONRPC(w, http.StatusOK, newJSONRPCError(nil, jsonRPCInvalidRequest, "Invalid Request"))
+			return
+		}
+		if len(batch) > maxJSONRPCBatchSize {
+			writeJSONRPC(w, http.StatusOK, newJSONRPCError(nil, jsonRPCInvalidRequest, "Batch too large, maximum is "+strconv.Itoa(maxJSONRPCBatchSize)+" requests"))
+			return
+		}
+		for _, raw := range batch {
+			if res := s.onJSONRPCRequest(c, raw); res != nil {
+				responses = append(responses, res)
+			}
+		}
+		rv = responses
+	} else {
+		if res := s.onJSONRPCRequest(c, body); res != nil {
+			responses = append(responses, res)
+			rv = res
+		}
+	}
+	// the response to notifications only is empty
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	retryAfter := 0
+	for _, res := range responses {
+		if res.Error != nil && res.Error.Code == jsonRPCRateLimitError {
+			if d, ok := res.Error.Data.(map[string]int); ok && d[jsonRPCRetryAfterField] > retryAfter {
+				retryAfter = d[jsonRPCRetryAfterField]
+			}
+		}
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	writeJSONRPC(w, http.StatusOK, rv)
+}
+
+// jsonRPCAccountInfoDescriptors returns the getAccountInfo descriptors shared by the JSON-RPC requests of the client
+// identified by the API key or the IP address, so that the stateless requests cannot bypass WsGetAccountInfoLimit
+// the descriptors are reset after jsonRPCAccountInfoWindow
+func (s *WebsocketServer) jsonRPCAccountInfoDescriptors(client string, now time.Time) *accountInfoDescriptors {
+	s.jsonRPCAccountInfoLock.Lock()
+	defer s.jsonRPCAccountInfoLock.Unlock()
+	if now.Sub(s.jsonRPCAccountInfoCleanup) > rateLimiterCleanupInterval {
+		for k, d := range s.jsonRPCAccountInfo {
+			if now.Sub(d.created) > jsonRPCAccountInfoWindow {
+				delete(s.jsonRPCAccountInfo, k)
+			}
+		}
+		s.jsonRPCAccountInfoCleanup = now
+	}
+	d, found := s.jsonRPCAccountInfo[client]
+	if !found || now.Sub(d.created) > jsonRPCAccountInfoWindow {
+		d = newAccountInfoDescriptors(now)
+		s.jsonRPCAccountInfo[client] = d
+	}
+	return d
+}
+
+func writeJSONRPC(w http.ResponseWriter, status int, data interface{}) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		glog.Warning("json encode ", err)
+	}
+}
+
+// onJSONRPCRequest processes one JSON-RPC request, returns nil for a notification
+func (s *WebsocketServer) onJSONRPCRequest(c *websocketChannel, raw json.RawMessage) *JSONRPCRes {
+	var req JSONRPCReq
+	if err := json.Unmarshal(raw, &req); err != nil {
+		if _, isSyntax := err.(*json.SyntaxError); isSyntax {
+			return newJSONRPCError(nil, jsonRPCParseError, "Parse error")
+		}
+		return newJSONRPCError(nil, jsonRPCInvalidRequest, "Invalid Request")
+	}
+	if !validJSONRPCID(req.ID) {
+		return newJSONRPCError(nil, jsonRPCInvalidRequest, "Invalid Request")
+	}
+	if req.JSONRPC != jsonRPCVersion || req.Method == "" {
+		return newJSONRPCError(req.ID, jsonRPCInvalidRequest, "Invalid Request")
+	}
+	res := s.callJSONRPC(c, &req)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	return res
+}
+
+// callJSONRPC executes the method of the request using the websocket request handler
+func (s *WebsocketServer) callJSONRPC(c *websocketChannel, req *JSONRPCReq) (res *JSONRPCRes) {
+	f, ok := requestHandlers[req.Method]
+	if !ok || !jsonRPCMethodAllowed(req.Method) {
+		glog.V(1).Info("Client ", c.id, " JSON-RPC ", req.Method, ": unknown method")
+		return newJSONRPCError(req.ID, jsonRPCMethodNotFound, "Method not found")
+	}
+	// the handlers accept only named params
+	params := req.Params
+	if len(params) == 0 || string(params) == "null" {
+		params = json.RawMessage("{}")
+	} else if params[0] != '{' {
+		return newJSONRPCError(req.ID, jsonRPCInvalidParams, "Invalid params, only named params are supported")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("Client ", c.id, " JSON-RPC ", req.Method, " recovered from panic: ", r)
+			debug.PrintStack()
+			s.metrics.JSONRPCRequests.With(common.Labels{"method": req.Method, "status": "failure"}).Inc()
+			res = newJSONRPCError(req.ID, jsonRPCInternalError, "Internal error")
+		}
+	}()
+	var data interface{}
+	client, keyRate, err := s.guard.admit(c.apiKey, c.ip, req.Method, c.requestHeader.Get("Origin"), apiKeyInterfaceJSONRPC)
+	if err == nil {
+		if data, err = f(s, c, &WsReq{ID: string(req.ID), Method: req.Method, Params: params}); err == nil {
+			s.guard.charge(client, keyRate, req.Method, data)
+		}
+	}
+	if err == nil && data != nil {
+		glog.V(1).Info("Client ", c.id, " JSON-RPC ", req.Method, " success")
+		s.metrics.JSONRPCRequests.With(common.Labels{"method": req.Method, "status": "success"}).Inc()
+		return &JSONRPCRes{JSONRPC: jsonRPCVersion, ID: req.ID, Result: data}
+	}
+	s.metrics.JSONRPCRequests.With(common.Labels{"method": req.Method, "status": "failure"}).Inc()
+	if err == nil {
+		// the handler returns no data if the limit of getAccountInfo descriptors is exceeded
+		return newJSONRPCError(req.ID, jsonRPCMethodError, "Limit exceeded")
+	}
+	res = newJSONRPCError(req.ID, jsonRPCMethodError, err.Error())
+	switch e := err.(type) {
+	case *rateLimitError:
+		res.Error.Code = jsonRPCRateLimitError
+		res.Error.Data = map[string]int{jsonRPCRetryAfterField: e.retryAfter}
+		glog.V(1).Info("Client ", c.id, " JSON-RPC ", req.Method, ": ", err)
+	case *apiKeyError:
+		res.Error.Code = jsonRPCAPIKeyError
+		glog.V(1).Info("Client ", c.id, " JSON-RPC ", req.Method, ": ", err)
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		res.Error.Code = jsonRPCInvalidParams
+	case *api.APIError:
+		if !e.Public {
+			glog.Error("Client ", c.id, " JSON-RPC ", req.Method, ": ", err, ", data ", string(params))
+		}
+	default:
+		glog.Error("Client ", c.id, " JSON-RPC ", req.Method, ": ", errors.ErrorStack(err), ", data ", string(params))
+	}
+	return res
+}
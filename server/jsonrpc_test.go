@@ -0,0 +1,36 @@
+//go:build unittest
+
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebsocketServer_jsonRPCAccountInfoDescriptors(t *testing.T) {
+	now := time.Now()
+	s := &WebsocketServer{jsonRPCAccountInfo: make(map[string]*accountInfoDescriptors), jsonRPCAccountInfoCleanup: now}
+	client := clientID("", "1.2.3.4")
+	// the requests of the same client share the descriptors
+	if l := s.jsonRPCAccountInfoDescriptors(client, now).add("a"); l != 1 {
+		t.Errorf("add() = %d, want 1", l)
+	}
+	if l := s.jsonRPCAccountInfoDescriptors(client, now.Add(time.Minute)).add("b"); l != 2 {
+		t.Errorf("add() = %d, want 2", l)
+	}
+	if l := s.jsonRPCAccountInfoDescriptors(client, now.Add(2*time.Minute)).add("a"); l != 2 {
+		t.Errorf("add() repeated descriptor = %d, want 2", l)
+	}
+	// other clients have their own descriptors
+	if l := s.jsonRPCAccountInfoDescriptors(clientID("key", "1.2.3.4"), now).add("c"); l != 1 {
+		t.Errorf("add() other client = %d, want 1", l)
+	}
+	// the descriptors are reset after the window and the expired clients are removed
+	later := now.Add(jsonRPCAccountInfoWindow + time.Minute)
+	if l := s.jsonRPCAccountInfoDescriptors(client, later).add("c"); l != 1 {
+		t.Errorf("add() after window = %d, want 1", l)
+	}
+	if len(s.jsonRPCAccountInfo) != 1 {
+		t.Errorf("jsonRPCAccountInfo has %d clients, want 1", len(s.jsonRPCAccountInfo))
+	}
+}
@@ -0,0 +1,2971 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: server/pb/blockbook.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AccountDetails int32
+
+const (
+	AccountDetails_ACCOUNT_DETAILS_BASIC          AccountDetails = 0
+	AccountDetails_ACCOUNT_DETAILS_TOKENS         AccountDetails = 1
+	AccountDetails_ACCOUNT_DETAILS_TOKEN_BALANCES AccountDetails = 2
+	AccountDetails_ACCOUNT_DETAILS_TXIDS          AccountDetails = 3
+	AccountDetails_ACCOUNT_DETAILS_TXS_LIGHT      AccountDetails = 4
+	AccountDetails_ACCOUNT_DETAILS_TXS            AccountDetails = 5
+)
+
+// Enum value maps for AccountDetails.
+var (
+	AccountDetails_name = map[int32]string{
+		0: "ACCOUNT_DETAILS_BASIC",
+		1: "ACCOUNT_DETAILS_TOKENS",
+		2: "ACCOUNT_DETAILS_TOKEN_BALANCES",
+		3: "ACCOUNT_DETAILS_TXIDS",
+		4: "ACCOUNT_DETAILS_TXS_LIGHT",
+		5: "ACCOUNT_DETAILS_TXS",
+	}
+	AccountDetails_value = map[string]int32{
+		"ACCOUNT_DETAILS_BASIC":          0,
+		"ACCOUNT_DETAILS_TOKENS":         1,
+		"ACCOUNT_DETAILS_TOKEN_BALANCES": 2,
+		"ACCOUNT_DETAILS_TXIDS":          3,
+		"ACCOUNT_DETAILS_TXS_LIGHT":      4,
+		"ACCOUNT_DETAILS_TXS":            5,
+	}
+)
+
+func (x AccountDetails) Enum() *AccountDetails {
+	p := new(AccountDetails)
+	*p = x
+	return p
+}
+
+func (x AccountDetails) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AccountDetails) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_pb_blockbook_proto_enumTypes[0].Descriptor()
+}
+
+func (AccountDetails) Type() protoreflect.EnumType {
+	return &file_server_pb_blockbook_proto_enumTypes[0]
+}
+
+func (x AccountDetails) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AccountDetails.Descriptor instead.
+func (AccountDetails) EnumDescriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{0}
+}
+
+type TokensToReturn int32
+
+const (
+	TokensToReturn_TOKENS_TO_RETURN_DERIVED         TokensToReturn = 0
+	TokensToReturn_TOKENS_TO_RETURN_USED            TokensToReturn = 1
+	TokensToReturn_TOKENS_TO_RETURN_NONZERO_BALANCE TokensToReturn = 2
+)
+
+// Enum value maps for TokensToReturn.
+var (
+	TokensToReturn_name = map[int32]string{
+		0: "TOKENS_TO_RETURN_DERIVED",
+		1: "TOKENS_TO_RETURN_USED",
+		2: "TOKENS_TO_RETURN_NONZERO_BALANCE",
+	}
+	TokensToReturn_value = map[string]int32{
+		"TOKENS_TO_RETURN_DERIVED":         0,
+		"TOKENS_TO_RETURN_USED":            1,
+		"TOKENS_TO_RETURN_NONZERO_BALANCE": 2,
+	}
+)
+
+func (x TokensToReturn) Enum() *TokensToReturn {
+	p := new(TokensToReturn)
+	*p = x
+	return p
+}
+
+func (x TokensToReturn) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TokensToReturn) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_pb_blockbook_proto_enumTypes[1].Descriptor()
+}
+
+func (TokensToReturn) Type() protoreflect.EnumType {
+	return &file_server_pb_blockbook_proto_enumTypes[1]
+}
+
+func (x TokensToReturn) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TokensToReturn.Descriptor instead.
+func (TokensToReturn) EnumDescriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{1}
+}
+
+type GetTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransactionRequest) Reset() {
+	*x = GetTransactionRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionRequest) ProtoMessage() {}
+
+func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetTransactionRequest) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+type GetAddressRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Address           string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Details           AccountDetails         `protobuf:"varint,2,opt,name=details,proto3,enum=blockbook.AccountDetails" json:"details,omitempty"`
+	Tokens            TokensToReturn         `protobuf:"varint,3,opt,name=tokens,proto3,enum=blockbook.TokensToReturn" json:"tokens,omitempty"`
+	Page              uint32                 `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize          uint32                 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FromHeight        uint32                 `protobuf:"varint,6,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	ToHeight          uint32                 `protobuf:"varint,7,opt,name=to_height,json=toHeight,proto3" json:"to_height,omitempty"`
+	Contract          string                 `protobuf:"bytes,8,opt,name=contract,proto3" json:"contract,omitempty"`
+	SecondaryCurrency string                 `protobuf:"bytes,9,opt,name=secondary_currency,json=secondaryCurrency,proto3" json:"secondary_currency,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetAddressRequest) Reset() {
+	*x = GetAddressRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAddressRequest) ProtoMessage() {}
+
+func (x *GetAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAddressRequest.ProtoReflect.Descriptor instead.
+func (*GetAddressRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAddressRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *GetAddressRequest) GetDetails() AccountDetails {
+	if x != nil {
+		return x.Details
+	}
+	return AccountDetails_ACCOUNT_DETAILS_BASIC
+}
+
+func (x *GetAddressRequest) GetTokens() TokensToReturn {
+	if x != nil {
+		return x.Tokens
+	}
+	return TokensToReturn_TOKENS_TO_RETURN_DERIVED
+}
+
+func (x *GetAddressRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetAddressRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetAddressRequest) GetFromHeight() uint32 {
+	if x != nil {
+		return x.FromHeight
+	}
+	return 0
+}
+
+func (x *GetAddressRequest) GetToHeight() uint32 {
+	if x != nil {
+		return x.ToHeight
+	}
+	return 0
+}
+
+func (x *GetAddressRequest) GetContract() string {
+	if x != nil {
+		return x.Contract
+	}
+	return ""
+}
+
+func (x *GetAddressRequest) GetSecondaryCurrency() string {
+	if x != nil {
+		return x.SecondaryCurrency
+	}
+	return ""
+}
+
+type GetXpubRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Xpub              string                 `protobuf:"bytes,1,opt,name=xpub,proto3" json:"xpub,omitempty"`
+	Details           AccountDetails         `protobuf:"varint,2,opt,name=details,proto3,enum=blockbook.AccountDetails" json:"details,omitempty"`
+	Tokens            TokensToReturn         `protobuf:"varint,3,opt,name=tokens,proto3,enum=blockbook.TokensToReturn" json:"tokens,omitempty"`
+	Page              uint32                 `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize          uint32                 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FromHeight        uint32                 `protobuf:"varint,6,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	ToHeight          uint32                 `protobuf:"varint,7,opt,name=to_height,json=toHeight,proto3" json:"to_height,omitempty"`
+	Contract          string                 `protobuf:"bytes,8,opt,name=contract,proto3" json:"contract,omitempty"`
+	SecondaryCurrency string                 `protobuf:"bytes,9,opt,name=secondary_currency,json=secondaryCurrency,proto3" json:"secondary_currency,omitempty"`
+	Gap               uint32                 `protobuf:"varint,10,opt,name=gap,proto3" json:"gap,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetXpubRequest) Reset() {
+	*x = GetXpubRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetXpubRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetXpubRequest) ProtoMessage() {}
+
+func (x *GetXpubRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetXpubRequest.ProtoReflect.Descriptor instead.
+func (*GetXpubRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetXpubRequest) GetXpub() string {
+	if x != nil {
+		return x.Xpub
+	}
+	return ""
+}
+
+func (x *GetXpubRequest) GetDetails() AccountDetails {
+	if x != nil {
+		return x.Details
+	}
+	return AccountDetails_ACCOUNT_DETAILS_BASIC
+}
+
+func (x *GetXpubRequest) GetTokens() TokensToReturn {
+	if x != nil {
+		return x.Tokens
+	}
+	return TokensToReturn_TOKENS_TO_RETURN_DERIVED
+}
+
+func (x *GetXpubRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetXpubRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetXpubRequest) GetFromHeight() uint32 {
+	if x != nil {
+		return x.FromHeight
+	}
+	return 0
+}
+
+func (x *GetXpubRequest) GetToHeight() uint32 {
+	if x != nil {
+		return x.ToHeight
+	}
+	return 0
+}
+
+func (x *GetXpubRequest) GetContract() string {
+	if x != nil {
+		return x.Contract
+	}
+	return ""
+}
+
+func (x *GetXpubRequest) GetSecondaryCurrency() string {
+	if x != nil {
+		return x.SecondaryCurrency
+	}
+	return ""
+}
+
+func (x *GetXpubRequest) GetGap() uint32 {
+	if x != nil {
+		return x.Gap
+	}
+	return 0
+}
+
+type GetUtxosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// address or xpub descriptor
+	Descriptor_   string `protobuf:"bytes,1,opt,name=descriptor,proto3" json:"descriptor,omitempty"`
+	OnlyConfirmed bool   `protobuf:"varint,2,opt,name=only_confirmed,json=onlyConfirmed,proto3" json:"only_confirmed,omitempty"`
+	Gap           uint32 `protobuf:"varint,3,opt,name=gap,proto3" json:"gap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUtxosRequest) Reset() {
+	*x = GetUtxosRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUtxosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUtxosRequest) ProtoMessage() {}
+
+func (x *GetUtxosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUtxosRequest.ProtoReflect.Descriptor instead.
+func (*GetUtxosRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetUtxosRequest) GetDescriptor_() string {
+	if x != nil {
+		return x.Descriptor_
+	}
+	return ""
+}
+
+func (x *GetUtxosRequest) GetOnlyConfirmed() bool {
+	if x != nil {
+		return x.OnlyConfirmed
+	}
+	return false
+}
+
+func (x *GetUtxosRequest) GetGap() uint32 {
+	if x != nil {
+		return x.Gap
+	}
+	return 0
+}
+
+type GetBlockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// block hash or height
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Page          uint32 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      uint32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockRequest) Reset() {
+	*x = GetBlockRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockRequest) ProtoMessage() {}
+
+func (x *GetBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBlockRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetBlockRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetBlockRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type EstimateFeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blocks        []uint32               `protobuf:"varint,1,rep,packed,name=blocks,proto3" json:"blocks,omitempty"`
+	Conservative  bool                   `protobuf:"varint,2,opt,name=conservative,proto3" json:"conservative,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateFeeRequest) Reset() {
+	*x = EstimateFeeRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateFeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateFeeRequest) ProtoMessage() {}
+
+func (x *EstimateFeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateFeeRequest.ProtoReflect.Descriptor instead.
+func (*EstimateFeeRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EstimateFeeRequest) GetBlocks() []uint32 {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *EstimateFeeRequest) GetConservative() bool {
+	if x != nil {
+		return x.Conservative
+	}
+	return false
+}
+
+type EstimateFeeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// fee per unit (sat/kB, wei/gas) for each of the requested blocks
+	FeePerUnit    []string `protobuf:"bytes,1,rep,name=fee_per_unit,json=feePerUnit,proto3" json:"fee_per_unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateFeeResponse) Reset() {
+	*x = EstimateFeeResponse{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateFeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateFeeResponse) ProtoMessage() {}
+
+func (x *EstimateFeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateFeeResponse.ProtoReflect.Descriptor instead.
+func (*EstimateFeeResponse) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EstimateFeeResponse) GetFeePerUnit() []string {
+	if x != nil {
+		return x.FeePerUnit
+	}
+	return nil
+}
+
+type GetCurrentFiatRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currencies    []string               `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentFiatRatesRequest) Reset() {
+	*x = GetCurrentFiatRatesRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentFiatRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentFiatRatesRequest) ProtoMessage() {}
+
+func (x *GetCurrentFiatRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentFiatRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentFiatRatesRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetCurrentFiatRatesRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+func (x *GetCurrentFiatRatesRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type GetFiatRatesForTimestampsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamps    []int64                `protobuf:"varint,1,rep,packed,name=timestamps,proto3" json:"timestamps,omitempty"`
+	Currencies    []string               `protobuf:"bytes,2,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFiatRatesForTimestampsRequest) Reset() {
+	*x = GetFiatRatesForTimestampsRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFiatRatesForTimestampsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFiatRatesForTimestampsRequest) ProtoMessage() {}
+
+func (x *GetFiatRatesForTimestampsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFiatRatesForTimestampsRequest.ProtoReflect.Descriptor instead.
+func (*GetFiatRatesForTimestampsRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetFiatRatesForTimestampsRequest) GetTimestamps() []int64 {
+	if x != nil {
+		return x.Timestamps
+	}
+	return nil
+}
+
+func (x *GetFiatRatesForTimestampsRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+func (x *GetFiatRatesForTimestampsRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type SubscribeNewBlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeNewBlockRequest) Reset() {
+	*x = SubscribeNewBlockRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeNewBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeNewBlockRequest) ProtoMessage() {}
+
+func (x *SubscribeNewBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeNewBlockRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeNewBlockRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{9}
+}
+
+type SubscribeAddressesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addresses     []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeAddressesRequest) Reset() {
+	*x = SubscribeAddressesRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeAddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeAddressesRequest) ProtoMessage() {}
+
+func (x *SubscribeAddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeAddressesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeAddressesRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubscribeAddressesRequest) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type SubscribeFiatRatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// currency of the rates, empty for all currencies
+	Currency string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	// contracts of the tokens whose rates are sent together with the currency rate
+	Tokens        []string `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeFiatRatesRequest) Reset() {
+	*x = SubscribeFiatRatesRequest{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeFiatRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeFiatRatesRequest) ProtoMessage() {}
+
+func (x *SubscribeFiatRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeFiatRatesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeFiatRatesRequest) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SubscribeFiatRatesRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SubscribeFiatRatesRequest) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type Vin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	N             int32                  `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Txid          string                 `protobuf:"bytes,2,opt,name=txid,proto3" json:"txid,omitempty"`
+	Vout          uint32                 `protobuf:"varint,3,opt,name=vout,proto3" json:"vout,omitempty"`
+	Sequence      int64                  `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Addresses     []string               `protobuf:"bytes,5,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	IsAddress     bool                   `protobuf:"varint,6,opt,name=is_address,json=isAddress,proto3" json:"is_address,omitempty"`
+	IsOwn         bool                   `protobuf:"varint,7,opt,name=is_own,json=isOwn,proto3" json:"is_own,omitempty"`
+	Value         string                 `protobuf:"bytes,8,opt,name=value,proto3" json:"value,omitempty"`
+	Hex           string                 `protobuf:"bytes,9,opt,name=hex,proto3" json:"hex,omitempty"`
+	Asm           string                 `protobuf:"bytes,10,opt,name=asm,proto3" json:"asm,omitempty"`
+	Coinbase      string                 `protobuf:"bytes,11,opt,name=coinbase,proto3" json:"coinbase,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vin) Reset() {
+	*x = Vin{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vin) ProtoMessage() {}
+
+func (x *Vin) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vin.ProtoReflect.Descriptor instead.
+func (*Vin) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Vin) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *Vin) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *Vin) GetVout() uint32 {
+	if x != nil {
+		return x.Vout
+	}
+	return 0
+}
+
+func (x *Vin) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Vin) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Vin) GetIsAddress() bool {
+	if x != nil {
+		return x.IsAddress
+	}
+	return false
+}
+
+func (x *Vin) GetIsOwn() bool {
+	if x != nil {
+		return x.IsOwn
+	}
+	return false
+}
+
+func (x *Vin) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Vin) GetHex() string {
+	if x != nil {
+		return x.Hex
+	}
+	return ""
+}
+
+func (x *Vin) GetAsm() string {
+	if x != nil {
+		return x.Asm
+	}
+	return ""
+}
+
+func (x *Vin) GetCoinbase() string {
+	if x != nil {
+		return x.Coinbase
+	}
+	return ""
+}
+
+type Vout struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	N             int32                  `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Spent         bool                   `protobuf:"varint,3,opt,name=spent,proto3" json:"spent,omitempty"`
+	SpentTxid     string                 `protobuf:"bytes,4,opt,name=spent_txid,json=spentTxid,proto3" json:"spent_txid,omitempty"`
+	SpentIndex    int32                  `protobuf:"varint,5,opt,name=spent_index,json=spentIndex,proto3" json:"spent_index,omitempty"`
+	SpentHeight   int32                  `protobuf:"varint,6,opt,name=spent_height,json=spentHeight,proto3" json:"spent_height,omitempty"`
+	Hex           string                 `protobuf:"bytes,7,opt,name=hex,proto3" json:"hex,omitempty"`
+	Asm           string                 `protobuf:"bytes,8,opt,name=asm,proto3" json:"asm,omitempty"`
+	Addresses     []string               `protobuf:"bytes,9,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	IsAddress     bool                   `protobuf:"varint,10,opt,name=is_address,json=isAddress,proto3" json:"is_address,omitempty"`
+	IsOwn         bool                   `protobuf:"varint,11,opt,name=is_own,json=isOwn,proto3" json:"is_own,omitempty"`
+	Type          string                 `protobuf:"bytes,12,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vout) Reset() {
+	*x = Vout{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vout) ProtoMessage() {}
+
+func (x *Vout) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vout.ProtoReflect.Descriptor instead.
+func (*Vout) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Vout) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *Vout) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Vout) GetSpent() bool {
+	if x != nil {
+		return x.Spent
+	}
+	return false
+}
+
+func (x *Vout) GetSpentTxid() string {
+	if x != nil {
+		return x.SpentTxid
+	}
+	return ""
+}
+
+func (x *Vout) GetSpentIndex() int32 {
+	if x != nil {
+		return x.SpentIndex
+	}
+	return 0
+}
+
+func (x *Vout) GetSpentHeight() int32 {
+	if x != nil {
+		return x.SpentHeight
+	}
+	return 0
+}
+
+func (x *Vout) GetHex() string {
+	if x != nil {
+		return x.Hex
+	}
+	return ""
+}
+
+func (x *Vout) GetAsm() string {
+	if x != nil {
+		return x.Asm
+	}
+	return ""
+}
+
+func (x *Vout) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Vout) GetIsAddress() bool {
+	if x != nil {
+		return x.IsAddress
+	}
+	return false
+}
+
+func (x *Vout) GetIsOwn() bool {
+	if x != nil {
+		return x.IsOwn
+	}
+	return false
+}
+
+func (x *Vout) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type MultiTokenValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiTokenValue) Reset() {
+	*x = MultiTokenValue{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiTokenValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiTokenValue) ProtoMessage() {}
+
+func (x *MultiTokenValue) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiTokenValue.ProtoReflect.Descriptor instead.
+func (*MultiTokenValue) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MultiTokenValue) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MultiTokenValue) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type TokenTransfer struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Standard         string                 `protobuf:"bytes,1,opt,name=standard,proto3" json:"standard,omitempty"`
+	From             string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To               string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Contract         string                 `protobuf:"bytes,4,opt,name=contract,proto3" json:"contract,omitempty"`
+	Name             string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol           string                 `protobuf:"bytes,6,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimals         int32                  `protobuf:"varint,7,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	Value            string                 `protobuf:"bytes,8,opt,name=value,proto3" json:"value,omitempty"`
+	MultiTokenValues []*MultiTokenValue     `protobuf:"bytes,9,rep,name=multi_token_values,json=multiTokenValues,proto3" json:"multi_token_values,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TokenTransfer) Reset() {
+	*x = TokenTransfer{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenTransfer) ProtoMessage() {}
+
+func (x *TokenTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenTransfer.ProtoReflect.Descriptor instead.
+func (*TokenTransfer) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TokenTransfer) GetStandard() string {
+	if x != nil {
+		return x.Standard
+	}
+	return ""
+}
+
+func (x *TokenTransfer) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *TokenTransfer) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *TokenTransfer) GetContract() string {
+	if x != nil {
+		return x.Contract
+	}
+	return ""
+}
+
+func (x *TokenTransfer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TokenTransfer) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *TokenTransfer) GetDecimals() int32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *TokenTransfer) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TokenTransfer) GetMultiTokenValues() []*MultiTokenValue {
+	if x != nil {
+		return x.MultiTokenValues
+	}
+	return nil
+}
+
+type EthereumInternalTransfer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          int32                  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Value         string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EthereumInternalTransfer) Reset() {
+	*x = EthereumInternalTransfer{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EthereumInternalTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EthereumInternalTransfer) ProtoMessage() {}
+
+func (x *EthereumInternalTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EthereumInternalTransfer.ProtoReflect.Descriptor instead.
+func (*EthereumInternalTransfer) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EthereumInternalTransfer) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *EthereumInternalTransfer) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *EthereumInternalTransfer) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *EthereumInternalTransfer) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type EthereumSpecific struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Type            int32                  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	CreatedContract string                 `protobuf:"bytes,2,opt,name=created_contract,json=createdContract,proto3" json:"created_contract,omitempty"`
+	// -1 pending, 0 failure, 1 success
+	Status               int32                       `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string                      `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Nonce                uint64                      `protobuf:"varint,5,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	GasLimit             string                      `protobuf:"bytes,6,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	GasUsed              string                      `protobuf:"bytes,7,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	GasPrice             string                      `protobuf:"bytes,8,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
+	MaxPriorityFeePerGas string                      `protobuf:"bytes,9,opt,name=max_priority_fee_per_gas,json=maxPriorityFeePerGas,proto3" json:"max_priority_fee_per_gas,omitempty"`
+	MaxFeePerGas         string                      `protobuf:"bytes,10,opt,name=max_fee_per_gas,json=maxFeePerGas,proto3" json:"max_fee_per_gas,omitempty"`
+	BaseFeePerGas        string                      `protobuf:"bytes,11,opt,name=base_fee_per_gas,json=baseFeePerGas,proto3" json:"base_fee_per_gas,omitempty"`
+	Data                 string                      `protobuf:"bytes,12,opt,name=data,proto3" json:"data,omitempty"`
+	InternalTransfers    []*EthereumInternalTransfer `protobuf:"bytes,13,rep,name=internal_transfers,json=internalTransfers,proto3" json:"internal_transfers,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *EthereumSpecific) Reset() {
+	*x = EthereumSpecific{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EthereumSpecific) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EthereumSpecific) ProtoMessage() {}
+
+func (x *EthereumSpecific) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EthereumSpecific.ProtoReflect.Descriptor instead.
+func (*EthereumSpecific) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EthereumSpecific) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *EthereumSpecific) GetCreatedContract() string {
+	if x != nil {
+		return x.CreatedContract
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *EthereumSpecific) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *EthereumSpecific) GetGasLimit() string {
+	if x != nil {
+		return x.GasLimit
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetGasUsed() string {
+	if x != nil {
+		return x.GasUsed
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetGasPrice() string {
+	if x != nil {
+		return x.GasPrice
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetMaxPriorityFeePerGas() string {
+	if x != nil {
+		return x.MaxPriorityFeePerGas
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetMaxFeePerGas() string {
+	if x != nil {
+		return x.MaxFeePerGas
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetBaseFeePerGas() string {
+	if x != nil {
+		return x.BaseFeePerGas
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+func (x *EthereumSpecific) GetInternalTransfers() []*EthereumInternalTransfer {
+	if x != nil {
+		return x.InternalTransfers
+	}
+	return nil
+}
+
+type Transaction struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Txid      string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Version   int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	LockTime  uint32                 `protobuf:"varint,3,opt,name=lock_time,json=lockTime,proto3" json:"lock_time,omitempty"`
+	Vin       []*Vin                 `protobuf:"bytes,4,rep,name=vin,proto3" json:"vin,omitempty"`
+	Vout      []*Vout                `protobuf:"bytes,5,rep,name=vout,proto3" json:"vout,omitempty"`
+	BlockHash string                 `protobuf:"bytes,6,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	// -1 for mempool transactions
+	BlockHeight      int64             `protobuf:"varint,7,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	Confirmations    uint32            `protobuf:"varint,8,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	BlockTime        int64             `protobuf:"varint,9,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`
+	Size             int64             `protobuf:"varint,10,opt,name=size,proto3" json:"size,omitempty"`
+	Vsize            int64             `protobuf:"varint,11,opt,name=vsize,proto3" json:"vsize,omitempty"`
+	Value            string            `protobuf:"bytes,12,opt,name=value,proto3" json:"value,omitempty"`
+	ValueIn          string            `protobuf:"bytes,13,opt,name=value_in,json=valueIn,proto3" json:"value_in,omitempty"`
+	Fees             string            `protobuf:"bytes,14,opt,name=fees,proto3" json:"fees,omitempty"`
+	Hex              string            `protobuf:"bytes,15,opt,name=hex,proto3" json:"hex,omitempty"`
+	Rbf              bool              `protobuf:"varint,16,opt,name=rbf,proto3" json:"rbf,omitempty"`
+	TokenTransfers   []*TokenTransfer  `protobuf:"bytes,17,rep,name=token_transfers,json=tokenTransfers,proto3" json:"token_transfers,omitempty"`
+	EthereumSpecific *EthereumSpecific `protobuf:"bytes,18,opt,name=ethereum_specific,json=ethereumSpecific,proto3" json:"ethereum_specific,omitempty"`
+	// coin specific data in JSON
+	CoinSpecificData []byte `protobuf:"bytes,19,opt,name=coin_specific_data,json=coinSpecificData,proto3" json:"coin_specific_data,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Transaction) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *Transaction) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Transaction) GetLockTime() uint32 {
+	if x != nil {
+		return x.LockTime
+	}
+	return 0
+}
+
+func (x *Transaction) GetVin() []*Vin {
+	if x != nil {
+		return x.Vin
+	}
+	return nil
+}
+
+func (x *Transaction) GetVout() []*Vout {
+	if x != nil {
+		return x.Vout
+	}
+	return nil
+}
+
+func (x *Transaction) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *Transaction) GetBlockHeight() int64 {
+	if x != nil {
+		return x.BlockHeight
+	}
+	return 0
+}
+
+func (x *Transaction) GetConfirmations() uint32 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+func (x *Transaction) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *Transaction) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Transaction) GetVsize() int64 {
+	if x != nil {
+		return x.Vsize
+	}
+	return 0
+}
+
+func (x *Transaction) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Transaction) GetValueIn() string {
+	if x != nil {
+		return x.ValueIn
+	}
+	return ""
+}
+
+func (x *Transaction) GetFees() string {
+	if x != nil {
+		return x.Fees
+	}
+	return ""
+}
+
+func (x *Transaction) GetHex() string {
+	if x != nil {
+		return x.Hex
+	}
+	return ""
+}
+
+func (x *Transaction) GetRbf() bool {
+	if x != nil {
+		return x.Rbf
+	}
+	return false
+}
+
+func (x *Transaction) GetTokenTransfers() []*TokenTransfer {
+	if x != nil {
+		return x.TokenTransfers
+	}
+	return nil
+}
+
+func (x *Transaction) GetEthereumSpecific() *EthereumSpecific {
+	if x != nil {
+		return x.EthereumSpecific
+	}
+	return nil
+}
+
+func (x *Transaction) GetCoinSpecificData() []byte {
+	if x != nil {
+		return x.CoinSpecificData
+	}
+	return nil
+}
+
+type Token struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Standard         string                 `protobuf:"bytes,1,opt,name=standard,proto3" json:"standard,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Path             string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Contract         string                 `protobuf:"bytes,4,opt,name=contract,proto3" json:"contract,omitempty"`
+	Transfers        int32                  `protobuf:"varint,5,opt,name=transfers,proto3" json:"transfers,omitempty"`
+	Symbol           string                 `protobuf:"bytes,6,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimals         int32                  `protobuf:"varint,7,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	Balance          string                 `protobuf:"bytes,8,opt,name=balance,proto3" json:"balance,omitempty"`
+	BaseValue        float64                `protobuf:"fixed64,9,opt,name=base_value,json=baseValue,proto3" json:"base_value,omitempty"`
+	SecondaryValue   float64                `protobuf:"fixed64,10,opt,name=secondary_value,json=secondaryValue,proto3" json:"secondary_value,omitempty"`
+	Ids              []string               `protobuf:"bytes,11,rep,name=ids,proto3" json:"ids,omitempty"`
+	MultiTokenValues []*MultiTokenValue     `protobuf:"bytes,12,rep,name=multi_token_values,json=multiTokenValues,proto3" json:"multi_token_values,omitempty"`
+	TotalReceived    string                 `protobuf:"bytes,13,opt,name=total_received,json=totalReceived,proto3" json:"total_received,omitempty"`
+	TotalSent        string                 `protobuf:"bytes,14,opt,name=total_sent,json=totalSent,proto3" json:"total_sent,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Token) GetStandard() string {
+	if x != nil {
+		return x.Standard
+	}
+	return ""
+}
+
+func (x *Token) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Token) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Token) GetContract() string {
+	if x != nil {
+		return x.Contract
+	}
+	return ""
+}
+
+func (x *Token) GetTransfers() int32 {
+	if x != nil {
+		return x.Transfers
+	}
+	return 0
+}
+
+func (x *Token) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Token) GetDecimals() int32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *Token) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *Token) GetBaseValue() float64 {
+	if x != nil {
+		return x.BaseValue
+	}
+	return 0
+}
+
+func (x *Token) GetSecondaryValue() float64 {
+	if x != nil {
+		return x.SecondaryValue
+	}
+	return 0
+}
+
+func (x *Token) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *Token) GetMultiTokenValues() []*MultiTokenValue {
+	if x != nil {
+		return x.MultiTokenValues
+	}
+	return nil
+}
+
+func (x *Token) GetTotalReceived() string {
+	if x != nil {
+		return x.TotalReceived
+	}
+	return ""
+}
+
+func (x *Token) GetTotalSent() string {
+	if x != nil {
+		return x.TotalSent
+	}
+	return ""
+}
+
+type Address struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Page                 int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	TotalPages           int32                  `protobuf:"varint,2,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	ItemsOnPage          int32                  `protobuf:"varint,3,opt,name=items_on_page,json=itemsOnPage,proto3" json:"items_on_page,omitempty"`
+	Address              string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	Balance              string                 `protobuf:"bytes,5,opt,name=balance,proto3" json:"balance,omitempty"`
+	TotalReceived        string                 `protobuf:"bytes,6,opt,name=total_received,json=totalReceived,proto3" json:"total_received,omitempty"`
+	TotalSent            string                 `protobuf:"bytes,7,opt,name=total_sent,json=totalSent,proto3" json:"total_sent,omitempty"`
+	UnconfirmedBalance   string                 `protobuf:"bytes,8,opt,name=unconfirmed_balance,json=unconfirmedBalance,proto3" json:"unconfirmed_balance,omitempty"`
+	UnconfirmedTxs       int32                  `protobuf:"varint,9,opt,name=unconfirmed_txs,json=unconfirmedTxs,proto3" json:"unconfirmed_txs,omitempty"`
+	Txs                  int32                  `protobuf:"varint,10,opt,name=txs,proto3" json:"txs,omitempty"`
+	Transactions         []*Transaction         `protobuf:"bytes,11,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	Txids                []string               `protobuf:"bytes,12,rep,name=txids,proto3" json:"txids,omitempty"`
+	Nonce                string                 `protobuf:"bytes,13,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	UsedTokens           int32                  `protobuf:"varint,14,opt,name=used_tokens,json=usedTokens,proto3" json:"used_tokens,omitempty"`
+	Tokens               []*Token               `protobuf:"bytes,15,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	SecondaryCurrency    string                 `protobuf:"bytes,16,opt,name=secondary_currency,json=secondaryCurrency,proto3" json:"secondary_currency,omitempty"`
+	SecondaryValue       float64                `protobuf:"fixed64,17,opt,name=secondary_value,json=secondaryValue,proto3" json:"secondary_value,omitempty"`
+	TokensBaseValue      float64                `protobuf:"fixed64,18,opt,name=tokens_base_value,json=tokensBaseValue,proto3" json:"tokens_base_value,omitempty"`
+	TokensSecondaryValue float64                `protobuf:"fixed64,19,opt,name=tokens_secondary_value,json=tokensSecondaryValue,proto3" json:"tokens_secondary_value,omitempty"`
+	TotalBaseValue       float64                `protobuf:"fixed64,20,opt,name=total_base_value,json=totalBaseValue,proto3" json:"total_base_value,omitempty"`
+	TotalSecondaryValue  float64                `protobuf:"fixed64,21,opt,name=total_secondary_value,json=totalSecondaryValue,proto3" json:"total_secondary_value,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Address) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Address) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *Address) GetItemsOnPage() int32 {
+	if x != nil {
+		return x.ItemsOnPage
+	}
+	return 0
+}
+
+func (x *Address) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Address) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *Address) GetTotalReceived() string {
+	if x != nil {
+		return x.TotalReceived
+	}
+	return ""
+}
+
+func (x *Address) GetTotalSent() string {
+	if x != nil {
+		return x.TotalSent
+	}
+	return ""
+}
+
+func (x *Address) GetUnconfirmedBalance() string {
+	if x != nil {
+		return x.UnconfirmedBalance
+	}
+	return ""
+}
+
+func (x *Address) GetUnconfirmedTxs() int32 {
+	if x != nil {
+		return x.UnconfirmedTxs
+	}
+	return 0
+}
+
+func (x *Address) GetTxs() int32 {
+	if x != nil {
+		return x.Txs
+	}
+	return 0
+}
+
+func (x *Address) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *Address) GetTxids() []string {
+	if x != nil {
+		return x.Txids
+	}
+	return nil
+}
+
+func (x *Address) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *Address) GetUsedTokens() int32 {
+	if x != nil {
+		return x.UsedTokens
+	}
+	return 0
+}
+
+func (x *Address) GetTokens() []*Token {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *Address) GetSecondaryCurrency() string {
+	if x != nil {
+		return x.SecondaryCurrency
+	}
+	return ""
+}
+
+func (x *Address) GetSecondaryValue() float64 {
+	if x != nil {
+		return x.SecondaryValue
+	}
+	return 0
+}
+
+func (x *Address) GetTokensBaseValue() float64 {
+	if x != nil {
+		return x.TokensBaseValue
+	}
+	return 0
+}
+
+func (x *Address) GetTokensSecondaryValue() float64 {
+	if x != nil {
+		return x.TokensSecondaryValue
+	}
+	return 0
+}
+
+func (x *Address) GetTotalBaseValue() float64 {
+	if x != nil {
+		return x.TotalBaseValue
+	}
+	return 0
+}
+
+func (x *Address) GetTotalSecondaryValue() float64 {
+	if x != nil {
+		return x.TotalSecondaryValue
+	}
+	return 0
+}
+
+type Utxo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Vout          int32                  `protobuf:"varint,2,opt,name=vout,proto3" json:"vout,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Height        int64                  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	Confirmations int64                  `protobuf:"varint,5,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	Address       string                 `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	Path          string                 `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`
+	LockTime      uint32                 `protobuf:"varint,8,opt,name=lock_time,json=lockTime,proto3" json:"lock_time,omitempty"`
+	Coinbase      bool                   `protobuf:"varint,9,opt,name=coinbase,proto3" json:"coinbase,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Utxo) Reset() {
+	*x = Utxo{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Utxo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Utxo) ProtoMessage() {}
+
+func (x *Utxo) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Utxo.ProtoReflect.Descriptor instead.
+func (*Utxo) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Utxo) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *Utxo) GetVout() int32 {
+	if x != nil {
+		return x.Vout
+	}
+	return 0
+}
+
+func (x *Utxo) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Utxo) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Utxo) GetConfirmations() int64 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+func (x *Utxo) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Utxo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Utxo) GetLockTime() uint32 {
+	if x != nil {
+		return x.LockTime
+	}
+	return 0
+}
+
+func (x *Utxo) GetCoinbase() bool {
+	if x != nil {
+		return x.Coinbase
+	}
+	return false
+}
+
+type Utxos struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Utxos         []*Utxo                `protobuf:"bytes,1,rep,name=utxos,proto3" json:"utxos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Utxos) Reset() {
+	*x = Utxos{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Utxos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Utxos) ProtoMessage() {}
+
+func (x *Utxos) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Utxos.ProtoReflect.Descriptor instead.
+func (*Utxos) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Utxos) GetUtxos() []*Utxo {
+	if x != nil {
+		return x.Utxos
+	}
+	return nil
+}
+
+type Block struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Page              int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	TotalPages        int32                  `protobuf:"varint,2,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	ItemsOnPage       int32                  `protobuf:"varint,3,opt,name=items_on_page,json=itemsOnPage,proto3" json:"items_on_page,omitempty"`
+	Hash              string                 `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	PreviousBlockHash string                 `protobuf:"bytes,5,opt,name=previous_block_hash,json=previousBlockHash,proto3" json:"previous_block_hash,omitempty"`
+	NextBlockHash     string                 `protobuf:"bytes,6,opt,name=next_block_hash,json=nextBlockHash,proto3" json:"next_block_hash,omitempty"`
+	Height            uint32                 `protobuf:"varint,7,opt,name=height,proto3" json:"height,omitempty"`
+	Confirmations     int64                  `protobuf:"varint,8,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	Size              int64                  `protobuf:"varint,9,opt,name=size,proto3" json:"size,omitempty"`
+	Time              int64                  `protobuf:"varint,10,opt,name=time,proto3" json:"time,omitempty"`
+	Version           string                 `protobuf:"bytes,11,opt,name=version,proto3" json:"version,omitempty"`
+	MerkleRoot        string                 `protobuf:"bytes,12,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Nonce             string                 `protobuf:"bytes,13,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Bits              string                 `protobuf:"bytes,14,opt,name=bits,proto3" json:"bits,omitempty"`
+	Difficulty        string                 `protobuf:"bytes,15,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	TxCount           int32                  `protobuf:"varint,16,opt,name=tx_count,json=txCount,proto3" json:"tx_count,omitempty"`
+	Txs               []*Transaction         `protobuf:"bytes,17,rep,name=txs,proto3" json:"txs,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Block) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Block) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *Block) GetItemsOnPage() int32 {
+	if x != nil {
+		return x.ItemsOnPage
+	}
+	return 0
+}
+
+func (x *Block) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Block) GetPreviousBlockHash() string {
+	if x != nil {
+		return x.PreviousBlockHash
+	}
+	return ""
+}
+
+func (x *Block) GetNextBlockHash() string {
+	if x != nil {
+		return x.NextBlockHash
+	}
+	return ""
+}
+
+func (x *Block) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Block) GetConfirmations() int64 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+func (x *Block) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Block) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Block) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Block) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *Block) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *Block) GetBits() string {
+	if x != nil {
+		return x.Bits
+	}
+	return ""
+}
+
+func (x *Block) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *Block) GetTxCount() int32 {
+	if x != nil {
+		return x.TxCount
+	}
+	return 0
+}
+
+func (x *Block) GetTxs() []*Transaction {
+	if x != nil {
+		return x.Txs
+	}
+	return nil
+}
+
+type FiatTicker struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Rates         map[string]float32     `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"`
+	TokenRates    map[string]float32     `protobuf:"bytes,3,rep,name=token_rates,json=tokenRates,proto3" json:"token_rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FiatTicker) Reset() {
+	*x = FiatTicker{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FiatTicker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FiatTicker) ProtoMessage() {}
+
+func (x *FiatTicker) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FiatTicker.ProtoReflect.Descriptor instead.
+func (*FiatTicker) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *FiatTicker) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *FiatTicker) GetRates() map[string]float32 {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *FiatTicker) GetTokenRates() map[string]float32 {
+	if x != nil {
+		return x.TokenRates
+	}
+	return nil
+}
+
+func (x *FiatTicker) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type FiatTickers struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tickers       []*FiatTicker          `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FiatTickers) Reset() {
+	*x = FiatTickers{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FiatTickers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FiatTickers) ProtoMessage() {}
+
+func (x *FiatTickers) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FiatTickers.ProtoReflect.Descriptor instead.
+func (*FiatTickers) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *FiatTickers) GetTickers() []*FiatTicker {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+type NewBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Height        uint32                 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Hash          string                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewBlock) Reset() {
+	*x = NewBlock{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewBlock) ProtoMessage() {}
+
+func (x *NewBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewBlock.ProtoReflect.Descriptor instead.
+func (*NewBlock) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *NewBlock) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *NewBlock) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type AddressTransaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Tx            *Transaction           `protobuf:"bytes,2,opt,name=tx,proto3" json:"tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressTransaction) Reset() {
+	*x = AddressTransaction{}
+	mi := &file_server_pb_blockbook_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressTransaction) ProtoMessage() {}
+
+func (x *AddressTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_server_pb_blockbook_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressTransaction.ProtoReflect.Descriptor instead.
+func (*AddressTransaction) Descriptor() ([]byte, []int) {
+	return file_server_pb_blockbook_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AddressTransaction) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *AddressTransaction) GetTx() *Transaction {
+	if x != nil {
+		return x.Tx
+	}
+	return nil
+}
+
+var File_server_pb_blockbook_proto protoreflect.FileDescriptor
+
+const file_server_pb_blockbook_proto_rawDesc = "" +
+	"\n" +
+	"\x19server/pb/blockbook.proto\x12\tblockbook\"+\n" +
+	"\x15GetTransactionRequest\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\"\xcf\x02\n" +
+	"\x11GetAddressRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x123\n" +
+	"\adetails\x18\x02 \x01(\x0e2\x19.blockbook.AccountDetailsR\adetails\x121\n" +
+	"\x06tokens\x18\x03 \x01(\x0e2\x19.blockbook.TokensToReturnR\x06tokens\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\rR\bpageSize\x12\x1f\n" +
+	"\vfrom_height\x18\x06 \x01(\rR\n" +
+	"fromHeight\x12\x1b\n" +
+	"\tto_height\x18\a \x01(\rR\btoHeight\x12\x1a\n" +
+	"\bcontract\x18\b \x01(\tR\bcontract\x12-\n" +
+	"\x12secondary_currency\x18\t \x01(\tR\x11secondaryCurrency\"\xd8\x02\n" +
+	"\x0eGetXpubRequest\x12\x12\n" +
+	"\x04xpub\x18\x01 \x01(\tR\x04xpub\x123\n" +
+	"\adetails\x18\x02 \x01(\x0e2\x19.blockbook.AccountDetailsR\adetails\x121\n" +
+	"\x06tokens\x18\x03 \x01(\x0e2\x19.blockbook.TokensToReturnR\x06tokens\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\rR\bpageSize\x12\x1f\n" +
+	"\vfrom_height\x18\x06 \x01(\rR\n" +
+	"fromHeight\x12\x1b\n" +
+	"\tto_height\x18\a \x01(\rR\btoHeight\x12\x1a\n" +
+	"\bcontract\x18\b \x01(\tR\bcontract\x12-\n" +
+	"\x12secondary_currency\x18\t \x01(\tR\x11secondaryCurrency\x12\x10\n" +
+	"\x03gap\x18\n" +
+	" \x01(\rR\x03gap\"j\n" +
+	"\x0fGetUtxosRequest\x12\x1e\n" +
+	"\n" +
+	"descriptor\x18\x01 \x01(\tR\n" +
+	"descriptor\x12%\n" +
+	"\x0eonly_confirmed\x18\x02 \x01(\bR\ronlyConfirmed\x12\x10\n" +
+	"\x03gap\x18\x03 \x01(\rR\x03gap\"R\n" +
+	"\x0fGetBlockRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\rR\bpageSize\"P\n" +
+	"\x12EstimateFeeRequest\x12\x16\n" +
+	"\x06blocks\x18\x01 \x03(\rR\x06blocks\x12\"\n" +
+	"\fconservative\x18\x02 \x01(\bR\fconservative\"7\n" +
+	"\x13EstimateFeeResponse\x12 \n" +
+	"\ffee_per_unit\x18\x01 \x03(\tR\n" +
+	"feePerUnit\"R\n" +
+	"\x1aGetCurrentFiatRatesRequest\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\tR\n" +
+	"currencies\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"x\n" +
+	" GetFiatRatesForTimestampsRequest\x12\x1e\n" +
+	"\n" +
+	"timestamps\x18\x01 \x03(\x03R\n" +
+	"timestamps\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x02 \x03(\tR\n" +
+	"currencies\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"\x1a\n" +
+	"\x18SubscribeNewBlockRequest\"9\n" +
+	"\x19SubscribeAddressesRequest\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\"O\n" +
+	"\x19SubscribeFiatRatesRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06tokens\x18\x02 \x03(\tR\x06tokens\"\x81\x02\n" +
+	"\x03Vin\x12\f\n" +
+	"\x01n\x18\x01 \x01(\x05R\x01n\x12\x12\n" +
+	"\x04txid\x18\x02 \x01(\tR\x04txid\x12\x12\n" +
+	"\x04vout\x18\x03 \x01(\rR\x04vout\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x03R\bsequence\x12\x1c\n" +
+	"\taddresses\x18\x05 \x03(\tR\taddresses\x12\x1d\n" +
+	"\n" +
+	"is_address\x18\x06 \x01(\bR\tisAddress\x12\x15\n" +
+	"\x06is_own\x18\a \x01(\bR\x05isOwn\x12\x14\n" +
+	"\x05value\x18\b \x01(\tR\x05value\x12\x10\n" +
+	"\x03hex\x18\t \x01(\tR\x03hex\x12\x10\n" +
+	"\x03asm\x18\n" +
+	" \x01(\tR\x03asm\x12\x1a\n" +
+	"\bcoinbase\x18\v \x01(\tR\bcoinbase\"\xaf\x02\n" +
+	"\x04Vout\x12\f\n" +
+	"\x01n\x18\x01 \x01(\x05R\x01n\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x14\n" +
+	"\x05spent\x18\x03 \x01(\bR\x05spent\x12\x1d\n" +
+	"\n" +
+	"spent_txid\x18\x04 \x01(\tR\tspentTxid\x12\x1f\n" +
+	"\vspent_index\x18\x05 \x01(\x05R\n" +
+	"spentIndex\x12!\n" +
+	"\fspent_height\x18\x06 \x01(\x05R\vspentHeight\x12\x10\n" +
+	"\x03hex\x18\a \x01(\tR\x03hex\x12\x10\n" +
+	"\x03asm\x18\b \x01(\tR\x03asm\x12\x1c\n" +
+	"\taddresses\x18\t \x03(\tR\taddresses\x12\x1d\n" +
+	"\n" +
+	"is_address\x18\n" +
+	" \x01(\bR\tisAddress\x12\x15\n" +
+	"\x06is_own\x18\v \x01(\bR\x05isOwn\x12\x12\n" +
+	"\x04type\x18\f \x01(\tR\x04type\"7\n" +
+	"\x0fMultiTokenValue\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\x93\x02\n" +
+	"\rTokenTransfer\x12\x1a\n" +
+	"\bstandard\x18\x01 \x01(\tR\bstandard\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x1a\n" +
+	"\bcontract\x18\x04 \x01(\tR\bcontract\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12\x16\n" +
+	"\x06symbol\x18\x06 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bdecimals\x18\a \x01(\x05R\bdecimals\x12\x14\n" +
+	"\x05value\x18\b \x01(\tR\x05value\x12H\n" +
+	"\x12multi_token_values\x18\t \x03(\v2\x1a.blockbook.MultiTokenValueR\x10multiTokenValues\"h\n" +
+	"\x18EthereumInternalTransfer\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x05R\x04type\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\"\xda\x03\n" +
+	"\x10EthereumSpecific\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x05R\x04type\x12)\n" +
+	"\x10created_contract\x18\x02 \x01(\tR\x0fcreatedContract\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\x05R\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x14\n" +
+	"\x05nonce\x18\x05 \x01(\x04R\x05nonce\x12\x1b\n" +
+	"\tgas_limit\x18\x06 \x01(\tR\bgasLimit\x12\x19\n" +
+	"\bgas_used\x18\a \x01(\tR\agasUsed\x12\x1b\n" +
+	"\tgas_price\x18\b \x01(\tR\bgasPrice\x126\n" +
+	"\x18max_priority_fee_per_gas\x18\t \x01(\tR\x14maxPriorityFeePerGas\x12%\n" +
+	"\x0fmax_fee_per_gas\x18\n" +
+	" \x01(\tR\fmaxFeePerGas\x12'\n" +
+	"\x10base_fee_per_gas\x18\v \x01(\tR\rbaseFeePerGas\x12\x12\n" +
+	"\x04data\x18\f \x01(\tR\x04data\x12R\n" +
+	"\x12internal_transfers\x18\r \x03(\v2#.blockbook.EthereumInternalTransferR\x11internalTransfers\"\xf4\x04\n" +
+	"\vTransaction\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x1b\n" +
+	"\tlock_time\x18\x03 \x01(\rR\blockTime\x12 \n" +
+	"\x03vin\x18\x04 \x03(\v2\x0e.blockbook.VinR\x03vin\x12#\n" +
+	"\x04vout\x18\x05 \x03(\v2\x0f.blockbook.VoutR\x04vout\x12\x1d\n" +
+	"\n" +
+	"block_hash\x18\x06 \x01(\tR\tblockHash\x12!\n" +
+	"\fblock_height\x18\a \x01(\x03R\vblockHeight\x12$\n" +
+	"\rconfirmations\x18\b \x01(\rR\rconfirmations\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\t \x01(\x03R\tblockTime\x12\x12\n" +
+	"\x04size\x18\n" +
+	" \x01(\x03R\x04size\x12\x14\n" +
+	"\x05vsize\x18\v \x01(\x03R\x05vsize\x12\x14\n" +
+	"\x05value\x18\f \x01(\tR\x05value\x12\x19\n" +
+	"\bvalue_in\x18\r \x01(\tR\avalueIn\x12\x12\n" +
+	"\x04fees\x18\x0e \x01(\tR\x04fees\x12\x10\n" +
+	"\x03hex\x18\x0f \x01(\tR\x03hex\x12\x10\n" +
+	"\x03rbf\x18\x10 \x01(\bR\x03rbf\x12A\n" +
+	"\x0ftoken_transfers\x18\x11 \x03(\v2\x18.blockbook.TokenTransferR\x0etokenTransfers\x12H\n" +
+	"\x11ethereum_specific\x18\x12 \x01(\v2\x1b.blockbook.EthereumSpecificR\x10ethereumSpecific\x12,\n" +
+	"\x12coin_specific_data\x18\x13 \x01(\fR\x10coinSpecificData\"\xbd\x03\n" +
+	"\x05Token\x12\x1a\n" +
+	"\bstandard\x18\x01 \x01(\tR\bstandard\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x1a\n" +
+	"\bcontract\x18\x04 \x01(\tR\bcontract\x12\x1c\n" +
+	"\ttransfers\x18\x05 \x01(\x05R\ttransfers\x12\x16\n" +
+	"\x06symbol\x18\x06 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bdecimals\x18\a \x01(\x05R\bdecimals\x12\x18\n" +
+	"\abalance\x18\b \x01(\tR\abalance\x12\x1d\n" +
+	"\n" +
+	"base_value\x18\t \x01(\x01R\tbaseValue\x12'\n" +
+	"\x0fsecondary_value\x18\n" +
+	" \x01(\x01R\x0esecondaryValue\x12\x10\n" +
+	"\x03ids\x18\v \x03(\tR\x03ids\x12H\n" +
+	"\x12multi_token_values\x18\f \x03(\v2\x1a.blockbook.MultiTokenValueR\x10multiTokenValues\x12%\n" +
+	"\x0etotal_received\x18\r \x01(\tR\rtotalReceived\x12\x1d\n" +
+	"\n" +
+	"total_sent\x18\x0e \x01(\tR\ttotalSent\"\x93\x06\n" +
+	"\aAddress\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1f\n" +
+	"\vtotal_pages\x18\x02 \x01(\x05R\n" +
+	"totalPages\x12\"\n" +
+	"\ritems_on_page\x18\x03 \x01(\x05R\vitemsOnPage\x12\x18\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\x12\x18\n" +
+	"\abalance\x18\x05 \x01(\tR\abalance\x12%\n" +
+	"\x0etotal_received\x18\x06 \x01(\tR\rtotalReceived\x12\x1d\n" +
+	"\n" +
+	"total_sent\x18\a \x01(\tR\ttotalSent\x12/\n" +
+	"\x13unconfirmed_balance\x18\b \x01(\tR\x12unconfirmedBalance\x12'\n" +
+	"\x0funconfirmed_txs\x18\t \x01(\x05R\x0eunconfirmedTxs\x12\x10\n" +
+	"\x03txs\x18\n" +
+	" \x01(\x05R\x03txs\x12:\n" +
+	"\ftransactions\x18\v \x03(\v2\x16.blockbook.TransactionR\ftransactions\x12\x14\n" +
+	"\x05txids\x18\f \x03(\tR\x05txids\x12\x14\n" +
+	"\x05nonce\x18\r \x01(\tR\x05nonce\x12\x1f\n" +
+	"\vused_tokens\x18\x0e \x01(\x05R\n" +
+	"usedTokens\x12(\n" +
+	"\x06tokens\x18\x0f \x03(\v2\x10.blockbook.TokenR\x06tokens\x12-\n" +
+	"\x12secondary_currency\x18\x10 \x01(\tR\x11secondaryCurrency\x12'\n" +
+	"\x0fsecondary_value\x18\x11 \x01(\x01R\x0esecondaryValue\x12*\n" +
+	"\x11tokens_base_value\x18\x12 \x01(\x01R\x0ftokensBaseValue\x124\n" +
+	"\x16tokens_secondary_value\x18\x13 \x01(\x01R\x14tokensSecondaryValue\x12(\n" +
+	"\x10total_base_value\x18\x14 \x01(\x01R\x0etotalBaseValue\x122\n" +
+	"\x15total_secondary_value\x18\x15 \x01(\x01R\x13totalSecondaryValue\"\xe9\x01\n" +
+	"\x04Utxo\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x12\n" +
+	"\x04vout\x18\x02 \x01(\x05R\x04vout\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x03R\x06height\x12$\n" +
+	"\rconfirmations\x18\x05 \x01(\x03R\rconfirmations\x12\x18\n" +
+	"\aaddress\x18\x06 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04path\x18\a \x01(\tR\x04path\x12\x1b\n" +
+	"\tlock_time\x18\b \x01(\rR\blockTime\x12\x1a\n" +
+	"\bcoinbase\x18\t \x01(\bR\bcoinbase\".\n" +
+	"\x05Utxos\x12%\n" +
+	"\x05utxos\x18\x01 \x03(\v2\x0f.blockbook.UtxoR\x05utxos\"\xfc\x03\n" +
+	"\x05Block\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1f\n" +
+	"\vtotal_pages\x18\x02 \x01(\x05R\n" +
+	"totalPages\x12\"\n" +
+	"\ritems_on_page\x18\x03 \x01(\x05R\vitemsOnPage\x12\x12\n" +
+	"\x04hash\x18\x04 \x01(\tR\x04hash\x12.\n" +
+	"\x13previous_block_hash\x18\x05 \x01(\tR\x11previousBlockHash\x12&\n" +
+	"\x0fnext_block_hash\x18\x06 \x01(\tR\rnextBlockHash\x12\x16\n" +
+	"\x06height\x18\a \x01(\rR\x06height\x12$\n" +
+	"\rconfirmations\x18\b \x01(\x03R\rconfirmations\x12\x12\n" +
+	"\x04size\x18\t \x01(\x03R\x04size\x12\x12\n" +
+	"\x04time\x18\n" +
+	" \x01(\x03R\x04time\x12\x18\n" +
+	"\aversion\x18\v \x01(\tR\aversion\x12\x1f\n" +
+	"\vmerkle_root\x18\f \x01(\tR\n" +
+	"merkleRoot\x12\x14\n" +
+	"\x05nonce\x18\r \x01(\tR\x05nonce\x12\x12\n" +
+	"\x04bits\x18\x0e \x01(\tR\x04bits\x12\x1e\n" +
+	"\n" +
+	"difficulty\x18\x0f \x01(\tR\n" +
+	"difficulty\x12\x19\n" +
+	"\btx_count\x18\x10 \x01(\x05R\atxCount\x12(\n" +
+	"\x03txs\x18\x11 \x03(\v2\x16.blockbook.TransactionR\x03txs\"\xb9\x02\n" +
+	"\n" +
+	"FiatTicker\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x126\n" +
+	"\x05rates\x18\x02 \x03(\v2 .blockbook.FiatTicker.RatesEntryR\x05rates\x12F\n" +
+	"\vtoken_rates\x18\x03 \x03(\v2%.blockbook.FiatTicker.TokenRatesEntryR\n" +
+	"tokenRates\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x1a8\n" +
+	"\n" +
+	"RatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01\x1a=\n" +
+	"\x0fTokenRatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01\">\n" +
+	"\vFiatTickers\x12/\n" +
+	"\atickers\x18\x01 \x03(\v2\x15.blockbook.FiatTickerR\atickers\"6\n" +
+	"\bNewBlock\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\rR\x06height\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\tR\x04hash\"V\n" +
+	"\x12AddressTransaction\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12&\n" +
+	"\x02tx\x18\x02 \x01(\v2\x16.blockbook.TransactionR\x02tx*\xbe\x01\n" +
+	"\x0eAccountDetails\x12\x19\n" +
+	"\x15ACCOUNT_DETAILS_BASIC\x10\x00\x12\x1a\n" +
+	"\x16ACCOUNT_DETAILS_TOKENS\x10\x01\x12\"\n" +
+	"\x1eACCOUNT_DETAILS_TOKEN_BALANCES\x10\x02\x12\x19\n" +
+	"\x15ACCOUNT_DETAILS_TXIDS\x10\x03\x12\x1d\n" +
+	"\x19ACCOUNT_DETAILS_TXS_LIGHT\x10\x04\x12\x17\n" +
+	"\x13ACCOUNT_DETAILS_TXS\x10\x05*o\n" +
+	"\x0eTokensToReturn\x12\x1c\n" +
+	"\x18TOKENS_TO_RETURN_DERIVED\x10\x00\x12\x19\n" +
+	"\x15TOKENS_TO_RETURN_USED\x10\x01\x12$\n" +
+	" TOKENS_TO_RETURN_NONZERO_BALANCE\x10\x022\xcd\x06\n" +
+	"\tBlockbook\x12J\n" +
+	"\x0eGetTransaction\x12 .blockbook.GetTransactionRequest\x1a\x16.blockbook.Transaction\x12>\n" +
+	"\n" +
+	"GetAddress\x12\x1c.blockbook.GetAddressRequest\x1a\x12.blockbook.Address\x128\n" +
+	"\aGetXpub\x12\x19.blockbook.GetXpubRequest\x1a\x12.blockbook.Address\x128\n" +
+	"\bGetUtxos\x12\x1a.blockbook.GetUtxosRequest\x1a\x10.blockbook.Utxos\x128\n" +
+	"\bGetBlock\x12\x1a.blockbook.GetBlockRequest\x1a\x10.blockbook.Block\x12L\n" +
+	"\vEstimateFee\x12\x1d.blockbook.EstimateFeeRequest\x1a\x1e.blockbook.EstimateFeeResponse\x12S\n" +
+	"\x13GetCurrentFiatRates\x12%.blockbook.GetCurrentFiatRatesRequest\x1a\x15.blockbook.FiatTicker\x12`\n" +
+	"\x19GetFiatRatesForTimestamps\x12+.blockbook.GetFiatRatesForTimestampsRequest\x1a\x16.blockbook.FiatTickers\x12O\n" +
+	"\x11SubscribeNewBlock\x12#.blockbook.SubscribeNewBlockRequest\x1a\x13.blockbook.NewBlock0\x01\x12[\n" +
+	"\x12SubscribeAddresses\x12$.blockbook.SubscribeAddressesRequest\x1a\x1d.blockbook.AddressTransaction0\x01\x12S\n" +
+	"\x12SubscribeFiatRates\x12$.blockbook.SubscribeFiatRatesRequest\x1a\x15.blockbook.FiatTicker0\x01B\fZ\n" +
+	"server/pb/b\x06proto3"
+
+var (
+	file_server_pb_blockbook_proto_rawDescOnce sync.Once
+	file_server_pb_blockbook_proto_rawDescData []byte
+)
+
+func file_server_pb_blockbook_proto_rawDescGZIP() []byte {
+	file_server_pb_blockbook_proto_rawDescOnce.Do(func() {
+		file_server_pb_blockbook_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_server_pb_blockbook_proto_rawDesc), len(file_server_pb_blockbook_proto_rawDesc)))
+	})
+	return file_server_pb_blockbook_proto_rawDescData
+}
+
+var file_server_pb_blockbook_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_server_pb_blockbook_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_server_pb_blockbook_proto_goTypes = []any{
+	(AccountDetails)(0),                      // 0: blockbook.AccountDetails
+	(TokensToReturn)(0),                      // 1: blockbook.TokensToReturn
+	(*GetTransactionRequest)(nil),            // 2: blockbook.GetTransactionRequest
+	(*GetAddressRequest)(nil),                // 3: blockbook.GetAddressRequest
+	(*GetXpubRequest)(nil),                   // 4: blockbook.GetXpubRequest
+	(*GetUtxosRequest)(nil),                  // 5: blockbook.GetUtxosRequest
+	(*GetBlockRequest)(nil),                  // 6: blockbook.GetBlockRequest
+	(*EstimateFeeRequest)(nil),               // 7: blockbook.EstimateFeeRequest
+	(*EstimateFeeResponse)(nil),              // 8: blockbook.EstimateFeeResponse
+	(*GetCurrentFiatRatesRequest)(nil),       // 9: blockbook.GetCurrentFiatRatesRequest
+	(*GetFiatRatesForTimestampsRequest)(nil), // 10: blockbook.GetFiatRatesForTimestampsRequest
+	(*SubscribeNewBlockRequest)(nil),         // 11: blockbook.SubscribeNewBlockRequest
+	(*SubscribeAddressesRequest)(nil),        // 12: blockbook.SubscribeAddressesRequest
+	(*SubscribeFiatRatesRequest)(nil),        // 13: blockbook.SubscribeFiatRatesRequest
+	(*Vin)(nil),                              // 14: blockbook.Vin
+	(*Vout)(nil),                             // 15: blockbook.Vout
+	(*MultiTokenValue)(nil),                  // 16: blockbook.MultiTokenValue
+	(*TokenTransfer)(nil),                    // 17: blockbook.TokenTransfer
+	(*EthereumInternalTransfer)(nil),         // 18: blockbook.EthereumInternalTransfer
+	(*EthereumSpecific)(nil),                 // 19: blockbook.EthereumSpecific
+	(*Transaction)(nil),                      // 20: blockbook.Transaction
+	(*Token)(nil),                            // 21: blockbook.Token
+	(*Address)(nil),                          // 22: blockbook.Address
+	(*Utxo)(nil),                             // 23: blockbook.Utxo
+	(*Utxos)(nil),                            // 24: blockbook.Utxos
+	(*Block)(nil),                            // 25: blockbook.Block
+	(*FiatTicker)(nil),                       // 26: blockbook.FiatTicker
+	(*FiatTickers)(nil),                      // 27: blockbook.FiatTickers
+	(*NewBlock)(nil),                         // 28: blockbook.NewBlock
+	(*AddressTransaction)(nil),               // 29: blockbook.AddressTransaction
+	nil,                                      // 30: blockbook.FiatTicker.RatesEntry
+	nil,                                      // 31: blockbook.FiatTicker.TokenRatesEntry
+}
+var file_server_pb_blockbook_proto_depIdxs = []int32{
+	0,  // 0: blockbook.GetAddressRequest.details:type_name -> blockbook.AccountDetails
+	1,  // 1: blockbook.GetAddressRequest.tokens:type_name -> blockbook.TokensToReturn
+	0,  // 2: blockbook.GetXpubRequest.details:type_name -> blockbook.AccountDetails
+	1,  // 3: blockbook.GetXpubRequest.tokens:type_name -> blockbook.TokensToReturn
+	16, // 4: blockbook.TokenTransfer.multi_token_values:type_name -> blockbook.MultiTokenValue
+	18, // 5: blockbook.EthereumSpecific.internal_transfers:type_name -> blockbook.EthereumInternalTransfer
+	14, // 6: blockbook.Transaction.vin:type_name -> blockbook.Vin
+	15, // 7: blockbook.Transaction.vout:type_name -> blockbook.Vout
+	17, // 8: blockbook.Transaction.token_transfers:type_name -> blockbook.TokenTransfer
+	19, // 9: blockbook.Transaction.ethereum_specific:type_name -> blockbook.EthereumSpecific
+	16, // 10: blockbook.Token.multi_token_values:type_name -> blockbook.MultiTokenValue
+	20, // 11: blockbook.Address.transactions:type_name -> blockbook.Transaction
+	21, // 12: blockbook.Address.tokens:type_name -> blockbook.Token
+	23, // 13: blockbook.Utxos.utxos:type_name -> blockbook.Utxo
+	20, // 14: blockbook.Block.txs:type_name -> blockbook.Transaction
+	30, // 15: blockbook.FiatTicker.rates:type_name -> blockbook.FiatTicker.RatesEntry
+	31, // 16: blockbook.FiatTicker.token_rates:type_name -> blockbook.FiatTicker.TokenRatesEntry
+	26, // 17: blockbook.FiatTickers.tickers:type_name -> blockbook.FiatTicker
+	20, // 18: blockbook.AddressTransaction.tx:type_name -> blockbook.Transaction
+	2,  // 19: blockbook.Blockbook.GetTransaction:input_type -> blockbook.GetTransactionRequest
+	3,  // 20: blockbook.Blockbook.GetAddress:input_type -> blockbook.GetAddressRequest
+	4,  // 21: blockbook.Blockbook.GetXpub:input_type -> blockbook.GetXpubRequest
+	5,  // 22: blockbook.Blockbook.GetUtxos:input_type -> blockbook.GetUtxosRequest
+	6,  // 23: blockbook.Blockbook.GetBlock:input_type -> blockbook.GetBlockRequest
+	7,  // 24: blockbook.Blockbook.EstimateFee:input_type -> blockbook.EstimateFeeRequest
+	9,  // 25: blockbook.Blockbook.GetCurrentFiatRates:input_type -> blockbook.GetCurrentFiatRatesRequest
+	10, // 26: blockbook.Blockbook.GetFiatRatesForTimestamps:input_type -> blockbook.GetFiatRatesForTimestampsRequest
+	11, // 27: blockbook.Blockbook.SubscribeNewBlock:input_type -> blockbook.SubscribeNewBlockRequest
+	12, // 28: blockbook.Blockbook.SubscribeAddresses:input_type -> blockbook.SubscribeAddressesRequest
+	13, // 29: blockbook.Blockbook.SubscribeFiatRates:input_type -> blockbook.SubscribeFiatRatesRequest
+	20, // 30: blockbook.Blockbook.GetTransaction:output_type -> blockbook.Transaction
+	22, // 31: blockbook.Blockbook.GetAddress:output_type -> blockbook.Address
+	22, // 32: blockbook.Blockbook.GetXpub:output_type -> blockbook.Address
+	24, // 33: blockbook.Blockbook.GetUtxos:output_type -> blockbook.Utxos
+	25, // 34: blockbook.Blockbook.GetBlock:output_type -> blockbook.Block
+	8,  // 35: blockbook.Blockbook.EstimateFee:output_type -> blockbook.EstimateFeeResponse
+	26, // 36: blockbook.Blockbook.GetCurrentFiatRates:output_type -> blockbook.FiatTicker
+	27, // 37: blockbook.Blockbook.GetFiatRatesForTimestamps:output_type -> blockbook.FiatTickers
+	28, // 38: blockbook.Blockbook.SubscribeNewBlock:output_type -> blockbook.NewBlock
+	29, // 39: blockbook.Blockbook.SubscribeAddresses:output_type -> blockbook.AddressTransaction
+	26, // 40: blockbook.Blockbook.SubscribeFiatRates:output_type -> blockbook.FiatTicker
+	30, // [30:41] is the sub-list for method output_type
+	19, // [19:30] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_server_pb_blockbook_proto_init() }
+func file_server_pb_blockbook_proto_init() {
+	if File_server_pb_blockbook_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_server_pb_blockbook_proto_rawDesc), len(file_server_pb_blockbook_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_server_pb_blockbook_proto_goTypes,
+		DependencyIndexes: file_server_pb_blockbook_proto_depIdxs,
+		EnumInfos:         file_server_pb_blockbook_proto_enumTypes,
+		MessageInfos:      file_server_pb_blockbook_proto_msgTypes,
+	}.Build()
+	File_server_pb_blockbook_proto = out.File
+	file_server_pb_blockbook_proto_goTypes = nil
+	file_server_pb_blockbook_proto_depIdxs = nil
+}
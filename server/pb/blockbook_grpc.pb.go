@@ -0,0 +1,517 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: server/pb/blockbook.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Blockbook_GetTransaction_FullMethodName            = "/blockbook.Blockbook/GetTransaction"
+	Blockbook_GetAddress_FullMethodName                = "/blockbook.Blockbook/GetAddress"
+	Blockbook_GetXpub_FullMethodName                   = "/blockbook.Blockbook/GetXpub"
+	Blockbook_GetUtxos_FullMethodName                  = "/blockbook.Blockbook/GetUtxos"
+	Blockbook_GetBlock_FullMethodName                  = "/blockbook.Blockbook/GetBlock"
+	Blockbook_EstimateFee_FullMethodName               = "/blockbook.Blockbook/EstimateFee"
+	Blockbook_GetCurrentFiatRates_FullMethodName       = "/blockbook.Blockbook/GetCurrentFiatRates"
+	Blockbook_GetFiatRatesForTimestamps_FullMethodName = "/blockbook.Blockbook/GetFiatRatesForTimestamps"
+	Blockbook_SubscribeNewBlock_FullMethodName         = "/blockbook.Blockbook/SubscribeNewBlock"
+	Blockbook_SubscribeAddresses_FullMethodName        = "/blockbook.Blockbook/SubscribeAddresses"
+	Blockbook_SubscribeFiatRates_FullMethodName        = "/blockbook.Blockbook/SubscribeFiatRates"
+)
+
+// BlockbookClient is the client API for Blockbook service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Blockbook is the gRPC interface of the public server, the queries mirror the websocket API.
+// Amounts are decimal strings in the base units (satoshi, wei).
+type BlockbookClient interface {
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	GetAddress(ctx context.Context, in *GetAddressRequest, opts ...grpc.CallOption) (*Address, error)
+	GetXpub(ctx context.Context, in *GetXpubRequest, opts ...grpc.CallOption) (*Address, error)
+	GetUtxos(ctx context.Context, in *GetUtxosRequest, opts ...grpc.CallOption) (*Utxos, error)
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	GetCurrentFiatRates(ctx context.Context, in *GetCurrentFiatRatesRequest, opts ...grpc.CallOption) (*FiatTicker, error)
+	GetFiatRatesForTimestamps(ctx context.Context, in *GetFiatRatesForTimestampsRequest, opts ...grpc.CallOption) (*FiatTickers, error)
+	SubscribeNewBlock(ctx context.Context, in *SubscribeNewBlockRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NewBlock], error)
+	SubscribeAddresses(ctx context.Context, in *SubscribeAddressesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AddressTransaction], error)
+	SubscribeFiatRates(ctx context.Context, in *SubscribeFiatRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FiatTicker], error)
+}
+
+type blockbookClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlockbookClient(cc grpc.ClientConnInterface) BlockbookClient {
+	return &blockbookClient{cc}
+}
+
+func (c *blockbookClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, Blockbook_GetTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetAddress(ctx context.Context, in *GetAddressRequest, opts ...grpc.CallOption) (*Address, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Address)
+	err := c.cc.Invoke(ctx, Blockbook_GetAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetXpub(ctx context.Context, in *GetXpubRequest, opts ...grpc.CallOption) (*Address, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Address)
+	err := c.cc.Invoke(ctx, Blockbook_GetXpub_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetUtxos(ctx context.Context, in *GetUtxosRequest, opts ...grpc.CallOption) (*Utxos, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Utxos)
+	err := c.cc.Invoke(ctx, Blockbook_GetUtxos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Block)
+	err := c.cc.Invoke(ctx, Blockbook_GetBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EstimateFeeResponse)
+	err := c.cc.Invoke(ctx, Blockbook_EstimateFee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetCurrentFiatRates(ctx context.Context, in *GetCurrentFiatRatesRequest, opts ...grpc.CallOption) (*FiatTicker, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FiatTicker)
+	err := c.cc.Invoke(ctx, Blockbook_GetCurrentFiatRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetFiatRatesForTimestamps(ctx context.Context, in *GetFiatRatesForTimestampsRequest, opts ...grpc.CallOption) (*FiatTickers, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FiatTickers)
+	err := c.cc.Invoke(ctx, Blockbook_GetFiatRatesForTimestamps_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) SubscribeNewBlock(ctx context.Context, in *SubscribeNewBlockRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NewBlock], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Blockbook_ServiceDesc.Streams[0], Blockbook_SubscribeNewBlock_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeNewBlockRequest, NewBlock]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Blockbook_SubscribeNewBlockClient = grpc.ServerStreamingClient[NewBlock]
+
+func (c *blockbookClient) SubscribeAddresses(ctx context.Context, in *SubscribeAddressesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AddressTransaction], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Blockbook_ServiceDesc.Streams[1], Blockbook_SubscribeAddresses_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeAddressesRequest, AddressTransaction]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Blockbook_SubscribeAddressesClient = grpc.ServerStreamingClient[AddressTransaction]
+
+func (c *blockbookClient) SubscribeFiatRates(ctx context.Context, in *SubscribeFiatRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FiatTicker], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Blockbook_ServiceDesc.Streams[2], Blockbook_SubscribeFiatRates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeFiatRatesRequest, FiatTicker]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Blockbook_SubscribeFiatRatesClient = grpc.ServerStreamingClient[FiatTicker]
+
+// BlockbookServer is the server API for Blockbook service.
+// All implementations must embed UnimplementedBlockbookServer
+// for forward compatibility.
+//
+// Blockbook is the gRPC interface of the public server, the queries mirror the websocket API.
+// Amounts are decimal strings in the base units (satoshi, wei).
+type BlockbookServer interface {
+	GetTransaction(context.Context, *GetTransactionRequest) (*Transaction, error)
+	GetAddress(context.Context, *GetAddressRequest) (*Address, error)
+	GetXpub(context.Context, *GetXpubRequest) (*Address, error)
+	GetUtxos(context.Context, *GetUtxosRequest) (*Utxos, error)
+	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	GetCurrentFiatRates(context.Context, *GetCurrentFiatRatesRequest) (*FiatTicker, error)
+	GetFiatRatesForTimestamps(context.Context, *GetFiatRatesForTimestampsRequest) (*FiatTickers, error)
+	SubscribeNewBlock(*SubscribeNewBlockRequest, grpc.ServerStreamingServer[NewBlock]) error
+	SubscribeAddresses(*SubscribeAddressesRequest, grpc.ServerStreamingServer[AddressTransaction]) error
+	SubscribeFiatRates(*SubscribeFiatRatesRequest, grpc.ServerStreamingServer[FiatTicker]) error
+	mustEmbedUnimplementedBlockbookServer()
+}
+
+// UnimplementedBlockbookServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBlockbookServer struct{}
+
+func (UnimplementedBlockbookServer) GetTransaction(context.Context, *GetTransactionRequest) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedBlockbookServer) GetAddress(context.Context, *GetAddressRequest) (*Address, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAddress not implemented")
+}
+func (UnimplementedBlockbookServer) GetXpub(context.Context, *GetXpubRequest) (*Address, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetXpub not implemented")
+}
+func (UnimplementedBlockbookServer) GetUtxos(context.Context, *GetUtxosRequest) (*Utxos, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUtxos not implemented")
+}
+func (UnimplementedBlockbookServer) GetBlock(context.Context, *GetBlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedBlockbookServer) EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
+}
+func (UnimplementedBlockbookServer) GetCurrentFiatRates(context.Context, *GetCurrentFiatRatesRequest) (*FiatTicker, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentFiatRates not implemented")
+}
+func (UnimplementedBlockbookServer) GetFiatRatesForTimestamps(context.Context, *GetFiatRatesForTimestampsRequest) (*FiatTickers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFiatRatesForTimestamps not implemented")
+}
+func (UnimplementedBlockbookServer) SubscribeNewBlock(*SubscribeNewBlockRequest, grpc.ServerStreamingServer[NewBlock]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeNewBlock not implemented")
+}
+func (UnimplementedBlockbookServer) SubscribeAddresses(*SubscribeAddressesRequest, grpc.ServerStreamingServer[AddressTransaction]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAddresses not implemented")
+}
+func (UnimplementedBlockbookServer) SubscribeFiatRates(*SubscribeFiatRatesRequest, grpc.ServerStreamingServer[FiatTicker]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeFiatRates not implemented")
+}
+func (UnimplementedBlockbookServer) mustEmbedUnimplementedBlockbookServer() {}
+func (UnimplementedBlockbookServer) testEmbeddedByValue()                   {}
+
+// UnsafeBlockbookServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlockbookServer will
+// result in compilation errors.
+type UnsafeBlockbookServer interface {
+	mustEmbedUnimplementedBlockbookServer()
+}
+
+func RegisterBlockbookServer(s grpc.ServiceRegistrar, srv BlockbookServer) {
+	// If the following call pancis, it indicates UnimplementedBlockbookServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Blockbook_ServiceDesc, srv)
+}
+
+func _Blockbook_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_GetTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_GetAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetAddress(ctx, req.(*GetAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetXpub_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetXpubRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetXpub(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_GetXpub_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetXpub(ctx, req.(*GetXpubRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetUtxos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUtxosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetUtxos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_GetUtxos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetUtxos(ctx, req.(*GetUtxosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_GetBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).EstimateFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_EstimateFee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetCurrentFiatRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentFiatRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetCurrentFiatRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_GetCurrentFiatRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetCurrentFiatRates(ctx, req.(*GetCurrentFiatRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetFiatRatesForTimestamps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFiatRatesForTimestampsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetFiatRatesForTimestamps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blockbook_GetFiatRatesForTimestamps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetFiatRatesForTimestamps(ctx, req.(*GetFiatRatesForTimestampsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_SubscribeNewBlock_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeNewBlockRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockbookServer).SubscribeNewBlock(m, &grpc.GenericServerStream[SubscribeNewBlockRequest, NewBlock]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Blockbook_SubscribeNewBlockServer = grpc.ServerStreamingServer[NewBlock]
+
+func _Blockbook_SubscribeAddresses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAddressesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockbookServer).SubscribeAddresses(m, &grpc.GenericServerStream[SubscribeAddressesRequest, AddressTransaction]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Blockbook_SubscribeAddressesServer = grpc.ServerStreamingServer[AddressTransaction]
+
+func _Blockbook_SubscribeFiatRates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeFiatRatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockbookServer).SubscribeFiatRates(m, &grpc.GenericServerStream[SubscribeFiatRatesRequest, FiatTicker]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Blockbook_SubscribeFiatRatesServer = grpc.ServerStreamingServer[FiatTicker]
+
+// Blockbook_ServiceDesc is the grpc.ServiceDesc for Blockbook service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Blockbook_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "blockbook.Blockbook",
+	HandlerType: (*BlockbookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTransaction",
+			Handler:    _Blockbook_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetAddress",
+			Handler:    _Blockbook_GetAddress_Handler,
+		},
+		{
+			MethodName: "GetXpub",
+			Handler:    _Blockbook_GetXpub_Handler,
+		},
+		{
+			MethodName: "GetUtxos",
+			Handler:    _Blockbook_GetUtxos_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Blockbook_GetBlock_Handler,
+		},
+		{
+			MethodName: "EstimateFee",
+			Handler:    _Blockbook_EstimateFee_Handler,
+		},
+		{
+			MethodName: "GetCurrentFiatRates",
+			Handler:    _Blockbook_GetCurrentFiatRates_Handler,
+		},
+		{
+			MethodName: "GetFiatRatesForTimestamps",
+			Handler:    _Blockbook_GetFiatRatesForTimestamps_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeNewBlock",
+			Handler:       _Blockbook_SubscribeNewBlock_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeAddresses",
+			Handler:       _Blockbook_SubscribeAddresses_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeFiatRates",
+			Handler:       _Blockbook_SubscribeFiatRates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "server/pb/blockbook.proto",
+}
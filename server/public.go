@@ -32,6 +32,10 @@ const txsOnPage = 25
 const blocksOnPage = 50
 const mempoolTxsOnPage = 50
 const txsInAPI = 1000
+const maxRichListPageSize = 100
+const maxOpReturnPageSize = 1000
+const maxNftPageSize = 100
+const maxSimulationRequestSize = 1 << 20
 
 const secondaryCoinCookieName = "secondary_coin"
 
@@ -115,6 +119,11 @@ func NewPublicServer(binding string, certFiles string, db *db.RocksDB, chain bch
 	s.htmlTemplates.newTemplateDataWithError = s.newTemplateDataWithError
 	s.htmlTemplates.parseTemplates = s.parseTemplates
 	s.htmlTemplates.postHtmlTemplateHandler = s.postHtmlTemplateHandler
+	// the rate limiter is shared by all public interfaces, the client is limited by the total cost of its requests
+	rateLimiter := NewRateLimiter(is.RateLimits, metrics)
+	s.htmlTemplates.guard.rateLimiter = rateLimiter
+	socketio.guard.rateLimiter = rateLimiter
+	websocket.guard.rateLimiter = rateLimiter
 	s.templates = s.parseTemplates()
 
 	// map only basic functions, the rest is enabled by method MapFullPublicInterface
@@ -128,6 +137,18 @@ func NewPublicServer(binding string, certFiles string, db *db.RocksDB, chain bch
 	return s, nil
 }
 
+// SetAPIKeys enables the authorization and metering of the requests by the API keys
+func (s *PublicServer) SetAPIKeys(k *APIKeys) {
+	s.htmlTemplates.guard.apiKeys = k
+	s.websocket.guard.apiKeys = k
+	s.socketio.guard.apiKeys = k
+}
+
+// RateLimiter returns the rate limiter shared by the public interfaces
+func (s *PublicServer) RateLimiter() *RateLimiter {
+	return s.websocket.guard.rateLimiter
+}
+
 // Run starts the server
 func (s *PublicServer) Run() error {
 	if s.certFiles == "" {
@@ -211,9 +232,18 @@ func (s *PublicServer) ConnectFullPublicInterface() {
 	serveMux.HandleFunc(path+"api/v2/estimatefee/", s.jsonHandler(s.apiEstimateFee, apiV2))
 	serveMux.HandleFunc(path+"api/v2/feestats/", s.jsonHandler(s.apiFeeStats, apiV2))
 	serveMux.HandleFunc(path+"api/v2/balancehistory/", s.jsonHandler(s.apiBalanceHistory, apiDefault))
+	serveMux.HandleFunc(path+"api/v2/stats/chain", s.jsonHandler(s.apiChainStats, apiV2))
+	serveMux.HandleFunc(path+"api/v2/richlist/", s.jsonHandler(s.apiRichList, apiV2))
+	serveMux.HandleFunc(path+"api/v2/approvals/", s.jsonHandler(s.apiTokenApprovals, apiV2))
+	serveMux.HandleFunc(path+"api/v2/nft/", s.jsonHandler(s.apiNft, apiV2))
+	serveMux.HandleFunc(path+"api/v2/internal-txs/", s.jsonHandler(s.apiInternalTransfers, apiV2))
+	serveMux.HandleFunc(path+"api/v2/simulate", s.jsonHandler(s.apiSimulateTransaction, apiV2))
+	serveMux.HandleFunc(path+"api/v2/opreturn", s.jsonHandler(s.apiOpReturn, apiV2))
 	serveMux.HandleFunc(path+"api/v2/tickers/", s.jsonHandler(s.apiTickers, apiV2))
 	serveMux.HandleFunc(path+"api/v2/multi-tickers/", s.jsonHandler(s.apiMultiTickers, apiV2))
 	serveMux.HandleFunc(path+"api/v2/tickers-list/", s.jsonHandler(s.apiAvailableVsCurrencies, apiV2))
+	serveMux.HandleFunc(path+"api/v2/candles", s.jsonHandler(s.apiCandles, apiV2))
+	serveMux.HandleFunc(path+"api/v2/jsonrpc", s.websocket.ServeJSONRPC)
 	// socket.io interface
 	serveMux.Handle(path+"socket.io/", s.socketio.GetHandler())
 	// websocket interface
@@ -402,8 +432,7 @@ type TemplateData struct {
 	SendTxHex                string
 	Status                   string
 	NonZeroBalanceTokens     bool
-	TokenId                  string
-	URI                      string
+	NftToken                 *api.NftToken
 	ContractInfo             *bchain.ContractInfo
 	SecondaryCoin            string
 	UseSecondaryCoin         bool
@@ -831,6 +860,8 @@ func (s *PublicServer) getAddressQueryParams(r *http.Request, accountDetails api
 			voutFilter = api.AddressFilterVoutInputs
 		} else if filterParam == "outputs" {
 			voutFilter = api.AddressFilterVoutOutputs
+		} else if filterParam == "internal" {
+			voutFilter = db.InternalTxIndexOffset
 		} else {
 			voutFilter, ec = strconv.Atoi(filterParam)
 			if ec != nil || voutFilter < 0 {
@@ -913,18 +944,17 @@ func (s *PublicServer) explorerNftDetail(w http.ResponseWriter, r *http.Request)
 	}
 	tokenId := parts[len(parts)-1]
 	contract := parts[len(parts)-2]
-	uri, ci, err := s.api.GetEthereumTokenURI(contract, tokenId)
+	token, err := s.api.GetNftToken(contract, tokenId, false)
 	s.metrics.ExplorerViews.With(common.Labels{"action": "nftDetail"}).Inc()
 	if err != nil {
 		return errorTpl, nil, api.NewAPIError(err.Error(), true)
 	}
-	if ci == nil {
+	if token.ContractInfo == nil {
 		return errorTpl, nil, api.NewAPIError(fmt.Sprintf("Unknown contract %s", contract), true)
 	}
 	data := s.newTemplateData(r)
-	data.TokenId = tokenId
-	data.ContractInfo = ci
-	data.URI = uri
+	data.NftToken = token
+	data.ContractInfo = token.ContractInfo
 	return nftDetailTpl, data, nil
 }
 
@@ -1346,7 +1376,11 @@ func (s *PublicServer) apiAddress(r *http.Request, apiVersion int) (interface{},
 	s.metrics.ExplorerViews.With(common.Labels{"action": "api-address"}).Inc()
 	page, pageSize, details, filter, _, _ := s.getAddressQueryParams(r, api.AccountDetailsTxidHistory, txsInAPI)
 	secondaryCoin := strings.ToLower(r.URL.Query().Get("secondary"))
-	address, err = s.api.GetAddress(addressParam, page, pageSize, details, filter, secondaryCoin)
+	if atBlock := r.URL.Query().Get("atBlock"); atBlock != "" {
+		address, err = s.api.GetAddressAtBlock(addressParam, atBlock, secondaryCoin)
+	} else {
+		address, err = s.api.GetAddress(addressParam, page, pageSize, details, filter, secondaryCoin)
+	}
 	if err == nil && apiVersion == apiV1 {
 		return s.api.AddressToV1(address), nil
 	}
@@ -1410,6 +1444,7 @@ func (s *PublicServer) apiUtxo(r *http.Request, apiVersion int) (interface{}, er
 
 func (s *PublicServer) apiBalanceHistory(r *http.Request, apiVersion int) (interface{}, error) {
 	var history []api.BalanceHistory
+	var historyGains *api.BalanceHistoryGains
 	var fromTimestamp, toTimestamp int64
 	var err error
 	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
@@ -1441,17 +1476,193 @@ func (s *PublicServer) apiBalanceHistory(r *http.Request, apiVersion int) (inter
 		if fiat != "" {
 			fiatArray = []string{fiat}
 		}
-		history, err = s.api.GetXpubBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, gap, uint32(groupBy))
+		gains := strings.ToLower(r.URL.Query().Get("gains"))
+		history, historyGains, err = s.api.GetXpubBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, gap, uint32(groupBy), gains)
 		if err == nil {
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub-balancehistory"}).Inc()
 		} else {
-			history, err = s.api.GetBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, uint32(groupBy))
+			history, historyGains, err = s.api.GetBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, uint32(groupBy), gains)
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-address-balancehistory"}).Inc()
 		}
 	}
+	if err == nil && historyGains != nil {
+		return &api.BalanceHistoryWithGains{Histories: history, Gains: historyGains}, nil
+	}
 	return history, err
 }
 
+func (s *PublicServer) apiChainStats(r *http.Request, apiVersion int) (interface{}, error) {
+	var fromTimestamp, toTimestamp int64
+	var err error
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-chain-stats"}).Inc()
+	from := r.URL.Query().Get("from")
+	if from != "" {
+		fromTimestamp, err = strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return nil, api.NewAPIError("Parameter 'from' is not a valid timestamp", true)
+		}
+	}
+	to := r.URL.Query().Get("to")
+	if to != "" {
+		toTimestamp, err = strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			return nil, api.NewAPIError("Parameter 'to' is not a valid timestamp", true)
+		}
+	}
+	groupBy, err := strconv.ParseUint(r.URL.Query().Get("groupBy"), 10, 32)
+	if err != nil || groupBy == 0 {
+		groupBy = 3600
+	}
+	return s.api.GetChainStats(fromTimestamp, toTimestamp, uint32(groupBy))
+}
+
+func (s *PublicServer) apiRichList(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-richlist"}).Inc()
+	var contract string
+	if i := strings.LastIndex(r.URL.Path, "richlist/"); i >= 0 {
+		contract = r.URL.Path[i+len("richlist/"):]
+	}
+	page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+	if ec != nil {
+		page = 0
+	}
+	pageSize, ec := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if ec != nil || pageSize <= 0 || pageSize > maxRichListPageSize {
+		pageSize = maxRichListPageSize
+	}
+	return s.api.GetRichList(contract, page, pageSize)
+}
+
+func (s *PublicServer) apiTokenApprovals(r *http.Request, apiVersion int) (interface{}, error) {
+	var address string
+	i := strings.LastIndexByte(r.URL.Path, '/')
+	if i > 0 {
+		address = r.URL.Path[i+1:]
+	}
+	if len(address) == 0 {
+		return nil, api.NewAPIError("Missing address", true)
+	}
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-approvals"}).Inc()
+	refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh"))
+	return s.api.GetTokenApprovals(address, refresh)
+}
+
+func (s *PublicServer) apiNft(r *http.Request, apiVersion int) (interface{}, error) {
+	var params []string
+	if i := strings.LastIndex(r.URL.Path, "nft/"); i >= 0 {
+		params = strings.Split(strings.Trim(r.URL.Path[i+len("nft/"):], "/"), "/")
+	}
+	if len(params) == 0 || params[0] == "" {
+		return nil, api.NewAPIError("Missing contract", true)
+	}
+	if len(params) > 1 {
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-nft-token"}).Inc()
+		refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh"))
+		return s.api.GetNftToken(params[0], params[1], refresh)
+	}
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-nft"}).Inc()
+	page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+	if ec != nil {
+		page = 0
+	}
+	pageSize, ec := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if ec != nil || pageSize <= 0 || pageSize > maxNftPageSize {
+		pageSize = maxNftPageSize
+	}
+	return s.api.GetNftCollection(params[0], page, pageSize)
+}
+
+func (s *PublicServer) apiInternalTransfers(r *http.Request, apiVersion int) (interface{}, error) {
+	var address string
+	i := strings.LastIndexByte(r.URL.Path, '/')
+	if i > 0 {
+		address = r.URL.Path[i+1:]
+	}
+	if len(address) == 0 {
+		return nil, api.NewAPIError("Missing address", true)
+	}
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-internal-txs"}).Inc()
+	filter := &api.InternalTransfersFilter{
+		Contract: r.URL.Query().Get("contract"),
+	}
+	if from, ec := strconv.Atoi(r.URL.Query().Get("from")); ec == nil && from > 0 {
+		filter.FromHeight = uint32(from)
+	}
+	if to, ec := strconv.Atoi(r.URL.Query().Get("to")); ec == nil && to > 0 {
+		filter.ToHeight = uint32(to)
+	}
+	if minValue := r.URL.Query().Get("minValue"); minValue != "" {
+		v, ok := new(big.Int).SetString(minValue, 10)
+		if !ok {
+			return nil, api.NewAPIError("Invalid minValue", true)
+		}
+		filter.MinValue = v
+	}
+	pageSize, ec := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if ec != nil || pageSize <= 0 || pageSize > txsInAPI {
+		pageSize = txsInAPI
+	}
+	return s.api.GetInternalTransfers(address, filter, pageSize)
+}
+
+// the values of the simulate_transaction option, the simulation is disabled by default
+const (
+	simulateTransactionPublic = "public"
+	simulateTransactionAPIKey = "apikey"
+)
+
+// checkSimulationAllowed checks the access to the transaction simulation configured by the simulate_transaction option,
+// the simulation with the state overrides is expensive for the backend and is not allowed to anonymous clients unless it is public
+func checkSimulationAllowed(is *common.InternalState, apiKey string) error {
+	switch is.SimulateTransaction {
+	case simulateTransactionPublic:
+		return nil
+	case simulateTransactionAPIKey:
+		if apiKey == "" {
+			return api.NewAPIError("Transaction simulation requires an API key", true)
+		}
+		return nil
+	}
+	return api.NewAPIError("Transaction simulation is disabled", true)
+}
+
+// apiSimulateTransaction simulates the transaction posted as a JSON object with the fields of the eth_call transaction
+func (s *PublicServer) apiSimulateTransaction(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-simulate"}).Inc()
+	if err := checkSimulationAllowed(s.is, apiKeyFromRequest(r)); err != nil {
+		return nil, err
+	}
+	if r.Method != http.MethodPost {
+		return nil, api.NewAPIError("Transaction must be posted as a JSON object", true)
+	}
+	var params map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxSimulationRequestSize)).Decode(&params); err != nil {
+		return nil, api.NewAPIError(fmt.Sprintf("Invalid transaction, %v", err), true)
+	}
+	return s.api.SimulateTransaction(params)
+}
+
+func (s *PublicServer) apiOpReturn(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-opreturn"}).Inc()
+	from, ec := strconv.Atoi(r.URL.Query().Get("from"))
+	if ec != nil {
+		from = 0
+	}
+	to, ec := strconv.Atoi(r.URL.Query().Get("to"))
+	if ec != nil {
+		to = 0
+	}
+	page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+	if ec != nil {
+		page = 0
+	}
+	pageSize, ec := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if ec != nil || pageSize <= 0 || pageSize > maxOpReturnPageSize {
+		pageSize = maxOpReturnPageSize
+	}
+	return s.api.GetOpReturnOutputs(r.URL.Query().Get("prefix"), from, to, page, pageSize)
+}
+
 func (s *PublicServer) apiBlock(r *http.Request, apiVersion int) (interface{}, error) {
 	var block *api.Block
 	var err error
@@ -1606,6 +1817,29 @@ func (s *PublicServer) apiMultiTickers(r *http.Request, apiVersion int) (interfa
 	return result, nil
 }
 
+// apiCandles returns open, high, low and close FiatRates prices in the intervals of the specified time range.
+func (s *PublicServer) apiCandles(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-candles"}).Inc()
+	q := r.URL.Query()
+	var from, to int64
+	var err error
+	if f := q.Get("from"); f != "" {
+		if from, err = strconv.ParseInt(f, 10, 64); err != nil {
+			return nil, api.NewAPIError("Parameter 'from' is not a valid Unix timestamp.", true)
+		}
+	}
+	if t := q.Get("to"); t != "" {
+		if to, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return nil, api.NewAPIError("Parameter 'to' is not a valid Unix timestamp.", true)
+		}
+	}
+	interval := q.Get("interval")
+	if interval == "" {
+		interval = fiat.CandleInterval1h
+	}
+	return s.api.GetFiatRatesCandles(strings.ToLower(q.Get("currency")), strings.ToLower(q.Get("token")), interval, from, to)
+}
+
 type resultEstimateFeeAsString struct {
 	Result string `json:"result"`
 }
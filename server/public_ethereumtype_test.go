@@ -4,6 +4,8 @@
 package server
 
 import (
+	"encoding/hex"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -51,7 +53,7 @@ func httpTestsEthereumType(t *testing.T, ts *httptest.Server) {
 			r:           newGetRequest(ts.URL + "/nft/" + dbtestdata.EthAddrContractCd + "/" + "1"),
 			status:      http.StatusOK,
 			contentType: "text/html; charset=utf-8",
-			body:        []string{`<!doctype html><html lang="en"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1.0,shrink-to-fit=no"><link rel="stylesheet" href="/static/css/bootstrap.5.2.2.min.css"><link rel="stylesheet" href="/static/css/main.min.4.css"><script>var hasSecondary=true;</script><script src="/static/js/bootstrap.bundle.5.2.2.min.js"></script><script src="/static/js/main.min.4.js"></script><meta http-equiv="X-UA-Compatible" content="IE=edge"><meta name="description" content="Trezor Fake Coin Explorer"><title>Trezor Fake Coin Explorer</title></head><body><header id="header"><nav class="navbar navbar-expand-lg"><div class="container"><a class="navbar-brand" href="/" title="Home"><span class="trezor-logo"></span><span style="padding-left: 140px;">Fake Coin Explorer</span></a><button class="navbar-toggler" type="button" data-bs-toggle="collapse" data-bs-target="#navbarSupportedContent" aria-controls="navbarSupportedContent" aria-expanded="false" aria-label="Toggle navigation"><span class="navbar-toggler-icon"></span></button><div class="collapse navbar-collapse" id="navbarSupportedContent"><ul class="navbar-nav m-md-auto"><li class="nav-item pe-xl-4"><a href="/blocks" class="nav-link">Blocks</a></li><li class="nav-item"><a href="/" class="nav-link">Status</a></li></ul><span class="navbar-form"><form class="d-flex" id="search" action="/search" method="get"><input name="q" type="text" class="form-control form-control-lg" placeholder="Search for block, transaction, address or xpub" focus="true"><button class="btn" type="submit"><span class="search-icon"></span></button></form></span><div class="bb-group ms-lg-2 mt-2 mt-lg-0" role="group" aria-label="Currency switch"><input type="radio" class="btn-check" name="btnradio" id="primary-coin" autocomplete="off" checked><label class="btn" for="primary-coin">FAKE</label><input type="radio" class="btn-check" name="btnradio" id="secondary-coin" autocomplete="off"><label class="btn" for="secondary-coin">USD</label><button type="button" class="btn dropdown-toggle" data-bs-toggle="dropdown" aria-expanded="false"></button><div class="dropdown-menu row"><div class="col-3"><a href="?secondary=EUR&use_secondary=true">EUR</a></div><div class="col-3"><a href="?secondary=USD&use_secondary=true">USD</a></div></div></div></div></div></nav></header><main id="wrap"><div class="container"><h1>NFT Token Detail</h1><div class="row"><div class="col-md-6"><table class="table data-table info-table"><tbody><tr><td style="width: 25%;">Token ID</td><td><span class="copyable">1</span></td></tr><tr id="name" style="display: none;"><td>NTF Name</td><td class="copyable"></td></tr><tr id="description" style="display: none;"><td>NTF Description</td><td></td></tr><tr><td>Contract</td><td><a href="/address/0xcdA9FC258358EcaA88845f19Af595e908bb7EfE9"><span class="copyable">0xcdA9FC258358EcaA88845f19Af595e908bb7EfE9</span></a><br>Contract 205</td></tr><tr><td>Standard</td><td>ERC20</td></tr></tbody></table></div><div class="col-md-6 mt-4" id="image"></div></div><div id="metadatablock"><h5>Metadata</h5><div class="json"><pre id="raw">Loading metadata from <a href="https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.json">https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.json</a>...</pre></div></div><script type="text/javascript">function showImage(s) {const img = document.createElement("img");img.className="border w-100 bg-white";img.src = s;const src = document.getElementById("image");src.appendChild(img);src.style.display="block";}function nftInfo(id,text) {const src = document.getElementById(id);src.getElementsByTagName("td")[1].innerText=text;src.style.display='';}async function getMetadata(url) {try {const uri="https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.json";if(uri) {const response = await fetch(uri);const contentType=response.headers.get('content-type');if(contentType&&contentType.toString().startsWith("image/")) {showImage(uri);document.getElementById("metadatablock").style.display='none';} else {const data = await response.json();document.getElementById("raw").innerHTML = syntaxHighlight(data);if (data.name) {nftInfo('name',data.name)}if (data.description) {nftInfo('description',data.description)}if (data.image||data.image_url) {let s=data.image?.toString();if(!s) {s=data.image_url;}if(s.startsWith("ipfs://")) {s=s.replace("ipfs://","https://ipfs.io/ipfs/");}if(s.startsWith("https://")) {showImage(s);}}}} else {document.getElementById("raw").innerText = "Error: cannot get metadata link from blockchain";}} catch(e) {document.getElementById("raw").innerText = "Error loading metadata: "+e;}}getMetadata();</script></div></main><footer id="footer"><div class="container"><nav class="navbar navbar-dark"><span class="navbar-nav"><a class="nav-link" href="https://satoshilabs.com/" target="_blank" rel="noopener noreferrer">Created by SatoshiLabs</a></span><span class="navbar-nav ml-md-auto"><a class="nav-link" href="https://trezor.io/terms-of-use" target="_blank" rel="noopener noreferrer">Terms of Use</a></span><span class="navbar-nav ml-md-auto d-md-flex d-none"><a class="nav-link" href="https://trezor.io/" target="_blank" rel="noopener noreferrer">Trezor</a></span><span class="navbar-nav ml-md-auto d-md-flex d-none"><a class="nav-link" href="https://trezor.io/trezor-suite" target="_blank" rel="noopener noreferrer">Suite</a></span><span class="navbar-nav ml-md-auto d-md-flex d-none"><a class="nav-link" href="https://trezor.io/support" target="_blank" rel="noopener noreferrer">Support</a></span><span class="navbar-nav ml-md-auto"><a class="nav-link" href="/sendtx">Send Transaction</a></span><span class="navbar-nav ml-md-auto d-lg-flex d-none"><a class="nav-link" href="https://trezor.io/compare" target="_blank" rel="noopener noreferrer">Don't have a Trezor? Get one!</a></span></nav></div></footer></body></html>`},
+			body:        []string{`<!doctype html><html lang="en"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1.0,shrink-to-fit=no"><link rel="stylesheet" href="/static/css/bootstrap.5.2.2.min.css"><link rel="stylesheet" href="/static/css/main.min.4.css"><script>var hasSecondary=true;</script><script src="/static/js/bootstrap.bundle.5.2.2.min.js"></script><script src="/static/js/main.min.4.js"></script><meta http-equiv="X-UA-Compatible" content="IE=edge"><meta name="description" content="Trezor Fake Coin Explorer"><title>Trezor Fake Coin Explorer</title></head><body><header id="header"><nav class="navbar navbar-expand-lg"><div class="container"><a class="navbar-brand" href="/" title="Home"><span class="trezor-logo"></span><span style="padding-left: 140px;">Fake Coin Explorer</span></a><button class="navbar-toggler" type="button" data-bs-toggle="collapse" data-bs-target="#navbarSupportedContent" aria-controls="navbarSupportedContent" aria-expanded="false" aria-label="Toggle navigation"><span class="navbar-toggler-icon"></span></button><div class="collapse navbar-collapse" id="navbarSupportedContent"><ul class="navbar-nav m-md-auto"><li class="nav-item pe-xl-4"><a href="/blocks" class="nav-link">Blocks</a></li><li class="nav-item"><a href="/" class="nav-link">Status</a></li></ul><span class="navbar-form"><form class="d-flex" id="search" action="/search" method="get"><input name="q" type="text" class="form-control form-control-lg" placeholder="Search for block, transaction, address or xpub" focus="true"><button class="btn" type="submit"><span class="search-icon"></span></button></form></span><div class="bb-group ms-lg-2 mt-2 mt-lg-0" role="group" aria-label="Currency switch"><input type="radio" class="btn-check" name="btnradio" id="primary-coin" autocomplete="off" checked><label class="btn" for="primary-coin">FAKE</label><input type="radio" class="btn-check" name="btnradio" id="secondary-coin" autocomplete="off"><label class="btn" for="secondary-coin">USD</label><button type="button" class="btn dropdown-toggle" data-bs-toggle="dropdown" aria-expanded="false"></button><div class="dropdown-menu row"><div class="col-3"><a href="?secondary=EUR&use_secondary=true">EUR</a></div><div class="col-3"><a href="?secondary=USD&use_secondary=true">USD</a></div></div></div></div></div></nav></header><main id="wrap"><div class="container"><h1>NFT Token Detail</h1><div class="row"><div class="col-md-6"><table class="table data-table info-table"><tbody><tr><td style="width: 25%;">Token ID</td><td><span class="copyable">1</span></td></tr><tr id="name" style="display: none;"><td>NTF Name</td><td class="copyable"></td></tr><tr id="description" style="display: none;"><td>NTF Description</td><td></td></tr><tr><td>Contract</td><td><a href="/address/0xcdA9FC258358EcaA88845f19Af595e908bb7EfE9"><span class="copyable">0xcdA9FC258358EcaA88845f19Af595e908bb7EfE9</span></a><br>Contract 205</td></tr><tr><td>Standard</td><td>ERC20</td></tr><tr><td>Owner</td><td><a href="/address/0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b"><span class="copyable">0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b</span></a> (1)</td></tr></tbody></table></div><div class="col-md-6 mt-4" id="image"><img class="border w-100 bg-white" src="https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.png"></div></div><div id="metadatablock"><h5>Metadata</h5><div class="json"><pre id="raw"></pre></div></div><script type="text/javascript">function nftInfo(id,text) {const src = document.getElementById(id);src.getElementsByTagName("td")[1].innerText=text;src.style.display='';}const metadata = {"name":"Test NFT","description":"Token for tests","image":"ipfs://cda9fc258358ecaa88845f19af595e908bb7efe9.png"};document.getElementById("raw").innerHTML = syntaxHighlight(metadata);if (metadata.name) {nftInfo('name',metadata.name);}if (metadata.description) {nftInfo('description',metadata.description);}</script><div class="row pt-3 pb-1"><h3 class="col-12">Owner History</h3></div><table class="table data-table"><tbody><tr><th style="width: 30%;">Transaction</th><th style="width: 10%;">Block</th><th style="width: 25%;">From</th><th style="width: 25%;">To</th><th class="text-end" style="width: 10%;">Quantity</th></tr><tr><td class="ellipsis"><a href="/tx/0xca7628be5c80cda77163729ec63d218ee868a399d827a4682a478c6f48a6e22a">0xca7628be5c80cda77163729ec63d218ee868a399d827a4682a478c6f48a6e22a</a></td><td><a href="/block/4321001">4<span class="ns">321</span><span class="ns">001</span></a></td><td class="ellipsis"><a href="/address/0x837E3f699d85a4b0B99894567e9233dFB1DcB081"><span class="copyable">0x837E3f699d85a4b0B99894567e9233dFB1DcB081</span></a></td><td class="ellipsis"><a href="/address/0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b"><span class="copyable">0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b</span></a></td><td class="text-end">1</td></tr></tbody></table></div></main><footer id="footer"><div class="container"><nav class="navbar navbar-dark"><span class="navbar-nav"><a class="nav-link" href="https://satoshilabs.com/" target="_blank" rel="noopener noreferrer">Created by SatoshiLabs</a></span><span class="navbar-nav ml-md-auto"><a class="nav-link" href="https://trezor.io/terms-of-use" target="_blank" rel="noopener noreferrer">Terms of Use</a></span><span class="navbar-nav ml-md-auto d-md-flex d-none"><a class="nav-link" href="https://trezor.io/" target="_blank" rel="noopener noreferrer">Trezor</a></span><span class="navbar-nav ml-md-auto d-md-flex d-none"><a class="nav-link" href="https://trezor.io/trezor-suite" target="_blank" rel="noopener noreferrer">Suite</a></span><span class="navbar-nav ml-md-auto d-md-flex d-none"><a class="nav-link" href="https://trezor.io/support" target="_blank" rel="noopener noreferrer">Support</a></span><span class="navbar-nav ml-md-auto"><a class="nav-link" href="/sendtx">Send Transaction</a></span><span class="navbar-nav ml-md-auto d-lg-flex d-none"><a class="nav-link" href="https://trezor.io/compare" target="_blank" rel="noopener noreferrer">Don't have a Trezor? Get one!</a></span></nav></div></footer></body></html>`},
 		},
 		{
 			name:        "apiIndex",
@@ -75,6 +77,78 @@ func httpTestsEthereumType(t *testing.T, ts *httptest.Server) {
 				`{"page":1,"totalPages":1,"itemsOnPage":1000,"address":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","balance":"123450075","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":1,"nonTokenTxs":1,"internalTxs":1,"txids":["0xc92919ad24ffd58f760b18df7949f06e1190cf54a50a0e3745a385608ed3cbf2"],"nonce":"75","tokens":[{"type":"ERC20","standard":"ERC20","name":"Contract 13","contract":"0x0d0F936Ee4c93e25944694D6C121de94D9760F11","transfers":2,"symbol":"S13","decimals":18,"balance":"1000075013"},{"type":"ERC20","standard":"ERC20","name":"Contract 74","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","transfers":2,"symbol":"S74","decimals":12,"balance":"1000075074"}]}`,
 			},
 		},
+		{
+			name:        "apiAddress EthAddr4b atBlock",
+			r:           newGetRequest(ts.URL + "/api/v2/address/" + dbtestdata.EthAddr4b + "?atBlock=4321000"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"address":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","balance":"123400075","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":0,"nonce":"37","tokens":[{"type":"ERC20","standard":"ERC20","name":"Contract 13","contract":"0x0d0F936Ee4c93e25944694D6C121de94D9760F11","transfers":0,"symbol":"S13","decimals":18,"balance":"900075013"},{"type":"ERC20","standard":"ERC20","name":"Contract 74","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","transfers":0,"symbol":"S74","decimals":12,"balance":"900075074"}],"atBlockHeight":4321000,"atBlockHash":"0xc7b98df95acfd11c51ba25611a39e004fe56c8fdfc1582af99354fcd09c17b11","atBlockTime":1534858022}`,
+			},
+		},
+		{
+			name:        "apiAddress EthAddr4b atBlock not found",
+			r:           newGetRequest(ts.URL + "/api/v2/address/" + dbtestdata.EthAddr4b + "?atBlock=4321999"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Block not found"}`,
+			},
+		},
+		{
+			name:        "apiNftCollection EthAddrContract6f",
+			r:           newGetRequest(ts.URL + "/api/v2/nft/" + dbtestdata.EthAddrContract6f),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":100,"contractInfo":{"type":"ERC20","standard":"ERC20","contract":"0x6Fd712E3A5B556654044608F9129040A4839E36c","name":"Contract 111","symbol":"S111","decimals":18,"createdInBlock":12345},"tokens":[{"id":"150","owner":"0x3E3a3D69dc66bA10737F531ed088954a9EC89d97","balance":"1"},{"id":"1776","owner":"0x5Dc6288b35E0807A3d6fEB89b3a2Ff4aB773168e","balance":"1"},{"id":"1898","owner":"0x5Dc6288b35E0807A3d6fEB89b3a2Ff4aB773168e","balance":"10"}]}`,
+			},
+		},
+		{
+			name:        "apiNftToken EthAddrContractCd 1",
+			r:           newGetRequest(ts.URL + "/api/v2/nft/" + dbtestdata.EthAddrContractCd + "/1"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"contractInfo":{"type":"ERC20","standard":"ERC20","contract":"0xcdA9FC258358EcaA88845f19Af595e908bb7EfE9","name":"Contract 205","symbol":"S205","decimals":18,"createdInBlock":12345},"id":"1","owners":[{"address":"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b","balance":"1"}],"uri":"https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.json","imageUri":"https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.png","metadata":{"name":"Test NFT","description":"Token for tests","image":"ipfs://cda9fc258358ecaa88845f19af595e908bb7efe9.png"},"metadataTime":1700000000,"transfers":[{"txid":"0xca7628be5c80cda77163729ec63d218ee868a399d827a4682a478c6f48a6e22a","blockHeight":4321001,"from":"0x837E3f699d85a4b0B99894567e9233dFB1DcB081","to":"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b","value":"1"}],"addressAliases":{"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b":{"Type":"ENS","Alias":"address7b.eth"}}}`,
+			},
+		},
+		{
+			name:        "apiInternalTransfers EthAddr7b",
+			r:           newGetRequest(ts.URL + "/api/v2/internal-txs/" + dbtestdata.EthAddr7b),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Internal transactions are not processed"}`,
+			},
+		},
+		{
+			name:        "apiSimulateTransaction",
+			r:           newPostRequest(ts.URL+"/api/v2/simulate", `{"from":"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b","to":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","value":"0x3e8"}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"success":true,"gasUsed":51234,"gasPrice":"1000000000","fee":"51234000000000","balanceChanges":[{"address":"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b","delta":"-51234000001000","tokens":[{"standard":"ERC20","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","name":"Contract 74","symbol":"S74","decimals":12,"delta":"-1000"}]},{"address":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","delta":"1000","tokens":[{"standard":"ERC20","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","name":"Contract 74","symbol":"S74","decimals":12,"delta":"1000"}]}],"tokenTransfers":[{"type":"ERC20","standard":"ERC20","from":"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b","to":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","name":"Contract 74","symbol":"S74","decimals":12,"value":"1000"}],"addressAliases":{"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b":{"Type":"ENS","Alias":"address7b.eth"}}}`,
+			},
+		},
+		{
+			name:        "apiSimulateTransaction missing from",
+			r:           newPostRequest(ts.URL+"/api/v2/simulate", `{"to":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D"}`),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Missing parameter from"}`,
+			},
+		},
+		{
+			name:        "apiTokenApprovals EthAddr4b",
+			r:           newGetRequest(ts.URL + "/api/v2/approvals/" + dbtestdata.EthAddr4b),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"address":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","approvals":[]}`,
+			},
+		},
 		{
 			name:        "apiAddress EthAddr7b details=txs",
 			r:           newGetRequest(ts.URL + "/api/v2/address/" + dbtestdata.EthAddr7b + "?details=txs"),
@@ -164,6 +238,20 @@ var websocketTestsEthereumType = []websocketTest{
 		},
 		want: `{"id":"2","data":{"result":"9876"}}`,
 	},
+	{
+		name: "websocket simulateTransaction",
+		req: websocketReq{
+			Method: "simulateTransaction",
+			Params: WsSimulateTransactionReq{
+				Tx: map[string]interface{}{
+					"from":  "0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b",
+					"to":    "0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D",
+					"value": "0x3e8",
+				},
+			},
+		},
+		want: `{"id":"3","data":{"success":true,"gasUsed":51234,"gasPrice":"1000000000","fee":"51234000000000","balanceChanges":[{"address":"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b","delta":"-51234000001000","tokens":[{"standard":"ERC20","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","name":"Contract 74","symbol":"S74","decimals":12,"delta":"-1000"}]},{"address":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","delta":"1000","tokens":[{"standard":"ERC20","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","name":"Contract 74","symbol":"S74","decimals":12,"delta":"1000"}]}],"tokenTransfers":[{"type":"ERC20","standard":"ERC20","from":"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b","to":"0x4Bda106325C335dF99eab7fE363cAC8A0ba2a24D","contract":"0x4af4114F73d1c1C903aC9E0361b379D1291808A2","name":"Contract 74","symbol":"S74","decimals":12,"value":"1000"}],"addressAliases":{"0x7B62EB7fe80350DC7EC945C0B73242cb9877FB1b":{"Type":"ENS","Alias":"address7b.eth"}}}}`,
+	},
 }
 
 func initEthereumTypeDB(d *db.RocksDB) error {
@@ -176,7 +264,20 @@ func initEthereumTypeDB(d *db.RocksDB) error {
 	}); err != nil {
 		return err
 	}
-	return d.WriteBatch(wb)
+	if err := d.WriteBatch(wb); err != nil {
+		return err
+	}
+	// cache the metadata of the NFT token, the tests must not download them
+	contract, err := hex.DecodeString(dbtestdata.EthAddrContractCd)
+	if err != nil {
+		return err
+	}
+	return d.StoreNftMetadata(contract, big.NewInt(1), &db.NftMetadata{
+		URI:      "https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.json",
+		ImageURI: "https://ipfs.io/ipfs/cda9fc258358ecaa88845f19af595e908bb7efe9.png",
+		Metadata: `{"name":"Test NFT","description":"Token for tests","image":"ipfs://cda9fc258358ecaa88845f19af595e908bb7efe9.png"}`,
+		Time:     1700000000,
+	})
 }
 
 // initTestFiatRatesEthereumType initializes test data for /api/v2/tickers endpoint
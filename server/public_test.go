@@ -102,6 +102,7 @@ func setupPublicHTTPServer(parser bchain.BlockChainParser, chain bchain.BlockCha
 		CoinShortcut:    "FAKE",
 		FiatRates:       "coingecko",
 		FiatRatesParams: `{"url": "none", "coin": "ethereum","platformIdentifier": "ethereum","platformVsCurrency": "usd","periodSeconds": 60}`,
+		OpReturnIndex:   true,
 	}
 
 	// add block golomb filters with extended index
@@ -110,6 +111,7 @@ func setupPublicHTTPServer(parser bchain.BlockChainParser, chain bchain.BlockCha
 	}
 
 	d, is, path := setupRocksDB(parser, chain, t, extendedIndex, &config)
+	is.SimulateTransaction = simulateTransactionPublic
 
 	var err error
 	// metrics can be setup only once
@@ -625,6 +627,24 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`{"ts":1574380800,"available_currencies":["eur","usd"]}`,
 			},
 		},
+		{
+			name:        "apiCandles daily",
+			r:           newGetRequest(ts.URL + "/api/v2/candles?currency=USD&interval=1d&from=1521417600&to=1521936000"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"currency":"usd","interval":"1d","from":1521417600,"to":1521936000,"candles":[{"ts":1521504000,"open":2000,"high":2000,"low":2000,"close":2000},{"ts":1521590400,"open":2000,"high":2001,"low":2000,"close":2001},{"ts":1521676800,"open":2001,"high":2002,"low":2001,"close":2002},{"ts":1521849600,"open":2002,"high":2003,"low":2002,"close":2003}]}`,
+			},
+		},
+		{
+			name:        "apiCandles invalid interval",
+			r:           newGetRequest(ts.URL + "/api/v2/candles?currency=usd&interval=1w"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"invalid interval \"1w\", expected 5m, 1h or 1d"}`,
+			},
+		},
 		{
 			name:        "apiAddress v1",
 			r:           newGetRequest(ts.URL + "/api/v1/address/mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"),
@@ -859,6 +879,96 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`[{"time":1521594000,"txs":1,"received":"118641975500","sent":"1","sentToSelf":"118641975500","rates":{"eur":1302,"usd":2002}}]`,
 			},
 		},
+		{
+			name:        "apiChainStats",
+			r:           newGetRequest(ts.URL + "/api/v2/stats/chain"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"startHeight":225493,"height":225494,"time":1521595678,"dustLimit":"546","txs":6,"outputs":{"nonstandard":{"count":1,"value":"0"},"opreturn":{"count":1,"value":"0"},"p2pkh":{"count":8,"value":"2669237822766"},"p2sh":{"count":4,"value":"118641994377"}},"dustOutputs":1,"utxos":8,"utxosValue":"1236027953737","dustUtxos":0}`,
+			},
+		},
+		{
+			name:        "apiChainStats from=1521504000&groupBy=86400",
+			r:           newGetRequest(ts.URL + "/api/v2/stats/chain?from=1521504000&groupBy=86400"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"startHeight":225493,"height":225494,"time":1521595678,"dustLimit":"546","txs":6,"outputs":{"nonstandard":{"count":1,"value":"0"},"opreturn":{"count":1,"value":"0"},"p2pkh":{"count":8,"value":"2669237822766"},"p2sh":{"count":4,"value":"118641994377"}},"dustOutputs":1,"utxos":8,"utxosValue":"1236027953737","dustUtxos":0,"history":[{"time":1521504000,"fromHeight":225493,"toHeight":225493,"txs":2,"outputs":{"p2pkh":{"count":4,"value":"1234667914813"},"p2sh":{"count":2,"value":"9877"}},"dustOutputs":1,"utxos":6,"utxosValue":"1234667924690","dustUtxos":1},{"time":1521590400,"fromHeight":225494,"toHeight":225494,"txs":4,"outputs":{"nonstandard":{"count":1,"value":"0"},"opreturn":{"count":1,"value":"0"},"p2pkh":{"count":4,"value":"1434569907953"},"p2sh":{"count":2,"value":"118641984500"}},"dustOutputs":0,"utxos":8,"utxosValue":"1236027953737","dustUtxos":0}]}`,
+			},
+		},
+		{
+			name:        "apiChainStats from",
+			r:           newGetRequest(ts.URL + "/api/v2/stats/chain?from=abc"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Parameter 'from' is not a valid timestamp"}`,
+			},
+		},
+		{
+			name:        "apiRichList",
+			r:           newGetRequest(ts.URL + "/api/v2/richlist/"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":100,"addresses":[{"rank":1,"address":"mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL","balance":"917283951061","txs":1,"firstSeenHeight":225494,"lastSeenHeight":225494},{"rank":2,"address":"mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP","balance":"198641975500","txs":1,"firstSeenHeight":225494,"lastSeenHeight":225494},{"rank":3,"address":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","balance":"118641975500","txs":1,"firstSeenHeight":225494,"lastSeenHeight":225494},{"rank":4,"address":"mzVznVsCHkVHX9UN8WPFASWUUHtxnNn4Jj","balance":"1360030331","txs":1,"firstSeenHeight":225494,"lastSeenHeight":225494},{"rank":5,"address":"mfcWp7DB6NuaZsExybTTXpVgWz559Np4Ti","balance":"100000000","txs":1,"firstSeenHeight":225493,"lastSeenHeight":225493},{"rank":6,"address":"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz","balance":"12345","txs":2,"firstSeenHeight":225493,"lastSeenHeight":225494},{"rank":7,"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","balance":"9000","txs":2,"firstSeenHeight":225493,"lastSeenHeight":225494}]}`,
+			},
+		},
+		{
+			name:        "apiRichList page=2&pageSize=3",
+			r:           newGetRequest(ts.URL + "/api/v2/richlist/?page=2&pageSize=3"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":2,"totalPages":3,"itemsOnPage":3,"addresses":[{"rank":4,"address":"mzVznVsCHkVHX9UN8WPFASWUUHtxnNn4Jj","balance":"1360030331","txs":1,"firstSeenHeight":225494,"lastSeenHeight":225494},{"rank":5,"address":"mfcWp7DB6NuaZsExybTTXpVgWz559Np4Ti","balance":"100000000","txs":1,"firstSeenHeight":225493,"lastSeenHeight":225493},{"rank":6,"address":"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz","balance":"12345","txs":2,"firstSeenHeight":225493,"lastSeenHeight":225494}]}`,
+			},
+		},
+		{
+			name:        "apiRichList contract",
+			r:           newGetRequest(ts.URL + "/api/v2/richlist/mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Contract is supported only by Ethereum type coins"}`,
+			},
+		},
+		{
+			name:        "apiOpReturn",
+			r:           newGetRequest(ts.URL + "/api/v2/opreturn?prefix=2020"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":1000,"prefix":"2020","outputs":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","vout":2,"height":225494,"data":"2020f1686f6a20"}]}`,
+			},
+		},
+		{
+			name:        "apiOpReturn from=225495",
+			r:           newGetRequest(ts.URL + "/api/v2/opreturn?prefix=2020f1686f6a20&from=225495"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":1000,"prefix":"2020f1686f6a20","outputs":[]}`,
+			},
+		},
+		{
+			name:        "apiOpReturn no match",
+			r:           newGetRequest(ts.URL + "/api/v2/opreturn?prefix=2021"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":1000,"prefix":"2021","outputs":[]}`,
+			},
+		},
+		{
+			name:        "apiOpReturn invalid prefix",
+			r:           newGetRequest(ts.URL + "/api/v2/opreturn?prefix=xyz"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Invalid prefix"}`,
+			},
+		},
 		{
 			name:        "apiSendTx",
 			r:           newGetRequest(ts.URL + "/api/v2/sendtx/1234567890"),
@@ -913,6 +1023,43 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`{"hex":"00e0ff3fd42677a86f1515bafcf9802c1765e02226655a9b97fd44132602000000000000"}`,
 			},
 		},
+		{
+			name:        "apiJSONRPC getBlockHash",
+			r:           newPostRequest(ts.URL+"/api/v2/jsonrpc", `{"jsonrpc":"2.0","id":1,"method":"getBlockHash","params":{"height":225494}}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"jsonrpc":"2.0","id":1,"result":{"hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6"}}`,
+			},
+		},
+		{
+			name:        "apiJSONRPC batch",
+			r:           newPostRequest(ts.URL+"/api/v2/jsonrpc", `[{"jsonrpc":"2.0","id":"a","method":"ping"},{"jsonrpc":"2.0","method":"ping"},{"jsonrpc":"2.0","id":"b","method":"subscribeNewBlock"},{"jsonrpc":"2.0","id":"c","method":"getBlockHash","params":[225494]},{"jsonrpc":"2.0","id":"d","method":"getBlockHash","params":{"height":"x"}},1]`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`[{"jsonrpc":"2.0","id":"a","result":{}},{"jsonrpc":"2.0","id":"b","error":{"code":-32601,"message":"Method not found"}},{"jsonrpc":"2.0","id":"c","error":{"code":-32602,"message":"Invalid params, only named params are supported"}},{"jsonrpc":"2.0","id":"d","error":{"code":-32602,"message":"json: cannot unmarshal`,
+				`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request"}}]`,
+			},
+		},
+		{
+			name:        "apiJSONRPC parse error",
+			r:           newPostRequest(ts.URL+"/api/v2/jsonrpc", `{"jsonrpc":"2.0","method"`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`,
+			},
+		},
+		{
+			name:        "apiJSONRPC GET",
+			r:           newGetRequest(ts.URL + "/api/v2/jsonrpc"),
+			status:      http.StatusMethodNotAllowed,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Only POST method is supported"}}`,
+			},
+		},
 	}
 	performHttpTests(tests, t, ts)
 }
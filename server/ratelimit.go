@@ -0,0 +1,251 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/common"
+)
+
+const (
+	defaultKeyBurstSeconds     = 10
+	rateLimiterCleanupInterval = time.Minute
+)
+
+// rateLimitError is returned when the client does not have enough tokens for the request
+type rateLimitError struct {
+	retryAfter int
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("Rate limit exceeded, retry after %d seconds", e.retryAfter)
+}
+
+// retryAfterHeader sets the Retry-After header if the error is a rate limit error
+func retryAfterHeader(w http.ResponseWriter, err error) {
+	if e, ok := err.(*rateLimitError); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(e.retryAfter))
+	}
+}
+
+type tokenBucket struct {
+	tokens  float64
+	rate    float64
+	burst   float64
+	updated time.Time
+}
+
+// refill adds the tokens for the time elapsed since the last update
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.updated).Seconds()*b.rate)
+	b.updated = now
+}
+
+// costFunc returns the additional cost of the request computed from its result
+type costFunc func(result interface{}) float64
+
+// methodCostFuncs are the cost functions of the methods whose cost depends on the amount of returned data,
+// the methods not listed have only the base cost
+var methodCostFuncs = map[string]costFunc{
+	"getAccountInfo":    addressCost,
+	"address":           addressCost,
+	"xpub":              addressCost,
+	"getAccountUtxo":    utxosCost,
+	"utxo":              utxosCost,
+	"getBalanceHistory": balanceHistoryCost,
+	"balancehistory":    balanceHistoryCost,
+	"block":             blockCost,
+	"getBlock":          blockCost,
+	"GetAddress":        addressCost,
+	"GetXpub":           addressCost,
+	"GetUtxos":          utxosCost,
+	"GetBlock":          blockCost,
+}
+
+// addressCost counts each returned transaction, the txids and tokens are cheaper
+func addressCost(result interface{}) float64 {
+	if a, ok := result.(*api.Address); ok && a != nil {
+		return float64(len(a.Transactions)) + float64(len(a.Txids)+len(a.Tokens))/10
+	}
+	return 0
+}
+
+func utxosCost(result interface{}) float64 {
+	switch r := result.(type) {
+	case api.Utxos:
+		return float64(len(r)) / 10
+	case []api.Utxo:
+		return float64(len(r)) / 10
+	}
+	return 0
+}
+
+// balanceHistoryCost counts the transactions scanned for the balance history
+func balanceHistoryCost(result interface{}) float64 {
+	var bhs []api.BalanceHistory
+	switch r := result.(type) {
+	case api.BalanceHistories:
+		bhs = r
+	case []api.BalanceHistory:
+		bhs = r
+	}
+	var txs uint32
+	for i := range bhs {
+		txs += bhs[i].Txs
+	}
+	return float64(txs) / 10
+}
+
+func blockCost(result interface{}) float64 {
+	if b, ok := result.(*api.Block); ok && b != nil {
+		return float64(len(b.Transactions)) / 10
+	}
+	return 0
+}
+
+// RateLimiter limits the cost of the requests of the clients identified by the IP address or by the API key using token buckets
+// the base cost of the method is consumed before the request is processed, the additional cost computed from the result after it,
+// the bucket can therefore get negative and the client must wait until it is replenished
+type RateLimiter struct {
+	config      common.RateLimits
+	metrics     *common.Metrics
+	mux         sync.Mutex
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+// NewRateLimiter creates the rate limiter, if config is nil, only the API keys with a rate limit are limited
+func NewRateLimiter(config *common.RateLimits, metrics *common.Metrics) *RateLimiter {
+	l := &RateLimiter{
+		metrics:     metrics,
+		buckets:     make(map[string]*tokenBucket),
+		lastCleanup: time.Now(),
+	}
+	if config != nil {
+		l.config = *config
+	}
+	if l.config.KeyBurstSeconds <= 0 {
+		l.config.KeyBurstSeconds = defaultKeyBurstSeconds
+	}
+	return l
+}
+
+// baseCost returns the configured base cost of the method, 1 by default
+func (l *RateLimiter) baseCost(method string) float64 {
+	if c, found := l.config.Costs[method]; found {
+		return c
+	}
+	return 1
+}
+
+// limits returns the rate and burst of the client, the API key with its own rate limit overrides the default limits
+func (l *RateLimiter) limits(keyRate float64) (float64, float64) {
+	if keyRate > 0 {
+		return keyRate, math.Max(keyRate*l.config.KeyBurstSeconds, 1)
+	}
+	return l.config.Rate, l.config.Burst
+}
+
+// clientID returns the id of the bucket of the client, the API key takes precedence over the IP address
+func clientID(key, ip string) string {
+	if key != "" {
+		return "key:" + key
+	}
+	return "ip:" + ip
+}
+
+func (l *RateLimiter) bucket(client string, rate, burst float64, now time.Time) *tokenBucket {
+	if now.Sub(l.lastCleanup) > rateLimiterCleanupInterval {
+		// the buckets which would be full are the same as new buckets
+		for k, b := range l.buckets {
+			if b.tokens+now.Sub(b.updated).Seconds()*b.rate >= b.burst {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastCleanup = now
+	}
+	b, found := l.buckets[client]
+	if !found {
+		b = &tokenBucket{tokens: burst, updated: now}
+		l.buckets[client] = b
+	}
+	b.rate, b.burst = rate, burst
+	b.refill(now)
+	return b
+}
+
+// allow consumes the base cost of the method from the bucket of the client or returns rateLimitError with the time to wait
+func (l *RateLimiter) allow(client string, keyRate float64, method string, iface string) error {
+	if l == nil {
+		return nil
+	}
+	rate, burst := l.limits(keyRate)
+	if rate <= 0 {
+		return nil
+	}
+	cost := l.baseCost(method)
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	b := l.bucket(client, rate, burst, time.Now())
+	// the cost higher than the burst is allowed when the bucket is full
+	if need := math.Min(cost, burst); b.tokens < need {
+		if l.metrics != nil {
+			l.metrics.RateLimitedRequests.With(common.Labels{"method": method, "interface": iface}).Inc()
+		}
+		return &rateLimitError{retryAfter: int(math.Max(1, math.Ceil((need-b.tokens)/rate)))}
+	}
+	b.tokens -= cost
+	return nil
+}
+
+// charge consumes the additional cost of the executed request computed from its result
+func (l *RateLimiter) charge(client string, keyRate float64, method string, result interface{}) {
+	if l == nil {
+		return
+	}
+	rate, burst := l.limits(keyRate)
+	if rate <= 0 {
+		return
+	}
+	f, found := methodCostFuncs[method]
+	if !found {
+		return
+	}
+	cost := f(result)
+	if cost <= 0 {
+		return
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	b := l.bucket(client, rate, burst, time.Now())
+	b.tokens -= cost
+}
+
+// requestGuard authorizes the requests to the public interfaces by the API keys and limits their cost
+type requestGuard struct {
+	apiKeys     *APIKeys
+	rateLimiter *RateLimiter
+}
+
+// admit checks the API key and the rate limit of the request, returns the client id and the rate of the API key used in charge
+func (g *requestGuard) admit(key, ip, method, origin, iface string) (string, float64, error) {
+	keyRate, err := g.apiKeys.authorize(key, method, origin, iface)
+	if err != nil {
+		return "", 0, err
+	}
+	client := clientID(key, ip)
+	if err = g.rateLimiter.allow(client, keyRate, method, iface); err != nil {
+		return "", 0, err
+	}
+	return client, keyRate, nil
+}
+
+// charge consumes the additional cost of the executed request
+func (g *requestGuard) charge(client string, keyRate float64, method string, result interface{}) {
+	g.rateLimiter.charge(client, keyRate, method, result)
+}
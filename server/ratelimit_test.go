@@ -0,0 +1,100 @@
+//go:build unittest
+
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/common"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(&common.RateLimits{Rate: 1, Burst: 3, Costs: map[string]float64{"ping": 0.5, "sendTransaction": 5}}, nil)
+	client := clientID("", "1.2.3.4")
+	// the burst allows 3 requests of base cost 1
+	for i := 0; i < 3; i++ {
+		if err := l.allow(client, 0, "getInfo", apiKeyInterfaceWebsocket); err != nil {
+			t.Fatalf("allow() %d = %v", i, err)
+		}
+	}
+	err := l.allow(client, 0, "getInfo", apiKeyInterfaceWebsocket)
+	if e, ok := err.(*rateLimitError); !ok || e.retryAfter != 1 {
+		t.Fatalf("allow() = %v, want rateLimitError with retryAfter 1", err)
+	}
+	// other clients have their own buckets
+	if err := l.allow(clientID("key", ""), 0, "getInfo", apiKeyInterfaceWebsocket); err != nil {
+		t.Errorf("allow() other client = %v", err)
+	}
+	// after 2 seconds there are tokens for 2 requests and the cost of the result
+	b := l.buckets[client]
+	b.updated = b.updated.Add(-2 * time.Second)
+	if err := l.allow(client, 0, "getAccountInfo", apiKeyInterfaceWebsocket); err != nil {
+		t.Fatalf("allow() = %v", err)
+	}
+	l.charge(client, 0, "getAccountInfo", &api.Address{Txids: make([]string, 20)})
+	// the result cost 2 units, the bucket is negative and even ping is rejected
+	err = l.allow(client, 0, "ping", apiKeyInterfaceWebsocket)
+	if e, ok := err.(*rateLimitError); !ok || e.retryAfter != 2 {
+		t.Fatalf("allow() = %v, want rateLimitError with retryAfter 2", err)
+	}
+	// the cost higher than the burst is allowed with full bucket
+	b.tokens = b.burst
+	if err := l.allow(client, 0, "sendTransaction", apiKeyInterfaceWebsocket); err != nil {
+		t.Errorf("allow() = %v", err)
+	}
+	// the rate of the API key overrides the default limits
+	keyClient := clientID("limited", "")
+	for i := 0; i < 20; i++ {
+		if err := l.allow(keyClient, 2, "getInfo", apiKeyInterfaceHTTP); err != nil {
+			t.Fatalf("allow() key %d = %v", i, err)
+		}
+	}
+	if err := l.allow(keyClient, 2, "getInfo", apiKeyInterfaceHTTP); err == nil {
+		t.Error("allow() key: expected rate limit error")
+	}
+	// without the config only the keys with a rate limit are limited
+	l = NewRateLimiter(nil, nil)
+	for i := 0; i < 100; i++ {
+		if err := l.allow(client, 0, "getInfo", apiKeyInterfaceHTTP); err != nil {
+			t.Fatalf("allow() without limits = %v", err)
+		}
+	}
+}
+
+func Test_getIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     map[string]string
+		want       string
+	}{
+		{name: "ipv4 with port", remoteAddr: "1.2.3.4:51234", want: "1.2.3.4"},
+		{name: "ipv6 with port", remoteAddr: "[2001:db8::1]:443", want: "2001:db8::1"},
+		{name: "without port", remoteAddr: "1.2.3.4", want: "1.2.3.4"},
+		{name: "X-Real-Ip", remoteAddr: "10.0.0.1:80", header: map[string]string{"X-Real-Ip": "5.6.7.8"}, want: "5.6.7.8"},
+		{name: "cf-connecting-ip", remoteAddr: "10.0.0.1:80", header: map[string]string{"X-Real-Ip": "5.6.7.8", "cf-connecting-ip": "9.9.9.9"}, want: "9.9.9.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			if got := getIP(r); got != tt.want {
+				t.Errorf("getIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// the connections of the same client from different ports share the bucket
+	a := httptest.NewRequest("GET", "/api", nil)
+	a.RemoteAddr = "1.2.3.4:1000"
+	b := httptest.NewRequest("GET", "/api", nil)
+	b.RemoteAddr = "1.2.3.4:2000"
+	if clientID("", getIP(a)) != clientID("", getIP(b)) {
+		t.Error("clientID() differs for the ports of the same address")
+	}
+}
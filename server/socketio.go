@@ -31,6 +31,7 @@ type SocketIoServer struct {
 	metrics     *common.Metrics
 	is          *common.InternalState
 	api         *api.Worker
+	guard       requestGuard
 }
 
 // NewSocketIoServer creates new SocketIo interface to blockbook and returns its handle
@@ -155,7 +156,8 @@ var onMessageHandlers = map[string]func(*SocketIoServer, json.RawMessage) (inter
 
 type resultError struct {
 	Error struct {
-		Message string `json:"message"`
+		Message    string `json:"message"`
+		RetryAfter int    `json:"retryAfter,omitempty"`
 	} `json:"error"`
 }
 
@@ -180,7 +182,14 @@ func (s *SocketIoServer) onMessage(c *gosocketio.Channel, req map[string]json.Ra
 	}()
 	f, ok := onMessageHandlers[method]
 	if ok {
-		rv, err = f(s, params)
+		h := c.RequestHeader()
+		var client string
+		var keyRate float64
+		if client, keyRate, err = s.guard.admit(h.Get(apiKeyHeader), c.Ip(), method, h.Get("Origin"), apiKeyInterfaceSocketIO); err == nil {
+			if rv, err = f(s, params); err == nil {
+				s.guard.charge(client, keyRate, method, rv)
+			}
+		}
 	} else {
 		err = errors.New("unknown method")
 	}
@@ -193,6 +202,9 @@ func (s *SocketIoServer) onMessage(c *gosocketio.Channel, req map[string]json.Ra
 	s.metrics.SocketIORequests.With(common.Labels{"method": method, "status": "failure"}).Inc()
 	e := resultError{}
 	e.Error.Message = err.Error()
+	if rlErr, isRateLimit := err.(*rateLimitError); isRateLimit {
+		e.Error.RetryAfter = rlErr.retryAfter
+	}
 	return e
 }
 
@@ -0,0 +1,952 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+	"github.com/trezor/blockbook/fiat"
+)
+
+const (
+	webhookEventHeader       = "X-Blockbook-Event"
+	webhookDeliveryHeader    = "X-Blockbook-Delivery"
+	webhookTimestampHeader   = "X-Blockbook-Timestamp"
+	webhookSignatureHeader   = "X-Blockbook-Signature"
+	webhookTimeout           = 10 * time.Second
+	webhookPollPeriod        = time.Second
+	webhookBatchSize         = 100
+	webhookWorkers           = 8
+	webhookMaxAttempts       = 12
+	webhookInitialBackoff    = 10 * time.Second
+	webhookMaxBackoff        = 2 * time.Hour
+	webhookMaxConfirmations  = 100
+	webhookMinSecretLength   = 16
+	webhookMaskedSecret      = "*****"
+	webhookUnconfirmedExpiry = 72 * time.Hour
+	// webhookRecentBlocks is the number of block hashes kept to find the fork point after a reorg
+	webhookRecentBlocks = 100
+	// webhookMaxCatchUpBlocks is the maximum number of blocks processed after the restart,
+	// the blocks connected while Blockbook was not running are processed from the last processed block
+	webhookMaxCatchUpBlocks = 10000
+	// webhookXpubRefreshInterval is the period of the derivation of the xpub addresses, which is otherwise done
+	// only after a transaction of a derived address, when the gap may have moved
+	webhookXpubRefreshInterval = 10 * time.Minute
+)
+
+var webhookEvents = map[string]struct{}{
+	db.WebhookEventMempool:      {},
+	db.WebhookEventConfirmation: {},
+	db.WebhookEventReorg:        {},
+	db.WebhookEventBlock:        {},
+}
+
+// WebhookEvent is the payload posted to the webhook
+type WebhookEvent struct {
+	ID            string  `json:"id"`
+	WebhookID     string  `json:"webhookId"`
+	Event         string  `json:"event"`
+	Time          int64   `json:"time"`
+	Address       string  `json:"address,omitempty"`
+	Txid          string  `json:"txid,omitempty"`
+	Confirmations uint32  `json:"confirmations,omitempty"`
+	BlockHeight   uint32  `json:"blockHeight,omitempty"`
+	BlockHash     string  `json:"blockHash,omitempty"`
+	Tx            *api.Tx `json:"tx,omitempty"`
+}
+
+type webhookState struct {
+	hook   db.Webhook
+	events map[string]struct{}
+	// addrDescs maps the address descriptors of the addresses and of the addresses derived from the xpubs to the addresses
+	addrDescs map[string]string
+	// xpubAddrDescs are the address descriptors derived from the xpubs
+	xpubAddrDescs map[string]struct{}
+	// xpubUsed is set to 1 when a transaction of an address derived from the xpubs is seen
+	xpubUsed int32
+}
+
+func (h *webhookState) wants(event string) bool {
+	_, ok := h.events[event]
+	return ok && !h.hook.Disabled
+}
+
+// tracksTxs returns true if the transactions of the webhook must be tracked for the confirmation or reorg events
+func (h *webhookState) tracksTxs() bool {
+	return h.wants(db.WebhookEventConfirmation) || h.wants(db.WebhookEventReorg)
+}
+
+// markXpubUsed requests the derivation of the xpub addresses if the address descriptor is derived from the xpubs
+func (h *webhookState) markXpubUsed(addrDesc string) {
+	if _, found := h.xpubAddrDescs[addrDesc]; found {
+		atomic.StoreInt32(&h.xpubUsed, 1)
+	}
+}
+
+func (h *webhookState) confirmations() uint32 {
+	if h.hook.Confirmations == 0 {
+		return 1
+	}
+	return h.hook.Confirmations
+}
+
+// Webhooks delivers the events of the watched addresses and of the new blocks to the registered webhooks
+// the registrations, the queue of the deliveries and the tracked transactions are stored in the db,
+// the queue survives the restart of Blockbook and the processing of the blocks continues from the last processed block
+type Webhooks struct {
+	db          *db.RocksDB
+	chainParser bchain.BlockChainParser
+	api         *api.Worker
+	metrics     *common.Metrics
+	client      *http.Client
+	mux         sync.Mutex
+	hooks       map[string]*webhookState
+	addrDescs   map[string][]*webhookState
+	// adminMux serializes the changes of the webhooks
+	adminMux sync.Mutex
+	// untracked are the webhooks whose tracked transactions are removed with the processing of the next block
+	untracked map[string]struct{}
+	// trackMux serializes the updates of the tracked transactions
+	trackMux sync.Mutex
+	// trackedTxs indexes the tracked transactions by the block height, the unconfirmed transactions have height 0
+	trackedTxs map[uint32]map[string]*db.WebhookTx
+	// trackedFullScan requests the check of the tracked transactions at all heights, done after the start
+	trackedFullScan bool
+	lastXpubRefresh time.Time
+	lastHeight      uint32
+	recentBlocks    map[uint32]string
+	counter         uint32
+	newBlock        chan struct{}
+	wake            chan struct{}
+	quit            chan struct{}
+	wg              sync.WaitGroup
+}
+
+// NewWebhooks loads the webhooks from the db and returns a handle to them, the delivery is started by Start
+func NewWebhooks(d *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState, fiatRates *fiat.FiatRates) (*Webhooks, error) {
+	worker, err := api.NewWorker(d, chain, mempool, txCache, metrics, is, fiatRates)
+	if err != nil {
+		return nil, err
+	}
+	w := &Webhooks{
+		db:          d,
+		chainParser: chain.GetChainParser(),
+		api:         worker,
+		metrics:     metrics,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+			// the redirects are not followed, the delivery must be acknowledged by the registered URL
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		hooks:      make(map[string]*webhookState),
+		untracked:  make(map[string]struct{}),
+		trackedTxs: make(map[uint32]map[string]*db.WebhookTx),
+		newBlock:   make(chan struct{}, 1),
+		wake:       make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}
+	hooks, err := d.GetWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*webhookState, 0, len(hooks))
+	for i := range hooks {
+		h, err := w.newWebhookState(hooks[i])
+		if err != nil {
+			glog.Error("webhook ", hooks[i].ID, ": ", err)
+			continue
+		}
+		states = append(states, h)
+	}
+	w.setStates(states)
+	glog.Info("webhooks: ", len(states), " webhooks loaded")
+	return w, nil
+}
+
+// Start starts the processing of the new blocks and the delivery of the queued events
+func (w *Webhooks) Start() error {
+	if err := w.load(); err != nil {
+		return err
+	}
+	w.wg.Add(2)
+	go w.blockLoop()
+	go w.dispatchLoop()
+	return nil
+}
+
+// load restores the last processed block and the tracked transactions,
+// the blocks connected since the last processed block, also while Blockbook was not running,
+// are processed by the first run of the block loop
+func (w *Webhooks) load() error {
+	best, hash, err := w.db.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	blocks, err := w.db.GetWebhookBlocks()
+	if err != nil {
+		return err
+	}
+	if blocks == nil {
+		w.lastHeight = best
+		w.recentBlocks = map[uint32]string{best: hash}
+		if err := w.storeProcessedBlocks(); err != nil {
+			return err
+		}
+	} else if best > blocks.Height+webhookMaxCatchUpBlocks {
+		glog.Warning("webhooks: last processed block ", blocks.Height, " is too old, processing only the last ", webhookMaxCatchUpBlocks, " blocks")
+		w.lastHeight = best - webhookMaxCatchUpBlocks
+		w.recentBlocks = make(map[uint32]string)
+	} else {
+		w.lastHeight = blocks.Height
+		w.recentBlocks = blocks.Hashes
+		if w.recentBlocks == nil {
+			w.recentBlocks = make(map[uint32]string)
+		}
+	}
+	txs, err := w.db.GetWebhookTxs("")
+	if err != nil {
+		return err
+	}
+	for i := range txs {
+		w.indexTrackedTx(&txs[i], 0)
+	}
+	// the confirmation events of the tracked transactions below the window of the processed blocks,
+	// e.g. after the skipped part of a too long catch-up, are checked once in the first processing
+	w.trackedFullScan = true
+	w.lastXpubRefresh = time.Now()
+	// process the blocks connected since the last processed block without waiting for a new block
+	w.OnNewBlock("", 0)
+	return nil
+}
+
+// storeProcessedBlocks stores the last processed block, from which the processing continues after the restart
+func (w *Webhooks) storeProcessedBlocks() error {
+	return w.db.StoreWebhookBlocks(&db.WebhookBlocks{Height: w.lastHeight, Hashes: w.recentBlocks})
+}
+
+// Stop stops the delivery, the undelivered events stay in the queue
+func (w *Webhooks) Stop() {
+	close(w.quit)
+	w.wg.Wait()
+	glog.Info("webhooks: stopped")
+}
+
+// newWebhookState resolves the address descriptors of the addresses and xpubs of the webhook
+func (w *Webhooks) newWebhookState(hook db.Webhook) (*webhookState, error) {
+	h := &webhookState{
+		hook:          hook,
+		events:        make(map[string]struct{}, len(hook.Events)),
+		addrDescs:     make(map[string]string),
+		xpubAddrDescs: make(map[string]struct{}),
+	}
+	for _, e := range hook.Events {
+		h.events[e] = struct{}{}
+	}
+	for _, address := range hook.Addresses {
+		addrDesc, err := w.chainParser.GetAddrDescFromAddress(address)
+		if err != nil {
+			return nil, api.NewAPIError("Invalid address "+address+", "+err.Error(), true)
+		}
+		h.addrDescs[string(addrDesc)] = address
+	}
+	for _, xpub := range hook.Xpubs {
+		addresses, err := w.xpubAddresses(xpub)
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range addresses {
+			if addrDesc, err := w.chainParser.GetAddrDescFromAddress(address); err == nil {
+				h.addrDescs[string(addrDesc)] = address
+				h.xpubAddrDescs[string(addrDesc)] = struct{}{}
+			}
+		}
+	}
+	return h, nil
+}
+
+// xpubAddresses returns the addresses derived from the xpub including the gap
+func (w *Webhooks) xpubAddresses(xpub string) ([]string, error) {
+	a, err := w.api.GetXpubAddress(xpub, 0, 1, api.AccountDetailsTokens, &api.AddressFilter{Vout: api.AddressFilterVoutOff, TokensToReturn: api.TokensToReturnDerived}, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(a.Tokens))
+	for i := range a.Tokens {
+		if a.Tokens[i].Standard == bchain.XPUBAddressStandard {
+			addresses = append(addresses, a.Tokens[i].Name)
+		}
+	}
+	return addresses, nil
+}
+
+// setStates replaces the webhooks in memory and rebuilds the index of the address descriptors
+func (w *Webhooks) setStates(states []*webhookState) {
+	hooks := make(map[string]*webhookState, len(states))
+	addrDescs := make(map[string][]*webhookState)
+	for _, h := range states {
+		hooks[h.hook.ID] = h
+		if h.hook.Disabled {
+			continue
+		}
+		for addrDesc := range h.addrDescs {
+			addrDescs[addrDesc] = append(addrDescs[addrDesc], h)
+		}
+	}
+	w.mux.Lock()
+	w.hooks = hooks
+	w.addrDescs = addrDescs
+	w.mux.Unlock()
+}
+
+func (w *Webhooks) getStates() map[string]*webhookState {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.hooks
+}
+
+func (w *Webhooks) getState(id string) *webhookState {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.hooks[id]
+}
+
+// refreshXpubs derives the addresses of the xpubs again, new addresses may be used since the last derivation
+// if all is false, only the xpubs of the webhooks with a transaction of a derived address are derived
+func (w *Webhooks) refreshXpubs(all bool) {
+	refreshed := make(map[*webhookState]*webhookState)
+	for id, h := range w.getStates() {
+		if len(h.hook.Xpubs) == 0 || h.hook.Disabled {
+			continue
+		}
+		if !all && atomic.LoadInt32(&h.xpubUsed) == 0 {
+			continue
+		}
+		nh, err := w.newWebhookState(h.hook)
+		if err != nil {
+			glog.Error("webhook ", id, ": ", err)
+			continue
+		}
+		refreshed[h] = nh
+	}
+	if len(refreshed) == 0 {
+		return
+	}
+	w.adminMux.Lock()
+	defer w.adminMux.Unlock()
+	states := make([]*webhookState, 0, len(w.hooks))
+	for _, h := range w.getStates() {
+		// the webhook may have been updated in the meantime
+		if nh, found := refreshed[h]; found {
+			h = nh
+		}
+		states = append(states, h)
+	}
+	w.setStates(states)
+}
+
+func validateWebhook(h *db.Webhook) error {
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return api.NewAPIError("Invalid webhook URL "+h.URL, true)
+	}
+	if len(h.Secret) < webhookMinSecretLength {
+		return api.NewAPIError("Webhook secret must have at least "+strconv.Itoa(webhookMinSecretLength)+" characters", true)
+	}
+	if len(h.Events) == 0 {
+		return api.NewAPIError("Missing webhook events", true)
+	}
+	for _, e := range h.Events {
+		if _, ok := webhookEvents[e]; !ok {
+			return api.NewAPIError("Unknown webhook event "+e, true)
+		}
+	}
+	if h.Confirmations > webhookMaxConfirmations {
+		return api.NewAPIError("Maximum number of webhook confirmations is "+strconv.Itoa(webhookMaxConfirmations), true)
+	}
+	return nil
+}
+
+func newWebhookID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func maskWebhook(h db.Webhook) db.Webhook {
+	h.Secret = webhookMaskedSecret
+	return h
+}
+
+// List returns the webhooks with masked secrets
+func (w *Webhooks) List() []db.Webhook {
+	states := w.getStates()
+	rv := make([]db.Webhook, 0, len(states))
+	for _, h := range states {
+		rv = append(rv, maskWebhook(h.hook))
+	}
+	sort.Slice(rv, func(i, j int) bool { return rv[i].ID < rv[j].ID })
+	return rv
+}
+
+// Update adds the webhooks without ID and replaces the webhooks with the same ID,
+// the secret of the replaced webhook is kept if it is not specified
+func (w *Webhooks) Update(hooks []db.Webhook) ([]db.Webhook, error) {
+	w.adminMux.Lock()
+	defer w.adminMux.Unlock()
+	current := w.getStates()
+	updated := make(map[string]*webhookState, len(current)+len(hooks))
+	for id, h := range current {
+		updated[id] = h
+	}
+	rv := make([]db.Webhook, 0, len(hooks))
+	for i := range hooks {
+		hook := hooks[i]
+		if hook.ID == "" {
+			id, err := newWebhookID()
+			if err != nil {
+				return nil, err
+			}
+			hook.ID = id
+		} else if h, found := current[hook.ID]; found && (hook.Secret == "" || hook.Secret == webhookMaskedSecret) {
+			hook.Secret = h.hook.Secret
+		} else if !found {
+			return nil, api.NewAPIError("Webhook "+hook.ID+" not found", true)
+		}
+		if err := validateWebhook(&hook); err != nil {
+			return nil, err
+		}
+		h, err := w.newWebhookState(hook)
+		if err != nil {
+			if _, ok := err.(*api.APIError); !ok {
+				err = api.NewAPIError("Webhook "+hook.ID+": "+err.Error(), true)
+			}
+			return nil, err
+		}
+		updated[hook.ID] = h
+		rv = append(rv, maskWebhook(hook))
+	}
+	if err := w.store(updated); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// Delete removes the webhook, its queued deliveries are dropped by the dispatcher
+// and its tracked transactions with the processing of the next block
+func (w *Webhooks) Delete(id string) (bool, error) {
+	w.adminMux.Lock()
+	defer w.adminMux.Unlock()
+	current := w.getStates()
+	if _, found := current[id]; !found {
+		return false, nil
+	}
+	updated := make(map[string]*webhookState, len(current))
+	for i, h := range current {
+		if i != id {
+			updated[i] = h
+		}
+	}
+	if err := w.store(updated); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (w *Webhooks) store(updated map[string]*webhookState) error {
+	// the tracked transactions of the webhooks, which are removed or do not track transactions anymore, are removed later
+	untracked := make([]string, 0)
+	for id, h := range w.getStates() {
+		if nh := updated[id]; h.tracksTxs() && (nh == nil || !nh.tracksTxs()) {
+			untracked = append(untracked, id)
+		}
+	}
+	states := make([]*webhookState, 0, len(updated))
+	for _, h := range updated {
+		states = append(states, h)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].hook.ID < states[j].hook.ID })
+	hooks := make([]db.Webhook, len(states))
+	for i, h := range states {
+		hooks[i] = h.hook
+	}
+	if err := w.db.StoreWebhooks(hooks); err != nil {
+		return err
+	}
+	w.setStates(states)
+	w.mux.Lock()
+	for _, id := range untracked {
+		w.untracked[id] = struct{}{}
+	}
+	w.mux.Unlock()
+	return nil
+}
+
+// OnNewTxAddr is a callback for a new mempool transaction of an address
+func (w *Webhooks) OnNewTxAddr(tx *bchain.Tx, desc bchain.AddressDescriptor) {
+	w.mux.Lock()
+	hooks := w.addrDescs[string(desc)]
+	w.mux.Unlock()
+	if len(hooks) > 0 {
+		go w.onNewTxAddrAsync(tx.Txid, string(desc), hooks)
+	}
+}
+
+func (w *Webhooks) onNewTxAddrAsync(txid string, addrDesc string, hooks []*webhookState) {
+	var tx *api.Tx
+	for _, h := range hooks {
+		address := h.addrDescs[addrDesc]
+		h.markXpubUsed(addrDesc)
+		if h.tracksTxs() {
+			if err := w.trackMempoolTx(h, txid, address); err != nil {
+				glog.Error("webhook ", h.hook.ID, " tx ", txid, ": ", err)
+			}
+		}
+		if h.wants(db.WebhookEventMempool) {
+			if tx == nil {
+				var err error
+				if tx, err = w.api.GetTransaction(txid, false, false); err != nil {
+					glog.Error("webhook GetTransaction ", txid, ": ", err)
+					return
+				}
+			}
+			w.enqueue(h, &WebhookEvent{Event: db.WebhookEventMempool, Address: address, Txid: txid, Tx: tx})
+		}
+	}
+}
+
+func (w *Webhooks) trackMempoolTx(h *webhookState, txid string, address string) error {
+	w.trackMux.Lock()
+	defer w.trackMux.Unlock()
+	t, err := w.db.GetWebhookTx(h.hook.ID, txid)
+	if err != nil || t != nil {
+		return err
+	}
+	return w.storeTrackedTx(&db.WebhookTx{WebhookID: h.hook.ID, Txid: txid, Address: address, FirstSeen: time.Now().Unix()}, 0)
+}
+
+func trackedTxKey(webhookID, txid string) string {
+	return webhookID + ":" + txid
+}
+
+// indexTrackedTx moves the tracked transaction in the index from prevHeight to its height
+func (w *Webhooks) indexTrackedTx(t *db.WebhookTx, prevHeight uint32) {
+	key := trackedTxKey(t.WebhookID, t.Txid)
+	if prevHeight != t.Height {
+		w.unindexTrackedTx(key, prevHeight)
+	}
+	m := w.trackedTxs[t.Height]
+	if m == nil {
+		m = make(map[string]*db.WebhookTx)
+		w.trackedTxs[t.Height] = m
+	}
+	m[key] = t
+}
+
+func (w *Webhooks) unindexTrackedTx(key string, height uint32) {
+	if m := w.trackedTxs[height]; m != nil {
+		delete(m, key)
+		if len(m) == 0 {
+			delete(w.trackedTxs, height)
+		}
+	}
+}
+
+// storeTrackedTx stores the tracked transaction, which was indexed at prevHeight
+func (w *Webhooks) storeTrackedTx(t *db.WebhookTx, prevHeight uint32) error {
+	if err := w.db.StoreWebhookTx(t); err != nil {
+		return err
+	}
+	w.indexTrackedTx(t, prevHeight)
+	return nil
+}
+
+// deleteTrackedTx stops the tracking of the transaction
+func (w *Webhooks) deleteTrackedTx(t *db.WebhookTx) error {
+	if err := w.db.DeleteWebhookTx(t.WebhookID, t.Txid); err != nil {
+		return err
+	}
+	w.unindexTrackedTx(trackedTxKey(t.WebhookID, t.Txid), t.Height)
+	return nil
+}
+
+// OnNewBlock is a callback for a new block, the blocks are processed asynchronously from the last processed block
+func (w *Webhooks) OnNewBlock(hash string, height uint32) {
+	select {
+	case w.newBlock <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Webhooks) blockLoop() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.quit:
+			return
+		case <-w.newBlock:
+			if err := w.processNewBlocks(); err != nil {
+				glog.Error("webhooks processNewBlocks: ", errors.ErrorStack(err))
+			}
+		}
+	}
+}
+
+// processNewBlocks sends the block events of the blocks connected since the last processed block
+// and updates the tracked transactions
+func (w *Webhooks) processNewBlocks() error {
+	w.trackMux.Lock()
+	defer w.trackMux.Unlock()
+	best, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	// find the fork point, the blocks above it are either new or were connected instead of the disconnected blocks
+	from := w.lastHeight
+	for from > 0 {
+		if from <= best {
+			hash, found := w.recentBlocks[from]
+			if !found {
+				break
+			}
+			if current, err := w.db.GetBlockHash(from); err == nil && current == hash {
+				break
+			}
+		}
+		delete(w.recentBlocks, from)
+		from--
+	}
+	hooks := w.getStates()
+	for height := from + 1; height <= best; height++ {
+		hash, err := w.db.GetBlockHash(height)
+		if err != nil {
+			return err
+		}
+		w.recentBlocks[height] = hash
+		delete(w.recentBlocks, height-webhookRecentBlocks)
+		for _, h := range hooks {
+			if h.wants(db.WebhookEventBlock) {
+				w.enqueue(h, &WebhookEvent{Event: db.WebhookEventBlock, BlockHeight: height, BlockHash: hash})
+			}
+		}
+	}
+	if err := w.updateTrackedTxs(hooks, from, best); err != nil {
+		return err
+	}
+	w.lastHeight = best
+	if err := w.storeProcessedBlocks(); err != nil {
+		return err
+	}
+	// the addresses derived now are watched from the next transactions
+	all := time.Since(w.lastXpubRefresh) > webhookXpubRefreshInterval
+	w.refreshXpubs(all)
+	if all {
+		w.lastXpubRefresh = time.Now()
+	}
+	return nil
+}
+
+// updateTrackedTxs sends the reorg events of the tracked transactions in the disconnected blocks,
+// finds the transactions of the watched addresses in the blocks from+1 to best
+// and sends the confirmation events of the tracked transactions
+// only the tracked transactions at the heights affected by the new blocks are checked, using the index by height
+func (w *Webhooks) updateTrackedTxs(hooks map[string]*webhookState, from, best uint32) error {
+	w.mux.Lock()
+	untracked := w.untracked
+	w.untracked = make(map[string]struct{})
+	w.mux.Unlock()
+	for id := range untracked {
+		// the webhook may track the transactions again after an update
+		if h := hooks[id]; h != nil && h.tracksTxs() {
+			continue
+		}
+		txs, err := w.db.GetWebhookTxs(id)
+		if err != nil {
+			return err
+		}
+		for i := range txs {
+			if err := w.deleteTrackedTx(&txs[i]); err != nil {
+				return err
+			}
+		}
+	}
+	for height := from + 1; height <= w.lastHeight; height++ {
+		for _, t := range w.trackedTxs[height] {
+			if hash, err := w.db.GetBlockHash(t.Height); err == nil && hash == t.BlockHash {
+				continue
+			}
+			if h := hooks[t.WebhookID]; h != nil && h.wants(db.WebhookEventReorg) {
+				w.enqueue(h, &WebhookEvent{Event: db.WebhookEventReorg, Address: t.Address, Txid: t.Txid, BlockHeight: t.Height, BlockHash: t.BlockHash})
+			}
+			t.Height, t.BlockHash, t.Confirmations = 0, "", 0
+			if err := w.storeTrackedTx(t, height); err != nil {
+				return err
+			}
+		}
+	}
+	for _, h := range hooks {
+		if !h.tracksTxs() {
+			continue
+		}
+		for addrDesc, address := range h.addrDescs {
+			err := w.db.GetAddrDescTransactions(bchain.AddressDescriptor(addrDesc), from+1, best, func(txid string, height uint32, indexes []int32) error {
+				h.markXpubUsed(addrDesc)
+				t, err := w.db.GetWebhookTx(h.hook.ID, txid)
+				if err != nil {
+					return err
+				}
+				var prevHeight uint32
+				if t == nil {
+					t = &db.WebhookTx{WebhookID: h.hook.ID, Txid: txid, Address: address, FirstSeen: time.Now().Unix()}
+				} else if t.Height == height {
+					return nil
+				} else {
+					prevHeight = t.Height
+				}
+				t.Height, t.BlockHash = height, w.recentBlocks[height]
+				return w.storeTrackedTx(t, prevHeight)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// the transactions below the window of the maximum confirmations were finished in the previous blocks
+	heights := []uint32{0}
+	if w.trackedFullScan {
+		for height := range w.trackedTxs {
+			if height > 0 {
+				heights = append(heights, height)
+			}
+		}
+		w.trackedFullScan = false
+	} else {
+		low := uint32(1)
+		if from+1 > webhookMaxConfirmations {
+			low = from + 1 - webhookMaxConfirmations
+		}
+		for height := low; height <= best; height++ {
+			if _, found := w.trackedTxs[height]; found {
+				heights = append(heights, height)
+			}
+		}
+	}
+	expiry := time.Now().Add(-webhookUnconfirmedExpiry).Unix()
+	for _, height := range heights {
+		for _, t := range w.trackedTxs[height] {
+			if err := w.confirmTrackedTx(hooks[t.WebhookID], t, best, expiry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// confirmTrackedTx sends the confirmation events of the tracked transaction and stops its tracking
+// when it has the required confirmations, when its webhook does not track transactions or when it expired unconfirmed
+func (w *Webhooks) confirmTrackedTx(h *webhookState, t *db.WebhookTx, best uint32, expiry int64) error {
+	if h == nil || !h.tracksTxs() || (t.Height == 0 && t.FirstSeen < expiry) {
+		return w.deleteTrackedTx(t)
+	}
+	if t.Height == 0 || t.Height > best {
+		return nil
+	}
+	confirmations := best - t.Height + 1
+	required := h.confirmations()
+	if confirmations > required {
+		confirmations = required
+	}
+	if confirmations <= t.Confirmations {
+		return nil
+	}
+	if h.wants(db.WebhookEventConfirmation) {
+		for c := t.Confirmations + 1; c <= confirmations; c++ {
+			w.enqueue(h, &WebhookEvent{Event: db.WebhookEventConfirmation, Address: t.Address, Txid: t.Txid, Confirmations: c, BlockHeight: t.Height, BlockHash: t.BlockHash})
+		}
+	}
+	// the tracking ends with the required number of confirmations, the later reorgs are not reported
+	if confirmations == required {
+		return w.deleteTrackedTx(t)
+	}
+	t.Confirmations = confirmations
+	return w.storeTrackedTx(t, t.Height)
+}
+
+// enqueue stores the event to the delivery queue
+func (w *Webhooks) enqueue(h *webhookState, e *WebhookEvent) {
+	now := time.Now()
+	e.ID = fmt.Sprintf("%016x%08x", now.UnixNano(), atomic.AddUint32(&w.counter, 1))
+	e.WebhookID = h.hook.ID
+	e.Time = now.Unix()
+	payload, err := json.Marshal(e)
+	if err != nil {
+		glog.Error("webhook ", h.hook.ID, " event ", e.Event, ": ", err)
+		return
+	}
+	d := &db.WebhookDelivery{
+		ID:          e.ID,
+		WebhookID:   e.WebhookID,
+		Event:       e.Event,
+		Payload:     payload,
+		Created:     e.Time,
+		NextAttempt: e.Time,
+	}
+	if err := w.db.StoreWebhookDelivery(d); err != nil {
+		glog.Error("webhook ", h.hook.ID, " event ", e.Event, ": ", err)
+		return
+	}
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Webhooks) dispatchLoop() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(webhookPollPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.quit:
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+		w.dispatchDue()
+	}
+}
+
+// dispatchDue delivers the queued events with the due time of the next attempt
+func (w *Webhooks) dispatchDue() {
+	for {
+		deliveries, err := w.db.GetWebhookDeliveries(time.Now().Unix(), webhookBatchSize)
+		if err != nil {
+			glog.Error("webhooks GetWebhookDeliveries: ", err)
+			return
+		}
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, webhookWorkers)
+		for i := range deliveries {
+			d := &deliveries[i]
+			h := w.getState(d.WebhookID)
+			if h == nil || h.hook.Disabled {
+				if err := w.db.DeleteWebhookDelivery(d); err != nil {
+					glog.Error("webhooks DeleteWebhookDelivery: ", err)
+					return
+				}
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				w.deliver(h, d)
+			}()
+		}
+		wg.Wait()
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+		select {
+		case <-w.quit:
+			return
+		default:
+		}
+	}
+}
+
+// deliver posts the event, on failure reschedules it with exponential backoff or drops it after webhookMaxAttempts
+func (w *Webhooks) deliver(h *webhookState, d *db.WebhookDelivery) {
+	var err error
+	if err = w.post(h, d); err == nil {
+		w.metrics.WebhookDeliveries.With(common.Labels{"event": d.Event, "status": "success"}).Inc()
+		err = w.db.DeleteWebhookDelivery(d)
+	} else {
+		d.Attempts++
+		d.LastError = err.Error()
+		if d.Attempts >= webhookMaxAttempts {
+			glog.Warning("webhook ", h.hook.ID, " delivery ", d.ID, " dropped after ", d.Attempts, " attempts: ", err)
+			w.metrics.WebhookDeliveries.With(common.Labels{"event": d.Event, "status": "dropped"}).Inc()
+			err = w.db.DeleteWebhookDelivery(d)
+		} else {
+			glog.V(1).Info("webhook ", h.hook.ID, " delivery ", d.ID, " attempt ", d.Attempts, ": ", err)
+			w.metrics.WebhookDeliveries.With(common.Labels{"event": d.Event, "status": "failure"}).Inc()
+			err = w.db.RescheduleWebhookDelivery(d, time.Now().Add(webhookBackoff(d.Attempts)).Unix())
+		}
+	}
+	if err != nil {
+		glog.Error("webhook ", h.hook.ID, " delivery ", d.ID, ": ", err)
+	}
+}
+
+// webhookBackoff returns the delay of the next attempt after the given number of failed attempts
+func webhookBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return webhookInitialBackoff
+	}
+	b := webhookInitialBackoff
+	for i := 1; i < attempts && b < webhookMaxBackoff; i++ {
+		b *= 2
+	}
+	if b > webhookMaxBackoff {
+		b = webhookMaxBackoff
+	}
+	return b
+}
+
+// webhookSignature returns the hex encoded HMAC-SHA256 of the timestamp, a dot and the body, keyed by the secret
+func webhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *Webhooks) post(h *webhookState, d *db.WebhookDelivery) error {
+	req, err := http.NewRequest(http.MethodPost, h.hook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Blockbook")
+	req.Header.Set(webhookEventHeader, d.Event)
+	req.Header.Set(webhookDeliveryHeader, d.ID)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, webhookSignature(h.hook.Secret, timestamp, d.Payload))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
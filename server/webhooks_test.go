@@ -0,0 +1,254 @@
+//go:build unittest
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+	"github.com/trezor/blockbook/tests/dbtestdata"
+)
+
+func Test_webhookSignature(t *testing.T) {
+	// echo -n '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac 'secret0123456789'
+	got := webhookSignature("secret0123456789", "1700000000", []byte(`{"id":"1"}`))
+	want := "sha256=f37513e458c5e03d67f49182a1419b9d8e8ed14f8134b32226e3222bcce575ed"
+	if got != want {
+		t.Errorf("webhookSignature() = %v, want %v", got, want)
+	}
+}
+
+func Test_webhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{5, 160 * time.Second},
+		{10, 5120 * time.Second},
+		{11, 2 * time.Hour},
+		{100, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func Test_validateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    db.Webhook
+		wantErr bool
+	}{
+		{"valid", db.Webhook{URL: "https://example.com/hook", Secret: "secret0123456789", Events: []string{"mempool", "confirmation"}, Confirmations: 6}, false},
+		{"invalid scheme", db.Webhook{URL: "ftp://example.com/hook", Secret: "secret0123456789", Events: []string{"block"}}, true},
+		{"short secret", db.Webhook{URL: "https://example.com/hook", Secret: "secret", Events: []string{"block"}}, true},
+		{"no events", db.Webhook{URL: "https://example.com/hook", Secret: "secret0123456789"}, true},
+		{"unknown event", db.Webhook{URL: "https://example.com/hook", Secret: "secret0123456789", Events: []string{"spent"}}, true},
+		{"too many confirmations", db.Webhook{URL: "https://example.com/hook", Secret: "secret0123456789", Events: []string{"confirmation"}, Confirmations: 101}, true},
+	}
+	for _, tt := range tests {
+		if err := validateWebhook(&tt.hook); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateWebhook() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWebhooks_post(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+	w := &Webhooks{client: &http.Client{Timeout: time.Second}}
+	h := &webhookState{hook: db.Webhook{ID: "h1", URL: ts.URL, Secret: "secret0123456789"}}
+	d := &db.WebhookDelivery{ID: "d1", WebhookID: "h1", Event: "block", Payload: []byte(`{"id":"d1","event":"block"}`)}
+	if err := w.post(h, d); err != nil {
+		t.Fatalf("post() error = %v", err)
+	}
+	if string(body) != string(d.Payload) {
+		t.Errorf("post() body = %s", body)
+	}
+	if received.Header.Get(webhookEventHeader) != "block" || received.Header.Get(webhookDeliveryHeader) != "d1" {
+		t.Errorf("post() headers = %v", received.Header)
+	}
+	timestamp := received.Header.Get(webhookTimestampHeader)
+	if got, want := received.Header.Get(webhookSignatureHeader), webhookSignature(h.hook.Secret, timestamp, d.Payload); got != want {
+		t.Errorf("post() signature = %v, want %v", got, want)
+	}
+	status = http.StatusInternalServerError
+	if err := w.post(h, d); err == nil {
+		t.Error("post() expected error on HTTP status 500")
+	}
+}
+
+func TestWebhooks_indexTrackedTx(t *testing.T) {
+	w := &Webhooks{trackedTxs: make(map[uint32]map[string]*db.WebhookTx)}
+	a := &db.WebhookTx{WebhookID: "h1", Txid: "a"}
+	b := &db.WebhookTx{WebhookID: "h2", Txid: "a", Height: 100}
+	w.indexTrackedTx(a, 0)
+	w.indexTrackedTx(b, 0)
+	if len(w.trackedTxs[0]) != 1 || len(w.trackedTxs[100]) != 1 {
+		t.Fatalf("trackedTxs = %v, want one tx at heights 0 and 100", w.trackedTxs)
+	}
+	// the confirmed transaction moves to its height
+	a.Height = 100
+	w.indexTrackedTx(a, 0)
+	if _, found := w.trackedTxs[0]; found || len(w.trackedTxs[100]) != 2 {
+		t.Fatalf("trackedTxs = %v, want two txs at height 100", w.trackedTxs)
+	}
+	// the update at the same height keeps the tx in the index
+	w.indexTrackedTx(a, 100)
+	if len(w.trackedTxs[100]) != 2 {
+		t.Fatalf("trackedTxs = %v, want two txs at height 100", w.trackedTxs)
+	}
+	w.unindexTrackedTx(trackedTxKey("h1", "a"), 100)
+	w.unindexTrackedTx(trackedTxKey("h2", "a"), 100)
+	if len(w.trackedTxs) != 0 {
+		t.Errorf("trackedTxs = %v, want empty", w.trackedTxs)
+	}
+}
+
+func Test_webhookState_markXpubUsed(t *testing.T) {
+	h := &webhookState{
+		addrDescs:     map[string]string{"a": "addr", "x": "xpubaddr"},
+		xpubAddrDescs: map[string]struct{}{"x": {}},
+	}
+	h.markXpubUsed("a")
+	if h.xpubUsed != 0 {
+		t.Error("markXpubUsed() of a not derived address requests the derivation")
+	}
+	h.markXpubUsed("x")
+	if h.xpubUsed != 1 {
+		t.Error("markXpubUsed() of a derived address does not request the derivation")
+	}
+}
+
+func TestWebhooks_restartCatchUp(t *testing.T) {
+	parser, chain := setupChain(t)
+	tmp, err := os.MkdirTemp("", "testdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	d, err := db.NewRocksDB(tmp, 100000, -1, parser, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	is, err := d.LoadInternalState(&common.Config{CoinName: "Fakecoin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetInternalState(is)
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(parser)
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(parser)
+	for i := uint32(0); i < block1.Height; i++ {
+		is.BlockTimes = append(is.BlockTimes, 0)
+	}
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first run processes the block 1 and tracks a mempool transaction of the watched address
+	w, err := NewWebhooks(d, chain, nil, nil, nil, is, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := w.Update([]db.Webhook{{
+		URL:           "https://example.com/hook",
+		Secret:        "0123456789abcdef",
+		Addresses:     []string{dbtestdata.Addr6},
+		Events:        []string{db.WebhookEventConfirmation, db.WebhookEventBlock},
+		Confirmations: 2,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := hooks[0].ID
+	if err := w.load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.processNewBlocks(); err != nil {
+		t.Fatal(err)
+	}
+	// the transaction was first seen before the expiry of the unconfirmed transactions
+	firstSeen := time.Now().Add(-webhookUnconfirmedExpiry - time.Hour).Unix()
+	if err := d.StoreWebhookTx(&db.WebhookTx{WebhookID: id, Txid: dbtestdata.TxidB2T1, Address: dbtestdata.Addr6, FirstSeen: firstSeen}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the block 2 is connected while Blockbook is not running
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = NewWebhooks(d, chain, nil, nil, nil, is, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.load(); err != nil {
+		t.Fatal(err)
+	}
+	if w.lastHeight != block1.Height {
+		t.Fatalf("lastHeight = %d, want %d", w.lastHeight, block1.Height)
+	}
+	if err := w.processNewBlocks(); err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := d.GetWebhookBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blocks == nil || blocks.Height != block2.Height || blocks.Hashes[block2.Height] != block2.Hash {
+		t.Errorf("GetWebhookBlocks() = %+v, want height %d", blocks, block2.Height)
+	}
+	tx, err := d.GetWebhookTx(id, dbtestdata.TxidB2T1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &db.WebhookTx{WebhookID: id, Txid: dbtestdata.TxidB2T1, Address: dbtestdata.Addr6, Height: block2.Height, BlockHash: block2.Hash, Confirmations: 1, FirstSeen: firstSeen}
+	if !reflect.DeepEqual(tx, want) {
+		t.Errorf("GetWebhookTx() = %+v, want %+v", tx, want)
+	}
+	deliveries, err := d.GetWebhookDeliveries(time.Now().Unix()+1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make([]string, 0, len(deliveries))
+	for i := range deliveries {
+		var e WebhookEvent
+		if err := json.Unmarshal(deliveries[i].Payload, &e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e.Event+" "+strconv.Itoa(int(e.BlockHeight))+" "+e.Txid+" "+strconv.Itoa(int(e.Confirmations)))
+	}
+	sort.Strings(events)
+	wantEvents := []string{
+		"block 225494  0",
+		"confirmation 225494 " + dbtestdata.TxidB2T2 + " 1",
+		"confirmation 225494 " + dbtestdata.TxidB2T1 + " 1",
+	}
+	sort.Strings(wantEvents)
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("events = %v, want %v", events, wantEvents)
+	}
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"math/big"
+	"net"
 	"net/http"
 	"os"
 	"runtime/debug"
@@ -37,18 +39,47 @@ var (
 )
 
 type websocketChannel struct {
-	id                           uint64
-	conn                         *websocket.Conn
-	out                          chan *WsRes
-	ip                           string
-	requestHeader                http.Header
-	alive                        bool
-	aliveLock                    sync.Mutex
-	addrDescs                    []string // subscribed address descriptors as strings
-	getAddressInfoDescriptorsMux sync.Mutex
-	getAddressInfoDescriptors    map[string]struct{}
+	id                     uint64
+	conn                   *websocket.Conn
+	out                    chan *WsRes
+	ip                     string
+	requestHeader          http.Header
+	apiKey                 string
+	alive                  bool
+	aliveLock              sync.Mutex
+	addrDescs              []string // subscribed address descriptors as strings
+	accountInfoDescriptors *accountInfoDescriptors
 }
 
+// accountInfoDescriptors are the distinct descriptors requested by getAccountInfo, their number is limited by WsGetAccountInfoLimit
+// the websocket connection has its own descriptors, the JSON-RPC requests of one client share them
+type accountInfoDescriptors struct {
+	mux         sync.Mutex
+	descriptors map[string]struct{}
+	created     time.Time
+}
+
+func newAccountInfoDescriptors(now time.Time) *accountInfoDescriptors {
+	return &accountInfoDescriptors{descriptors: make(map[string]struct{}), created: now}
+}
+
+// add adds the descriptor and returns the number of the distinct descriptors
+func (d *accountInfoDescriptors) add(descriptor string) int {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.descriptors[descriptor] = struct{}{}
+	return len(d.descriptors)
+}
+
+// opReturnSubscription contains the hex-encoded prefixes of the OP_RETURN data subscribed by a channel
+type opReturnSubscription struct {
+	id       string
+	prefixes []string
+}
+
+// maxOpReturnSubscriptionPrefixes is the maximum number of prefixes subscribed by one channel
+const maxOpReturnSubscriptionPrefixes = 100
+
 // WebsocketServer is a handle to websocket server
 type WebsocketServer struct {
 	upgrader                        *websocket.Upgrader
@@ -71,7 +102,13 @@ type WebsocketServer struct {
 	fiatRatesSubscriptions          map[string]map[*websocketChannel]string
 	fiatRatesTokenSubscriptions     map[*websocketChannel][]string
 	fiatRatesSubscriptionsLock      sync.Mutex
+	opReturnSubscriptions           map[*websocketChannel]*opReturnSubscription
+	opReturnSubscriptionsLock       sync.Mutex
+	jsonRPCAccountInfo              map[string]*accountInfoDescriptors
+	jsonRPCAccountInfoLock          sync.Mutex
+	jsonRPCAccountInfoCleanup       time.Time
 	allowedRpcCallTo                map[string]struct{}
+	guard                           requestGuard
 }
 
 // NewWebsocketServer creates new websocket interface to blockbook and returns its handle
@@ -106,6 +143,9 @@ func NewWebsocketServer(db *db.RocksDB, chain bchain.BlockChain, mempool bchain.
 		addressSubscriptions:        make(map[string]map[*websocketChannel]string),
 		fiatRatesSubscriptions:      make(map[string]map[*websocketChannel]string),
 		fiatRatesTokenSubscriptions: make(map[*websocketChannel][]string),
+		opReturnSubscriptions:       make(map[*websocketChannel]*opReturnSubscription),
+		jsonRPCAccountInfo:          make(map[string]*accountInfoDescriptors),
+		jsonRPCAccountInfoCleanup:   time.Now(),
 	}
 	envRpcCall := os.Getenv(strings.ToUpper(is.GetNetwork()) + "_ALLOWED_RPC_CALL_TO")
 	if envRpcCall != "" {
@@ -132,6 +172,10 @@ func getIP(r *http.Request) string {
 	if ip != "" {
 		return ip
 	}
+	// the port of the remote address differs for each connection of the same client
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
 	return r.RemoteAddr
 }
 
@@ -152,10 +196,11 @@ func (s *WebsocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		out:           make(chan *WsRes, outChannelSize),
 		ip:            getIP(r),
 		requestHeader: r.Header,
+		apiKey:        apiKeyFromRequest(r),
 		alive:         true,
 	}
 	if s.is.WsGetAccountInfoLimit > 0 {
-		c.getAddressInfoDescriptors = make(map[string]struct{})
+		c.accountInfoDescriptors = newAccountInfoDescriptors(time.Now())
 	}
 	go s.inputLoop(c)
 	go s.outputLoop(c)
@@ -272,6 +317,7 @@ func (s *WebsocketServer) onDisconnect(c *websocketChannel) {
 	s.unsubscribeNewTransaction(c)
 	s.unsubscribeAddresses(c)
 	s.unsubscribeFiatRates(c)
+	s.unsubscribeOpReturn(c)
 	glog.Info("Client disconnected ", c.id, ", ", c.ip)
 	s.metrics.WebsocketClients.Dec()
 }
@@ -280,12 +326,8 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *WsRe
 	"getAccountInfo": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
 		r, err := unmarshalGetAccountInfoRequest(req.Params)
 		if err == nil {
-			if s.is.WsGetAccountInfoLimit > 0 {
-				c.getAddressInfoDescriptorsMux.Lock()
-				c.getAddressInfoDescriptors[r.Descriptor] = struct{}{}
-				l := len(c.getAddressInfoDescriptors)
-				c.getAddressInfoDescriptorsMux.Unlock()
-				if l > s.is.WsGetAccountInfoLimit {
+			if c.accountInfoDescriptors != nil {
+				if c.accountInfoDescriptors.add(r.Descriptor) > s.is.WsGetAccountInfoLimit {
 					if s.closeChannel(c) {
 						glog.Info("Client ", c.id, " exceeded getAddressInfo limit, ", c.ip)
 						s.is.AddWsLimitExceedingIP(c.ip)
@@ -343,9 +385,17 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *WsRe
 			if r.GroupBy <= 0 {
 				r.GroupBy = 3600
 			}
-			rv, err = s.api.GetXpubBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.Gap, r.GroupBy)
+			r.Gains = strings.ToLower(r.Gains)
+			var history api.BalanceHistories
+			var historyGains *api.BalanceHistoryGains
+			history, historyGains, err = s.api.GetXpubBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.Gap, r.GroupBy, r.Gains)
 			if err != nil {
-				rv, err = s.api.GetBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.GroupBy)
+				history, historyGains, err = s.api.GetBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.GroupBy, r.Gains)
+			}
+			if err == nil && historyGains != nil {
+				rv = &api.BalanceHistoryWithGains{Histories: history, Gains: historyGains}
+			} else {
+				rv = history
 			}
 		}
 		return
@@ -380,6 +430,17 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *WsRe
 		}
 		return
 	},
+	"simulateTransaction": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
+		if err = checkSimulationAllowed(s.is, c.apiKey); err != nil {
+			return
+		}
+		r := WsSimulateTransactionReq{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			rv, err = s.api.SimulateTransaction(r.Tx)
+		}
+		return
+	},
 
 	"getMempoolFilters": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
 		r := WsMempoolFiltersReq{}
@@ -450,6 +511,17 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *WsRe
 	"unsubscribeFiatRates": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
 		return s.unsubscribeFiatRates(c)
 	},
+	"subscribeOpReturn": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
+		var r WsSubscribeOpReturnReq
+		err = json.Unmarshal(req.Params, &r)
+		if err != nil {
+			return nil, err
+		}
+		return s.subscribeOpReturn(c, &r, req)
+	},
+	"unsubscribeOpReturn": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
+		return s.unsubscribeOpReturn(c)
+	},
 	"ping": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
 		r := struct{}{}
 		return r, nil
@@ -478,6 +550,14 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *WsRe
 		}
 		return
 	},
+	"getFiatRatesCandles": func(s *WebsocketServer, c *websocketChannel, req *WsReq) (rv interface{}, err error) {
+		r := WsFiatRatesCandlesReq{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			rv, err = s.getFiatRatesCandles(&r)
+		}
+		return
+	},
 }
 
 func (s *WebsocketServer) onRequest(c *websocketChannel, req *WsReq) {
@@ -507,17 +587,28 @@ func (s *WebsocketServer) onRequest(c *websocketChannel, req *WsReq) {
 	}()
 	f, ok := requestHandlers[req.Method]
 	if ok {
-		data, err = f(s, c, req)
+		var client string
+		var keyRate float64
+		if client, keyRate, err = s.guard.admit(c.apiKey, c.ip, req.Method, c.requestHeader.Get("Origin"), apiKeyInterfaceWebsocket); err == nil {
+			if data, err = f(s, c, req); err == nil {
+				s.guard.charge(client, keyRate, req.Method, data)
+			}
+		}
 		if err == nil {
 			glog.V(1).Info("Client ", c.id, " onRequest ", req.Method, " success")
 			s.metrics.WebsocketRequests.With(common.Labels{"method": req.Method, "status": "success"}).Inc()
 		} else {
-			if apiErr, ok := err.(*api.APIError); !ok || !apiErr.Public {
+			e := resultError{}
+			e.Error.Message = err.Error()
+			if rlErr, isRateLimit := err.(*rateLimitError); isRateLimit {
+				e.Error.RetryAfter = rlErr.retryAfter
+				glog.V(1).Info("Client ", c.id, " onRequest ", req.Method, ": ", err)
+			} else if _, isKeyErr := err.(*apiKeyError); isKeyErr {
+				glog.V(1).Info("Client ", c.id, " onRequest ", req.Method, ": ", err)
+			} else if apiErr, ok := err.(*api.APIError); !ok || !apiErr.Public {
 				glog.Error("Client ", c.id, " onMessage ", req.Method, ": ", errors.ErrorStack(err), ", data ", string(req.Params))
 			}
 			s.metrics.WebsocketRequests.With(common.Labels{"method": req.Method, "status": "failure"}).Inc()
-			e := resultError{}
-			e.Error.Message = err.Error()
 			data = e
 		}
 	} else {
@@ -690,6 +781,7 @@ func (s *WebsocketServer) estimateFee(params []byte) (interface{}, error) {
 		if eip1559 != nil {
 			eip1559Api = &api.Eip1559Fees{}
 			eip1559Api.BaseFeePerGas = (*api.Amount)(eip1559.BaseFeePerGas)
+			eip1559Api.BlobBaseFeePerGas = (*api.Amount)(eip1559.BlobBaseFeePerGas)
 			eip1559Api.Instant = eip1559FeesToApi(eip1559.Instant)
 			eip1559Api.High = eip1559FeesToApi(eip1559.High)
 			eip1559Api.Medium = eip1559FeesToApi(eip1559.Medium)
@@ -995,6 +1087,79 @@ func (s *WebsocketServer) unsubscribeFiatRates(c *websocketChannel) (res interfa
 	return &subscriptionResponse{false}, nil
 }
 
+// subscribeOpReturn subscribes the channel to new outputs with OP_RETURN data starting with any of the prefixes, replacing previous subscription
+func (s *WebsocketServer) subscribeOpReturn(c *websocketChannel, r *WsSubscribeOpReturnReq, req *WsReq) (res interface{}, err error) {
+	if !s.db.HasOpReturnIndex() {
+		return &subscriptionResponseMessage{false, "subscribeOpReturn not enabled, use opreturn_index option to enable."}, nil
+	}
+	if len(r.Prefixes) == 0 || len(r.Prefixes) > maxOpReturnSubscriptionPrefixes {
+		return nil, errors.New("Invalid number of prefixes")
+	}
+	prefixes := make([]string, len(r.Prefixes))
+	for i, p := range r.Prefixes {
+		b, err := hex.DecodeString(p)
+		if err != nil || len(b) == 0 {
+			return nil, errors.New("Invalid prefix " + p)
+		}
+		prefixes[i] = hex.EncodeToString(b)
+	}
+	s.opReturnSubscriptionsLock.Lock()
+	defer s.opReturnSubscriptionsLock.Unlock()
+	s.opReturnSubscriptions[c] = &opReturnSubscription{id: req.ID, prefixes: prefixes}
+	s.metrics.WebsocketSubscribes.With((common.Labels{"method": "subscribeOpReturn"})).Set(float64(len(s.opReturnSubscriptions)))
+	return &subscriptionResponse{true}, nil
+}
+
+// unsubscribeOpReturn unsubscribes the OP_RETURN subscription of this channel
+func (s *WebsocketServer) unsubscribeOpReturn(c *websocketChannel) (res interface{}, err error) {
+	s.opReturnSubscriptionsLock.Lock()
+	defer s.opReturnSubscriptionsLock.Unlock()
+	delete(s.opReturnSubscriptions, c)
+	s.metrics.WebsocketSubscribes.With((common.Labels{"method": "subscribeOpReturn"})).Set(float64(len(s.opReturnSubscriptions)))
+	return &subscriptionResponse{false}, nil
+}
+
+func (s *WebsocketServer) hasOpReturnSubscriptions() bool {
+	s.opReturnSubscriptionsLock.Lock()
+	defer s.opReturnSubscriptionsLock.Unlock()
+	return len(s.opReturnSubscriptions) > 0
+}
+
+func (s *WebsocketServer) sendOnNewOpReturns(outputs []api.OpReturnOutput) {
+	s.opReturnSubscriptionsLock.Lock()
+	defer s.opReturnSubscriptionsLock.Unlock()
+	for i := range outputs {
+		o := &outputs[i]
+		for c, sub := range s.opReturnSubscriptions {
+			for _, prefix := range sub.prefixes {
+				if strings.HasPrefix(o.Data, prefix) {
+					data := struct {
+						Prefix string              `json:"prefix"`
+						Output *api.OpReturnOutput `json:"output"`
+					}{
+						Prefix: prefix,
+						Output: o,
+					}
+					c.DataOut(&WsRes{
+						ID:   sub.id,
+						Data: &data,
+					})
+					break
+				}
+			}
+		}
+	}
+}
+
+func (s *WebsocketServer) onNewBlockOpReturnsAsync(height uint32) {
+	outputs, err := s.api.GetOpReturnOutputsInBlock(height)
+	if err != nil {
+		glog.Error("GetOpReturnOutputsInBlock error ", err, " for ", height)
+		return
+	}
+	s.sendOnNewOpReturns(outputs)
+}
+
 func (s *WebsocketServer) onNewBlockAsync(hash string, height uint32) {
 	s.newBlockSubscriptionsLock.Lock()
 	defer s.newBlockSubscriptionsLock.Unlock()
@@ -1017,6 +1182,9 @@ func (s *WebsocketServer) onNewBlockAsync(hash string, height uint32) {
 // OnNewBlock is a callback that broadcasts info about new block to subscribed clients
 func (s *WebsocketServer) OnNewBlock(hash string, height uint32) {
 	go s.onNewBlockAsync(hash, height)
+	if s.hasOpReturnSubscriptions() {
+		go s.onNewBlockOpReturnsAsync(height)
+	}
 }
 
 func (s *WebsocketServer) sendOnNewTx(tx *api.Tx) {
@@ -1061,48 +1229,42 @@ func (s *WebsocketServer) sendOnNewTxAddr(stringAddressDescriptor string, tx *ap
 	}
 }
 
-func (s *WebsocketServer) getNewTxSubscriptions(tx *bchain.MempoolTx) map[string]struct{} {
-	// check if there is any subscription in inputs, outputs and token transfers
-	s.addressSubscriptionsLock.Lock()
-	defer s.addressSubscriptionsLock.Unlock()
-	subscribed := make(map[string]struct{})
+// forEachMempoolTxAddrDesc calls f with the address descriptors of the inputs, outputs and token transfers of the tx
+func forEachMempoolTxAddrDesc(parser bchain.BlockChainParser, tx *bchain.MempoolTx, f func(sad string)) {
 	for i := range tx.Vin {
-		sad := string(tx.Vin[i].AddrDesc)
-		if len(sad) > 0 {
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
-			}
+		if len(tx.Vin[i].AddrDesc) > 0 {
+			f(string(tx.Vin[i].AddrDesc))
 		}
 	}
 	for i := range tx.Vout {
-		addrDesc, err := s.chainParser.GetAddrDescFromVout(&tx.Vout[i])
+		addrDesc, err := parser.GetAddrDescFromVout(&tx.Vout[i])
 		if err == nil && len(addrDesc) > 0 {
-			sad := string(addrDesc)
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
-			}
+			f(string(addrDesc))
 		}
 	}
 	for i := range tx.TokenTransfers {
-		addrDesc, err := s.chainParser.GetAddrDescFromAddress(tx.TokenTransfers[i].From)
+		addrDesc, err := parser.GetAddrDescFromAddress(tx.TokenTransfers[i].From)
 		if err == nil && len(addrDesc) > 0 {
-			sad := string(addrDesc)
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
-			}
+			f(string(addrDesc))
 		}
-		addrDesc, err = s.chainParser.GetAddrDescFromAddress(tx.TokenTransfers[i].To)
+		addrDesc, err = parser.GetAddrDescFromAddress(tx.TokenTransfers[i].To)
 		if err == nil && len(addrDesc) > 0 {
-			sad := string(addrDesc)
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
-			}
+			f(string(addrDesc))
 		}
 	}
+}
+
+func (s *WebsocketServer) getNewTxSubscriptions(tx *bchain.MempoolTx) map[string]struct{} {
+	// check if there is any subscription in inputs, outputs and token transfers
+	s.addressSubscriptionsLock.Lock()
+	defer s.addressSubscriptionsLock.Unlock()
+	subscribed := make(map[string]struct{})
+	forEachMempoolTxAddrDesc(s.chainParser, tx, func(sad string) {
+		as, ok := s.addressSubscriptions[sad]
+		if ok && len(as) > 0 {
+			subscribed[sad] = struct{}{}
+		}
+	})
 	return subscribed
 }
 
@@ -1124,6 +1286,11 @@ func (s *WebsocketServer) OnNewTx(tx *bchain.MempoolTx) {
 	if len(s.newTransactionSubscriptions) > 0 || len(subscribed) > 0 {
 		go s.onNewTxAsync(tx, subscribed)
 	}
+	if s.hasOpReturnSubscriptions() {
+		if outputs := s.api.GetOpReturnOutputsFromMempoolTx(tx); len(outputs) > 0 {
+			go s.sendOnNewOpReturns(outputs)
+		}
+	}
 }
 
 func (s *WebsocketServer) broadcastTicker(currency string, rates map[string]float32, ticker *common.CurrencyRatesTicker) {
@@ -1188,6 +1355,14 @@ func (s *WebsocketServer) getFiatRatesForTimestamps(timestamps []int64, currenci
 	return ret, err
 }
 
+func (s *WebsocketServer) getFiatRatesCandles(r *WsFiatRatesCandlesReq) (*api.FiatCandles, error) {
+	interval := r.Interval
+	if interval == "" {
+		interval = fiat.CandleInterval1h
+	}
+	return s.api.GetFiatRatesCandles(strings.ToLower(r.Currency), strings.ToLower(r.Token), interval, r.From, r.To)
+}
+
 func (s *WebsocketServer) getAvailableVsCurrencies(timestamp int64, token string) (*api.AvailableVsCurrencies, error) {
 	ret, err := s.api.GetAvailableVsCurrencies(timestamp, strings.ToLower(token))
 	return ret, err
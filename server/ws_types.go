@@ -9,7 +9,7 @@ import (
 // WsReq represents a generic WebSocket request with an ID, method, and raw parameters.
 type WsReq struct {
 	ID     string          `json:"id" ts_doc:"Unique request identifier."`
-	Method string          `json:"method" ts_type:"'getAccountInfo' | 'getInfo' | 'getBlockHash'| 'getBlock' | 'getAccountUtxo' | 'getBalanceHistory' | 'getTransaction' | 'getTransactionSpecific' | 'estimateFee' | 'sendTransaction' | 'subscribeNewBlock' | 'unsubscribeNewBlock' | 'subscribeNewTransaction' | 'unsubscribeNewTransaction' | 'subscribeAddresses' | 'unsubscribeAddresses' | 'subscribeFiatRates' | 'unsubscribeFiatRates' | 'ping' | 'getCurrentFiatRates' | 'getFiatRatesForTimestamps' | 'getFiatRatesTickersList' | 'getMempoolFilters'" ts_doc:"Requested method name."`
+	Method string          `json:"method" ts_type:"'getAccountInfo' | 'getInfo' | 'getBlockHash'| 'getBlock' | 'getAccountUtxo' | 'getBalanceHistory' | 'getTransaction' | 'getTransactionSpecific' | 'estimateFee' | 'sendTransaction' | 'simulateTransaction' | 'subscribeNewBlock' | 'unsubscribeNewBlock' | 'subscribeNewTransaction' | 'unsubscribeNewTransaction' | 'subscribeAddresses' | 'unsubscribeAddresses' | 'subscribeFiatRates' | 'unsubscribeFiatRates' | 'subscribeOpReturn' | 'unsubscribeOpReturn' | 'ping' | 'getCurrentFiatRates' | 'getFiatRatesForTimestamps' | 'getFiatRatesTickersList' | 'getFiatRatesCandles' | 'getMempoolFilters'" ts_doc:"Requested method name."`
 	Params json.RawMessage `json:"params" ts_type:"any" ts_doc:"Parameters for the requested method in raw JSON format."`
 }
 
@@ -85,6 +85,7 @@ type WsBalanceHistoryReq struct {
 	Currencies []string `json:"currencies,omitempty" ts_doc:"List of currency codes for which to fetch exchange rates at each interval."`
 	Gap        int      `json:"gap,omitempty" ts_doc:"Gap limit for XPUB scanning, if relevant."`
 	GroupBy    uint32   `json:"groupBy,omitempty" ts_doc:"Size of each aggregated time window in seconds."`
+	Gains      string   `json:"gains,omitempty" ts_doc:"Cost basis method of the realized and unrealized gains: 'fifo', 'lifo' or 'avg'. Requires currencies."`
 }
 
 // WsTransactionReq requests details for a specific transaction by its txid.
@@ -145,6 +146,11 @@ type WsSendTransactionReq struct {
 	DisableAlternativeRPC bool   `json:"disableAlternativeRpc" ts_doc:"Use alternative RPC method to broadcast transaction."`
 }
 
+// WsSimulateTransactionReq requests a simulation of an Ethereum transaction in the state of the latest block.
+type WsSimulateTransactionReq struct {
+	Tx map[string]interface{} `json:"tx" ts_type:"{from: string; to?: string; data?: string; value?: string; gas?: string; gasPrice?: string; maxFeePerGas?: string; maxPriorityFeePerGas?: string; stateOverrides?: {[address: string]: any};}" ts_doc:"Transaction fields as in eth_call (hex encoded values) with an optional state override set."`
+}
+
 // WsSubscribeAddressesReq is used to subscribe to updates on a list of addresses.
 type WsSubscribeAddressesReq struct {
 	Addresses []string `json:"addresses" ts_doc:"List of addresses to subscribe for updates (e.g., new transactions)."`
@@ -156,6 +162,11 @@ type WsSubscribeFiatRatesReq struct {
 	Tokens   []string `json:"tokens,omitempty" ts_doc:"List of token symbols or IDs to get fiat rates for."`
 }
 
+// WsSubscribeOpReturnReq subscribes to new outputs with OP_RETURN data starting with any of the prefixes.
+type WsSubscribeOpReturnReq struct {
+	Prefixes []string `json:"prefixes" ts_doc:"List of hex-encoded prefixes of the OP_RETURN data."`
+}
+
 // WsCurrentFiatRatesReq requests the current fiat rates for specified currencies (and optionally a token).
 type WsCurrentFiatRatesReq struct {
 	Currencies []string `json:"currencies,omitempty" ts_doc:"List of fiat currencies, e.g. ['USD','EUR']."`
@@ -175,6 +186,15 @@ type WsFiatRatesTickersListReq struct {
 	Token     string `json:"token,omitempty" ts_doc:"Token symbol or ID if asking for token-specific fiat rates."`
 }
 
+// WsFiatRatesCandlesReq requests open, high, low and close fiat rates in the intervals of a time range.
+type WsFiatRatesCandlesReq struct {
+	Currency string `json:"currency" ts_doc:"Fiat currency code, e.g. 'usd'."`
+	Token    string `json:"token,omitempty" ts_doc:"Token contract address if asking for token fiat rates."`
+	Interval string `json:"interval,omitempty" ts_doc:"Interval of the candles: '5m', '1h' (default) or '1d'."`
+	From     int64  `json:"from,omitempty" ts_doc:"Unix timestamp of the start of the range, default 100 intervals before 'to'."`
+	To       int64  `json:"to,omitempty" ts_doc:"Unix timestamp of the end of the range, default current time."`
+}
+
 // WsRpcCallReq is used for raw RPC calls (for example, on an Ethereum-like backend).
 type WsRpcCallReq struct {
 	From string `json:"from,omitempty" ts_doc:"Address from which the RPC call is originated (if relevant)."`
@@ -186,3 +206,26 @@ type WsRpcCallReq struct {
 type WsRpcCallRes struct {
 	Data string `json:"data" ts_doc:"Hex-encoded return data from the call."`
 }
+
+// JSONRPCReq represents a JSON-RPC 2.0 request to the /api/v2/jsonrpc endpoint, the methods and their params are the same as in the websocket interface.
+type JSONRPCReq struct {
+	JSONRPC string          `json:"jsonrpc" ts_type:"'2.0'" ts_doc:"JSON-RPC protocol version, must be '2.0'."`
+	ID      json.RawMessage `json:"id,omitempty" ts_type:"string | number | null" ts_doc:"Request identifier, a request without id is a notification and gets no response."`
+	Method  string          `json:"method" ts_type:"'getAccountInfo' | 'getInfo' | 'getBlockHash' | 'getBlock' | 'getAccountUtxo' | 'getBalanceHistory' | 'getTransaction' | 'getTransactionSpecific' | 'estimateFee' | 'longTermFeeRate' | 'sendTransaction' | 'simulateTransaction' | 'ping' | 'getCurrentFiatRates' | 'getFiatRatesForTimestamps' | 'getFiatRatesTickersList' | 'getFiatRatesCandles' | 'getMempoolFilters' | 'getBlockFilter' | 'getBlockFiltersBatch' | 'rpcCall'" ts_doc:"Requested method name, the subscription methods are not available."`
+	Params  json.RawMessage `json:"params,omitempty" ts_type:"any" ts_doc:"Named parameters of the method, the same as in the websocket request."`
+}
+
+// JSONRPCError describes the error of a JSON-RPC 2.0 request.
+type JSONRPCError struct {
+	Code    int         `json:"code" ts_doc:"Error code, -32700 to -32600 are the protocol errors, -32000 error of the method, -32001 API key error, -32002 rate limit error."`
+	Message string      `json:"message" ts_doc:"Error message."`
+	Data    interface{} `json:"data,omitempty" ts_type:"{ retryAfter?: number }" ts_doc:"Additional data, retryAfter in seconds for the rate limit error."`
+}
+
+// JSONRPCRes represents a JSON-RPC 2.0 response, it contains either the result or the error.
+type JSONRPCRes struct {
+	JSONRPC string          `json:"jsonrpc" ts_type:"'2.0'" ts_doc:"JSON-RPC protocol version."`
+	ID      json.RawMessage `json:"id" ts_type:"string | number | null" ts_doc:"Corresponding request identifier, null if it could not be determined."`
+	Result  interface{}     `json:"result,omitempty" ts_doc:"Result of the method, the same as the data of the websocket response."`
+	Error   *JSONRPCError   `json:"error,omitempty" ts_doc:"Error, if the request failed."`
+}
@@ -117,6 +117,14 @@ func (c *fakeBlockChainEthereumType) EthereumTypeGetNonce(addrDesc bchain.Addres
 	return uint64(addrDesc[0]), nil
 }
 
+func (c *fakeBlockChainEthereumType) EthereumTypeGetBalanceAtBlock(addrDesc bchain.AddressDescriptor, blockNumber *big.Int) (*big.Int, error) {
+	return big.NewInt(123400000 + int64(addrDesc[0]) + blockNumber.Int64()%1000), nil
+}
+
+func (c *fakeBlockChainEthereumType) EthereumTypeGetNonceAtBlock(addrDesc bchain.AddressDescriptor, blockNumber *big.Int) (uint64, error) {
+	return uint64(addrDesc[0]) / 2, nil
+}
+
 func (c *fakeBlockChainEthereumType) GetContractInfo(contractDesc bchain.AddressDescriptor) (*bchain.ContractInfo, error) {
 	addresses, _, _ := c.Parser.GetAddressesFromAddrDesc(contractDesc)
 	return &bchain.ContractInfo{
@@ -134,6 +142,18 @@ func (c *fakeBlockChainEthereumType) EthereumTypeGetErc20ContractBalance(addrDes
 	return big.NewInt(1000000000 + int64(addrDesc[0])*1000 + int64(contractDesc[0])), nil
 }
 
+func (c *fakeBlockChainEthereumType) EthereumTypeGetErc20ContractBalanceAtBlock(addrDesc, contractDesc bchain.AddressDescriptor, blockNumber *big.Int) (*big.Int, error) {
+	return big.NewInt(900000000 + int64(addrDesc[0])*1000 + int64(contractDesc[0])), nil
+}
+
+func (c *fakeBlockChainEthereumType) EthereumTypeGetErc20ContractBalancesAtBlock(addrDesc bchain.AddressDescriptor, contractDescs []bchain.AddressDescriptor, blockNumber *big.Int) ([]*big.Int, error) {
+	balances := make([]*big.Int, len(contractDescs))
+	for i := range contractDescs {
+		balances[i], _ = c.EthereumTypeGetErc20ContractBalanceAtBlock(addrDesc, contractDescs[i], blockNumber)
+	}
+	return balances, nil
+}
+
 // EthereumTypeRpcCall calls eth_call with given data and to address
 func (c *fakeBlockChainEthereumType) EthereumTypeRpcCall(data, to, from string) (string, error) {
 	return data + "abcd", nil
@@ -148,3 +168,29 @@ func (c *fakeBlockChainEthereumType) EthereumTypeGetRawTransaction(txid string)
 func (c *fakeBlockChainEthereumType) GetTokenURI(contractDesc bchain.AddressDescriptor, tokenID *big.Int) (string, error) {
 	return "https://ipfs.io/ipfs/" + contractDesc.String()[3:] + ".json", nil
 }
+
+// EthereumTypeSimulateTransaction returns simulated transfer of the value and of 1000 tokens of EthAddrContract4a from the sender to the recipient,
+// paid by the gas price 1 Gwei
+func (c *fakeBlockChainEthereumType) EthereumTypeSimulateTransaction(params map[string]interface{}) (*bchain.EthereumSimulation, error) {
+	from, _ := params["from"].(string)
+	to, _ := params["to"].(string)
+	s := &bchain.EthereumSimulation{
+		From:     from,
+		To:       to,
+		GasUsed:  51234,
+		GasPrice: big.NewInt(1000000000),
+		TokenTransfers: bchain.TokenTransfers{
+			{
+				Standard: bchain.FungibleToken,
+				Contract: "0x4af4114F73d1c1C903aC9E0361b379D1291808A2",
+				From:     from,
+				To:       to,
+				Value:    *big.NewInt(1000),
+			},
+		},
+	}
+	if value, ok := params["value"].(string); ok {
+		s.Value.SetString(value, 0)
+	}
+	return s, nil
+}
@@ -0,0 +1,163 @@
+//go:build integration
+
+package sync
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+// testResumeBulkConnect crashes the bulk connect between checkpoints and verifies
+// that after reopening the db the sync resumes from the last checkpoint to the same state as uninterrupted sync
+func testResumeBulkConnect(t *testing.T, h *TestHandler) {
+	for _, rng := range h.TestData.ConnectBlocks.SyncRanges {
+		if rng.Upper-rng.Lower < 4 {
+			continue
+		}
+		m, err := getMetrics(h.Coin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parser := h.Chain.GetChainParser()
+		p, err := os.MkdirTemp("", "sync_test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(p)
+
+		d, err := db.NewRocksDB(p, 1<<17, 1<<14, parser, m, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.SetInternalState(&common.InternalState{})
+		bc, err := d.InitBulkConnect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// checkpoint after every 2 blocks, crash after odd number of blocks so that the last block is lost
+		db.SetBulkConnectCheckpointBlocks(bc, 2)
+		crashHeight := rng.Lower + 2*((rng.Upper-rng.Lower)/4)
+		for height := rng.Lower; height <= crashHeight; height++ {
+			block, err := getBlock(h.Chain, height)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := bc.ConnectBlock(block, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := db.SimulateCrash(d); err != nil {
+			t.Fatal(err)
+		}
+
+		d, err = db.NewRocksDB(p, 1<<17, 1<<14, parser, m, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer d.Close()
+		is, err := d.LoadInternalState(&common.Config{CoinName: h.Coin})
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.SetInternalState(is)
+		if is.DbState == common.DbStateInconsistent {
+			t.Fatal("DB in DbStateInconsistent after crash")
+		}
+		resumed, err := d.ResumeFromBulkCheckpoint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resumed {
+			t.Fatal("Bulk connect checkpoint not found after crash")
+		}
+		height, hash, err := d.GetBestBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedHash, err := h.Chain.GetBlockHash(crashHeight - 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != crashHeight-1 || hash != expectedHash {
+			t.Fatalf("Best block after crash %d %s, want checkpoint %d %s", height, hash, crashHeight-1, expectedHash)
+		}
+
+		ch := make(chan os.Signal)
+		sw, err := db.NewSyncWorkerWithConfig(d, h.Chain, 8, 0, int(height+1), false, ch, m, is, testSyncWorkerConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sw.BulkConnectBlocks(height+1, rng.Upper); err != nil {
+			t.Fatal(err)
+		}
+		height, hash, err = d.GetBestBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		upperHash, err := h.Chain.GetBlockHash(rng.Upper)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != rng.Upper || hash != upperHash {
+			t.Fatalf("Best block after resume %d %s, want %d %s", height, hash, rng.Upper, upperHash)
+		}
+
+		t.Run("verifyBlockInfo", func(t *testing.T) { verifyBlockInfo(t, d, h, rng) })
+		t.Run("verifyTransactions", func(t *testing.T) { verifyTransactions(t, d, h, rng) })
+		t.Run("verifyAddresses", func(t *testing.T) { verifyAddresses(t, d, h, rng) })
+		t.Run("verifyBalances", func(t *testing.T) { verifyBalancesAgainstUninterrupted(t, d, h, rng) })
+	}
+}
+
+func getBlock(chain bchain.BlockChain, height uint32) (*bchain.Block, error) {
+	hash, err := chain.GetBlockHash(height)
+	if err != nil {
+		return nil, err
+	}
+	block, err := chain.GetBlock(hash, height)
+	if err != nil {
+		return nil, err
+	}
+	block.Height = height
+	return block, nil
+}
+
+// verifyBalancesAgainstUninterrupted checks that the balances are not double counted by the replay of blocks after crash
+func verifyBalancesAgainstUninterrupted(t *testing.T, d *db.RocksDB, h *TestHandler, rng Range) {
+	withRocksDBAndSyncWorker(t, h, rng.Lower, func(ref *db.RocksDB, sw *db.SyncWorker, _ chan os.Signal) {
+		if err := sw.BulkConnectBlocks(rng.Lower, rng.Upper); err != nil {
+			t.Fatal(err)
+		}
+		parser := h.Chain.GetChainParser()
+		for height := rng.Lower; height <= rng.Upper; height++ {
+			block, found := h.TestData.ConnectBlocks.Blocks[height]
+			if !found {
+				continue
+			}
+			for _, tx := range block.TxDetails {
+				for i := range tx.Vout {
+					addrDesc, err := parser.GetAddrDescFromVout(&tx.Vout[i])
+					if err != nil || len(addrDesc) == 0 {
+						continue
+					}
+					got, err := d.GetAddrDescBalance(addrDesc, db.AddressBalanceDetailUTXO)
+					if err != nil {
+						t.Fatal(err)
+					}
+					want, err := ref.GetAddrDescBalance(addrDesc, db.AddressBalanceDetailUTXO)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("Tx %s output %d: balance mismatch: got %+v, want %+v", tx.Txid, i, got, want)
+					}
+				}
+			}
+		}
+	})
+}
@@ -20,6 +20,7 @@ var testMap = map[string]func(t *testing.T, th *TestHandler){
 	"ConnectBlocks":         testConnectBlocks,
 	"ConnectBlocksParallel": testConnectBlocksParallel,
 	"HandleFork":            testHandleFork,
+	"ResumeBulkConnect":     testResumeBulkConnect,
 }
 
 type TestHandler struct {